@@ -11,27 +11,44 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"service/internal/config"
+	"service/internal/domain/repository"
 	"service/internal/http-server/handler"
+	"service/internal/http-server/middleware/idempotency"
+	"service/internal/lib/audit"
 	"service/internal/lib/logger/handlers/slogpretty"
+	"service/internal/lib/logger/rotate"
 	"service/internal/lib/logger/sl"
 	"service/internal/storage/mysql"
 	"syscall"
+	"time"
 )
 
 const (
 	envLocal = "local"
 	envDev   = "dev"
 	envProd  = "prod"
+	envTest  = "test"
 )
 
 func main() {
 	cfg := config.MustLoad()
 
-	log := setupLogger(cfg.Env)
+	out, err := setupLogOutput(cfg.LogFile)
+	if err != nil {
+		slog.Error("failed to open log file", sl.Err(err))
+		os.Exit(1)
+	}
+	defer out.Close()
+
+	log := setupLogger(cfg.Env, out)
 
 	log.Info("starting edu-helper", slog.String("env", cfg.Env))
 	log.Debug("debug messages are enabled")
@@ -42,6 +59,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkCtx, checkCancel := context.WithTimeout(ctx, 10*time.Second)
+	err = mysql.HealthCheck(checkCtx, storage)
+	checkCancel()
+	if err != nil {
+		log.Error("storage self-check failed", sl.Err(err))
+		os.Exit(1)
+	}
+
+	go audit.RunRetention(ctx, repository.NewAuditLogRepository(storage, cfg.Audit.StoreSnapshots),
+		cfg.AuditRetention.MaxAge, cfg.AuditRetention.Interval, log)
+	go idempotency.RunCleanup(ctx, repository.NewIdempotencyKeyRepository(storage), cfg.Idempotency.CleanupInterval, log)
+
 	srv, err := handler.NewServer(log, cfg, storage)
 	if err != nil {
 		log.Error("failed to init http server", sl.Err(err))
@@ -52,38 +84,72 @@ func main() {
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		if err := srv.ListenAndServe(); err != nil {
-			log.Error("failed to start server")
+		var err error
+		if srv.TLSConfig != nil {
+			// сертификат уже загружен в TLSConfig в NewServer
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("failed to start server", sl.Err(err))
 		}
 	}()
 
-	log.Info("server started")
+	log.Info("server started", slog.String("address", srv.Addr), slog.Bool("tls", srv.TLSConfig != nil))
 
 	<-done
 	log.Info("stopping server")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to stop server", sl.Err(err))
+	}
+	if err := storage.Close(); err != nil {
+		log.Error("failed to close storage", sl.Err(err))
+	}
 }
 
-func setupLogger(env string) *slog.Logger {
+// setupLogOutput stdout по умолчанию или файл с ротацией, если задан log_file.path
+func setupLogOutput(cfg config.LogFile) (io.WriteCloser, error) {
+	if cfg.Path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return rotate.New(cfg.Path, int64(cfg.MaxSizeMB)<<20, cfg.MaxBackups)
+}
+
+// nopCloser не даёт закрыть os.Stdout
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func setupLogger(env string, out io.Writer) *slog.Logger {
 	var log *slog.Logger
 	switch env {
 	case envLocal:
-		log = setupPrettySlog()
+		log = setupPrettySlog(out)
 	case envDev:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		log = slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	case envProd:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		log = slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	case envTest:
+		log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	default:
+		// неизвестное окружение: логируем как в prod, чтобы не получить nil
+		log = slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	}
 
 	return log
 }
-func setupPrettySlog() *slog.Logger {
+func setupPrettySlog(out io.Writer) *slog.Logger {
 	opts := slogpretty.PrettyHandlerOptions{
 		SlogOpts: &slog.HandlerOptions{
 			Level: slog.LevelDebug,
 		},
 	}
 
-	handler := opts.NewPrettyHandler(os.Stdout)
+	handler := opts.NewPrettyHandler(out)
 
 	return slog.New(handler)
 }
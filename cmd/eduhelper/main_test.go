@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetupLogger_UnknownEnvFallsBackToProd(t *testing.T) {
+	var out bytes.Buffer
+	log := setupLogger("unknown", &out)
+	if log == nil {
+		t.Fatal("setupLogger returned nil")
+	}
+
+	log.Debug("debug message")
+	log.Info("info message")
+
+	got := out.String()
+	if strings.Contains(got, "debug message") {
+		t.Fatalf("debug is logged for unknown env: %s", got)
+	}
+	if !strings.Contains(got, `"msg":"info message"`) {
+		t.Fatalf("info is not logged as JSON: %s", got)
+	}
+}
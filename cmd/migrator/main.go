@@ -4,62 +4,140 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"os"
+
+	"service/migrations"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
-func main() {
-	var (
-		migrationsPath  string
-		migrationsTable string
-		dbUser          string
-		dbPassword      string
-		dbHost          string
-		dbPort          string
-		dbName          string
-		down            bool
-		step            int
-	)
+// options параметры командной строки мигратора
+type options struct {
+	migrationsPath  string
+	migrationsTable string
+	dbUser          string
+	dbPassword      string
+	dbHost          string
+	dbPort          string
+	dbName          string
+	down            bool
+	step            int
+	version         bool
+	force           int
+	embedded        bool
+}
+
+// flagOutput куда печатаются usage и ошибки разбора флагов
+var flagOutput io.Writer = os.Stderr
 
-	flag.StringVar(&migrationsPath, "migrations-path", "", "path to migrations")
-	flag.StringVar(&migrationsTable, "migrations-table", "", "name of the migrations table")
-	flag.StringVar(&dbUser, "db-user", "root", "MySQL user")
-	flag.StringVar(&dbPassword, "db-password", "", "MySQL password")
-	flag.StringVar(&dbHost, "db-host", "localhost", "MySQL host")
-	flag.StringVar(&dbPort, "db-port", "3306", "MySQL port")
-	flag.StringVar(&dbName, "db-name", "", "MySQL database name")
-	flag.BoolVar(&down, "down", false, "revert all migrations (down to version 0)")
-	flag.IntVar(&step, "step", 0, "migrate up/down N steps. Use negative for down, positive for up.")
-	flag.Parse()
-
-	if migrationsPath == "" {
-		panic("migrations-path is required")
+func parseFlags(args []string) (options, error) {
+	var o options
+	fs := flag.NewFlagSet("migrator", flag.ContinueOnError)
+	fs.SetOutput(flagOutput)
+	fs.StringVar(&o.migrationsPath, "migrations-path", "", "path to migrations")
+	fs.StringVar(&o.migrationsTable, "migrations-table", "", "name of the migrations table")
+	fs.StringVar(&o.dbUser, "db-user", "root", "MySQL user")
+	fs.StringVar(&o.dbPassword, "db-password", "", "MySQL password")
+	fs.StringVar(&o.dbHost, "db-host", "localhost", "MySQL host")
+	fs.StringVar(&o.dbPort, "db-port", "3306", "MySQL port")
+	fs.StringVar(&o.dbName, "db-name", "", "MySQL database name")
+	fs.BoolVar(&o.down, "down", false, "revert all migrations (down to version 0)")
+	fs.IntVar(&o.step, "step", 0, "migrate up/down N steps. Use negative for down, positive for up.")
+	fs.BoolVar(&o.version, "version", false, "print current migration version and dirty state")
+	fs.IntVar(&o.force, "force", -1, "force set migration version and clear dirty state")
+	fs.BoolVar(&o.embedded, "embedded", false, "use migrations embedded into the binary instead of migrations-path")
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
 	}
-	if dbName == "" {
-		panic("db-name is required")
+
+	if o.migrationsPath == "" && !o.embedded {
+		return options{}, errors.New("migrations-path is required")
+	}
+	if o.dbName == "" {
+		return options{}, errors.New("db-name is required")
+	}
+	return o, nil
+}
+
+// versioner часть migrate.Migrate, нужная для -version
+type versioner interface {
+	Version() (version uint, dirty bool, err error)
+}
+
+// printVersion выводит текущую версию миграций и признак dirty
+func printVersion(w io.Writer, m versioner) error {
+	v, dirty, err := m.Version()
+	if err != nil {
+		if errors.Is(err, migrate.ErrNilVersion) {
+			fmt.Fprintln(w, "no migrations applied")
+			return nil
+		}
+		return err
+	}
+	fmt.Fprintf(w, "version: %d, dirty: %t\n", v, dirty)
+	return nil
+}
+
+func main() {
+	o, err := parseFlags(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		panic(err)
 	}
 
 	dsn := fmt.Sprintf(
 		"mysql://%s:%s@tcp(%s:%s)/%s?multiStatements=true",
-		dbUser, dbPassword, dbHost, dbPort, dbName,
+		o.dbUser, o.dbPassword, o.dbHost, o.dbPort, o.dbName,
 	)
-	if migrationsTable != "" {
-		dsn = fmt.Sprintf("%s&x-migrations-table=%s", dsn, migrationsTable)
+	if o.migrationsTable != "" {
+		dsn = fmt.Sprintf("%s&x-migrations-table=%s", dsn, o.migrationsTable)
 	}
 
-	m, err := migrate.New(
-		"file://"+migrationsPath,
-		dsn,
-	)
-	if err != nil {
-		panic(err)
+	var m *migrate.Migrate
+	if o.embedded {
+		src, err := iofs.New(migrations.FS, ".")
+		if err != nil {
+			panic(err)
+		}
+		m, err = migrate.NewWithSourceInstance("iofs", src, dsn)
+		if err != nil {
+			panic(err)
+		}
+	} else {
+		m, err = migrate.New(
+			"file://"+o.migrationsPath,
+			dsn,
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	if o.version {
+		if err := printVersion(os.Stdout, m); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if o.force >= 0 {
+		fmt.Printf("forcing version %d...\n", o.force)
+		if err := m.Force(o.force); err != nil {
+			panic(err)
+		}
+		fmt.Println("version forced successfully")
+		return
 	}
 
-	if step != 0 {
-		fmt.Printf("migrating %d steps...\n", step)
-		if err := m.Steps(step); err != nil {
+	if o.step != 0 {
+		fmt.Printf("migrating %d steps...\n", o.step)
+		if err := m.Steps(o.step); err != nil {
 			if errors.Is(err, migrate.ErrNoChange) {
 				fmt.Println("no migrations to apply")
 				return
@@ -70,7 +148,7 @@ func main() {
 		return
 	}
 
-	if down {
+	if o.down {
 		fmt.Println("reverting all migrations (down to version 0)...")
 		if err := m.Down(); err != nil {
 			if errors.Is(err, migrate.ErrNoChange) {
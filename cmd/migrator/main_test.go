@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		check   func(t *testing.T, o options)
+	}{
+		{
+			name: "по умолчанию up без version и force",
+			args: []string{"-migrations-path=./migrations", "-db-name=edu"},
+			check: func(t *testing.T, o options) {
+				if o.version || o.down || o.step != 0 || o.force != -1 {
+					t.Errorf("options = %+v, want plain up", o)
+				}
+				if o.dbUser != "root" || o.dbHost != "localhost" || o.dbPort != "3306" {
+					t.Errorf("db defaults = %s@%s:%s", o.dbUser, o.dbHost, o.dbPort)
+				}
+			},
+		},
+		{
+			name: "version",
+			args: []string{"-migrations-path=./migrations", "-db-name=edu", "-version"},
+			check: func(t *testing.T, o options) {
+				if !o.version {
+					t.Error("version flag not set")
+				}
+			},
+		},
+		{
+			name: "force нулевой версии",
+			args: []string{"-embedded", "-db-name=edu", "-force", "0"},
+			check: func(t *testing.T, o options) {
+				if o.force != 0 || !o.embedded {
+					t.Errorf("force = %d, embedded = %t", o.force, o.embedded)
+				}
+			},
+		},
+		{name: "нет пути к миграциям", args: []string{"-db-name=edu"}, wantErr: true},
+		{name: "нет имени БД", args: []string{"-embedded"}, wantErr: true},
+		{name: "force не число", args: []string{"-embedded", "-db-name=edu", "-force=abc"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := parseFlagsQuiet(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, o)
+			}
+		})
+	}
+}
+
+// parseFlagsQuiet не даёт FlagSet печатать usage в вывод теста
+func parseFlagsQuiet(args []string) (options, error) {
+	stderr := flagOutput
+	flagOutput = io.Discard
+	defer func() { flagOutput = stderr }()
+	return parseFlags(args)
+}
+
+type fakeVersion struct {
+	v     uint
+	dirty bool
+	err   error
+}
+
+func (f fakeVersion) Version() (uint, bool, error) { return f.v, f.dirty, f.err }
+
+func TestPrintVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       fakeVersion
+		want    string
+		wantErr bool
+	}{
+		{"чистая версия", fakeVersion{v: 24}, "version: 24, dirty: false\n", false},
+		{"dirty", fakeVersion{v: 7, dirty: true}, "version: 7, dirty: true\n", false},
+		{"миграции не применялись", fakeVersion{err: migrate.ErrNilVersion}, "no migrations applied\n", false},
+		{"ошибка БД", fakeVersion{err: errors.New("connection refused")}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := printVersion(&out, tt.m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
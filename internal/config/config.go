@@ -9,10 +9,26 @@ import (
 )
 
 type Config struct {
-	Env        string `yaml:"env" env:"ENV" env-required:"true"`
-	SQLPath    `yaml:"sql_path" env-required:"true"`
-	HTTPServer `yaml:"http_server"`
-	JwtSecret  string `yaml:"jwt-secret" env-required:"true"`
+	Env             string `yaml:"env" env:"ENV" env-required:"true"`
+	SQLPath         `yaml:"sql_path" env-required:"true"`
+	HTTPServer      `yaml:"http_server"`
+	JwtSecret       string        `yaml:"jwt-secret" env-required:"true"`
+	JwtTTL          time.Duration `yaml:"jwt-ttl" env-default:"24h"`
+	Validation      `yaml:"validation"`
+	SMTP            SMTP            `yaml:"smtp"`
+	Audit           Audit           `yaml:"audit"`
+	AuditRetention  AuditRetention  `yaml:"audit_retention"`
+	Idempotency     Idempotency     `yaml:"idempotency"`
+	GlobalRateLimit GlobalRateLimit `yaml:"global_rate_limit"`
+	LogFile         LogFile         `yaml:"log_file"`
+	TLS             TLS             `yaml:"tls"`
+	RBAC            RBAC            `yaml:"rbac"`
+	// DefaultRole роль, назначаемая при регистрации, пустая — без роли
+	DefaultRole string `yaml:"default_role" env:"DEFAULT_ROLE"`
+	// MaxBatchSize наибольшее число элементов в одном пакетном запросе (bulk-delete, импорт CSV)
+	MaxBatchSize int `yaml:"max_batch_size" env:"MAX_BATCH_SIZE" env-default:"1000"`
+	// TrustedProxies адреса/подсети прокси, которым доверяем X-Forwarded-For и X-Real-IP
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES" env-separator:","`
 }
 
 type SQLPath struct {
@@ -23,10 +39,83 @@ type SQLPath struct {
 	DBName   string `yaml:"db_name" env-required:"true"`
 }
 
+// HTTPServer ReadTimeout и WriteTimeout по умолчанию берутся из Timeout.
+// HandlerTimeout ограничивает работу обработчика защищённых эндпоинтов, 0 — без ограничения
 type HTTPServer struct {
-	Address     string        `yaml:"address" env-default:"localhost:8080"`
-	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
-	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	Address        string        `yaml:"address" env-default:"localhost:8080"`
+	Timeout        time.Duration `yaml:"timeout" env-default:"4s"`
+	ReadTimeout    time.Duration `yaml:"read_timeout"`
+	WriteTimeout   time.Duration `yaml:"write_timeout"`
+	IdleTimeout    time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	HandlerTimeout time.Duration `yaml:"handler_timeout" env-default:"0"`
+}
+
+type Validation struct {
+	DisciplineAcademicYear bool `yaml:"discipline_academic_year" env-default:"false"`
+	// StudentAcademicYear группа студента должна относиться к текущему учебному году
+	StudentAcademicYear bool `yaml:"student_academic_year" env-default:"false"`
+	// CurrentAcademicYearID текущий учебный год для проверки, 0 — определяется по дате
+	CurrentAcademicYearID int64 `yaml:"current_academic_year_id" env-default:"0"`
+	// PhoneStrict принимать телефоны только в международном формате "+..."
+	PhoneStrict bool `yaml:"phone_strict" env-default:"false"`
+}
+
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port" env-default:"587"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// Audit StoreSnapshots = false — для изменений, у которых сохраняется changes, не хранить
+// полные снимки old_data/new_data, чтобы audit_log не разрастался
+type Audit struct {
+	StoreSnapshots bool `yaml:"store_snapshots" env:"AUDIT_STORE_SNAPSHOTS" env-default:"true"`
+}
+
+// AuditRetention срок хранения записей аудита, MaxAge = 0 отключает очистку
+type AuditRetention struct {
+	MaxAge   time.Duration `yaml:"max_age" env-default:"0"`
+	Interval time.Duration `yaml:"interval" env-default:"24h"`
+}
+
+// Idempotency CleanupInterval период удаления истёкших ключей идемпотентности, 0 отключает очистку
+type Idempotency struct {
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env-default:"1h"`
+}
+
+// GlobalRateLimit ограничение запросов с одного IP, RPS = 0 отключает ограничение
+type GlobalRateLimit struct {
+	RPS   float64 `yaml:"rps" env-default:"0"`
+	Burst int     `yaml:"burst" env-default:"20"`
+}
+
+// LogFile файл для логов вместо stdout, пустой Path — писать в stdout.
+// MaxSizeMB = 0 отключает ротацию
+type LogFile struct {
+	Path       string `yaml:"path" env:"LOG_FILE"`
+	MaxSizeMB  int    `yaml:"max_size_mb" env-default:"100"`
+	MaxBackups int    `yaml:"max_backups" env-default:"5"`
+}
+
+// TLS сертификат и ключ в PEM. Если оба пустые, сервер работает по HTTP,
+// иначе по HTTPS с HTTP/2
+type TLS struct {
+	CertFile string `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"TLS_KEY_FILE"`
+}
+
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// RBAC TokenClaims — при логине роли и права записываются в токен, и проверка прав
+// берёт их оттуда без запросов в БД. Цена — устаревание: выданные или снятые роли
+// вступают в силу только с новым токеном, то есть до jwt-ttl спустя.
+// Токены без прав (выданные до включения) по-прежнему проверяются по БД
+type RBAC struct {
+	TokenClaims bool `yaml:"token_claims" env:"RBAC_TOKEN_CLAIMS" env-default:"false"`
 }
 
 func MustLoad() *Config {
@@ -38,11 +127,28 @@ func MustLoad() *Config {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		panic("config file does not exist: " + path)
 	}
+	return mustRead(path)
+}
+
+// mustRead читает конфиг из path и дополняет значения по умолчанию
+func mustRead(path string) *Config {
 	var cfg Config
 
 	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
 		panic("failed to read config: " + err.Error())
 	}
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = cfg.Timeout
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = cfg.Timeout
+	}
+	if cfg.JwtTTL <= 0 {
+		panic("jwt-ttl must be greater than zero")
+	}
+	if cfg.TLS.Enabled() && (cfg.TLS.CertFile == "" || cfg.TLS.KeyFile == "") {
+		panic("tls.cert_file and tls.key_file must be set together")
+	}
 	return &cfg
 }
 
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, httpServer string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `env: "local"
+sql_path:
+  user: "root"
+  password: "root"
+  db_name: "eduhelper"
+jwt-secret: "secret"
+http_server:
+` + httpServer
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMustRead_ServerTimeouts(t *testing.T) {
+	tests := []struct {
+		name                   string
+		httpServer             string
+		wantRead, wantWrite    time.Duration
+		wantTimeout, wantLimit time.Duration
+	}{
+		{"separate", "  timeout: 4s\n  read_timeout: 5s\n  write_timeout: 30s\n  handler_timeout: 10s\n", 5 * time.Second, 30 * time.Second, 4 * time.Second, 10 * time.Second},
+		{"fallback to timeout", "  timeout: 7s\n", 7 * time.Second, 7 * time.Second, 7 * time.Second, 0},
+		{"only write", "  timeout: 4s\n  write_timeout: 1m\n", 4 * time.Second, time.Minute, 4 * time.Second, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := mustRead(writeConfig(t, tt.httpServer))
+			if cfg.ReadTimeout != tt.wantRead || cfg.WriteTimeout != tt.wantWrite {
+				t.Errorf("read/write = %s/%s, want %s/%s", cfg.ReadTimeout, cfg.WriteTimeout, tt.wantRead, tt.wantWrite)
+			}
+			// timeout и handler_timeout не подменяются серверными
+			if cfg.Timeout != tt.wantTimeout || cfg.HandlerTimeout != tt.wantLimit {
+				t.Errorf("timeout/handler_timeout = %s/%s, want %s/%s", cfg.Timeout, cfg.HandlerTimeout, tt.wantTimeout, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -40,7 +40,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -49,6 +49,12 @@ const docTemplate = `{
                         "description": "Смещение",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -59,6 +65,12 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/service_internal_domain_models.AcademicYear"
                             }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
+                            }
                         }
                     }
                 }
@@ -100,6 +112,59 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/academic-years/current/semesters": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "academic-years"
+                ],
+                "summary": "Семестры текущего учебного года",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.Semester"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/academic-years/{id}": {
             "get": {
                 "security": [
@@ -209,6 +274,82 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "В учебном году есть группы или семестры",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/academic-years/{id}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт новый учебный год и копирует в него семестры (со сдвигом дат на разницу начал годов) и группы исходного года одной транзакцией. Студенты не переносятся",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "academic-years"
+                ],
+                "summary": "Скопировать структуру учебного года",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исходного учебного года",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Название и даты нового года",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.AcademicYearCloneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.AcademicYearCloneResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
             }
@@ -224,7 +365,8 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
                     "attendances"
@@ -243,29 +385,47 @@ const docTemplate = `{
                         "name": "discipline_id",
                         "in": "query"
                     },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (через группу дисциплины)",
+                        "name": "academic_year_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "С даты (YYYY-MM-DD)",
-                        "name": "from_date",
+                        "description": "Дата занятия (YYYY-MM-DD)",
+                        "name": "date",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "По дату (YYYY-MM-DD)",
-                        "name": "to_date",
+                        "description": "Изменённые начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "updated_since",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Смещение",
+                        "description": "Смещение (игнорируется при after_id)",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Keyset-пагинация: записи с id больше after_id, для выгрузок и глубоких страниц",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -276,6 +436,16 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/service_internal_domain_models.Attendance"
                             }
+                        },
+                        "headers": {
+                            "X-Next-After-Id": {
+                                "type": "integer",
+                                "description": "after_id следующей страницы в keyset-режиме"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
+                            }
                         }
                     }
                 }
@@ -303,7 +473,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Attendance"
+                            "$ref": "#/definitions/service_internal_domain_models.CreateAttendanceRequest"
                         }
                     }
                 ],
@@ -313,89 +483,155 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/service_internal_domain_models.Attendance"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/attendances/{id}": {
+        "/api/v1/attendances/low": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Студенты, у которых доля посещённых занятий за период меньше порога, от худших к лучшим",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "attendances"
                 ],
-                "summary": "Получить посещаемость по ID",
+                "summary": "Студенты с низкой посещаемостью",
                 "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Порог доли посещений, (0, 1] (по умолчанию 0.75)",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату включительно (YYYY-MM-DD)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID посещаемости",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Attendance"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.AttendanceLowRate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/attendances/me": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "attendances"
                 ],
-                "summary": "Обновить посещаемость",
+                "summary": "Посещаемость текущего студента",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID посещаемости",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ID дисциплины",
+                        "name": "discipline_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Посещаемость",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Attendance"
-                        }
+                        "type": "string",
+                        "description": "Дата занятия (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменённые начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Attendance"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.Attendance"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/attendances/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -410,7 +646,7 @@ const docTemplate = `{
                 "tags": [
                     "attendances"
                 ],
-                "summary": "Удалить посещаемость",
+                "summary": "Получить посещаемость по ID",
                 "parameters": [
                     {
                         "type": "integer",
@@ -421,8 +657,91 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Attendance"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendances"
+                ],
+                "summary": "Обновить посещаемость",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID посещаемости",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Посещаемость",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Attendance"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Attendance"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendances"
+                ],
+                "summary": "Удалить посещаемость",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID посещаемости",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
                         "schema": {
                             "type": "string"
                         }
@@ -430,6 +749,101 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/audit-logs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit-logs"
+                ],
+                "summary": "Получить список аудитов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип действия (INSERT, UPDATE, DELETE или RENAME — переименование дисциплины)",
+                        "name": "action_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.AuditLogWithUser"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/audit-logs/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Поток записей аудита, по одной JSON-записи на строку, от старых к новым. Для SIEM:\nследующий запрос продолжает с from_date = created_at последней полученной записи.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "audit-logs"
+                ],
+                "summary": "Выгрузка аудита в NDJSON",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип действия (INSERT, UPDATE, DELETE или RENAME — переименование дисциплины)",
+                        "name": "action_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Записи начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Одна запись на строку",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.AuditLog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/curriculums": {
             "get": {
                 "security": [
@@ -462,7 +876,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -635,8 +1049,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/disciplines": {
-            "get": {
+        "/api/v1/curriculums/{id}/copy": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -649,48 +1063,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "disciplines"
+                    "curriculums"
                 ],
-                "summary": "Получить список дисциплин с фильтрацией",
+                "summary": "Скопировать учебный план в другой семестр",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID преподавателя",
-                        "name": "teacher_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID группы",
-                        "name": "student_group_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Ограничение",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "ID исходного учебного плана",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Смещение",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Целевой семестр",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.CurriculumCopyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.Discipline"
-                            }
+                            "$ref": "#/definitions/service_internal_domain_models.Curriculum"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/dashboard": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -703,31 +1132,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "disciplines"
+                    "dashboard"
                 ],
-                "summary": "Создать дисциплину",
-                "parameters": [
-                    {
-                        "description": "Дисциплина",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                "summary": "Получить метрики для дашборда",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
+                            "$ref": "#/definitions/service_internal_domain_models.DashboardMetrics"
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/disciplines/public": {
+        "/api/v1/disciplines": {
             "get": {
                 "security": [
                     {
@@ -743,7 +1167,7 @@ const docTemplate = `{
                 "tags": [
                     "disciplines"
                 ],
-                "summary": "Получить список публичных дисциплин",
+                "summary": "Получить список дисциплин с фильтрацией",
                 "parameters": [
                     {
                         "type": "integer",
@@ -753,7 +1177,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "ID группы студентов",
+                        "description": "ID группы",
                         "name": "student_group_id",
                         "in": "query"
                     },
@@ -763,9 +1187,15 @@ const docTemplate = `{
                         "name": "academic_year_id",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Изменённые начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -774,6 +1204,18 @@ const docTemplate = `{
                         "description": "Смещение",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только с выставленными оценками (true) или без них (false)",
+                        "name": "has_grades",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть models.DisciplineWithCurriculumCount с числом пунктов учебного плана",
+                        "name": "with_curriculum_count",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -782,15 +1224,19 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.DisciplinePublic"
+                                "$ref": "#/definitions/service_internal_domain_models.Discipline"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
-            }
-        },
-        "/api/v1/disciplines/public/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -805,27 +1251,35 @@ const docTemplate = `{
                 "tags": [
                     "disciplines"
                 ],
-                "summary": "Получить публичную дисциплину по ID",
+                "summary": "Создать дисциплину",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID дисциплины",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Дисциплина",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.DisciplinePublic"
+                            "$ref": "#/definitions/internal_http-server_handler_v1.disciplineCreateResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/disciplines/{id}": {
+        "/api/v1/disciplines/public": {
             "get": {
                 "security": [
                     {
@@ -841,26 +1295,60 @@ const docTemplate = `{
                 "tags": [
                     "disciplines"
                 ],
-                "summary": "Получить дисциплину по ID",
+                "summary": "Получить список публичных дисциплин",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID дисциплины",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ID преподавателя",
+                        "name": "teacher_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID группы студентов",
+                        "name": "student_group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года",
+                        "name": "academic_year_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменённые начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.DisciplinePublic"
+                            }
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/disciplines/public/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -875,7 +1363,7 @@ const docTemplate = `{
                 "tags": [
                     "disciplines"
                 ],
-                "summary": "Обновить дисциплину",
+                "summary": "Получить публичную дисциплину по ID",
                 "parameters": [
                     {
                         "type": "integer",
@@ -883,27 +1371,20 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Дисциплина",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
+                            "$ref": "#/definitions/service_internal_domain_models.DisciplinePublic"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/disciplines/reassign": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -918,71 +1399,71 @@ const docTemplate = `{
                 "tags": [
                     "disciplines"
                 ],
-                "summary": "Удалить дисциплину",
+                "summary": "Передать дисциплины другому преподавателю",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID дисциплины",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Текущий и новый преподаватель",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.DisciplineReassignRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/service_internal_domain_models.DisciplineReassignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/gradejournals": {
+        "/api/v1/disciplines/search": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "gradejournals"
+                    "disciplines"
                 ],
-                "summary": "Получить список оценок с фильтрацией",
+                "summary": "Поиск дисциплин по названию",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID студента",
-                        "name": "student_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID дисциплины",
-                        "name": "discipline_id",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "С даты (YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "По дату (YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query"
+                        "description": "Часть названия",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -999,13 +1480,27 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
+                                "$ref": "#/definitions/service_internal_domain_models.DisciplinePublic"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/disciplines/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1018,32 +1513,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "gradejournals"
+                    "disciplines"
                 ],
-                "summary": "Добавить запись в журнал оценок",
+                "summary": "Получить дисциплину по ID",
                 "parameters": [
                     {
-                        "description": "Запись",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
-                        }
+                        "type": "integer",
+                        "description": "ID дисциплины",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
+                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/gradejournals/average": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1056,50 +1547,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "gradejournals"
+                    "disciplines"
                 ],
-                "summary": "Получить средний балл",
+                "summary": "Обновить дисциплину",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID студента",
-                        "name": "student_id",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
                         "description": "ID дисциплины",
-                        "name": "discipline_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "С даты (YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query"
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "По дату (YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                        "description": "Дисциплина",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "number"
-                            }
+                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Discipline"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/gradejournals/public": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1112,37 +1596,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "gradejournals"
+                    "disciplines"
                 ],
-                "summary": "Получить список публичных оценок",
+                "summary": "Удалить дисциплину",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID студента",
-                        "name": "student_id",
-                        "in": "query"
+                        "description": "ID дисциплины",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
+                    "409": {
+                        "description": "На дисциплину ссылаются оценки, посещаемость или учебный план",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/disciplines/{id}/curriculum": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Без semester_id берётся текущий семестр",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "curriculums"
+                ],
+                "summary": "Учебный план дисциплины на семестр",
+                "parameters": [
                     {
                         "type": "integer",
                         "description": "ID дисциплины",
-                        "name": "discipline_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "С даты (YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query"
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "По дату (YYYY-MM-DD)",
-                        "name": "to_date",
+                        "type": "integer",
+                        "description": "ID семестра (по умолчанию текущий)",
+                        "name": "semester_id",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -1159,34 +1672,50 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.GradeJournalPublic"
+                                "$ref": "#/definitions/service_internal_domain_models.Curriculum"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/gradejournals/{id}": {
+        "/api/v1/disciplines/{id}/student-count": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Количество студентов в группе, у которой ведётся дисциплина",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "gradejournals"
+                    "disciplines"
                 ],
-                "summary": "Получить запись журнала по ID",
+                "summary": "Число студентов дисциплины",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID записи",
+                        "description": "ID дисциплины",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1196,12 +1725,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
+                            "$ref": "#/definitions/service_internal_domain_models.DisciplineStudentCount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/gradejournals": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1211,45 +1760,122 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
                     "gradejournals"
                 ],
-                "summary": "Обновить запись в журнале",
+                "summary": "Получить список оценок с фильтрацией",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID записей через запятую (1,2,3), остальные фильтры игнорируются; порядок сохраняется, отсутствующие пропускаются",
+                        "name": "ids",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID записи",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ID студента",
+                        "name": "student_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Запись",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
-                        }
+                        "type": "string",
+                        "description": "ID студентов через запятую (1,2,3)",
+                        "name": "student_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID дисциплины",
+                        "name": "discipline_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату (YYYY-MM-DD)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменённые начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (игнорируется при after_id)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Keyset-пагинация: записи с id больше after_id, для выгрузок и глубоких страниц",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-After-Id": {
+                                "type": "integer",
+                                "description": "after_id следующей страницы в keyset-режиме"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Доступно преподавателю дисциплины или пользователю с правом gradejournal:manage_any",
                 "consumes": [
                     "application/json"
                 ],
@@ -1259,28 +1885,47 @@ const docTemplate = `{
                 "tags": [
                     "gradejournals"
                 ],
-                "summary": "Удалить запись из журнала",
+                "summary": "Добавить запись в журнал оценок",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID записи",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Запись",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.CreateGradeJournalRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/login": {
-            "post": {
+        "/api/v1/gradejournals/average": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -1288,28 +1933,82 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "gradejournals"
                 ],
-                "summary": "Логин пользователя",
+                "summary": "Получить средний балл",
                 "parameters": [
                     {
-                        "description": "Email и пароль",
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "student_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID дисциплины",
+                        "name": "discipline_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату (YYYY-MM-DD)",
+                        "name": "to_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "number"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/gradejournals/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет записи одной транзакцией и пишет в аудит одну сводную запись. Отсутствующие id пропускаются.\nБез права gradejournal:manage_any все записи должны относиться к дисциплинам текущего преподавателя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gradejournals"
+                ],
+                "summary": "Удалить записи журнала пакетом",
+                "parameters": [
+                    {
+                        "description": "ID записей (не более max_batch_size, по умолчанию 1000)",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.LoginRequest"
+                            "$ref": "#/definitions/service_internal_domain_models.GradeJournalBulkDeleteRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "JWT Token",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/service_internal_domain_models.GradeJournalBulkDeleteResponse"
                         }
                     },
                     "400": {
@@ -1318,8 +2017,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
@@ -1333,7 +2038,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/permissions": {
+        "/api/v1/gradejournals/public": {
             "get": {
                 "security": [
                     {
@@ -1344,16 +2049,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "permissions"
+                    "gradejournals"
                 ],
-                "summary": "Получить список прав",
+                "summary": "Получить список публичных оценок",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "ID студента",
+                        "name": "student_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID студентов через запятую (1,2,3)",
+                        "name": "student_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID дисциплины",
+                        "name": "discipline_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату (YYYY-MM-DD)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменённые начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -1362,6 +2104,12 @@ const docTemplate = `{
                         "description": "Смещение",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1370,19 +2118,21 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.Permission"
+                                "$ref": "#/definitions/service_internal_domain_models.GradeJournalPublic"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
                             }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/gradejournals/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1395,49 +2145,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "gradejournals"
                 ],
-                "summary": "Создать право",
+                "summary": "Получить запись журнала по ID",
                 "parameters": [
                     {
-                        "description": "Право",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Permission"
-                        }
+                        "type": "integer",
+                        "description": "ID записи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Permission"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/permissions/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Полная замена: непереданный comment очищается, для частичного обновления — PATCH.\nДоступно преподавателю дисциплины или пользователю с правом gradejournal:manage_any",
                 "consumes": [
                     "application/json"
                 ],
@@ -1445,27 +2180,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "gradejournals"
                 ],
-                "summary": "Получить право по ID",
+                "summary": "Обновить запись в журнале",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID права",
+                        "description": "ID записи",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Запись",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Permission"
+                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
@@ -1475,21 +2219,16 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Доступно преподавателю дисциплины или пользователю с правом gradejournal:manage_any",
                 "consumes": [
                     "application/json"
                 ],
@@ -1497,36 +2236,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "gradejournals"
                 ],
-                "summary": "Обновить право",
+                "summary": "Удалить запись из журнала",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID права",
+                        "description": "ID записи",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Право",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Permission"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Permission"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
@@ -1536,21 +2266,16 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
                     }
                 }
             },
-            "delete": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Обновляются только переданные поля, \"comment\": null очищает комментарий.\nДоступно преподавателю дисциплины или пользователю с правом gradejournal:manage_any",
                 "consumes": [
                     "application/json"
                 ],
@@ -1558,23 +2283,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "gradejournals"
                 ],
-                "summary": "Удалить право",
+                "summary": "Частично обновить запись в журнале",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID права",
+                        "description": "ID записи",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.GradeJournalPatch"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
                         }
                     },
                     "400": {
@@ -1583,6 +2317,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1598,7 +2338,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/register": {
+        "/api/v1/login": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -1609,15 +2349,15 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Логин пользователя",
                 "parameters": [
                     {
-                        "description": "Данные для регистрации",
+                        "description": "Email и пароль",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.RegisterRequest"
+                            "$ref": "#/definitions/service_internal_domain_models.LoginRequest"
                         }
                     }
                 ],
@@ -1637,9 +2377,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
@@ -1652,40 +2398,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/role-permissions/assign": {
+        "/api/v1/logout": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Отзывает текущий токен до истечения его срока действия",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "role-permissions"
-                ],
-                "summary": "Назначить право роли",
-                "parameters": [
-                    {
-                        "description": "Роль и право",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_http-server_handler_v1.assignPermissionInput"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "Выход пользователя",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
+                    "204": {
+                        "description": ""
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1702,43 +2432,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/role-permissions/remove": {
-            "post": {
+        "/api/v1/me": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "role-permissions"
-                ],
-                "summary": "Удалить право у роли",
-                "parameters": [
-                    {
-                        "description": "Роль и право",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_http-server_handler_v1.assignPermissionInput"
-                        }
-                    }
+                    "me"
                 ],
+                "summary": "Получить профиль текущего пользователя",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                            "$ref": "#/definitions/service_internal_domain_models.Me"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
@@ -1752,7 +2468,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/role-permissions/{id}": {
+        "/api/v1/permissions": {
             "get": {
                 "security": [
                     {
@@ -1766,16 +2482,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "role-permissions"
+                    "permissions"
                 ],
-                "summary": "Получить список прав роли",
+                "summary": "Получить список прав",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID роли",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1786,16 +2513,58 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/service_internal_domain_models.Permission"
                             }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Создать право",
+                "parameters": [
+                    {
+                        "description": "Право",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Permission"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Permission"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
@@ -1809,30 +2578,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/roles": {
+        "/api/v1/permissions/catalog": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Имена прав, сгруппированные по ресурсу: {\"gradejournal\": [\"create\", \"view\", ...]}",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "permissions"
                 ],
-                "summary": "Получить список ролей",
+                "summary": "Каталог прав по ресурсам",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.Role"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "type": "string"
+                                }
                             }
                         }
                     },
@@ -1843,13 +2613,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/permissions/rename": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Имя меняется на месте, поэтому назначения ролям сохраняются. \"resource:*\" переименовывает все права ресурса",
                 "consumes": [
                     "application/json"
                 ],
@@ -1857,25 +2630,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "permissions"
                 ],
-                "summary": "Создать роль",
+                "summary": "Переименовать права",
                 "parameters": [
                     {
-                        "description": "Роль",
+                        "description": "Старое и новое имя",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Role"
+                            "$ref": "#/definitions/service_internal_domain_models.PermissionRenameRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Role"
+                            "$ref": "#/definitions/service_internal_domain_models.PermissionRenameResponse"
                         }
                     },
                     "400": {
@@ -1884,6 +2657,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1893,7 +2678,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/roles/{id}": {
+        "/api/v1/permissions/{id}": {
             "get": {
                 "security": [
                     {
@@ -1907,13 +2692,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "permissions"
                 ],
-                "summary": "Получить роль по ID",
+                "summary": "Получить право по ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID роли",
+                        "description": "ID права",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1923,7 +2708,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Role"
+                            "$ref": "#/definitions/service_internal_domain_models.Permission"
                         }
                     },
                     "400": {
@@ -1959,24 +2744,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "permissions"
                 ],
-                "summary": "Обновить роль",
+                "summary": "Обновить право",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID роли",
+                        "description": "ID права",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Роль",
+                        "description": "Право",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Role"
+                            "$ref": "#/definitions/service_internal_domain_models.Permission"
                         }
                     }
                 ],
@@ -1984,7 +2769,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Role"
+                            "$ref": "#/definitions/service_internal_domain_models.Permission"
                         }
                     },
                     "400": {
@@ -2020,13 +2805,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "permissions"
                 ],
-                "summary": "Удалить роль",
+                "summary": "Удалить право",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID роли",
+                        "description": "ID права",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2060,13 +2845,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/semesters": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
+        "/api/v1/register": {
+            "post": {
+                "description": "Пользователю назначается роль default_role из конфига, если она задана и существует",
                 "consumes": [
                     "application/json"
                 ],
@@ -2074,92 +2855,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "semesters"
+                    "auth"
                 ],
-                "summary": "Получить список семестров с фильтрацией",
+                "summary": "Регистрация пользователя",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID учебного года",
-                        "name": "academic_year_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "С даты (YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "По дату (YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Ограничение",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Смещение",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Данные для регистрации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "JWT Token",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.Semester"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "semesters"
-                ],
-                "summary": "Создать семестр",
-                "parameters": [
-                    {
-                        "description": "Семестр",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Semester"
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Semester"
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/semesters/{id}": {
-            "get": {
+        "/api/v1/role-permissions/assign": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2172,28 +2914,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "semesters"
+                    "role-permissions"
                 ],
-                "summary": "Получить семестр по ID",
+                "summary": "Назначить право роли",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID семестра",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Роль и право",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_http-server_handler_v1.assignPermissionInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Semester"
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/role-permissions/remove": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2206,24 +2964,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "semesters"
+                    "role-permissions"
                 ],
-                "summary": "Обновить семестр",
+                "summary": "Удалить право у роли",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID семестра",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Семестр",
+                        "description": "Роль и право",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Semester"
+                            "$ref": "#/definitions/internal_http-server_handler_v1.assignPermissionInput"
                         }
                     }
                 ],
@@ -2231,12 +2982,26 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Semester"
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/role-permissions/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2249,29 +3014,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "semesters"
+                    "role-permissions"
                 ],
-                "summary": "Удалить семестр",
+                "summary": "Получить список прав роли",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID семестра",
+                        "description": "ID роли",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.Permission"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/student-groups": {
+        "/api/v1/roles": {
             "get": {
                 "security": [
                     {
@@ -2285,44 +3071,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student-groups"
-                ],
-                "summary": "Получить список групп студентов",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID куратора",
-                        "name": "curator_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID учебного года",
-                        "name": "academic_year_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Ограничение",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Смещение",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "roles"
                 ],
+                "summary": "Получить список ролей",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                                "$ref": "#/definitions/service_internal_domain_models.Role"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
             },
@@ -2339,17 +3105,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student-groups"
+                    "roles"
                 ],
-                "summary": "Создать группу студентов",
+                "summary": "Создать роль",
                 "parameters": [
                     {
-                        "description": "Группа",
+                        "description": "Роль",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                            "$ref": "#/definitions/service_internal_domain_models.Role"
                         }
                     }
                 ],
@@ -2357,7 +3123,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                            "$ref": "#/definitions/service_internal_domain_models.Role"
                         }
                     },
                     "400": {
@@ -2375,51 +3141,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/student-groups/public": {
+        "/api/v1/roles/with-counts": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "student-groups"
-                ],
-                "summary": "Получить список публичных групп",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Ограничение",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Смещение",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "roles"
                 ],
+                "summary": "Получить список ролей с числом пользователей",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.StudentGroupPublic"
+                                "$ref": "#/definitions/service_internal_domain_models.RoleWithCount"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/student-groups/public/{id}": {
+        "/api/v1/roles/{id}": {
             "get": {
                 "security": [
                     {
@@ -2433,13 +3188,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student-groups"
+                    "roles"
                 ],
-                "summary": "Получить публичную группу по ID",
+                "summary": "Получить роль по ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID группы",
+                        "description": "ID роли",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2449,7 +3204,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.StudentGroupPublic"
+                            "$ref": "#/definitions/service_internal_domain_models.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
                     "404": {
@@ -2457,12 +3218,16 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
-            }
-        },
-        "/api/v1/student-groups/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2475,64 +3240,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student-groups"
+                    "roles"
                 ],
-                "summary": "Получить группу по ID",
+                "summary": "Обновить роль",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID группы",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "student-groups"
-                ],
-                "summary": "Обновить группу студентов",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID группы",
+                        "description": "ID роли",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Группа",
+                        "description": "Роль",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                            "$ref": "#/definitions/service_internal_domain_models.Role"
                         }
                     }
                 ],
@@ -2540,7 +3265,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                            "$ref": "#/definitions/service_internal_domain_models.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
                     "404": {
@@ -2548,6 +3279,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
             },
@@ -2564,13 +3301,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student-groups"
+                    "roles"
                 ],
-                "summary": "Удалить группу студентов",
+                "summary": "Удалить роль",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID группы",
+                        "description": "ID роли",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2583,16 +3320,28 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/students": {
+        "/api/v1/semesters": {
             "get": {
                 "security": [
                     {
@@ -2606,13 +3355,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "students"
+                    "semesters"
                 ],
-                "summary": "Получить список студентов",
+                "summary": "Получить список семестров с фильтрацией",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "ID учебного года",
+                        "name": "academic_year_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату (YYYY-MM-DD)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -2621,6 +3388,12 @@ const docTemplate = `{
                         "description": "Смещение",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2629,14 +3402,14 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.Student"
+                                "$ref": "#/definitions/service_internal_domain_models.Semester"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
                             }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
@@ -2654,17 +3427,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "students"
+                    "semesters"
                 ],
-                "summary": "Создать студента",
+                "summary": "Создать семестр",
                 "parameters": [
                     {
-                        "description": "Студент",
+                        "description": "Семестр",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Student"
+                            "$ref": "#/definitions/service_internal_domain_models.Semester"
                         }
                     }
                 ],
@@ -2672,25 +3445,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Student"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                            "$ref": "#/definitions/service_internal_domain_models.Semester"
                         }
                     }
                 }
             }
         },
-        "/api/v1/students/public": {
+        "/api/v1/semesters/{id}": {
             "get": {
                 "security": [
                     {
@@ -2704,38 +3465,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "students"
+                    "semesters"
                 ],
-                "summary": "Получить публичный список студентов",
+                "summary": "Получить семестр по ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ограничение",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Смещение",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "ID семестра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.StudentPublic"
-                            }
+                            "$ref": "#/definitions/service_internal_domain_models.Semester"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/students/public/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2748,36 +3499,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "students"
+                    "semesters"
                 ],
-                "summary": "Получить публичного студента по ID",
+                "summary": "Обновить семестр",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID студента",
+                        "description": "ID семестра",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Семестр",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Semester"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.StudentPublic"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                            "$ref": "#/definitions/service_internal_domain_models.Semester"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/students/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2790,35 +3542,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "students"
+                    "semesters"
                 ],
-                "summary": "Получить студента по ID",
+                "summary": "Удалить семестр",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID студента",
+                        "description": "ID семестра",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Student"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                            "type": "string"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    }
+                }
+            }
+        },
+        "/api/v1/stats/overview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Получить количество записей по сущностям",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "500": {
@@ -2828,8 +3595,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/student-groups": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -2842,128 +3611,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "students"
+                    "student-groups"
                 ],
-                "summary": "Обновить данные студента",
+                "summary": "Получить список групп студентов",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID студента",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ID куратора",
+                        "name": "curator_id",
+                        "in": "query"
                     },
-                    {
-                        "description": "Студент",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Student"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Student"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "students"
-                ],
-                "summary": "Удалить студента",
-                "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID студента",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
+                        "description": "ID учебного года",
+                        "name": "academic_year_id",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/teacher": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "teachers"
-                ],
-                "summary": "Получить список преподавателей",
-                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -2972,6 +3638,12 @@ const docTemplate = `{
                         "description": "Смещение",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2980,7 +3652,13 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                                "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
                             }
                         }
                     }
@@ -2999,17 +3677,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "student-groups"
                 ],
-                "summary": "Создать преподавателя",
+                "summary": "Создать группу студентов",
                 "parameters": [
                     {
-                        "description": "Преподаватель",
+                        "description": "Группа",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
                         }
                     }
                 ],
@@ -3017,44 +3695,32 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/teacher/me": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "teachers"
-                ],
-                "summary": "Получить свой профиль преподавателя",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/student-groups/merge": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Переводит всех студентов из from_group_id в to_group_id одной транзакцией, при delete_source удаляет исходную группу. Нужно быть куратором обеих групп или иметь studentgroup:manage_any",
                 "consumes": [
                     "application/json"
                 ],
@@ -3062,17 +3728,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "student-groups"
                 ],
-                "summary": "Обновить свой профиль преподавателя",
+                "summary": "Объединить группы",
                 "parameters": [
                     {
-                        "description": "Преподаватель",
+                        "description": "Исходная и целевая группа",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentGroupMergeRequest"
                         }
                     }
                 ],
@@ -3080,13 +3746,43 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentGroupMergeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/teacher/public": {
+        "/api/v1/student-groups/public": {
             "get": {
                 "security": [
                     {
@@ -3100,13 +3796,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "student-groups"
                 ],
-                "summary": "Получить публичный список преподавателей",
+                "summary": "Получить список публичных групп",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -3115,6 +3811,12 @@ const docTemplate = `{
                         "description": "Смещение",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3123,14 +3825,20 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.TeacherPublic"
+                                "$ref": "#/definitions/service_internal_domain_models.StudentGroupPublic"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
                             }
                         }
                     }
                 }
             }
         },
-        "/api/v1/teacher/public/{id}": {
+        "/api/v1/student-groups/public/{id}": {
             "get": {
                 "security": [
                     {
@@ -3144,13 +3852,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "student-groups"
                 ],
-                "summary": "Получить публичный профиль преподавателя по ID",
+                "summary": "Получить публичную группу по ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID преподавателя",
+                        "description": "ID группы",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3160,13 +3868,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.TeacherPublic"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentGroupPublic"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/teacher/{id}": {
+        "/api/v1/student-groups/{id}": {
             "get": {
                 "security": [
                     {
@@ -3180,13 +3894,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "student-groups"
                 ],
-                "summary": "Получить преподавателя по ID",
+                "summary": "Получить группу по ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID преподавателя",
+                        "description": "ID группы",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3196,7 +3910,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
@@ -3207,6 +3927,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Доступно куратору группы или пользователю с правом studentgroup:manage_any",
                 "consumes": [
                     "application/json"
                 ],
@@ -3214,24 +3935,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "student-groups"
                 ],
-                "summary": "Обновить преподавателя по ID",
+                "summary": "Обновить группу студентов",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID преподавателя",
+                        "description": "ID группы",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Преподаватель",
+                        "description": "Группа",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
                         }
                     }
                 ],
@@ -3239,7 +3960,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
@@ -3250,6 +3983,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Доступно куратору группы или пользователю с правом studentgroup:manage_any",
                 "consumes": [
                     "application/json"
                 ],
@@ -3257,13 +3991,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "student-groups"
                 ],
-                "summary": "Удалить преподавателя по ID",
+                "summary": "Удалить группу студентов",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID преподавателя",
+                        "description": "ID группы",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3275,53 +4009,15 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/user-roles/assign": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user-roles"
-                ],
-                "summary": "Назначить роль пользователю",
-                "parameters": [
-                    {
-                        "description": "Пользователь и роль",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_http-server_handler_v1.assignRoleInput"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
@@ -3329,39 +4025,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user-roles/remove": {
-            "post": {
+        "/api/v1/student-groups/{id}/ranking": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Студенты группы по убыванию среднего балла за период. При равном среднем место общее (1, 1, 3), студенты без оценок идут в конце с rank = null",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user-roles"
+                    "student-groups"
                 ],
-                "summary": "Удалить роль у пользователя",
+                "summary": "Рейтинг студентов группы",
                 "parameters": [
                     {
-                        "description": "Пользователь и роль",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_http-server_handler_v1.assignRoleInput"
-                        }
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату включительно (YYYY-MM-DD)",
+                        "name": "to_date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.GroupRankingEntry"
+                            }
                         }
                     },
                     "400": {
@@ -3370,6 +4077,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3379,27 +4092,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user-roles/{id}": {
+        "/api/v1/student-groups/{id}/students.csv": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "user-roles"
+                    "student-groups"
                 ],
-                "summary": "Получить роли пользователя",
+                "summary": "Выгрузить список студентов группы в CSV",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID пользователя",
+                        "description": "ID группы",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3407,12 +4117,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "CSV",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.UserRole"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -3436,7 +4143,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users": {
+        "/api/v1/students": {
             "get": {
                 "security": [
                     {
@@ -3450,13 +4157,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "students"
                 ],
-                "summary": "Получить список пользователей",
+                "summary": "Получить список студентов",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ограничение",
+                        "description": "ID группы",
+                        "name": "student_group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату (YYYY-MM-DD)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменённые начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -3473,7 +4204,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/service_internal_domain_models.User"
+                                "$ref": "#/definitions/service_internal_domain_models.Student"
                             }
                         }
                     },
@@ -3498,25 +4229,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "students"
                 ],
-                "summary": "Создать пользователя",
+                "summary": "Создать студента",
                 "parameters": [
                     {
-                        "description": "Пользователь",
+                        "description": "Студент",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.User"
+                            "$ref": "#/definitions/service_internal_domain_models.Student"
                         }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ожидаемый учебный год группы (по умолчанию текущий)",
+                        "name": "academic_year_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.User"
+                            "$ref": "#/definitions/service_internal_domain_models.Student"
                         }
                     },
                     "400": {
@@ -3525,6 +4262,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3534,29 +4277,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/{id}": {
-            "get": {
+        "/api/v1/students/import": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Колонки: first_name, last_name, middle_name (необяз.), email, password (необяз.), phone, birthday (YYYY-MM-DD), student_group_id.\nКаждая строка создаётся в отдельной транзакции, существующие email пропускаются.\nСтрок данных не больше max_batch_size, иначе 413 и ничего не создаётся.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "students"
                 ],
-                "summary": "Получить пользователя по ID",
+                "summary": "Импорт студентов из CSV",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID пользователя",
-                        "name": "id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "CSV файл",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     }
                 ],
@@ -3564,7 +4308,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.User"
+                            "$ref": "#/definitions/service_internal_domain_models.StudentImportReport"
                         }
                     },
                     "400": {
@@ -3573,69 +4317,55 @@ const docTemplate = `{
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/students/me/disciplines": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "students"
                 ],
-                "summary": "Обновить пользователя",
+                "summary": "Дисциплины группы текущего студента",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID пользователя",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
-                        "description": "Пользователь",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.User"
-                        }
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service_internal_domain_models.User"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.DisciplinePublic"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/service_internal_lib_api_response.Response"
                         }
@@ -3647,8 +4377,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/students/public": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -3661,36 +4393,283 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "students"
                 ],
-                "summary": "Удалить пользователя",
+                "summary": "Получить публичный список студентов",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID пользователя",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
+                        "description": "ID группы",
+                        "name": "student_group_id",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
-                        }
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату (YYYY-MM-DD)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменённые начиная с (RFC3339 или YYYY-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.StudentPublic"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/public/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "students"
+                ],
+                "summary": "Получить публичного студента по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.StudentPublic"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "students"
+                ],
+                "summary": "Получить студента по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дополнительные поля (user)",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.StudentWithUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "students"
+                ],
+                "summary": "Обновить данные студента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Студент",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Student"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ожидаемый учебный год группы (по умолчанию текущий)",
+                        "name": "academic_year_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Student"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "students"
+                ],
+                "summary": "Удалить студента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "У студента есть оценки или посещаемость",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -3699,100 +4678,2707 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляются только переданные поля",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "students"
+                ],
+                "summary": "Частично обновить данные студента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.StudentPatch"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ожидаемый учебный год группы (по умолчанию текущий)",
+                        "name": "academic_year_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Student"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{id}/at-risk": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Дисциплины, в которых средний балл студента строго ниже порога",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gradejournals"
+                ],
+                "summary": "Дисциплины, где студент в зоне риска",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Порог среднего балла (по умолчанию 3)",
+                        "name": "threshold",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.DisciplineAverage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{id}/attendance-summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendances"
+                ],
+                "summary": "Сводка посещаемости студента за семестр",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID семестра",
+                        "name": "semester_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.AttendanceSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{id}/grade-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit-logs"
+                ],
+                "summary": "История изменений оценок студента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.AuditLogWithUser"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/{id}/grades": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gradejournals"
+                ],
+                "summary": "Оценки студента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID студента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID дисциплины",
+                        "name": "discipline_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.GradeJournal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teacher": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Получить список преподавателей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Создать преподавателя",
+                "parameters": [
+                    {
+                        "description": "Преподаватель",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teacher/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Получить свой профиль преподавателя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Обновить свой профиль преподавателя",
+                "parameters": [
+                    {
+                        "description": "Преподаватель",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teacher/me/students": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Студенты всех групп, где преподаватель ведёт дисциплины, без повторов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Студенты текущего преподавателя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.StudentWithUser"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teacher/me/workload": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Нагрузка текущего преподавателя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.TeacherWorkload"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teacher/public": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пользователи с правом teacher:view дополнительно получают телефон",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Получить публичный список преподавателей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.TeacherPublicWithPhone"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teacher/public/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пользователи с правом teacher:view дополнительно получают телефон",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Получить публичный профиль преподавателя по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID преподавателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.TeacherPublicWithPhone"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teacher/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Получить преподавателя по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID преподавателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Полная замена: непереданные working_experience и education очищаются, для частичного обновления — PATCH",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Обновить преподавателя по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID преподавателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Преподаватель",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Удалить преподавателя по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID преподавателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляются только переданные поля, null в working_experience или education очищает значение",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Частично обновить преподавателя по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID преподавателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.TeacherPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Teacher"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teacher/{id}/workload": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Число дисциплин, различных групп и студентов в них",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Нагрузка преподавателя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID преподавателя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.TeacherWorkload"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user-roles/assign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-roles"
+                ],
+                "summary": "Назначить роль пользователю",
+                "parameters": [
+                    {
+                        "description": "Пользователь и роль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_http-server_handler_v1.assignRoleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user-roles/remove": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-roles"
+                ],
+                "summary": "Удалить роль у пользователя",
+                "parameters": [
+                    {
+                        "description": "Пользователь и роль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_http-server_handler_v1.assignRoleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user-roles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-roles"
+                ],
+                "summary": "Получить роли пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.UserRole"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить список пользователей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только активные (true) или деактивированные (false)",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее число записей в X-Total-Count",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.User"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Общее число записей под фильтром"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Создать пользователя",
+                "parameters": [
+                    {
+                        "description": "Пользователь",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить пользователя по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Обновить пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Пользователь",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.UserUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удалить пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляются только переданные поля, \"middle_name\": null очищает отчество",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Частично обновить пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.UserPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/activate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Активировать пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/deactivate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пользователь остаётся в БД (оценки и аудит сохраняются), но не может войти, а выданные ему токены перестают приниматься",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Деактивировать пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/password-reset": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если пароль не передан, генерируется случайный и возвращается один раз в ответе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Сбросить пароль пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый пароль",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.PasswordResetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Данные пользователя с блоком student или teacher и ролями. Если пользователь не студент и не преподаватель, type = user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Полный профиль пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.UserProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Получить список вебхуков",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service_internal_domain_models.Webhook"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Создать вебхук",
+                "parameters": [
+                    {
+                        "description": "Вебхук",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Получить вебхук по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Webhook"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Обновить вебхук",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Вебхук",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_domain_models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/service_internal_lib_api_response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Удалить вебхук",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "internal_http-server_handler_v1.assignPermissionInput": {
+            "type": "object",
+            "properties": {
+                "permission_id": {
+                    "type": "integer"
+                },
+                "role_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_http-server_handler_v1.assignRoleInput": {
+            "type": "object",
+            "properties": {
+                "role_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_http-server_handler_v1.disciplineCreateResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "discipline_id": {
+                    "type": "integer"
+                },
+                "discipline_name": {
+                    "type": "string"
+                },
+                "student_group_id": {
+                    "type": "integer"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "service_internal_domain_models.AcademicYear": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_with": {
+                    "type": "string"
+                },
+                "name_academic_year": {
+                    "type": "string"
+                },
+                "start_with": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.AcademicYearCloneRequest": {
+            "type": "object",
+            "properties": {
+                "ends_with": {
+                    "type": "string"
+                },
+                "name_academic_year": {
+                    "type": "string",
+                    "example": "2025/2026"
+                },
+                "start_with": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.AcademicYearCloneResponse": {
+            "type": "object",
+            "properties": {
+                "academic_year": {
+                    "$ref": "#/definitions/service_internal_domain_models.AcademicYear"
+                },
+                "semesters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service_internal_domain_models.Semester"
+                    }
+                },
+                "student_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service_internal_domain_models.StudentGroup"
+                    }
+                }
+            }
+        },
+        "service_internal_domain_models.Attendance": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "integer"
+                },
+                "class_date": {
+                    "type": "string",
+                    "example": "2025-09-01T00:00:00Z"
+                },
+                "comment": {
+                    "type": "string",
+                    "example": "Опоздал на 10 минут"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discipline_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "student_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visit": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "service_internal_domain_models.AttendanceDisciplineSummary": {
+            "type": "object",
+            "properties": {
+                "absent": {
+                    "type": "integer"
+                },
+                "discipline_id": {
+                    "type": "integer"
+                },
+                "discipline_name": {
+                    "type": "string"
+                },
+                "present": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.AttendanceLowRate": {
+            "type": "object",
+            "properties": {
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "present": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 0.6
+                },
+                "student_group_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.AttendanceSummary": {
+            "type": "object",
+            "properties": {
+                "absent": {
+                    "type": "integer"
+                },
+                "disciplines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service_internal_domain_models.AttendanceDisciplineSummary"
+                    }
+                },
+                "ends_with": {
+                    "type": "string"
+                },
+                "present": {
+                    "type": "integer"
+                },
+                "semester_id": {
+                    "type": "integer"
+                },
+                "start_with": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action_type": {
+                    "type": "string"
+                },
+                "audit_id": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "new_data": {
+                    "type": "string"
+                },
+                "old_data": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID id HTTP-запроса, в котором сделано изменение, для связи с логами сервера",
+                    "type": "string"
+                },
+                "row_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "description": "StudentID студент, к которому относится запись журнала оценок, для истории оценок",
+                    "type": "integer"
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
+                }
+            }
+        },
+        "service_internal_domain_models.AuditLogWithUser": {
+            "type": "object",
+            "properties": {
+                "action_type": {
+                    "type": "string"
+                },
+                "audit_id": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "new_data": {
+                    "type": "string"
+                },
+                "old_data": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID id HTTP-запроса, в котором сделано изменение, для связи с логами сервера",
+                    "type": "string"
+                },
+                "row_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "description": "StudentID студент, к которому относится запись журнала оценок, для истории оценок",
+                    "type": "integer"
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "user_first_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "user_last_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.CreateAttendanceRequest": {
+            "type": "object",
+            "properties": {
+                "class_date": {
+                    "type": "string",
+                    "example": "2025-09-01T00:00:00Z"
+                },
+                "comment": {
+                    "type": "string",
+                    "example": "Опоздал на 10 минут"
+                },
+                "discipline_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "student_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "visit": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "service_internal_domain_models.CreateGradeJournalRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Контрольная работа"
+                },
+                "discipline_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "grade": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "student_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "service_internal_domain_models.Curriculum": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "curriculum_id": {
+                    "type": "integer"
+                },
+                "discipline_id": {
+                    "type": "integer"
+                },
+                "semester_id": {
+                    "type": "integer"
+                },
+                "subject_description": {
+                    "type": "string"
+                },
+                "subject_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.CurriculumCopyRequest": {
+            "type": "object",
+            "properties": {
+                "target_semester_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.DashboardMetrics": {
+            "type": "object",
+            "properties": {
+                "disciplines": {
+                    "type": "integer"
+                },
+                "grades_this_week": {
+                    "type": "integer"
+                },
+                "student_groups": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                },
+                "teachers": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.Discipline": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "discipline_id": {
+                    "type": "integer"
+                },
+                "discipline_name": {
+                    "type": "string"
+                },
+                "student_group_id": {
+                    "type": "integer"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.DisciplineAverage": {
+            "type": "object",
+            "properties": {
+                "average_grade": {
+                    "type": "number"
+                },
+                "discipline_id": {
+                    "type": "integer"
+                },
+                "discipline_name": {
+                    "type": "string"
+                },
+                "grades_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.DisciplinePublic": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "curator_first_name": {
+                    "type": "string"
+                },
+                "curator_id": {
+                    "type": "integer"
+                },
+                "curator_last_name": {
+                    "type": "string"
+                },
+                "curator_middle_name": {
+                    "type": "string"
+                },
+                "discipline_id": {
+                    "type": "integer"
+                },
+                "discipline_name": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "student_group_id": {
+                    "type": "integer"
+                },
+                "student_group_name": {
+                    "type": "string"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.DisciplineReassignRequest": {
+            "type": "object",
+            "properties": {
+                "from_teacher_id": {
+                    "type": "integer"
+                },
+                "to_teacher_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.DisciplineReassignResponse": {
+            "type": "object",
+            "properties": {
+                "discipline_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "reassigned": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.DisciplineStudentCount": {
+            "type": "object",
+            "properties": {
+                "discipline_id": {
+                    "type": "integer"
+                },
+                "student_count": {
+                    "type": "integer"
+                },
+                "student_group_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.GradeJournal": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Контрольная работа"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discipline_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "grade": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "grade_journal_id": {
+                    "type": "string",
+                    "example": "1001"
+                },
+                "student_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.GradeJournalBulkDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        101,
+                        102,
+                        103
+                    ]
+                }
+            }
+        },
+        "service_internal_domain_models.GradeJournalBulkDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                },
+                "ids": {
+                    "description": "IDs фактически удалённые записи, отсутствующие id пропускаются",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "service_internal_domain_models.GradeJournalPatch": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer",
+                    "example": 4
+                }
             }
-        }
-    },
-    "definitions": {
-        "internal_http-server_handler_v1.assignPermissionInput": {
+        },
+        "service_internal_domain_models.GradeJournalPublic": {
             "type": "object",
             "properties": {
-                "permission_id": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discipline_id": {
                     "type": "integer"
                 },
-                "role_id": {
+                "discipline_name": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "grade_journal_id": {
+                    "type": "string",
+                    "example": "1001"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "student_id": {
                     "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "internal_http-server_handler_v1.assignRoleInput": {
+        "service_internal_domain_models.GroupRankingEntry": {
             "type": "object",
             "properties": {
-                "role_id": {
+                "average_grade": {
+                    "type": "number",
+                    "example": 8.5
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "grades_count": {
                     "type": "integer"
                 },
-                "user_id": {
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "student_id": {
                     "type": "integer"
                 }
             }
         },
-        "service_internal_domain_models.AcademicYear": {
+        "service_internal_domain_models.LoginRequest": {
             "type": "object",
             "properties": {
-                "academic_year_id": {
-                    "type": "integer"
+                "email": {
+                    "type": "string",
+                    "example": "ivanov@example.com"
                 },
+                "password": {
+                    "type": "string",
+                    "example": "s3cretPass"
+                }
+            }
+        },
+        "service_internal_domain_models.Me": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "ends_with": {
+                "email": {
                     "type": "string"
                 },
-                "name_academic_year": {
+                "first_name": {
                     "type": "string"
                 },
-                "start_with": {
+                "last_name": {
                     "type": "string"
                 },
+                "middle_name": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service_internal_domain_models.UserRole"
+                    }
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
+                }
+            }
+        },
+        "service_internal_domain_models.PasswordResetRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
                 }
             }
         },
-        "service_internal_domain_models.Attendance": {
+        "service_internal_domain_models.PasswordResetResponse": {
             "type": "object",
             "properties": {
-                "attendance_id": {
-                    "type": "integer"
-                },
-                "comment": {
+                "password": {
+                    "description": "Password возвращается только если пароль был сгенерирован сервером",
                     "type": "string"
                 },
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
+                }
+            }
+        },
+        "service_internal_domain_models.Permission": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "discipline_id": {
+                "permission_id": {
                     "type": "integer"
                 },
-                "student_id": {
-                    "type": "integer"
+                "permission_name": {
+                    "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.PermissionRenameRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "studentgroup:*"
                 },
-                "visit": {
-                    "type": "boolean"
+                "to": {
+                    "type": "string",
+                    "example": "student_group:*"
                 }
             }
         },
-        "service_internal_domain_models.Curriculum": {
+        "service_internal_domain_models.PermissionRenameResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service_internal_domain_models.PermissionRenamed"
+                    }
                 },
-                "curriculum_id": {
+                "renamed": {
                     "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.PermissionRenamed": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
                 },
-                "discipline_id": {
+                "permission_id": {
                     "type": "integer"
                 },
-                "semester_id": {
-                    "type": "integer"
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "ivanov@example.com"
                 },
-                "subject_description": {
+                "first_name": {
+                    "type": "string",
+                    "example": "Иван"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Иванов"
+                },
+                "middle_name": {
+                    "type": "string",
+                    "example": "Иванович"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "s3cretPass"
+                }
+            }
+        },
+        "service_internal_domain_models.Role": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "subject_name": {
+                "role_id": {
+                    "type": "integer"
+                },
+                "role_name": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -3800,30 +7386,27 @@ const docTemplate = `{
                 }
             }
         },
-        "service_internal_domain_models.Discipline": {
+        "service_internal_domain_models.RoleWithCount": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "discipline_id": {
+                "role_id": {
                     "type": "integer"
                 },
-                "discipline_name": {
+                "role_name": {
                     "type": "string"
                 },
-                "student_group_id": {
-                    "type": "integer"
-                },
-                "teacher_id": {
-                    "type": "integer"
-                },
                 "updated_at": {
                     "type": "string"
+                },
+                "user_count": {
+                    "type": "integer"
                 }
             }
         },
-        "service_internal_domain_models.DisciplinePublic": {
+        "service_internal_domain_models.Semester": {
             "type": "object",
             "properties": {
                 "academic_year_id": {
@@ -3832,140 +7415,207 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
-                "curator_first_name": {
+                "ends_with": {
                     "type": "string"
                 },
-                "curator_id": {
+                "semester_id": {
                     "type": "integer"
                 },
-                "curator_last_name": {
+                "start_with": {
                     "type": "string"
                 },
-                "curator_middle_name": {
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.Student": {
+            "type": "object",
+            "properties": {
+                "birthday": {
                     "type": "string"
                 },
-                "discipline_id": {
-                    "type": "integer"
-                },
-                "discipline_name": {
+                "created_at": {
                     "type": "string"
                 },
-                "first_name": {
+                "phone": {
                     "type": "string"
                 },
-                "last_name": {
+                "student_group_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "middle_name": {
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
+                }
+            }
+        },
+        "service_internal_domain_models.StudentGroup": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
+                "curator_id": {
+                    "type": "integer"
+                },
                 "student_group_id": {
                     "type": "integer"
                 },
                 "student_group_name": {
                     "type": "string"
                 },
-                "teacher_id": {
-                    "type": "integer"
-                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "service_internal_domain_models.GradeJournal": {
+        "service_internal_domain_models.StudentGroupMergeRequest": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "type": "string"
+                "delete_source": {
+                    "description": "DeleteSource удалить опустевшую исходную группу (нужно право studentgroup:delete)",
+                    "type": "boolean"
                 },
-                "created_at": {
-                    "type": "string"
+                "from_group_id": {
+                    "type": "integer",
+                    "example": 3
                 },
-                "discipline_id": {
+                "to_group_id": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "service_internal_domain_models.StudentGroupMergeResponse": {
+            "type": "object",
+            "properties": {
+                "moved": {
                     "type": "integer"
                 },
-                "grade": {
+                "source_deleted": {
+                    "type": "boolean"
+                },
+                "student_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "service_internal_domain_models.StudentGroupPublic": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
                     "type": "integer"
                 },
-                "grade_journal_id": {
+                "curator_first_name": {
+                    "type": "string"
+                },
+                "curator_id": {
                     "type": "integer"
                 },
-                "student_id": {
+                "curator_last_name": {
+                    "type": "string"
+                },
+                "curator_middle_name": {
+                    "type": "string"
+                },
+                "student_group_id": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "student_group_name": {
                     "type": "string"
                 }
             }
         },
-        "service_internal_domain_models.GradeJournalPublic": {
+        "service_internal_domain_models.StudentImportReport": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+                "created": {
+                    "type": "integer"
                 },
-                "discipline_id": {
+                "failed": {
                     "type": "integer"
                 },
-                "discipline_name": {
-                    "type": "string"
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service_internal_domain_models.StudentImportRow"
+                    }
                 },
-                "first_name": {
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service_internal_domain_models.StudentImportRow": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string"
                 },
-                "grade": {
-                    "type": "integer"
+                "error": {
+                    "type": "string"
                 },
-                "grade_journal_id": {
+                "line": {
                     "type": "integer"
                 },
-                "last_name": {
+                "password": {
+                    "description": "Password возвращается только если пароль был сгенерирован сервером",
                     "type": "string"
                 },
-                "student_id": {
-                    "type": "integer"
-                },
-                "updated_at": {
+                "status": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
                 }
             }
         },
-        "service_internal_domain_models.LoginRequest": {
+        "service_internal_domain_models.StudentPatch": {
             "type": "object",
             "properties": {
-                "email": {
+                "birthday": {
                     "type": "string"
                 },
-                "password": {
+                "phone": {
                     "type": "string"
+                },
+                "student_group_id": {
+                    "type": "integer"
                 }
             }
         },
-        "service_internal_domain_models.Permission": {
+        "service_internal_domain_models.StudentProfile": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "birthday": {
                     "type": "string"
                 },
-                "permission_id": {
-                    "type": "integer"
-                },
-                "permission_name": {
+                "phone": {
                     "type": "string"
                 },
-                "updated_at": {
+                "student_group_id": {
+                    "type": "integer"
+                },
+                "student_group_name": {
                     "type": "string"
                 }
             }
         },
-        "service_internal_domain_models.RegisterRequest": {
+        "service_internal_domain_models.StudentPublic": {
             "type": "object",
             "properties": {
-                "email": {
+                "birthday": {
                     "type": "string"
                 },
                 "first_name": {
@@ -3977,127 +7627,131 @@ const docTemplate = `{
                 "middle_name": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
+                "student_group_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
                 }
             }
         },
-        "service_internal_domain_models.Role": {
+        "service_internal_domain_models.StudentWithUser": {
             "type": "object",
             "properties": {
+                "birthday": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "role_id": {
-                    "type": "integer"
+                "email": {
+                    "type": "string"
                 },
-                "role_name": {
+                "first_name": {
                     "type": "string"
                 },
-                "updated_at": {
+                "last_name": {
                     "type": "string"
-                }
-            }
-        },
-        "service_internal_domain_models.Semester": {
-            "type": "object",
-            "properties": {
-                "academic_year_id": {
-                    "type": "integer"
                 },
-                "created_at": {
+                "middle_name": {
                     "type": "string"
                 },
-                "ends_with": {
+                "phone": {
                     "type": "string"
                 },
-                "semester_id": {
+                "student_group_id": {
                     "type": "integer"
                 },
-                "start_with": {
-                    "type": "string"
-                },
                 "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
                 }
             }
         },
-        "service_internal_domain_models.Student": {
+        "service_internal_domain_models.Teacher": {
             "type": "object",
             "properties": {
-                "birthday": {
+                "created_at": {
                     "type": "string"
                 },
-                "created_at": {
+                "education": {
                     "type": "string"
                 },
                 "phone": {
                     "type": "string"
                 },
-                "student_group_id": {
-                    "type": "integer"
-                },
                 "updated_at": {
                     "type": "string"
                 },
                 "user_id": {
-                    "type": "integer"
+                    "type": "string",
+                    "example": "42"
+                },
+                "working_experience": {
+                    "type": "string"
                 }
             }
         },
-        "service_internal_domain_models.StudentGroup": {
+        "service_internal_domain_models.TeacherPatch": {
             "type": "object",
             "properties": {
-                "academic_year_id": {
-                    "type": "integer"
-                },
-                "created_at": {
+                "education": {
                     "type": "string"
                 },
-                "curator_id": {
-                    "type": "integer"
+                "phone": {
+                    "type": "string"
                 },
-                "student_group_id": {
-                    "type": "integer"
+                "working_experience": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.TeacherProfile": {
+            "type": "object",
+            "properties": {
+                "education": {
+                    "type": "string"
                 },
-                "student_group_name": {
+                "phone": {
                     "type": "string"
                 },
-                "updated_at": {
+                "working_experience": {
                     "type": "string"
                 }
             }
         },
-        "service_internal_domain_models.StudentGroupPublic": {
+        "service_internal_domain_models.TeacherPublic": {
             "type": "object",
             "properties": {
-                "academic_year_id": {
-                    "type": "integer"
-                },
-                "curator_first_name": {
+                "education": {
                     "type": "string"
                 },
-                "curator_id": {
-                    "type": "integer"
+                "first_name": {
+                    "type": "string"
                 },
-                "curator_last_name": {
+                "last_name": {
                     "type": "string"
                 },
-                "curator_middle_name": {
+                "middle_name": {
                     "type": "string"
                 },
-                "student_group_id": {
-                    "type": "integer"
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
                 },
-                "student_group_name": {
+                "working_experience": {
                     "type": "string"
                 }
             }
         },
-        "service_internal_domain_models.StudentPublic": {
+        "service_internal_domain_models.TeacherPublicWithPhone": {
             "type": "object",
             "properties": {
-                "birthday": {
+                "education": {
                     "type": "string"
                 },
                 "first_name": {
@@ -4109,41 +7763,76 @@ const docTemplate = `{
                 "middle_name": {
                     "type": "string"
                 },
-                "student_group_id": {
-                    "type": "integer"
+                "phone": {
+                    "type": "string"
                 },
                 "user_id": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "working_experience": {
+                    "type": "string"
+                }
+            }
+        },
+        "service_internal_domain_models.TeacherWorkload": {
+            "type": "object",
+            "properties": {
+                "disciplines": {
+                    "type": "integer"
+                },
+                "groups": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                },
+                "teacher_id": {
                     "type": "integer"
                 }
             }
         },
-        "service_internal_domain_models.Teacher": {
+        "service_internal_domain_models.User": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "education": {
+                "email": {
                     "type": "string"
                 },
-                "phone": {
+                "first_name": {
                     "type": "string"
                 },
-                "updated_at": {
+                "is_active": {
+                    "description": "IsActive false — пользователь деактивирован и не может войти",
+                    "type": "boolean"
+                },
+                "last_name": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "integer"
+                "middle_name": {
+                    "type": "string"
                 },
-                "working_experience": {
+                "password": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "42"
                 }
             }
         },
-        "service_internal_domain_models.TeacherPublic": {
+        "service_internal_domain_models.UserPatch": {
             "type": "object",
             "properties": {
-                "education": {
+                "email": {
                     "type": "string"
                 },
                 "first_name": {
@@ -4155,15 +7844,12 @@ const docTemplate = `{
                 "middle_name": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "integer"
-                },
-                "working_experience": {
+                "password": {
                     "type": "string"
                 }
             }
         },
-        "service_internal_domain_models.User": {
+        "service_internal_domain_models.UserProfile": {
             "type": "object",
             "properties": {
                 "created_at": {
@@ -4175,23 +7861,42 @@ const docTemplate = `{
                 "first_name": {
                     "type": "string"
                 },
+                "is_active": {
+                    "type": "boolean"
+                },
                 "last_name": {
                     "type": "string"
                 },
                 "middle_name": {
                     "type": "string"
                 },
-                "password": {
+                "roles": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/service_internal_domain_models.UserRole"
                     }
                 },
+                "student": {
+                    "$ref": "#/definitions/service_internal_domain_models.StudentProfile"
+                },
+                "teacher": {
+                    "$ref": "#/definitions/service_internal_domain_models.TeacherProfile"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "student",
+                        "teacher",
+                        "user"
+                    ],
+                    "example": "student"
+                },
                 "updated_at": {
                     "type": "string"
                 },
                 "user_id": {
-                    "type": "integer"
+                    "type": "string",
+                    "example": "42"
                 }
             }
         },
@@ -4208,6 +7913,58 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "user_id": {
+                    "type": "string",
+                    "example": "42"
+                }
+            }
+        },
+        "service_internal_domain_models.UserUpdate": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "ivanov@example.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "Иван"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Иванов"
+                },
+                "middle_name": {
+                    "type": "string",
+                    "example": "Иванович"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "s3cretPass"
+                }
+            }
+        },
+        "service_internal_domain_models.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
                     "type": "integer"
                 }
             }
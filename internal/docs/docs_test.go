@@ -0,0 +1,71 @@
+package docs
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type swaggerDoc struct {
+	Paths       map[string]interface{} `json:"paths"`
+	Definitions map[string]struct {
+		Properties map[string]struct {
+			Example interface{} `json:"example"`
+		} `json:"properties"`
+	} `json:"definitions"`
+}
+
+func readSwaggerJSON(t *testing.T) swaggerDoc {
+	t.Helper()
+	b, err := os.ReadFile("swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc swaggerDoc
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestSwaggerJSON_HasExamples(t *testing.T) {
+	doc := readSwaggerJSON(t)
+
+	want := map[string][]string{
+		"service_internal_domain_models.LoginRequest":              {"email", "password"},
+		"service_internal_domain_models.RegisterRequest":           {"email", "password", "first_name", "last_name"},
+		"service_internal_domain_models.GradeJournal":              {"student_id", "discipline_id", "grade"},
+		"service_internal_domain_models.CreateGradeJournalRequest": {"student_id", "discipline_id", "grade"},
+		"service_internal_domain_models.Attendance":                {"student_id", "discipline_id", "class_date", "visit"},
+		"service_internal_domain_models.CreateAttendanceRequest":   {"student_id", "discipline_id", "class_date", "visit"},
+	}
+	for def, fields := range want {
+		d, ok := doc.Definitions[def]
+		if !ok {
+			t.Errorf("definition %s is missing", def)
+			continue
+		}
+		for _, f := range fields {
+			if d.Properties[f].Example == nil {
+				t.Errorf("%s.%s has no example", def, f)
+			}
+		}
+	}
+}
+
+// docs.go и swagger.json генерируются вместе, расхождение значит, что один из них устарел
+func TestDocTemplate_MatchesSwaggerJSON(t *testing.T) {
+	var fromTemplate swaggerDoc
+	if err := json.Unmarshal([]byte(SwaggerInfo.ReadDoc()), &fromTemplate); err != nil {
+		t.Fatal(err)
+	}
+	fromFile := readSwaggerJSON(t)
+
+	if !reflect.DeepEqual(fromTemplate.Paths, fromFile.Paths) {
+		t.Fatal("paths in docs.go differ from swagger.json")
+	}
+	if !reflect.DeepEqual(fromTemplate.Definitions, fromFile.Definitions) {
+		t.Fatal("definitions in docs.go differ from swagger.json")
+	}
+}
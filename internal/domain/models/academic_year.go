@@ -6,7 +6,21 @@ type AcademicYear struct {
 	AcademicYearID int64     `json:"academic_year_id"`
 	Name           string    `json:"name_academic_year"`
 	CreatedAt      time.Time `json:"created_at"`
-	UpdateAt       time.Time `json:"updated_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 	StartWith      time.Time `json:"start_with"`
 	EndsWith       time.Time `json:"ends_with"`
 }
+
+// AcademicYearCloneRequest параметры нового учебного года при копировании структуры
+type AcademicYearCloneRequest struct {
+	Name      string    `json:"name_academic_year" example:"2025/2026"`
+	StartWith time.Time `json:"start_with"`
+	EndsWith  time.Time `json:"ends_with"`
+}
+
+// AcademicYearCloneResponse созданный год вместе со скопированными семестрами и группами
+type AcademicYearCloneResponse struct {
+	AcademicYear  *AcademicYear   `json:"academic_year"`
+	Semesters     []*Semester     `json:"semesters"`
+	StudentGroups []*StudentGroup `json:"student_groups"`
+}
@@ -5,9 +5,51 @@ import "time"
 type Attendance struct {
 	AttendanceID int64     `json:"attendance_id"`
 	CreatedAt    time.Time `json:"created_at"`
-	Visit        bool      `json:"visit"`
-	Comment      *string   `json:"comment,omitempty"`
-	UpdateAt     time.Time `json:"updated_at"`
-	StudentID    int64     `json:"student_id"`
-	DisciplineID int64     `json:"discipline_id"`
+	Visit        bool      `json:"visit" example:"true"`
+	ClassDate    time.Time `json:"class_date" example:"2025-09-01T00:00:00Z"`
+	Comment      *string   `json:"comment,omitempty" example:"Опоздал на 10 минут"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	StudentID    int64     `json:"student_id" example:"12"`
+	DisciplineID int64     `json:"discipline_id" example:"3"`
+}
+
+// CreateAttendanceRequest поля, которые клиент может задать при создании отметки.
+// id и временные метки выставляет сервер, class_date по умолчанию — сегодня.
+type CreateAttendanceRequest struct {
+	StudentID    int64     `json:"student_id" example:"12"`
+	DisciplineID int64     `json:"discipline_id" example:"3"`
+	Visit        bool      `json:"visit" example:"true"`
+	ClassDate    time.Time `json:"class_date" example:"2025-09-01T00:00:00Z"`
+	Comment      *string   `json:"comment,omitempty" example:"Опоздал на 10 минут"`
+}
+
+type AttendanceDisciplineSummary struct {
+	DisciplineID   int64  `json:"discipline_id"`
+	DisciplineName string `json:"discipline_name"`
+	Present        int64  `json:"present"`
+	Absent         int64  `json:"absent"`
+	Total          int64  `json:"total"`
+}
+
+type AttendanceSummary struct {
+	StudentID   int64                          `json:"student_id"`
+	SemesterID  int64                          `json:"semester_id"`
+	StartWith   time.Time                      `json:"start_with"`
+	EndsWith    time.Time                      `json:"ends_with"`
+	Present     int64                          `json:"present"`
+	Absent      int64                          `json:"absent"`
+	Total       int64                          `json:"total"`
+	Disciplines []*AttendanceDisciplineSummary `json:"disciplines"`
+}
+
+// AttendanceLowRate студент с долей посещённых занятий ниже порога за период
+type AttendanceLowRate struct {
+	StudentID      int64   `json:"student_id"`
+	FirstName      string  `json:"first_name"`
+	LastName       string  `json:"last_name"`
+	MiddleName     *string `json:"middle_name,omitempty"`
+	StudentGroupID int64   `json:"student_group_id"`
+	Present        int64   `json:"present"`
+	Total          int64   `json:"total"`
+	Rate           float64 `json:"rate" example:"0.6"`
 }
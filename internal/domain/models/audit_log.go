@@ -2,14 +2,29 @@ package models
 
 import "time"
 
+// AuditActionRename синтетический тип для фильтра: UPDATE, в котором изменилось название
+const AuditActionRename = "RENAME"
+
 type AuditLog struct {
-	AuditID    int64     `json:"audit_id"`
-	CreatedAt  time.Time `json:"created_at"`
-	UserID     *int64    `json:"user_id,omitempty"`
-	TableName  string    `json:"table_name"`
-	RowID      int64     `json:"row_id"`
-	ActionType string    `json:"action_type"`
-	OldData    *string   `json:"old_data,omitempty"`
-	NewData    *string   `json:"new_data,omitempty"`
-	Comment    *string   `json:"comment,omitempty"`
+	AuditID   int64     `json:"audit_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    *ID       `json:"user_id,omitempty" swaggertype:"string" example:"42"`
+	TableName string    `json:"table_name"`
+	RowID     int64     `json:"row_id"`
+	// StudentID студент, к которому относится запись журнала оценок, для истории оценок
+	StudentID  *int64  `json:"student_id,omitempty"`
+	ActionType string  `json:"action_type"`
+	OldData    *string `json:"old_data,omitempty"`
+	NewData    *string `json:"new_data,omitempty"`
+	Changes    *string `json:"changes,omitempty"`
+	Comment    *string `json:"comment,omitempty"`
+	// RequestID id HTTP-запроса, в котором сделано изменение, для связи с логами сервера
+	RequestID *string `json:"request_id,omitempty"`
+}
+
+// AuditLogWithUser запись аудита с именем автора изменения
+type AuditLogWithUser struct {
+	AuditLog
+	UserFirstName *string `json:"user_first_name,omitempty"`
+	UserLastName  *string `json:"user_last_name,omitempty"`
 }
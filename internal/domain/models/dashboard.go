@@ -0,0 +1,10 @@
+package models
+
+type DashboardMetrics struct {
+	Users          int64 `json:"users"`
+	Teachers       int64 `json:"teachers"`
+	Students       int64 `json:"students"`
+	StudentGroups  int64 `json:"student_groups"`
+	Disciplines    int64 `json:"disciplines"`
+	GradesThisWeek int64 `json:"grades_this_week"`
+}
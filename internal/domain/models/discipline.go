@@ -5,16 +5,29 @@ import "time"
 type Discipline struct {
 	DisciplineID   int64     `json:"discipline_id"`
 	CreatedAt      time.Time `json:"created_at"`
-	UpdateAt       time.Time `json:"updated_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 	DisciplineName string    `json:"discipline_name"`
 	TeacherID      int64     `json:"teacher_id"`
 	StudentGroupID int64     `json:"student_group_id"`
 }
 
+// DisciplineWithCurriculumCount дисциплина с числом пунктов учебного плана
+type DisciplineWithCurriculumCount struct {
+	Discipline
+	CurriculumCount int64 `json:"curriculum_count"`
+}
+
+// DisciplineStudentCount число студентов группы, у которой ведётся дисциплина
+type DisciplineStudentCount struct {
+	DisciplineID   int64 `json:"discipline_id"`
+	StudentGroupID int64 `json:"student_group_id"`
+	StudentCount   int64 `json:"student_count"`
+}
+
 type DisciplinePublic struct {
 	DisciplineID      int64     `json:"discipline_id"`
 	CreatedAt         time.Time `json:"created_at"`
-	UpdateAt          time.Time `json:"updated_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 	DisciplineName    string    `json:"discipline_name"`
 	TeacherID         int64     `json:"teacher_id"`
 	FirstName         string    `json:"first_name"`
@@ -28,3 +41,13 @@ type DisciplinePublic struct {
 	CuratorMiddleName *string   `json:"curator_middle_name,omitempty"`
 	AcademicYearID    int64     `json:"academic_year_id"`
 }
+
+type DisciplineReassignRequest struct {
+	FromTeacherID int64 `json:"from_teacher_id"`
+	ToTeacherID   int64 `json:"to_teacher_id"`
+}
+
+type DisciplineReassignResponse struct {
+	Reassigned    int     `json:"reassigned"`
+	DisciplineIDs []int64 `json:"discipline_ids"`
+}
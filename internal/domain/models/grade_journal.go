@@ -3,19 +3,54 @@ package models
 import "time"
 
 type GradeJournal struct {
-	GradeJournalID int64     `json:"grade_journal_id"`
+	GradeJournalID ID        `json:"grade_journal_id" swaggertype:"string" example:"1001"`
 	CreatedAt      time.Time `json:"created_at"`
-	UpdateAt       time.Time `json:"updated_at"`
-	StudentID      int64     `json:"student_id"`
-	Grade          int16     `json:"grade"`
-	Comment        *string   `json:"comment,omitempty"`
-	DisciplineID   int64     `json:"discipline_id"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	StudentID      int64     `json:"student_id" example:"12"`
+	Grade          int16     `json:"grade" example:"5"`
+	Comment        *string   `json:"comment,omitempty" example:"Контрольная работа"`
+	DisciplineID   int64     `json:"discipline_id" example:"3"`
+}
+
+// CreateGradeJournalRequest поля, которые клиент может задать при создании оценки.
+// id и временные метки выставляет сервер.
+type CreateGradeJournalRequest struct {
+	StudentID    int64   `json:"student_id" example:"12"`
+	DisciplineID int64   `json:"discipline_id" example:"3"`
+	Grade        int16   `json:"grade" example:"5"`
+	Comment      *string `json:"comment,omitempty" example:"Контрольная работа"`
+}
+
+// GradeJournalPatch частичное обновление оценки, непереданные поля не меняются.
+// comment: null очищает комментарий
+type GradeJournalPatch struct {
+	Grade   *int16           `json:"grade,omitempty" example:"4"`
+	Comment Nullable[string] `json:"comment" swaggertype:"string"`
+}
+
+// GradeJournalBulkDeleteRequest id удаляемых записей журнала
+type GradeJournalBulkDeleteRequest struct {
+	IDs []int64 `json:"ids" example:"101,102,103"`
+}
+
+type GradeJournalBulkDeleteResponse struct {
+	Deleted int `json:"deleted"`
+	// IDs фактически удалённые записи, отсутствующие id пропускаются
+	IDs []int64 `json:"ids"`
+}
+
+// DisciplineAverage средний балл студента по дисциплине
+type DisciplineAverage struct {
+	DisciplineID   int64   `json:"discipline_id"`
+	DisciplineName string  `json:"discipline_name"`
+	AverageGrade   float64 `json:"average_grade"`
+	GradesCount    int64   `json:"grades_count"`
 }
 
 type GradeJournalPublic struct {
-	GradeJournalID int64     `json:"grade_journal_id"`
+	GradeJournalID ID        `json:"grade_journal_id" swaggertype:"string" example:"1001"`
 	CreatedAt      time.Time `json:"created_at"`
-	UpdateAt       time.Time `json:"updated_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 	StudentID      int64     `json:"student_id"`
 	FirstName      string    `json:"first_name"`
 	LastName       string    `json:"last_name"`
@@ -0,0 +1,34 @@
+package models
+
+import "strconv"
+
+// ID идентификатор, который в JSON передаётся строкой: клиенты, разбирающие числа
+// как float64 (JavaScript), теряют точность для значений больше 2^53.
+// При разборе принимается и строка, и число.
+//
+// Контракт API: user_id во всех ответах (пользователь, студент, преподаватель,
+// роли пользователя, аудит, импорт) и id пользователей в путях/телах запросов
+// передаются строкой вида "42". Новые поля с user_id должны иметь тип ID.
+type ID int64
+
+func (id ID) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, 22)
+	b = append(b, '"')
+	b = strconv.AppendInt(b, int64(id), 10)
+	return append(b, '"'), nil
+}
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	v, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*id = ID(v)
+	return nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestID_RoundTripAbove2To53(t *testing.T) {
+	// 2^53 + 1 не представимо в float64
+	const big ID = 9007199254740993
+
+	b, err := json.Marshal(struct {
+		ID ID `json:"id"`
+	}{big})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"id":"9007199254740993"}` {
+		t.Fatalf("marshal = %s", b)
+	}
+
+	var v struct {
+		ID ID `json:"id"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.ID != big {
+		t.Fatalf("round trip = %d, want %d", v.ID, big)
+	}
+}
+
+func TestID_UnmarshalAcceptsNumber(t *testing.T) {
+	var id ID
+	if err := json.Unmarshal([]byte(`9007199254740993`), &id); err != nil {
+		t.Fatal(err)
+	}
+	if id != 9007199254740993 {
+		t.Fatalf("id = %d", id)
+	}
+}
+
+func TestID_UnmarshalNullKeepsValue(t *testing.T) {
+	id := ID(5)
+	if err := json.Unmarshal([]byte(`null`), &id); err != nil || id != 5 {
+		t.Fatalf("id = %d, err %v", id, err)
+	}
+}
+
+func TestID_UnmarshalRejectsMalformed(t *testing.T) {
+	for _, in := range []string{`"abc"`, `"1.5"`, `""`, `"12`, `true`} {
+		var id ID
+		if err := id.UnmarshalJSON([]byte(in)); err == nil {
+			t.Fatalf("%s: expected error, got %d", in, id)
+		}
+	}
+}
+
+func TestUserIDFieldsMarshalAsString(t *testing.T) {
+	const big ID = 9007199254740993
+	const want = `"user_id":"9007199254740993"`
+	author := big
+	values := map[string]interface{}{
+		"User":             User{UserID: big},
+		"Student":          Student{UserID: big},
+		"StudentPublic":    StudentPublic{UserID: big},
+		"StudentImportRow": StudentImportRow{UserID: big},
+		"Teacher":          Teacher{UserID: big},
+		"TeacherResponse":  TeacherResponse{UserID: big},
+		"TeacherPublic":    TeacherPublic{UserID: big},
+		"UserRole":         UserRole{UserID: big},
+		"AuditLog":         AuditLog{UserID: &author},
+	}
+	for name, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("%s: %s does not contain %s", name, b, want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type IdempotencyKey struct {
+	UserID int64  `json:"user_id"`
+	Key    string `json:"idempotency_key"`
+	// Fingerprint sha256 от метода, пути и тела запроса, для которого выдан ключ
+	Fingerprint string    `json:"fingerprint"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// StatusCode 0 — запрос ещё выполняется
+	StatusCode   int    `json:"status_code"`
+	ContentType  string `json:"content_type"`
+	Location     string `json:"location"`
+	ResponseBody []byte `json:"response_body"`
+}
+
+// Pending ключ зарезервирован, но ответ ещё не сохранён
+func (k *IdempotencyKey) Pending() bool {
+	return k.StatusCode == 0
+}
@@ -0,0 +1,29 @@
+package models
+
+import "encoding/json"
+
+// Nullable поле частичного обновления, различающее отсутствие ключа и явный null:
+// Set = false — поле не передано и не меняется, Set = true и Value = nil — очистить (NULL).
+type Nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON вызывается только для присутствующего ключа, поэтому сразу отмечает Set
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Value = &v
+	return nil
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullable_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantSet   bool
+		wantValue *string
+	}{
+		{"omitted", `{"grade":4}`, false, nil},
+		{"explicit null", `{"comment":null}`, true, nil},
+		{"value", `{"comment":"пересдача"}`, true, strPtr("пересдача")},
+		{"empty string", `{"comment":""}`, true, strPtr("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch GradeJournalPatch
+			if err := json.Unmarshal([]byte(tt.body), &patch); err != nil {
+				t.Fatal(err)
+			}
+			got := patch.Comment
+			if got.Set != tt.wantSet {
+				t.Errorf("Set = %v, want %v", got.Set, tt.wantSet)
+			}
+			switch {
+			case tt.wantValue == nil && got.Value != nil:
+				t.Errorf("Value = %q, want nil", *got.Value)
+			case tt.wantValue != nil && (got.Value == nil || *got.Value != *tt.wantValue):
+				t.Errorf("Value = %v, want %q", got.Value, *tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestNullable_UnmarshalWrongType(t *testing.T) {
+	var patch GradeJournalPatch
+	if err := json.Unmarshal([]byte(`{"comment":5}`), &patch); err == nil {
+		t.Fatalf("unmarshal = %+v, want type error", patch.Comment)
+	}
+}
+
+func TestNullable_Marshal(t *testing.T) {
+	for _, tt := range []struct {
+		n    Nullable[string]
+		want string
+	}{
+		{Nullable[string]{}, `null`},
+		{Nullable[string]{Set: true}, `null`},
+		{Nullable[string]{Set: true, Value: strPtr("пересдача")}, `"пересдача"`},
+	} {
+		b, err := json.Marshal(tt.n)
+		if err != nil || string(b) != tt.want {
+			t.Errorf("marshal %+v = %s, %v; want %s", tt.n, b, err, tt.want)
+		}
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -5,6 +5,24 @@ import "time"
 type Permission struct {
 	PermissionID   int64     `json:"permission_id"`
 	CreatedAt      time.Time `json:"created_at"`
-	UpdateAt       time.Time `json:"updated_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 	PermissionName string    `json:"permission_name"`
 }
+
+// PermissionRenameRequest переименование права или группы прав.
+// "resource:*" переименовывает все права ресурса, например studentgroup:* → student_group:*
+type PermissionRenameRequest struct {
+	From string `json:"from" example:"studentgroup:*"`
+	To   string `json:"to" example:"student_group:*"`
+}
+
+type PermissionRenamed struct {
+	PermissionID int64  `json:"permission_id"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+}
+
+type PermissionRenameResponse struct {
+	Renamed     int                  `json:"renamed"`
+	Permissions []*PermissionRenamed `json:"permissions"`
+}
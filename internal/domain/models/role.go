@@ -5,6 +5,11 @@ import "time"
 type Role struct {
 	RoleID    int64     `json:"role_id"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdateAt  time.Time `json:"updated_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 	RoleName  string    `json:"role_name"`
 }
+
+type RoleWithCount struct {
+	Role
+	UserCount int64 `json:"user_count"`
+}
@@ -3,19 +3,58 @@ package models
 import "time"
 
 type Student struct {
-	UserID         int64     `json:"user_id"`
+	UserID         ID        `json:"user_id" swaggertype:"string" example:"42"`
 	Phone          string    `json:"phone"`
 	Birthday       time.Time `json:"birthday"`
 	CreatedAt      time.Time `json:"created_at"`
-	UpdateAt       time.Time `json:"updated_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 	StudentGroupID int64     `json:"student_group_id"`
 }
 
 type StudentPublic struct {
-	UserID         int64     `json:"user_id"`
+	UserID         ID        `json:"user_id" swaggertype:"string" example:"42"`
 	FirstName      string    `json:"first_name"`
 	LastName       string    `json:"last_name"`
 	MiddleName     *string   `json:"middle_name,omitempty"`
 	Birthday       time.Time `json:"birthday"`
 	StudentGroupID int64     `json:"student_group_id"`
 }
+
+type StudentWithUser struct {
+	Student
+	FirstName  string  `json:"first_name"`
+	LastName   string  `json:"last_name"`
+	MiddleName *string `json:"middle_name,omitempty"`
+	Email      string  `json:"email"`
+}
+
+// StudentPatch частичное обновление студента, nil-поля не меняются
+type StudentPatch struct {
+	Phone          *string    `json:"phone,omitempty"`
+	Birthday       *time.Time `json:"birthday,omitempty"`
+	StudentGroupID *int64     `json:"student_group_id,omitempty"`
+}
+
+const (
+	StudentImportCreated = "created"
+	StudentImportSkipped = "skipped"
+	StudentImportError   = "error"
+)
+
+// StudentImportRow результат обработки одной строки CSV, Line — номер строки в файле с учётом заголовка
+type StudentImportRow struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	Email  string `json:"email,omitempty"`
+	UserID ID     `json:"user_id,omitempty" swaggertype:"string" example:"42"`
+	// Password возвращается только если пароль был сгенерирован сервером
+	Password string `json:"password,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type StudentImportReport struct {
+	Created int                 `json:"created"`
+	Skipped int                 `json:"skipped"`
+	Failed  int                 `json:"failed"`
+	Rows    []*StudentImportRow `json:"rows"`
+}
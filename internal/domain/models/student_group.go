@@ -5,12 +5,25 @@ import "time"
 type StudentGroup struct {
 	StudentGroupID   int64     `json:"student_group_id"`
 	CreatedAt        time.Time `json:"created_at"`
-	UpdateAt         time.Time `json:"updated_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 	StudentGroupName string    `json:"student_group_name"`
 	CuratorID        int64     `json:"curator_id"`
 	AcademicYearID   int64     `json:"academic_year_id"`
 }
 
+type StudentGroupMergeRequest struct {
+	FromGroupID int64 `json:"from_group_id" example:"3"`
+	ToGroupID   int64 `json:"to_group_id" example:"5"`
+	// DeleteSource удалить опустевшую исходную группу (нужно право studentgroup:delete)
+	DeleteSource bool `json:"delete_source"`
+}
+
+type StudentGroupMergeResponse struct {
+	Moved         int     `json:"moved"`
+	StudentIDs    []int64 `json:"student_ids"`
+	SourceDeleted bool    `json:"source_deleted"`
+}
+
 type StudentGroupPublic struct {
 	StudentGroupID    int64   `json:"student_group_id"`
 	StudentGroupName  string  `json:"student_group_name"`
@@ -20,3 +33,16 @@ type StudentGroupPublic struct {
 	CuratorMiddleName *string `json:"curator_middle_name,omitempty"`
 	AcademicYearID    int64   `json:"academic_year_id"`
 }
+
+// GroupRankingEntry место студента в рейтинге группы по среднему баллу.
+// Студенты с равным средним делят место, следующее место пропускается (1, 1, 3).
+// У студентов без оценок за период rank и average_grade равны null, они идут в конце.
+type GroupRankingEntry struct {
+	Rank         *int64   `json:"rank" example:"1"`
+	StudentID    int64    `json:"student_id"`
+	FirstName    string   `json:"first_name"`
+	LastName     string   `json:"last_name"`
+	MiddleName   *string  `json:"middle_name,omitempty"`
+	AverageGrade *float64 `json:"average_grade" example:"8.5"`
+	GradesCount  int64    `json:"grades_count"`
+}
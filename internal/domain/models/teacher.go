@@ -3,16 +3,24 @@ package models
 import "time"
 
 type Teacher struct {
-	UserID            int64     `json:"user_id"`
+	UserID            ID        `json:"user_id" swaggertype:"string" example:"42"`
 	CreatedAt         time.Time `json:"created_at"`
-	UpdateAt          time.Time `json:"updated_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 	Phone             string    `json:"phone"`
 	WorkingExperience *string   `json:"working_experience,omitempty"`
 	Education         *string   `json:"education,omitempty"`
 }
 
+// TeacherPatch частичное обновление преподавателя, непереданные поля не меняются.
+// working_experience и education: null очищает значение
+type TeacherPatch struct {
+	Phone             *string          `json:"phone,omitempty"`
+	WorkingExperience Nullable[string] `json:"working_experience" swaggertype:"string"`
+	Education         Nullable[string] `json:"education" swaggertype:"string"`
+}
+
 type TeacherResponse struct {
-	UserID            int64   `json:"user_id"`
+	UserID            ID      `json:"user_id" swaggertype:"string" example:"42"`
 	Phone             string  `json:"phone"`
 	WorkingExperience *string `json:"working_experience,omitempty"`
 	Education         *string `json:"education,omitempty"`
@@ -22,10 +30,24 @@ type TeacherResponse struct {
 }
 
 type TeacherPublic struct {
-	UserID            int64   `json:"user_id"`
+	UserID            ID      `json:"user_id" swaggertype:"string" example:"42"`
 	FirstName         string  `json:"first_name"`
 	LastName          string  `json:"last_name"`
 	MiddleName        *string `json:"middle_name,omitempty"`
 	Education         string  `json:"education"`
 	WorkingExperience *string `json:"working_experience,omitempty"`
 }
+
+// TeacherPublicWithPhone публичный профиль с телефоном для пользователей с правом teacher:view
+type TeacherPublicWithPhone struct {
+	TeacherPublic
+	Phone string `json:"phone"`
+}
+
+// TeacherWorkload нагрузка преподавателя: дисциплины, группы и студенты в них
+type TeacherWorkload struct {
+	TeacherID   int64 `json:"teacher_id"`
+	Disciplines int64 `json:"disciplines"`
+	Groups      int64 `json:"groups"`
+	Students    int64 `json:"students"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestUpdatedAt_JSONName все сущности с меткой изменения отдают её как updated_at
+func TestUpdatedAt_JSONName(t *testing.T) {
+	entities := []interface{}{
+		AcademicYear{}, Attendance{}, Curriculum{}, Discipline{}, DisciplinePublic{},
+		GradeJournal{}, Permission{}, Role{}, RolePermission{}, Semester{}, Student{},
+		StudentGroup{}, Teacher{}, User{}, Me{}, UserRole{}, Webhook{},
+	}
+	at := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, e := range entities {
+		typ := reflect.TypeOf(e)
+		t.Run(typ.Name(), func(t *testing.T) {
+			if _, ok := typ.FieldByName("UpdateAt"); ok {
+				t.Fatal("has legacy UpdateAt field")
+			}
+			f, ok := typ.FieldByName("UpdatedAt")
+			if !ok {
+				t.Fatal("no UpdatedAt field")
+			}
+			if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "updated_at" {
+				t.Fatalf("UpdatedAt json name = %q, want updated_at", name)
+			}
+
+			// запись и чтение через JSON сохраняют значение
+			v := reflect.New(typ)
+			v.Elem().FieldByName("UpdatedAt").Set(reflect.ValueOf(at))
+			b, err := json.Marshal(v.Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(b), `"update_at"`) {
+				t.Fatalf("marshal = %s", b)
+			}
+			back := reflect.New(typ)
+			if err := json.Unmarshal(b, back.Interface()); err != nil {
+				t.Fatal(err)
+			}
+			if got := back.Elem().FieldByName("UpdatedAt").Interface().(time.Time); !got.Equal(at) {
+				t.Fatalf("round trip updated_at = %s, want %s", got, at)
+			}
+		})
+	}
+}
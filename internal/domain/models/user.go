@@ -3,25 +3,106 @@ package models
 import "time"
 
 type User struct {
-	UserID     int64     `json:"user_id"`
+	UserID     ID        `json:"user_id" swaggertype:"string" example:"42"`
 	CreatedAt  time.Time `json:"created_at,omitempty"`
-	UpdateAt   time.Time `json:"updated_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
 	FirstName  string    `json:"first_name"`
 	LastName   string    `json:"last_name"`
 	MiddleName *string   `json:"middle_name,omitempty"`
 	Email      string    `json:"email"`
 	Password   []byte    `json:"password"`
+	// IsActive false — пользователь деактивирован и не может войти
+	IsActive bool `json:"is_active"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" example:"ivanov@example.com"`
+	Password string `json:"password" example:"s3cretPass"`
 }
 
 type RegisterRequest struct {
-	FirstName  string  `json:"first_name"`
-	LastName   string  `json:"last_name"`
-	MiddleName *string `json:"middle_name,omitempty"`
-	Email      string  `json:"email"`
-	Password   string  `json:"password"`
+	FirstName  string  `json:"first_name" example:"Иван"`
+	LastName   string  `json:"last_name" example:"Иванов"`
+	MiddleName *string `json:"middle_name,omitempty" example:"Иванович"`
+	Email      string  `json:"email" example:"ivanov@example.com"`
+	Password   string  `json:"password" example:"s3cretPass"`
+}
+
+// Me профиль текущего пользователя без пароля
+type Me struct {
+	UserID     ID          `json:"user_id" swaggertype:"string" example:"42"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+	FirstName  string      `json:"first_name"`
+	LastName   string      `json:"last_name"`
+	MiddleName *string     `json:"middle_name,omitempty"`
+	Email      string      `json:"email"`
+	Roles      []*UserRole `json:"roles"`
+}
+
+// Типы пользователя в UserProfile
+const (
+	UserTypeStudent = "student"
+	UserTypeTeacher = "teacher"
+	UserTypeUser    = "user"
+)
+
+// UserProfile пользователь вместе с данными студента или преподавателя и ролями.
+// Если пользователь одновременно студент и преподаватель, заполнены оба блока, а type = teacher.
+type UserProfile struct {
+	UserID     ID              `json:"user_id" swaggertype:"string" example:"42"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	FirstName  string          `json:"first_name"`
+	LastName   string          `json:"last_name"`
+	MiddleName *string         `json:"middle_name,omitempty"`
+	Email      string          `json:"email"`
+	IsActive   bool            `json:"is_active"`
+	Type       string          `json:"type" enums:"student,teacher,user" example:"student"`
+	Student    *StudentProfile `json:"student,omitempty"`
+	Teacher    *TeacherProfile `json:"teacher,omitempty"`
+	Roles      []*UserRole     `json:"roles"`
+}
+
+type StudentProfile struct {
+	Phone            string    `json:"phone"`
+	Birthday         time.Time `json:"birthday"`
+	StudentGroupID   int64     `json:"student_group_id"`
+	StudentGroupName string    `json:"student_group_name"`
+}
+
+type TeacherProfile struct {
+	Phone             string  `json:"phone"`
+	WorkingExperience *string `json:"working_experience,omitempty"`
+	Education         *string `json:"education,omitempty"`
+}
+
+type PasswordResetRequest struct {
+	Password string `json:"password,omitempty"`
+}
+
+type PasswordResetResponse struct {
+	UserID ID `json:"user_id" swaggertype:"string" example:"42"`
+	// Password возвращается только если пароль был сгенерирован сервером
+	Password string `json:"password,omitempty"`
+}
+
+// UserUpdate полная замена пользователя через PUT. Пароль передаётся открытым
+// текстом и хешируется обработчиком, пустой пароль оставляет текущий
+type UserUpdate struct {
+	FirstName  string  `json:"first_name" example:"Иван"`
+	LastName   string  `json:"last_name" example:"Иванов"`
+	MiddleName *string `json:"middle_name,omitempty" example:"Иванович"`
+	Email      string  `json:"email" example:"ivanov@example.com"`
+	Password   string  `json:"password,omitempty" example:"s3cretPass"`
+}
+
+// UserPatch частичное обновление пользователя, nil-поля не меняются.
+// middle_name: null очищает отчество
+type UserPatch struct {
+	FirstName  *string          `json:"first_name,omitempty"`
+	LastName   *string          `json:"last_name,omitempty"`
+	MiddleName Nullable[string] `json:"middle_name" swaggertype:"string"`
+	Email      *string          `json:"email,omitempty"`
+	Password   *string          `json:"password,omitempty"`
 }
@@ -4,7 +4,7 @@ import "time"
 
 type UserRole struct {
 	CreatedAt time.Time `json:"created_at"`
-	UpdateAt  time.Time `json:"updated_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 	RoleID    int64     `json:"role_id"`
-	UserID    int64     `json:"user_id"`
+	UserID    ID        `json:"user_id" swaggertype:"string" example:"42"`
 }
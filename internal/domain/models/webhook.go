@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type Webhook struct {
+	WebhookID  int64     `json:"webhook_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+}
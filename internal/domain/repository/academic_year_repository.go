@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"service/internal/domain/models"
+	"service/internal/storage"
 	"time"
 )
 
@@ -21,16 +22,16 @@ func (r *academicYearRepository) CreateAcademicYear(ctx context.Context, year *m
 		INSERT INTO academic_year (name_academic_year, start_with, ends_with, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	year.CreatedAt = now
-	year.UpdateAt = now
+	year.UpdatedAt = now
 
 	res, err := r.db.ExecContext(ctx, query,
 		year.Name,
 		year.StartWith,
 		year.EndsWith,
 		year.CreatedAt,
-		year.UpdateAt,
+		year.UpdatedAt,
 	)
 	if err != nil {
 		return err
@@ -55,7 +56,35 @@ func (r *academicYearRepository) GetAcademicYearByID(ctx context.Context, id int
 		&year.StartWith,
 		&year.EndsWith,
 		&year.CreatedAt,
-		&year.UpdateAt,
+		&year.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	return year, nil
+}
+
+// GetCurrentAcademicYear возвращает учебный год, в который попадает дата at.
+// Если годы пересекаются, берётся начавшийся позже.
+func (r *academicYearRepository) GetCurrentAcademicYear(ctx context.Context, at time.Time) (*models.AcademicYear, error) {
+	query := `
+		SELECT academic_year_id, name_academic_year, start_with, ends_with, created_at, updated_at
+		FROM academic_year
+		WHERE ? BETWEEN start_with AND ends_with
+		ORDER BY start_with DESC
+		LIMIT 1
+	`
+	year := &models.AcademicYear{}
+	err := r.db.QueryRowContext(ctx, query, at.Format("2006-01-02")).Scan(
+		&year.AcademicYearID,
+		&year.Name,
+		&year.StartWith,
+		&year.EndsWith,
+		&year.CreatedAt,
+		&year.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -76,7 +105,7 @@ func (r *academicYearRepository) UpdateAcademicYear(ctx context.Context, year *m
 		year.Name,
 		year.StartWith,
 		year.EndsWith,
-		time.Now(),
+		time.Now().UTC(),
 		year.AcademicYearID,
 	)
 	return err
@@ -85,6 +114,9 @@ func (r *academicYearRepository) UpdateAcademicYear(ctx context.Context, year *m
 func (r *academicYearRepository) DeleteAcademicYear(ctx context.Context, id int64) error {
 	query := `DELETE FROM academic_year WHERE academic_year_id = ?`
 	_, err := r.db.ExecContext(ctx, query, id)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
@@ -110,7 +142,7 @@ func (r *academicYearRepository) ListAcademicYear(ctx context.Context, limit, of
 			&year.StartWith,
 			&year.EndsWith,
 			&year.CreatedAt,
-			&year.UpdateAt,
+			&year.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -119,3 +151,126 @@ func (r *academicYearRepository) ListAcademicYear(ctx context.Context, limit, of
 	}
 	return years, nil
 }
+
+func (r *academicYearRepository) CountAcademicYear(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM academic_year`).Scan(&total)
+	return total, err
+}
+
+// CloneAcademicYear создаёт учебный год year и копирует в него семестры и группы года sourceID
+// одной транзакцией. Даты семестров сдвигаются на столько дней, на сколько начало нового года
+// отстоит от начала исходного. Студенты в группы не переносятся.
+// Если исходного года нет — sql.ErrNoRows, год с таким названием уже есть — storage.ErrDuplicate.
+func (r *academicYearRepository) CloneAcademicYear(ctx context.Context, sourceID int64, year *models.AcademicYear) (*models.AcademicYearCloneResponse, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var sourceStart time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT start_with FROM academic_year WHERE academic_year_id = ? FOR UPDATE`, sourceID).Scan(&sourceStart)
+	if err != nil {
+		return nil, err
+	}
+	// уникального индекса на name_academic_year нет, поэтому повторное копирование ловим сами
+	var taken int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM academic_year WHERE name_academic_year = ?`, year.Name).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+	if taken > 0 {
+		return nil, storage.ErrDuplicate
+	}
+	shift := int(truncateToDate(year.StartWith).Sub(truncateToDate(sourceStart)).Hours() / 24)
+
+	now := time.Now().UTC()
+	year.CreatedAt = now
+	year.UpdatedAt = now
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO academic_year (name_academic_year, start_with, ends_with, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		year.Name, year.StartWith, year.EndsWith, year.CreatedAt, year.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if year.AcademicYearID, err = res.LastInsertId(); err != nil {
+		return nil, err
+	}
+	out := &models.AcademicYearCloneResponse{
+		AcademicYear:  year,
+		Semesters:     []*models.Semester{},
+		StudentGroups: []*models.StudentGroup{},
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT start_with, ends_with FROM semester WHERE academic_year_id = ? ORDER BY start_with, semester_id`, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		s := &models.Semester{AcademicYearID: year.AcademicYearID, CreatedAt: now, UpdatedAt: now}
+		if err := rows.Scan(&s.StartWith, &s.EndsWith); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		s.StartWith = s.StartWith.AddDate(0, 0, shift)
+		s.EndsWith = s.EndsWith.AddDate(0, 0, shift)
+		out.Semesters = append(out.Semesters, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx,
+		`SELECT student_group_name, curator_id FROM student_group WHERE academic_year_id = ? ORDER BY student_group_id`, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		g := &models.StudentGroup{AcademicYearID: year.AcademicYearID, CreatedAt: now, UpdatedAt: now}
+		if err := rows.Scan(&g.StudentGroupName, &g.CuratorID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out.StudentGroups = append(out.StudentGroups, g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, s := range out.Semesters {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO semester (start_with, ends_with, academic_year_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			s.StartWith, s.EndsWith, s.AcademicYearID, s.CreatedAt, s.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if s.SemesterID, err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+	}
+	for _, g := range out.StudentGroups {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO student_group (student_group_name, curator_id, academic_year_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			g.StudentGroupName, g.CuratorID, g.AcademicYearID, g.CreatedAt, g.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if g.StudentGroupID, err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
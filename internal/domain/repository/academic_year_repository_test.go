@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"service/internal/domain/models"
+	"service/internal/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cloneDB исходный год 2024/2025 с двумя семестрами и одной группой
+func cloneDB(taken int64) *fakeDB {
+	date := func(s string) time.Time { d, _ := time.Parse("2006-01-02", s); return d }
+	var nextID int64 = 100
+	return &fakeDB{
+		onQuery: func(q string, _ []driver.NamedValue) (driver.Rows, error) {
+			switch {
+			case strings.Contains(q, "COUNT(*)"):
+				return &fakeRows{cols: []string{"count"}, vals: [][]driver.Value{{taken}}}, nil
+			case strings.Contains(q, "FROM academic_year"):
+				return &fakeRows{cols: []string{"start_with"}, vals: [][]driver.Value{{date("2024-09-01")}}}, nil
+			case strings.Contains(q, "FROM semester"):
+				return &fakeRows{cols: []string{"start_with", "ends_with"}, vals: [][]driver.Value{
+					{date("2024-09-01"), date("2024-12-31")},
+					{date("2025-02-01"), date("2025-06-30")},
+				}}, nil
+			case strings.Contains(q, "FROM student_group"):
+				return &fakeRows{cols: []string{"student_group_name", "curator_id"}, vals: [][]driver.Value{{"ИВТ-21", int64(10)}}}, nil
+			}
+			return &fakeRows{}, nil
+		},
+		onExec: func(string, []driver.NamedValue) (driver.Result, error) {
+			nextID++
+			return fakeResult{id: nextID, affected: 1}, nil
+		},
+	}
+}
+
+func TestCloneAcademicYear(t *testing.T) {
+	f := cloneDB(0)
+	year := &models.AcademicYear{
+		Name:      "2025/2026",
+		StartWith: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		EndsWith:  time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	out, err := NewAcademicYearRepository(newFakeDB(t, f)).CloneAcademicYear(context.Background(), 1, year)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.AcademicYear.AcademicYearID != 101 {
+		t.Errorf("academic year id = %d, want 101", out.AcademicYear.AcademicYearID)
+	}
+	// даты семестров сдвигаются на разницу начал годов (365 дней)
+	if len(out.Semesters) != 2 ||
+		out.Semesters[0].StartWith.Format("2006-01-02") != "2025-09-01" || out.Semesters[0].EndsWith.Format("2006-01-02") != "2025-12-31" ||
+		out.Semesters[1].StartWith.Format("2006-01-02") != "2026-02-01" || out.Semesters[1].AcademicYearID != 101 {
+		t.Errorf("semesters = %+v", out.Semesters)
+	}
+	if len(out.StudentGroups) != 1 || out.StudentGroups[0].StudentGroupName != "ИВТ-21" || out.StudentGroups[0].CuratorID != 10 ||
+		out.StudentGroups[0].AcademicYearID != 101 || out.StudentGroups[0].StudentGroupID != 104 {
+		t.Errorf("student groups = %+v", out.StudentGroups)
+	}
+	log := f.entries()
+	if log[0] != "BEGIN" || log[len(log)-1] != "COMMIT" {
+		t.Errorf("log = %q, want one transaction ending with COMMIT", log)
+	}
+}
+
+func TestCloneAcademicYear_TargetExists(t *testing.T) {
+	f := cloneDB(1)
+	year := &models.AcademicYear{Name: "2025/2026", StartWith: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)}
+	out, err := NewAcademicYearRepository(newFakeDB(t, f)).CloneAcademicYear(context.Background(), 1, year)
+	if !errors.Is(err, storage.ErrDuplicate) {
+		t.Fatalf("err = %v, want storage.ErrDuplicate", err)
+	}
+	if out != nil {
+		t.Errorf("out = %+v, want nil", out)
+	}
+	log := f.entries()
+	for _, e := range log {
+		if strings.HasPrefix(e, "INSERT") || e == "COMMIT" {
+			t.Fatalf("log = %q, want nothing written", log)
+		}
+	}
+	if log[len(log)-1] != "ROLLBACK" {
+		t.Errorf("log = %q, want ROLLBACK", log)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"service/internal/domain/models"
+	"service/internal/storage"
 	"time"
 )
 
@@ -18,13 +19,21 @@ func NewAttendanceRepository(db *sql.DB) *attendanceRepository {
 
 func (r *attendanceRepository) CreateAttendance(ctx context.Context, a *models.Attendance) error {
 	query := `
-		INSERT INTO attendance (created_at, visit, comment, updated_at, student_id, discipline_id)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO attendance (created_at, visit, class_date, comment, updated_at, student_id, discipline_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	a.CreatedAt = now
-	a.UpdateAt = now
-	res, err := r.db.ExecContext(ctx, query, a.CreatedAt, a.Visit, a.Comment, a.UpdateAt, a.StudentID, a.DisciplineID)
+	a.UpdatedAt = now
+	// без явной даты занятия считаем, что отметку ставят в день занятия
+	if a.ClassDate.IsZero() {
+		a.ClassDate = now
+	}
+	a.ClassDate = truncateToDate(a.ClassDate)
+	res, err := r.db.ExecContext(ctx, query, a.CreatedAt, a.Visit, a.ClassDate, a.Comment, a.UpdatedAt, a.StudentID, a.DisciplineID)
+	if isDuplicateEntry(err) {
+		return storage.ErrDuplicate
+	}
 	if err != nil {
 		return err
 	}
@@ -37,7 +46,7 @@ func (r *attendanceRepository) CreateAttendance(ctx context.Context, a *models.A
 
 func (r *attendanceRepository) GetAttendanceByID(ctx context.Context, id int64) (*models.Attendance, error) {
 	query := `
-		SELECT attendance_id, created_at, visit, comment, updated_at, student_id, discipline_id
+		SELECT attendance_id, created_at, visit, class_date, comment, updated_at, student_id, discipline_id
 		FROM attendance
 		WHERE attendance_id = ?
 	`
@@ -46,8 +55,9 @@ func (r *attendanceRepository) GetAttendanceByID(ctx context.Context, id int64)
 		&a.AttendanceID,
 		&a.CreatedAt,
 		&a.Visit,
+		&a.ClassDate,
 		&a.Comment,
-		&a.UpdateAt,
+		&a.UpdatedAt,
 		&a.StudentID,
 		&a.DisciplineID,
 	)
@@ -63,10 +73,18 @@ func (r *attendanceRepository) GetAttendanceByID(ctx context.Context, id int64)
 func (r *attendanceRepository) UpdateAttendance(ctx context.Context, a *models.Attendance) error {
 	query := `
 		UPDATE attendance
-		SET visit = ?, comment = ?, updated_at = ?, student_id = ?, discipline_id = ?
+		SET visit = ?, class_date = COALESCE(?, class_date), comment = ?, updated_at = ?, student_id = ?, discipline_id = ?
 		WHERE attendance_id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, a.Visit, a.Comment, time.Now(), a.StudentID, a.DisciplineID, a.AttendanceID)
+	// пустая дата занятия при обновлении означает "не менять"
+	var classDate interface{}
+	if !a.ClassDate.IsZero() {
+		classDate = truncateToDate(a.ClassDate)
+	}
+	_, err := r.db.ExecContext(ctx, query, a.Visit, classDate, a.Comment, time.Now().UTC(), a.StudentID, a.DisciplineID, a.AttendanceID)
+	if isDuplicateEntry(err) {
+		return storage.ErrDuplicate
+	}
 	return err
 }
 
@@ -78,7 +96,7 @@ func (r *attendanceRepository) DeleteAttendance(ctx context.Context, id int64) e
 
 func (r *attendanceRepository) ListAttendance(ctx context.Context, limit, offset int) ([]*models.Attendance, error) {
 	query := `
-		SELECT attendance_id, created_at, visit, comment, updated_at, student_id, discipline_id
+		SELECT attendance_id, created_at, visit, class_date, comment, updated_at, student_id, discipline_id
 		FROM attendance
 		ORDER BY attendance_id
 		LIMIT ? OFFSET ?
@@ -96,8 +114,9 @@ func (r *attendanceRepository) ListAttendance(ctx context.Context, limit, offset
 			&a.AttendanceID,
 			&a.CreatedAt,
 			&a.Visit,
+			&a.ClassDate,
 			&a.Comment,
-			&a.UpdateAt,
+			&a.UpdatedAt,
 			&a.StudentID,
 			&a.DisciplineID,
 		)
@@ -109,29 +128,24 @@ func (r *attendanceRepository) ListAttendance(ctx context.Context, limit, offset
 	return items, nil
 }
 
+// ListAttendanceWithFilters при afterID работает в keyset-режиме: записи с id больше afterID,
+// offset игнорируется
 func (r *attendanceRepository) ListAttendanceWithFilters(
 	ctx context.Context,
-	studentID, disciplineID *int64,
-	date *time.Time,
+	studentID, disciplineID, academicYearID *int64,
+	date, updatedSince *time.Time,
+	afterID *int64,
 	limit, offset int,
 ) ([]*models.Attendance, error) {
-	query := `SELECT attendance_id, created_at, visit, comment, updated_at, student_id, discipline_id FROM attendance WHERE 1=1`
-	var args []interface{}
-
-	if studentID != nil {
-		query += " AND student_id = ?"
-		args = append(args, *studentID)
+	from, args := attendanceFilter(studentID, disciplineID, academicYearID, date, updatedSince)
+	query := `SELECT a.attendance_id, a.created_at, a.visit, a.class_date, a.comment, a.updated_at, a.student_id, a.discipline_id` + from
+	if afterID != nil {
+		query += " AND a.attendance_id > ? ORDER BY a.attendance_id LIMIT ?"
+		args = append(args, *afterID, limit)
+	} else {
+		query += " ORDER BY a.attendance_id LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
 	}
-	if disciplineID != nil {
-		query += " AND discipline_id = ?"
-		args = append(args, *disciplineID)
-	}
-	if date != nil {
-		query += " AND DATE(created_at) = ?"
-		args = append(args, date.Format("2006-01-02"))
-	}
-	query += " ORDER BY attendance_id LIMIT ? OFFSET ?"
-	args = append(args, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -146,8 +160,9 @@ func (r *attendanceRepository) ListAttendanceWithFilters(
 			&a.AttendanceID,
 			&a.CreatedAt,
 			&a.Visit,
+			&a.ClassDate,
 			&a.Comment,
-			&a.UpdateAt,
+			&a.UpdatedAt,
 			&a.StudentID,
 			&a.DisciplineID,
 		)
@@ -158,3 +173,137 @@ func (r *attendanceRepository) ListAttendanceWithFilters(
 	}
 	return items, nil
 }
+
+// attendanceFilter собирает FROM ... WHERE для списка посещаемости, общий для List и Count
+func attendanceFilter(studentID, disciplineID, academicYearID *int64, date, updatedSince *time.Time) (string, []interface{}) {
+	query := ` FROM attendance a`
+	var args []interface{}
+
+	// учебный год хранится у группы дисциплины, join нужен только для этого фильтра
+	if academicYearID != nil {
+		query += ` INNER JOIN discipline d ON a.discipline_id = d.discipline_id
+			INNER JOIN student_group sg ON d.student_group_id = sg.student_group_id`
+	}
+	query += " WHERE 1=1"
+
+	if studentID != nil {
+		query += " AND a.student_id = ?"
+		args = append(args, *studentID)
+	}
+	if disciplineID != nil {
+		query += " AND a.discipline_id = ?"
+		args = append(args, *disciplineID)
+	}
+	if academicYearID != nil {
+		query += " AND sg.academic_year_id = ?"
+		args = append(args, *academicYearID)
+	}
+	if date != nil {
+		query += " AND a.class_date = ?"
+		args = append(args, date.Format("2006-01-02"))
+	}
+	if updatedSince != nil {
+		query += " AND a.updated_at >= ?"
+		args = append(args, *updatedSince)
+	}
+	return query, args
+}
+
+// CountAttendanceWithFilters считает записи с теми же фильтрами, что и ListAttendanceWithFilters
+func (r *attendanceRepository) CountAttendanceWithFilters(
+	ctx context.Context,
+	studentID, disciplineID, academicYearID *int64,
+	date, updatedSince *time.Time,
+) (int64, error) {
+	from, args := attendanceFilter(studentID, disciplineID, academicYearID, date, updatedSince)
+	var total int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+from, args...).Scan(&total)
+	return total, err
+}
+
+// GetAttendanceSummary считает посещения студента по дисциплинам за период [from, to] включительно
+func (r *attendanceRepository) GetAttendanceSummary(ctx context.Context, studentID int64, from, to time.Time) ([]*models.AttendanceDisciplineSummary, error) {
+	query := `
+		SELECT a.discipline_id, d.discipline_name,
+			SUM(CASE WHEN a.visit THEN 1 ELSE 0 END) AS present,
+			SUM(CASE WHEN a.visit THEN 0 ELSE 1 END) AS absent,
+			COUNT(*) AS total
+		FROM attendance a
+		JOIN discipline d ON a.discipline_id = d.discipline_id
+		WHERE a.student_id = ?
+			AND a.class_date BETWEEN ? AND ?
+		GROUP BY a.discipline_id, d.discipline_name
+		ORDER BY a.discipline_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, studentID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*models.AttendanceDisciplineSummary
+	for rows.Next() {
+		s := &models.AttendanceDisciplineSummary{}
+		if err := rows.Scan(&s.DisciplineID, &s.DisciplineName, &s.Present, &s.Absent, &s.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, s)
+	}
+	return items, rows.Err()
+}
+
+// ListLowAttendance студенты, у которых доля посещённых занятий за период меньше threshold.
+// Границы периода необязательны и включаются. Сначала идут студенты с худшей посещаемостью.
+func (r *attendanceRepository) ListLowAttendance(ctx context.Context, threshold float64, from, to *time.Time, limit, offset int) ([]*models.AttendanceLowRate, error) {
+	query := `
+		SELECT s.user_id, u.first_name, u.last_name, u.middle_name, s.student_group_id,
+			SUM(CASE WHEN a.visit THEN 1 ELSE 0 END) AS present,
+			COUNT(*) AS total
+		FROM attendance a
+		JOIN student s ON a.student_id = s.user_id
+		JOIN user u ON s.user_id = u.user_id
+		WHERE 1=1`
+	var args []interface{}
+	if from != nil {
+		query += " AND a.class_date >= ?"
+		args = append(args, from.Format("2006-01-02"))
+	}
+	if to != nil {
+		query += " AND a.class_date <= ?"
+		args = append(args, to.Format("2006-01-02"))
+	}
+	query += `
+		GROUP BY s.user_id, u.first_name, u.last_name, u.middle_name, s.student_group_id
+		HAVING SUM(CASE WHEN a.visit THEN 1 ELSE 0 END) / COUNT(*) < ?
+		ORDER BY SUM(CASE WHEN a.visit THEN 1 ELSE 0 END) / COUNT(*), s.user_id
+		LIMIT ? OFFSET ?`
+	args = append(args, threshold, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*models.AttendanceLowRate
+	for rows.Next() {
+		item := &models.AttendanceLowRate{}
+		var middleName sql.NullString
+		if err := rows.Scan(
+			&item.StudentID, &item.FirstName, &item.LastName, &middleName, &item.StudentGroupID, &item.Present, &item.Total,
+		); err != nil {
+			return nil, err
+		}
+		if middleName.Valid {
+			item.MiddleName = &middleName.String
+		}
+		item.Rate = float64(item.Present) / float64(item.Total)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func truncateToDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetAttendanceSummary_ScopesByClassDate(t *testing.T) {
+	var c queryCapture
+	repo := NewAttendanceRepository(newFakeDB(t, c.db(t)))
+
+	// время суток у границ не должно сдвигать период
+	from := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+	if _, err := repo.GetAttendanceSummary(context.Background(), 7, from, to); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(c.query, "a.class_date BETWEEN ? AND ?") {
+		t.Fatalf("query does not scope by class_date: %s", c.query)
+	}
+	if want := []driver.Value{int64(7), "2024-09-01", "2024-12-31"}; !reflect.DeepEqual(c.args, want) {
+		t.Fatalf("args = %v, want %v", c.args, want)
+	}
+}
+
+func TestListAttendanceWithFilters_AfterIDIgnoresOffset(t *testing.T) {
+	student, after := int64(7), int64(40)
+	tests := []struct {
+		name     string
+		afterID  *int64
+		wantTail string
+		wantArgs []driver.Value
+	}{
+		{"keyset", &after, "AND a.attendance_id > ? ORDER BY a.attendance_id LIMIT ?", []driver.Value{int64(7), int64(40), int64(20)}},
+		{"offset", nil, "ORDER BY a.attendance_id LIMIT ? OFFSET ?", []driver.Value{int64(7), int64(20), int64(60)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c queryCapture
+			repo := NewAttendanceRepository(newFakeDB(t, c.db(t)))
+			if _, err := repo.ListAttendanceWithFilters(context.Background(), &student, nil, nil, nil, nil, tt.afterID, 20, 60); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(c.query, tt.wantTail) {
+				t.Errorf("query = %s, want suffix %q", c.query, tt.wantTail)
+			}
+			if !reflect.DeepEqual(c.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", c.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestListAttendanceWithFilters_AcademicYear(t *testing.T) {
+	student, discipline, year := int64(7), int64(3), int64(2)
+	date := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+	const join = "FROM attendance a INNER JOIN discipline d ON a.discipline_id = d.discipline_id INNER JOIN student_group sg ON d.student_group_id = sg.student_group_id WHERE 1=1"
+
+	tests := []struct {
+		name                string
+		student, discipline *int64
+		year                *int64
+		date                *time.Time
+		wantJoin            bool
+		wantWhere           string
+		wantArgs            []driver.Value
+	}{
+		{
+			name:      "year only",
+			year:      &year,
+			wantJoin:  true,
+			wantWhere: "AND sg.academic_year_id = ?",
+			wantArgs:  []driver.Value{int64(2), int64(20), int64(0)},
+		},
+		{
+			name:    "combined with other filters",
+			student: &student, discipline: &discipline, year: &year, date: &date,
+			wantJoin:  true,
+			wantWhere: "AND a.student_id = ? AND a.discipline_id = ? AND sg.academic_year_id = ? AND a.class_date = ?",
+			wantArgs:  []driver.Value{int64(7), int64(3), int64(2), "2024-09-02", int64(20), int64(0)},
+		},
+		{
+			// без учебного года join не нужен
+			name:      "without year",
+			student:   &student,
+			wantWhere: "AND a.student_id = ?",
+			wantArgs:  []driver.Value{int64(7), int64(20), int64(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c queryCapture
+			repo := NewAttendanceRepository(newFakeDB(t, c.db(t)))
+			if _, err := repo.ListAttendanceWithFilters(context.Background(), tt.student, tt.discipline, tt.year, tt.date, nil, nil, 20, 0); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(c.query, join); got != tt.wantJoin {
+				t.Errorf("query = %s, want join %v", c.query, tt.wantJoin)
+			}
+			if c.where() != tt.wantWhere {
+				t.Errorf("where = %q, want %q", c.where(), tt.wantWhere)
+			}
+			if !reflect.DeepEqual(c.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", c.args, tt.wantArgs)
+			}
+		})
+	}
+
+	// total в заголовке считается по тому же join, иначе он разойдётся со страницей
+	var countQuery string
+	f := &fakeDB{onQuery: func(query string, _ []driver.NamedValue) (driver.Rows, error) {
+		countQuery = compactSQL(query)
+		return &fakeRows{cols: []string{"count"}, vals: [][]driver.Value{{int64(5)}}}, nil
+	}}
+	total, err := NewAttendanceRepository(newFakeDB(t, f)).CountAttendanceWithFilters(context.Background(), nil, nil, &year, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 || !strings.Contains(countQuery, join+" AND sg.academic_year_id = ?") {
+		t.Errorf("total = %d, query = %s", total, countQuery)
+	}
+}
+
+func TestListLowAttendance(t *testing.T) {
+	var query string
+	var args []driver.Value
+	db := newFakeDB(t, &fakeDB{onQuery: func(q string, a []driver.NamedValue) (driver.Rows, error) {
+		query = compactSQL(q)
+		for _, v := range a {
+			args = append(args, v.Value)
+		}
+		return &fakeRows{
+			cols: []string{"user_id", "first_name", "last_name", "middle_name", "student_group_id", "present", "total"},
+			vals: [][]driver.Value{{int64(7), "Иван", "Иванов", nil, int64(2), int64(1), int64(4)}},
+		}, nil
+	}})
+
+	from := time.Date(2024, 9, 1, 15, 0, 0, 0, time.UTC)
+	items, err := NewAttendanceRepository(db).ListLowAttendance(context.Background(), 0.75, &from, nil, 20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Rate != 0.25 || items[0].MiddleName != nil {
+		t.Fatalf("items = %+v, want one with rate 0.25", items)
+	}
+	// порог строгий, сначала худшие
+	if !strings.Contains(query, "HAVING SUM(CASE WHEN a.visit THEN 1 ELSE 0 END) / COUNT(*) < ?") ||
+		!strings.Contains(query, "ORDER BY SUM(CASE WHEN a.visit THEN 1 ELSE 0 END) / COUNT(*), s.user_id") {
+		t.Errorf("query = %s", query)
+	}
+	if strings.Contains(query, "a.class_date <= ?") {
+		t.Errorf("query filters by to_date without it: %s", query)
+	}
+	if want := []driver.Value{"2024-09-01", 0.75, int64(20), int64(0)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestAttendance_ClassDateDefault(t *testing.T) {
+	var args []driver.Value
+	f := &fakeDB{onExec: func(_ string, a []driver.NamedValue) (driver.Result, error) {
+		args = nil
+		for _, v := range a {
+			args = append(args, v.Value)
+		}
+		return fakeResult{id: 1, affected: 1}, nil
+	}}
+	repo := NewAttendanceRepository(newFakeDB(t, f))
+
+	// без даты — день создания отметки, время отбрасывается
+	a := &models.Attendance{StudentID: 7, DisciplineID: 3}
+	if err := repo.CreateAttendance(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	if want := truncateToDate(a.CreatedAt); !a.ClassDate.Equal(want) || args[2] != want {
+		t.Errorf("class_date = %v (arg %v), want %v", a.ClassDate, args[2], want)
+	}
+
+	backfilled := &models.Attendance{StudentID: 7, DisciplineID: 3, ClassDate: time.Date(2024, 9, 2, 15, 30, 0, 0, time.UTC)}
+	if err := repo.CreateAttendance(context.Background(), backfilled); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC); args[2] != want {
+		t.Errorf("class_date arg = %v, want %v", args[2], want)
+	}
+
+	// при обновлении пустая дата не меняет сохранённую
+	if err := repo.UpdateAttendance(context.Background(), &models.Attendance{AttendanceID: 1, StudentID: 7, DisciplineID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if args[1] != nil {
+		t.Errorf("update class_date arg = %v, want NULL for COALESCE", args[1])
+	}
+}
@@ -4,28 +4,111 @@ import (
 	"context"
 	"database/sql"
 	"service/internal/domain/models"
+	"service/internal/lib/utils"
+	"time"
 )
 
 type AuditLogRepository struct {
 	db *sql.DB
+	// storeSnapshots хранить old_data/new_data и для записей, у которых есть changes
+	storeSnapshots bool
 }
 
-func NewAuditLogRepository(db *sql.DB) *AuditLogRepository {
-	return &AuditLogRepository{db: db}
+func NewAuditLogRepository(db *sql.DB, storeSnapshots bool) *AuditLogRepository {
+	return &AuditLogRepository{db: db, storeSnapshots: storeSnapshots}
 }
 
+// AddAuditLog сохраняет запись аудита. Если RequestID не задан, он берётся из контекста запроса.
+// При выключенном storeSnapshots у записей с changes полные снимки old_data/new_data не сохраняются.
 func (r *AuditLogRepository) AddAuditLog(ctx context.Context, entry *models.AuditLog) error {
-	query := `INSERT INTO audit_log (user_id, table_name, row_id, action_type, old_data, new_data, comment)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if entry.RequestID == nil {
+		entry.RequestID = utils.GetRequestIDFromContext(ctx)
+	}
+	if !r.storeSnapshots && entry.Changes != nil {
+		entry.OldData = nil
+		entry.NewData = nil
+	}
+	query := `INSERT INTO audit_log (user_id, table_name, row_id, student_id, action_type, old_data, new_data, changes, comment, request_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	_, err := r.db.ExecContext(ctx, query,
-		entry.UserID, entry.TableName, entry.RowID, entry.ActionType, entry.OldData, entry.NewData, entry.Comment)
+		entry.UserID, entry.TableName, entry.RowID, entry.StudentID, entry.ActionType, entry.OldData, entry.NewData, entry.Changes, entry.Comment, entry.RequestID)
 	return err
 }
 
-func (r *AuditLogRepository) ListAuditLogs(ctx context.Context, limit, offset int) ([]*models.AuditLog, error) {
-	query := `SELECT audit_id, created_at, user_id, table_name, row_id, action_type, old_data, new_data, comment
-		FROM audit_log ORDER BY created_at DESC LIMIT ? OFFSET ?`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+// auditActionCondition фильтр по типу действия, общий для ListAuditLogs и StreamAuditLogs
+func auditActionCondition(actionType *string) (string, []interface{}) {
+	if actionType == nil {
+		return "", nil
+	}
+	if *actionType == models.AuditActionRename {
+		return " AND action_type = 'UPDATE' AND table_name = 'discipline' AND JSON_EXTRACT(changes, '$.discipline_name') IS NOT NULL", nil
+	}
+	return " AND action_type = ?", []interface{}{*actionType}
+}
+
+func (r *AuditLogRepository) ListAuditLogs(ctx context.Context, actionType *string, limit, offset int) ([]*models.AuditLog, error) {
+	where, args := auditActionCondition(actionType)
+	query := `SELECT audit_id, created_at, user_id, table_name, row_id, student_id, action_type, old_data, new_data, changes, comment, request_id
+		FROM audit_log WHERE 1=1` + where
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	return r.queryAuditLogs(ctx, query, args...)
+}
+
+// StreamAuditLogs передаёт записи в fn по одной по мере чтения из БД, от старых к новым.
+// fromDate — курсор: записи с created_at не раньше него. Ошибка fn прерывает чтение.
+func (r *AuditLogRepository) StreamAuditLogs(
+	ctx context.Context,
+	actionType *string,
+	fromDate *time.Time,
+	fn func(*models.AuditLog) error,
+) error {
+	where, args := auditActionCondition(actionType)
+	query := `SELECT audit_id, created_at, user_id, table_name, row_id, student_id, action_type, old_data, new_data, changes, comment, request_id
+		FROM audit_log WHERE 1=1` + where
+	if fromDate != nil {
+		query += " AND created_at >= ?"
+		args = append(args, *fromDate)
+	}
+	query += " ORDER BY created_at, audit_id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.AuditLog
+		err := rows.Scan(
+			&a.AuditID, &a.CreatedAt, &a.UserID, &a.TableName, &a.RowID, &a.StudentID,
+			&a.ActionType, &a.OldData, &a.NewData, &a.Changes, &a.Comment, &a.RequestID,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(&a); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ListGradeAuditByStudent записи аудита журнала оценок одного студента.
+// Студент хранится в отдельной колонке student_id, поэтому история сохраняется
+// и для удалённых оценок, и при выключенных снимках old_data/new_data.
+func (r *AuditLogRepository) ListGradeAuditByStudent(ctx context.Context, studentID int64, limit, offset int) ([]*models.AuditLog, error) {
+	query := `SELECT audit_id, created_at, user_id, table_name, row_id, student_id, action_type, old_data, new_data, changes, comment, request_id
+		FROM audit_log
+		WHERE table_name = 'grade_journal'
+			AND student_id = ?
+		ORDER BY created_at DESC, audit_id DESC LIMIT ? OFFSET ?`
+	return r.queryAuditLogs(ctx, query, studentID, limit, offset)
+}
+
+func (r *AuditLogRepository) queryAuditLogs(ctx context.Context, query string, args ...interface{}) ([]*models.AuditLog, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -35,8 +118,8 @@ func (r *AuditLogRepository) ListAuditLogs(ctx context.Context, limit, offset in
 	for rows.Next() {
 		var a models.AuditLog
 		err := rows.Scan(
-			&a.AuditID, &a.CreatedAt, &a.UserID, &a.TableName, &a.RowID,
-			&a.ActionType, &a.OldData, &a.NewData, &a.Comment,
+			&a.AuditID, &a.CreatedAt, &a.UserID, &a.TableName, &a.RowID, &a.StudentID,
+			&a.ActionType, &a.OldData, &a.NewData, &a.Changes, &a.Comment, &a.RequestID,
 		)
 		if err != nil {
 			return nil, err
@@ -45,3 +128,11 @@ func (r *AuditLogRepository) ListAuditLogs(ctx context.Context, limit, offset in
 	}
 	return result, nil
 }
+
+func (r *AuditLogRepository) PurgeAuditLogsOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM audit_log WHERE created_at < ?`, t)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"service/internal/domain/models"
+	"service/internal/lib/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestAuditActionCondition_RenameOnlyMatchesDisciplines(t *testing.T) {
+	rename := models.AuditActionRename
+	where, args := auditActionCondition(&rename)
+	if len(args) != 0 {
+		t.Fatalf("unexpected args %v", args)
+	}
+	for _, want := range []string{"action_type = 'UPDATE'", "table_name = 'discipline'", "$.discipline_name"} {
+		if !strings.Contains(where, want) {
+			t.Fatalf("condition %q does not contain %q", where, want)
+		}
+	}
+}
+
+func TestAuditActionCondition_PlainAction(t *testing.T) {
+	action := "DELETE"
+	where, args := auditActionCondition(&action)
+	if where != " AND action_type = ?" || len(args) != 1 || args[0] != "DELETE" {
+		t.Fatalf("got %q %v", where, args)
+	}
+	if where, args := auditActionCondition(nil); where != "" || args != nil {
+		t.Fatalf("nil action: got %q %v", where, args)
+	}
+}
+
+func TestAddAuditLog_StoreSnapshots(t *testing.T) {
+	old, changes := utils.PtrToStr(`{"a":1}`), utils.PtrToStr(`{"a":{"old":1,"new":2}}`)
+	tests := []struct {
+		name           string
+		storeSnapshots bool
+		changes        *string
+		wantSnapshots  bool
+	}{
+		{"snapshots on", true, changes, true},
+		{"snapshots off with changes", false, changes, false},
+		{"snapshots off without changes", false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []driver.NamedValue
+			db := newFakeDB(t, &fakeDB{onExec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+				got = args
+				return fakeResult{id: 1, affected: 1}, nil
+			}})
+			repo := NewAuditLogRepository(db, tt.storeSnapshots)
+			err := repo.AddAuditLog(context.Background(), &models.AuditLog{
+				TableName: "student", RowID: 1, ActionType: "UPDATE",
+				OldData: old, NewData: old, Changes: tt.changes,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// old_data и new_data — 6-й и 7-й параметры INSERT
+			hasSnapshots := got[5].Value != nil && got[6].Value != nil
+			if hasSnapshots != tt.wantSnapshots {
+				t.Fatalf("snapshots stored = %v, want %v (args %v)", hasSnapshots, tt.wantSnapshots, got)
+			}
+		})
+	}
+}
+
+func TestAddAuditLog_RequestID(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		entry string
+		want  driver.Value
+	}{
+		{"from request context", context.WithValue(context.Background(), middleware.RequestIDKey, "api-1/kX2r-000042"), "", "api-1/kX2r-000042"},
+		// фоновые задачи пишут аудит вне HTTP-запроса
+		{"outside request", context.Background(), "", nil},
+		{"explicit id wins", context.WithValue(context.Background(), middleware.RequestIDKey, "api-1/kX2r-000042"), "retention", "retention"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []driver.NamedValue
+			db := newFakeDB(t, &fakeDB{onExec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+				got = args
+				return fakeResult{id: 1, affected: 1}, nil
+			}})
+			entry := &models.AuditLog{TableName: "student", RowID: 1, ActionType: "DELETE"}
+			if tt.entry != "" {
+				entry.RequestID = utils.PtrToStr(tt.entry)
+			}
+			if err := NewAuditLogRepository(db, true).AddAuditLog(tt.ctx, entry); err != nil {
+				t.Fatal(err)
+			}
+			// request_id — последний параметр INSERT
+			if v := got[len(got)-1].Value; v != tt.want {
+				t.Errorf("request_id arg = %v, want %v", v, tt.want)
+			}
+		})
+	}
+
+	// сохранённый id читается обратно в списке
+	db := newFakeDB(t, &fakeDB{onQuery: func(string, []driver.NamedValue) (driver.Rows, error) {
+		return &fakeRows{
+			cols: []string{"audit_id", "created_at", "user_id", "table_name", "row_id", "student_id",
+				"action_type", "old_data", "new_data", "changes", "comment", "request_id"},
+			vals: [][]driver.Value{{int64(1), time.Now(), int64(2), "student", int64(1), nil, "DELETE", nil, nil, nil, nil, "api-1/kX2r-000042"}},
+		}, nil
+	}})
+	logs, err := NewAuditLogRepository(db, true).ListAuditLogs(context.Background(), nil, 50, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].RequestID == nil || *logs[0].RequestID != "api-1/kX2r-000042" {
+		t.Fatalf("logs = %+v, want request id read back", logs)
+	}
+}
+
+func TestListGradeAuditByStudent_FiltersByStudentColumn(t *testing.T) {
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	// записи без снимков old_data/new_data: store_snapshots=false
+	seeded := [][]driver.Value{
+		{int64(1), created, int64(2), "grade_journal", int64(10), int64(7), "INSERT", nil, nil, nil, nil, nil},
+		{int64(2), created, int64(2), "grade_journal", int64(11), int64(8), "INSERT", nil, nil, nil, nil, nil},
+		{int64(3), created, int64(2), "grade_journal", int64(10), int64(7), "UPDATE", nil, nil, []byte(`{"grade_value":{"old":"4","new":"5"}}`), nil, nil},
+	}
+	var query string
+	db := newFakeDB(t, &fakeDB{onQuery: func(q string, args []driver.NamedValue) (driver.Rows, error) {
+		query = compactSQL(q)
+		rows := &fakeRows{cols: []string{"audit_id", "created_at", "user_id", "table_name", "row_id", "student_id",
+			"action_type", "old_data", "new_data", "changes", "comment", "request_id"}}
+		for _, v := range seeded {
+			if v[5] == args[0].Value {
+				rows.vals = append(rows.vals, v)
+			}
+		}
+		return rows, nil
+	}})
+
+	got, err := NewAuditLogRepository(db, false).ListGradeAuditByStudent(context.Background(), 7, 50, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(query, "student_id = ?") || strings.Contains(query, "JSON_EXTRACT") {
+		t.Fatalf("unexpected query %q", query)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got))
+	}
+	for _, a := range got {
+		if a.StudentID == nil || *a.StudentID != 7 || a.RowID != 10 {
+			t.Fatalf("entry %d belongs to another student: %+v", a.AuditID, a)
+		}
+	}
+}
+
+func TestPurgeAuditLogsOlderThan_DeletesOnlyOldRows(t *testing.T) {
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rows := map[int64]time.Time{
+		1: cutoff.Add(-30 * 24 * time.Hour),
+		2: cutoff.Add(-time.Second),
+		3: cutoff,
+		4: cutoff.Add(time.Hour),
+	}
+	f := &fakeDB{onExec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+		if !strings.Contains(compactSQL(query), "DELETE FROM audit_log WHERE created_at < ?") {
+			t.Fatalf("unexpected query: %s", query)
+		}
+		before := args[0].Value.(time.Time)
+		var n int64
+		for id, created := range rows {
+			if created.Before(before) {
+				delete(rows, id)
+				n++
+			}
+		}
+		return fakeResult{affected: n}, nil
+	}}
+
+	n, err := NewAuditLogRepository(newFakeDB(t, f), false).PurgeAuditLogsOlderThan(context.Background(), cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("purged %d rows, want 2", n)
+	}
+	// запись ровно на границе остаётся
+	if _, ok := rows[3]; !ok || len(rows) != 2 {
+		t.Errorf("remaining rows = %v, want 3 and 4", rows)
+	}
+}
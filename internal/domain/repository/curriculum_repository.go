@@ -29,10 +29,10 @@ func (r *curriculumRepository) CreateCurriculum(ctx context.Context, c *models.C
 		INSERT INTO curriculum (created_at, updated_at, subject_name, subject_description, semester_id, discipline_id)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	c.CreatedAt = now
-	c.UpdateAt = now
-	res, err := r.db.ExecContext(ctx, query, c.CreatedAt, c.UpdateAt, c.SubjectName, c.SubjectDescription, c.SemesterID, c.DisciplineID)
+	c.UpdatedAt = now
+	res, err := r.db.ExecContext(ctx, query, c.CreatedAt, c.UpdatedAt, c.SubjectName, c.SubjectDescription, c.SemesterID, c.DisciplineID)
 	if err != nil {
 		return err
 	}
@@ -52,7 +52,7 @@ func (r *curriculumRepository) GetCurriculumByID(ctx context.Context, id int64)
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&c.CurriculumID,
 		&c.CreatedAt,
-		&c.UpdateAt,
+		&c.UpdatedAt,
 		&c.SubjectName,
 		&c.SubjectDescription,
 		&c.SemesterID,
@@ -73,7 +73,7 @@ func (r *curriculumRepository) UpdateCurriculum(ctx context.Context, c *models.C
 		SET updated_at = ?, subject_name = ?, subject_description = ?, semester_id = ?, discipline_id = ?
 		WHERE curriculum_id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, time.Now(), c.SubjectName, c.SubjectDescription, c.SemesterID, c.DisciplineID, c.CurriculumID)
+	_, err := r.db.ExecContext(ctx, query, time.Now().UTC(), c.SubjectName, c.SubjectDescription, c.SemesterID, c.DisciplineID, c.CurriculumID)
 	return err
 }
 
@@ -113,7 +113,7 @@ func (r *curriculumRepository) ListCurriculum(
 		err := rows.Scan(
 			&c.CurriculumID,
 			&c.CreatedAt,
-			&c.UpdateAt,
+			&c.UpdatedAt,
 			&c.SubjectName,
 			&c.SubjectDescription,
 			&c.SemesterID,
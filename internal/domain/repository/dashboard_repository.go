@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"service/internal/domain/models"
+	"sync"
+	"time"
+)
+
+type dashboardRepository struct {
+	db *sql.DB
+}
+
+func NewDashboardRepository(db *sql.DB) *dashboardRepository {
+	return &dashboardRepository{db: db}
+}
+
+// GetDashboardMetrics выполняет count-запросы параллельно и собирает результат
+func (r *dashboardRepository) GetDashboardMetrics(ctx context.Context, weekStart time.Time) (*models.DashboardMetrics, error) {
+	m := &models.DashboardMetrics{}
+	counts := []struct {
+		query string
+		args  []interface{}
+		dest  *int64
+	}{
+		{query: `SELECT COUNT(*) FROM user`, dest: &m.Users},
+		{query: `SELECT COUNT(*) FROM teacher`, dest: &m.Teachers},
+		{query: `SELECT COUNT(*) FROM student`, dest: &m.Students},
+		{query: `SELECT COUNT(*) FROM student_group`, dest: &m.StudentGroups},
+		{query: `SELECT COUNT(*) FROM discipline`, dest: &m.Disciplines},
+		{query: `SELECT COUNT(*) FROM grade_journal WHERE created_at >= ?`, args: []interface{}{weekStart}, dest: &m.GradesThisWeek},
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, c := range counts {
+		wg.Add(1)
+		go func(query string, args []interface{}, dest *int64) {
+			defer wg.Done()
+			if err := r.db.QueryRowContext(ctx, query, args...).Scan(dest); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(c.query, c.args, c.dest)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return m, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// seededCounts отвечает на COUNT(*) по засеянным таблицам; у grade_journal учитывается
+// фильтр created_at >= ?
+func seededCounts(t *testing.T, tables map[string]int64, grades []time.Time) func(string, []driver.NamedValue) (driver.Rows, error) {
+	return func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		q := compactSQL(query)
+		table := strings.Fields(q[strings.Index(q, "FROM ")+len("FROM "):])[0]
+		var n int64
+		if table == "grade_journal" {
+			since := args[0].Value.(time.Time)
+			for _, created := range grades {
+				if !created.Before(since) {
+					n++
+				}
+			}
+		} else {
+			count, ok := tables[table]
+			if !ok {
+				t.Errorf("unexpected table %q", table)
+			}
+			n = count
+		}
+		return &fakeRows{cols: []string{"COUNT(*)"}, vals: [][]driver.Value{{n}}}, nil
+	}
+}
+
+func TestGetDashboardMetrics_CountsSeededData(t *testing.T) {
+	weekStart := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	tables := map[string]int64{"user": 12, "teacher": 3, "student": 8, "student_group": 2, "discipline": 5}
+	grades := []time.Time{
+		weekStart.Add(-time.Second), // прошлая неделя
+		weekStart,
+		weekStart.Add(48 * time.Hour),
+	}
+	db := newFakeDB(t, &fakeDB{onQuery: seededCounts(t, tables, grades)})
+
+	got, err := NewDashboardRepository(db).GetDashboardMetrics(context.Background(), weekStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.DashboardMetrics{Users: 12, Teachers: 3, Students: 8, StudentGroups: 2, Disciplines: 5, GradesThisWeek: 2}
+	if *got != want {
+		t.Fatalf("metrics = %+v, want %+v", *got, want)
+	}
+}
+
+func TestGetDashboardMetrics_QueryError(t *testing.T) {
+	queryErr := errors.New("table is locked")
+	db := newFakeDB(t, &fakeDB{onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		if strings.Contains(query, "FROM discipline") {
+			return nil, queryErr
+		}
+		return &fakeRows{cols: []string{"COUNT(*)"}, vals: [][]driver.Value{{int64(1)}}}, nil
+	}})
+	if _, err := NewDashboardRepository(db).GetDashboardMetrics(context.Background(), time.Now()); !errors.Is(err, queryErr) {
+		t.Fatalf("err = %v, want %v", err, queryErr)
+	}
+}
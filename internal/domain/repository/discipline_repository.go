@@ -22,11 +22,14 @@ func (r *disciplineRepository) CreateDiscipline(ctx context.Context, d *models.D
 		INSERT INTO discipline (discipline_name, teacher_id, student_group_id, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	d.CreatedAt = now
-	d.UpdateAt = now
+	d.UpdatedAt = now
 
-	res, err := r.db.ExecContext(ctx, query, d.DisciplineName, d.TeacherID, d.StudentGroupID, d.CreatedAt, d.UpdateAt)
+	res, err := r.db.ExecContext(ctx, query, d.DisciplineName, d.TeacherID, d.StudentGroupID, d.CreatedAt, d.UpdatedAt)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	if err != nil {
 		return err
 	}
@@ -47,7 +50,7 @@ func (r *disciplineRepository) GetDisciplineByID(ctx context.Context, id int64)
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&d.DisciplineID,
 		&d.CreatedAt,
-		&d.UpdateAt,
+		&d.UpdatedAt,
 		&d.DisciplineName,
 		&d.TeacherID,
 		&d.StudentGroupID,
@@ -67,24 +70,66 @@ func (r *disciplineRepository) UpdateDiscipline(ctx context.Context, d *models.D
 		SET discipline_name = ?, teacher_id = ?, student_group_id = ?, updated_at = ?
 		WHERE discipline_id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, d.DisciplineName, d.TeacherID, d.StudentGroupID, time.Now(), d.DisciplineID)
+	_, err := r.db.ExecContext(ctx, query, d.DisciplineName, d.TeacherID, d.StudentGroupID, time.Now().UTC(), d.DisciplineID)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
 func (r *disciplineRepository) DeleteDiscipline(ctx context.Context, id int64) error {
 	query := `DELETE FROM discipline WHERE discipline_id = ?`
 	_, err := r.db.ExecContext(ctx, query, id)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
-func (r *disciplineRepository) ListDiscipline(ctx context.Context, limit, offset int) ([]*models.Discipline, error) {
+func (r *disciplineRepository) ListDiscipline(
+	ctx context.Context,
+	teacherID, studentGroupID, academicYearID *int64,
+	updatedSince *time.Time,
+	hasGrades *bool,
+	limit, offset int,
+) ([]*models.Discipline, error) {
 	query := `
-		SELECT discipline_id, created_at, updated_at, discipline_name, teacher_id, student_group_id
-		FROM discipline
-		ORDER BY discipline_id
-		LIMIT ? OFFSET ?
+		SELECT d.discipline_id, d.created_at, d.updated_at, d.discipline_name, d.teacher_id, d.student_group_id
+		FROM discipline d
 	`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	var args []interface{}
+	// учебный год хранится у группы, join нужен только для этого фильтра
+	if academicYearID != nil {
+		query += " JOIN student_group sg ON d.student_group_id = sg.student_group_id"
+	}
+	query += " WHERE 1=1"
+	if teacherID != nil {
+		query += " AND d.teacher_id = ?"
+		args = append(args, *teacherID)
+	}
+	if studentGroupID != nil {
+		query += " AND d.student_group_id = ?"
+		args = append(args, *studentGroupID)
+	}
+	if academicYearID != nil {
+		query += " AND sg.academic_year_id = ?"
+		args = append(args, *academicYearID)
+	}
+	if updatedSince != nil {
+		query += " AND d.updated_at >= ?"
+		args = append(args, *updatedSince)
+	}
+	if hasGrades != nil {
+		exists := " AND EXISTS (SELECT 1 FROM grade_journal gj WHERE gj.discipline_id = d.discipline_id)"
+		if !*hasGrades {
+			exists = " AND NOT EXISTS (SELECT 1 FROM grade_journal gj WHERE gj.discipline_id = d.discipline_id)"
+		}
+		query += exists
+	}
+	query += " ORDER BY d.discipline_id LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +141,7 @@ func (r *disciplineRepository) ListDiscipline(ctx context.Context, limit, offset
 		err := rows.Scan(
 			&d.DisciplineID,
 			&d.CreatedAt,
-			&d.UpdateAt,
+			&d.UpdatedAt,
 			&d.DisciplineName,
 			&d.TeacherID,
 			&d.StudentGroupID,
@@ -109,6 +154,80 @@ func (r *disciplineRepository) ListDiscipline(ctx context.Context, limit, offset
 	return disciplines, nil
 }
 
+// Количество дисциплин преподавателя в учебном году, к которому относится группа
+func (r *disciplineRepository) CountTeacherDisciplinesInGroupYear(ctx context.Context, teacherID, studentGroupID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM discipline d
+		JOIN student_group sg ON d.student_group_id = sg.student_group_id
+		WHERE d.teacher_id = ?
+			AND sg.academic_year_id = (
+				SELECT academic_year_id FROM student_group WHERE student_group_id = ?
+			)
+	`
+	var count int
+	err := r.db.QueryRowContext(ctx, query, teacherID, studentGroupID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *disciplineRepository) TeacherExists(ctx context.Context, teacherID int64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM teacher WHERE user_id = ?)`, teacherID).Scan(&exists)
+	return exists, err
+}
+
+func (r *disciplineRepository) StudentGroupExists(ctx context.Context, studentGroupID int64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM student_group WHERE student_group_id = ?)`, studentGroupID).Scan(&exists)
+	return exists, err
+}
+
+// ReassignTeacherDisciplines переносит все дисциплины преподавателя на другого в одной транзакции
+// и возвращает id перенесённых дисциплин
+func (r *disciplineRepository) ReassignTeacherDisciplines(ctx context.Context, fromTeacherID, toTeacherID int64) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT discipline_id FROM discipline WHERE teacher_id = ? ORDER BY discipline_id FOR UPDATE`, fromTeacherID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE discipline SET teacher_id = ?, updated_at = ? WHERE teacher_id = ?`,
+		toTeacherID, time.Now().UTC(), fromTeacherID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 // --- PUBLIC ---
 
 func (r *disciplineRepository) GetDisciplinePublicByID(ctx context.Context, id int64) (*models.DisciplinePublic, error) {
@@ -141,7 +260,7 @@ WHERE d.discipline_id = ?
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&dp.DisciplineID,
 		&dp.CreatedAt,
-		&dp.UpdateAt,
+		&dp.UpdatedAt,
 		&dp.DisciplineName,
 		&dp.TeacherID,
 		&dp.FirstName,
@@ -174,29 +293,9 @@ func (r *disciplineRepository) ListDisciplinePublic(
 	ctx context.Context,
 	limit, offset int,
 	teacherID, studentGroupID, academicYearID *int64,
+	updatedSince *time.Time,
 ) ([]*models.DisciplinePublic, error) {
-	query := `
-		SELECT
-			d.discipline_id,
-			d.created_at,
-			d.updated_at,
-			d.discipline_name,
-			d.teacher_id,
-			t.first_name,
-			t.last_name,
-			t.middle_name,
-			d.student_group_id,
-			sg.student_group_name,
-			sg.curator_id,
-			c.first_name AS curator_first_name,
-			c.last_name AS curator_last_name,
-			c.middle_name AS curator_middle_name,
-			sg.academic_year_id
-		FROM discipline d
-		JOIN user t ON d.teacher_id = t.user_id
-		JOIN student_group sg ON d.student_group_id = sg.student_group_id
-		JOIN user c ON sg.curator_id = c.user_id
-		`
+	query := disciplinePublicSelect
 	var (
 		where []string
 		args  []interface{}
@@ -214,6 +313,10 @@ func (r *disciplineRepository) ListDisciplinePublic(
 		where = append(where, "sg.academic_year_id = ?")
 		args = append(args, *academicYearID)
 	}
+	if updatedSince != nil {
+		where = append(where, "d.updated_at >= ?")
+		args = append(args, *updatedSince)
+	}
 
 	if len(where) > 0 {
 		query += " WHERE " + joinWithAnd(where)
@@ -227,6 +330,46 @@ func (r *disciplineRepository) ListDisciplinePublic(
 	}
 	defer rows.Close()
 
+	return scanDisciplinePublicRows(rows)
+}
+
+const disciplinePublicSelect = `
+		SELECT
+			d.discipline_id,
+			d.created_at,
+			d.updated_at,
+			d.discipline_name,
+			d.teacher_id,
+			t.first_name,
+			t.last_name,
+			t.middle_name,
+			d.student_group_id,
+			sg.student_group_name,
+			sg.curator_id,
+			c.first_name AS curator_first_name,
+			c.last_name AS curator_last_name,
+			c.middle_name AS curator_middle_name,
+			sg.academic_year_id
+		FROM discipline d
+		JOIN user t ON d.teacher_id = t.user_id
+		JOIN student_group sg ON d.student_group_id = sg.student_group_id
+		JOIN user c ON sg.curator_id = c.user_id
+		`
+
+// SearchDisciplinePublic ищет дисциплины по подстроке в названии.
+// Символы %, _ и \ в запросе экранируются и ищутся буквально.
+func (r *disciplineRepository) SearchDisciplinePublic(ctx context.Context, q string, limit, offset int) ([]*models.DisciplinePublic, error) {
+	query := disciplinePublicSelect + ` WHERE d.discipline_name LIKE ? ESCAPE '\\' ORDER BY d.discipline_name, d.discipline_id LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, "%"+escapeLike(q)+"%", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDisciplinePublicRows(rows)
+}
+
+func scanDisciplinePublicRows(rows *sql.Rows) ([]*models.DisciplinePublic, error) {
 	var disciplines []*models.DisciplinePublic
 	for rows.Next() {
 		dp := &models.DisciplinePublic{}
@@ -234,7 +377,7 @@ func (r *disciplineRepository) ListDisciplinePublic(
 		err := rows.Scan(
 			&dp.DisciplineID,
 			&dp.CreatedAt,
-			&dp.UpdateAt,
+			&dp.UpdatedAt,
 			&dp.DisciplineName,
 			&dp.TeacherID,
 			&dp.FirstName,
@@ -259,9 +402,75 @@ func (r *disciplineRepository) ListDisciplinePublic(
 		}
 		disciplines = append(disciplines, dp)
 	}
-	return disciplines, nil
+	return disciplines, rows.Err()
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы пользовательский ввод искался буквально
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
 }
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func joinWithAnd(conds []string) string {
 	return strings.Join(conds, " AND ")
 }
+
+// CountCurriculumByDisciplines число пунктов учебного плана по дисциплинам одним запросом.
+// Дисциплины без учебного плана в результат не попадают.
+func (r *disciplineRepository) CountCurriculumByDisciplines(ctx context.Context, disciplineIDs []int64) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(disciplineIDs))
+	if len(disciplineIDs) == 0 {
+		return counts, nil
+	}
+	placeholders, args := inClause(disciplineIDs)
+	query := `
+		SELECT discipline_id, COUNT(*)
+		FROM curriculum
+		WHERE discipline_id IN (` + placeholders + `)
+		GROUP BY discipline_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, count int64
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		counts[id] = count
+	}
+	return counts, rows.Err()
+}
+
+// inClause строит плейсхолдеры "?, ?, ?" и аргументы для IN (...)
+func inClause(ids []int64) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// CountDisciplineStudents число студентов в группе дисциплины.
+// Если дисциплины нет — sql.ErrNoRows.
+func (r *disciplineRepository) CountDisciplineStudents(ctx context.Context, disciplineID int64) (*models.DisciplineStudentCount, error) {
+	query := `
+		SELECT d.discipline_id, d.student_group_id, COUNT(s.user_id)
+		FROM discipline d
+		LEFT JOIN student s ON s.student_group_id = d.student_group_id
+		WHERE d.discipline_id = ?
+		GROUP BY d.discipline_id, d.student_group_id
+	`
+	c := &models.DisciplineStudentCount{}
+	err := r.db.QueryRowContext(ctx, query, disciplineID).Scan(&c.DisciplineID, &c.StudentGroupID, &c.StudentCount)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+)
+
+func TestReassignTeacherDisciplines_OneTransaction(t *testing.T) {
+	var updateArgs []driver.NamedValue
+	f := &fakeDB{
+		onQuery: func(string, []driver.NamedValue) (driver.Rows, error) {
+			return &fakeRows{cols: []string{"discipline_id"}, vals: [][]driver.Value{{int64(1)}, {int64(3)}}}, nil
+		},
+		onExec: func(_ string, args []driver.NamedValue) (driver.Result, error) {
+			updateArgs = args
+			return fakeResult{affected: 2}, nil
+		},
+	}
+	ids, err := NewDisciplineRepository(newFakeDB(t, f)).ReassignTeacherDisciplines(context.Background(), 10, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 3}) {
+		t.Fatalf("ids = %v", ids)
+	}
+
+	log := f.entries()
+	want := []string{"BEGIN", "SELECT discipline_id FROM discipline WHERE teacher_id = ? ORDER BY discipline_id FOR UPDATE", "UPDATE", "COMMIT"}
+	if len(log) < len(want) {
+		t.Fatalf("log = %v", log)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(log[i], w) {
+			t.Fatalf("log[%d] = %q, want prefix %q (log %v)", i, log[i], w, log)
+		}
+	}
+	if updateArgs[0].Value != int64(20) || updateArgs[2].Value != int64(10) {
+		t.Errorf("update args = %v, want to=20 from=10", updateArgs)
+	}
+}
+
+func TestReassignTeacherDisciplines_NothingToMove(t *testing.T) {
+	f := &fakeDB{}
+	ids, err := NewDisciplineRepository(newFakeDB(t, f)).ReassignTeacherDisciplines(context.Background(), 10, 20)
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("ids = %v, err = %v", ids, err)
+	}
+	for _, e := range f.entries() {
+		if strings.HasPrefix(e, "UPDATE") || e == "COMMIT" {
+			t.Fatalf("unexpected %q without disciplines: %v", e, f.entries())
+		}
+	}
+}
+
+func TestSearchDisciplinePublic_EscapesLike(t *testing.T) {
+	var c queryCapture
+	repo := NewDisciplineRepository(newFakeDB(t, c.db(t)))
+	if _, err := repo.SearchDisciplinePublic(context.Background(), `100%_a\b`, 20, 40); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(c.query, `d.discipline_name LIKE ? ESCAPE`) {
+		t.Fatalf("query = %s", c.query)
+	}
+	if want := []driver.Value{`%100\%\_a\\b%`, int64(20), int64(40)}; !reflect.DeepEqual(c.args, want) {
+		t.Errorf("args = %q, want %q", c.args, want)
+	}
+}
+
+func TestListDiscipline_AcademicYearJoin(t *testing.T) {
+	year, teacher := int64(3), int64(10)
+	tests := []struct {
+		name     string
+		year     *int64
+		wantJoin bool
+		wantArgs []driver.Value
+	}{
+		{"with year", &year, true, []driver.Value{int64(10), int64(3), int64(20), int64(0)}},
+		{"without year", nil, false, []driver.Value{int64(10), int64(20), int64(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c queryCapture
+			repo := NewDisciplineRepository(newFakeDB(t, c.db(t)))
+			if _, err := repo.ListDiscipline(context.Background(), &teacher, nil, tt.year, nil, nil, 20, 0); err != nil {
+				t.Fatal(err)
+			}
+			// год хранится у группы, join добавляется только под фильтр
+			if hasJoin := strings.Contains(c.query, "JOIN student_group sg ON d.student_group_id = sg.student_group_id"); hasJoin != tt.wantJoin {
+				t.Errorf("join = %v, want %v: %s", hasJoin, tt.wantJoin, c.query)
+			}
+			if tt.wantJoin && !strings.Contains(c.query, "AND sg.academic_year_id = ?") {
+				t.Errorf("query does not filter by year: %s", c.query)
+			}
+			if !reflect.DeepEqual(c.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", c.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCountDisciplineStudents(t *testing.T) {
+	var query string
+	db := newFakeDB(t, &fakeDB{onQuery: func(q string, args []driver.NamedValue) (driver.Rows, error) {
+		query = compactSQL(q)
+		rows := &fakeRows{cols: []string{"discipline_id", "student_group_id", "count"}}
+		if args[0].Value == int64(5) {
+			rows.vals = [][]driver.Value{{int64(5), int64(2), int64(0)}}
+		}
+		return rows, nil
+	}})
+	repo := NewDisciplineRepository(db)
+
+	got, err := repo.CountDisciplineStudents(context.Background(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&models.DisciplineStudentCount{DisciplineID: 5, StudentGroupID: 2}); !reflect.DeepEqual(got, want) {
+		t.Errorf("count = %+v, want %+v", got, want)
+	}
+	// пустая группа даёт строку с нулём благодаря LEFT JOIN
+	if !strings.Contains(query, "LEFT JOIN student s") || !strings.Contains(query, "COUNT(s.user_id)") {
+		t.Errorf("query = %s", query)
+	}
+
+	if _, err := repo.CountDisciplineStudents(context.Background(), 404); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing discipline: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestListDiscipline_HasGrades(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name      string
+		hasGrades *bool
+		wantWhere string
+	}{
+		{"with grades", &yes, "AND EXISTS (SELECT 1 FROM grade_journal gj WHERE gj.discipline_id = d.discipline_id)"},
+		{"without grades", &no, "AND NOT EXISTS (SELECT 1 FROM grade_journal gj WHERE gj.discipline_id = d.discipline_id)"},
+		{"no filter", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c queryCapture
+			repo := NewDisciplineRepository(newFakeDB(t, c.db(t)))
+			if _, err := repo.ListDiscipline(context.Background(), nil, nil, nil, nil, tt.hasGrades, 20, 0); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.where(); got != tt.wantWhere {
+				t.Errorf("where = %q, want %q", got, tt.wantWhere)
+			}
+			// фильтр без параметров: остаются только LIMIT и OFFSET
+			if want := []driver.Value{int64(20), int64(0)}; !reflect.DeepEqual(c.args, want) {
+				t.Errorf("args = %v, want %v", c.args, want)
+			}
+		})
+	}
+}
+
+func TestCountCurriculumByDisciplines(t *testing.T) {
+	var (
+		query string
+		args  []driver.Value
+	)
+	f := &fakeDB{onQuery: func(q string, named []driver.NamedValue) (driver.Rows, error) {
+		query = compactSQL(q)
+		for _, a := range named {
+			args = append(args, a.Value)
+		}
+		// дисциплина 2 без учебного плана: GROUP BY не даёт для неё строки
+		return &fakeRows{cols: []string{"discipline_id", "count"}, vals: [][]driver.Value{{int64(1), int64(3)}, {int64(3), int64(1)}}}, nil
+	}}
+	repo := NewDisciplineRepository(newFakeDB(t, f))
+
+	counts, err := repo.CountCurriculumByDisciplines(context.Background(), []int64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[int64]int64{1: 3, 3: 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+	if !strings.Contains(query, "FROM curriculum WHERE discipline_id IN (?, ?, ?) GROUP BY discipline_id") {
+		t.Errorf("query = %s", query)
+	}
+	if want := []driver.Value{int64(1), int64(2), int64(3)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+
+	// пустая страница — без запроса с пустым IN ()
+	counts, err = repo.CountCurriculumByDisciplines(context.Background(), nil)
+	if err != nil || len(counts) != 0 {
+		t.Fatalf("empty ids: counts = %v, err = %v", counts, err)
+	}
+	if log := f.entries(); len(log) != 1 {
+		t.Errorf("log = %q, want a single query", log)
+	}
+}
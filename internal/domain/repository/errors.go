@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"service/internal/storage"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// код ошибки MySQL при нарушении уникального индекса
+	mysqlErrDuplicateEntry = 1062
+	// удаление/изменение строки, на которую ссылается внешний ключ (RESTRICT)
+	mysqlErrRowIsReferenced = 1451
+	// вставка/изменение строки со ссылкой на несуществующую родительскую строку
+	mysqlErrNoReferencedRow = 1452
+)
+
+var (
+	// (`db`.`child`, CONSTRAINT ... — ссылающаяся таблица в тексте ошибок 1451/1452
+	fkChildTableRe = regexp.MustCompile("\\(`[^`]*`\\.`([^`]+)`")
+	// REFERENCES `parent` — родительская таблица
+	fkParentTableRe = regexp.MustCompile("REFERENCES `([^`]+)`")
+)
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+// foreignKeyError переводит ошибки внешних ключей MySQL в *storage.ForeignKeyError
+// с именем таблицы на другой стороне связи. Для остальных ошибок возвращает nil.
+func foreignKeyError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return nil
+	}
+	switch mysqlErr.Number {
+	case mysqlErrRowIsReferenced:
+		return &storage.ForeignKeyError{Table: submatch(fkChildTableRe, mysqlErr.Message), Err: storage.ErrReferenced}
+	case mysqlErrNoReferencedRow:
+		return &storage.ForeignKeyError{Table: submatch(fkParentTableRe, mysqlErr.Message), Err: storage.ErrMissingReference}
+	}
+	return nil
+}
+
+func submatch(re *regexp.Regexp, s string) string {
+	if m := re.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"service/internal/domain/models"
+	"service/internal/storage"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestForeignKeyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantErr   error
+		wantTable string
+	}{
+		{
+			name: "row is referenced",
+			err: &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails " +
+				"(`eduhelper`.`grade_journal`, CONSTRAINT `fk_grade_discipline` FOREIGN KEY (`discipline_id`) REFERENCES `discipline` (`discipline_id`))"},
+			wantErr:   storage.ErrReferenced,
+			wantTable: "grade_journal",
+		},
+		{
+			name: "no referenced row",
+			err: &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails " +
+				"(`eduhelper`.`discipline`, CONSTRAINT `fk_discipline_group` FOREIGN KEY (`student_group_id`) REFERENCES `student_group` (`student_group_id`))"},
+			wantErr:   storage.ErrMissingReference,
+			wantTable: "student_group",
+		},
+		{
+			name:      "unparsable message keeps the sentinel",
+			err:       &mysql.MySQLError{Number: 1451, Message: "foreign key constraint fails"},
+			wantErr:   storage.ErrReferenced,
+			wantTable: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := foreignKeyError(tt.err)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			var fkErr *storage.ForeignKeyError
+			if !errors.As(err, &fkErr) || fkErr.Table != tt.wantTable {
+				t.Fatalf("table = %q, want %q", fkErr.Table, tt.wantTable)
+			}
+		})
+	}
+}
+
+func TestForeignKeyError_IgnoresOtherErrors(t *testing.T) {
+	for _, err := range []error{
+		nil,
+		errors.New("connection refused"),
+		&mysql.MySQLError{Number: mysqlErrDuplicateEntry, Message: "Duplicate entry"},
+	} {
+		if got := foreignKeyError(err); got != nil {
+			t.Fatalf("foreignKeyError(%v) = %v, want nil", err, got)
+		}
+	}
+}
+
+func TestDeleteDiscipline_MapsForeignKeyViolation(t *testing.T) {
+	db := newFakeDB(t, &fakeDB{onExec: func(string, []driver.NamedValue) (driver.Result, error) {
+		return nil, &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails " +
+			"(`eduhelper`.`attendance`, CONSTRAINT `fk_attendance_discipline` FOREIGN KEY (`discipline_id`) REFERENCES `discipline` (`discipline_id`))"}
+	}})
+
+	err := NewDisciplineRepository(db).DeleteDiscipline(context.Background(), 1)
+	var fkErr *storage.ForeignKeyError
+	if !errors.As(err, &fkErr) || !errors.Is(err, storage.ErrReferenced) || fkErr.Table != "attendance" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestUpdateDiscipline_MapsMissingReference(t *testing.T) {
+	db := newFakeDB(t, &fakeDB{onExec: func(string, []driver.NamedValue) (driver.Result, error) {
+		return nil, &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails " +
+			"(`eduhelper`.`discipline`, CONSTRAINT `fk_discipline_teacher` FOREIGN KEY (`teacher_id`) REFERENCES `teacher` (`teacher_id`))"}
+	}})
+
+	err := NewDisciplineRepository(db).UpdateDiscipline(context.Background(), &models.Discipline{DisciplineID: 1})
+	var fkErr *storage.ForeignKeyError
+	if !errors.As(err, &fkErr) || !errors.Is(err, storage.ErrMissingReference) || fkErr.Table != "teacher" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestCreateDiscipline_MapsMissingReference(t *testing.T) {
+	db := newFakeDB(t, &fakeDB{onExec: func(string, []driver.NamedValue) (driver.Result, error) {
+		return nil, &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails " +
+			"(`eduhelper`.`discipline`, CONSTRAINT `fk_discipline_group` FOREIGN KEY (`student_group_id`) REFERENCES `student_group` (`student_group_id`))"}
+	}})
+
+	err := NewDisciplineRepository(db).CreateDiscipline(context.Background(), &models.Discipline{DisciplineName: "Физика", TeacherID: 10, StudentGroupID: 2})
+	var fkErr *storage.ForeignKeyError
+	if !errors.As(err, &fkErr) || !errors.Is(err, storage.ErrMissingReference) || fkErr.Table != "student_group" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestDeleteClient_MapsForeignKeyViolation(t *testing.T) {
+	db := newFakeDB(t, &fakeDB{onExec: func(string, []driver.NamedValue) (driver.Result, error) {
+		return nil, &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails " +
+			"(`eduhelper`.`student`, CONSTRAINT `fk_student_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`user_id`))"}
+	}})
+
+	err := NewUserRepository(db).DeleteClient(context.Background(), 1)
+	var fkErr *storage.ForeignKeyError
+	if !errors.As(err, &fkErr) || !errors.Is(err, storage.ErrReferenced) || fkErr.Table != "student" {
+		t.Fatalf("err = %v", err)
+	}
+}
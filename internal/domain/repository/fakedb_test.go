@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB минимальный драйвер database/sql для тестов репозиториев без MySQL.
+// Запросы обрабатывают onExec/onQuery, а журнал log фиксирует выполненные
+// запросы и границы транзакций (BEGIN, COMMIT, ROLLBACK).
+type fakeDB struct {
+	mu      sync.Mutex
+	log     []string
+	onExec  func(query string, args []driver.NamedValue) (driver.Result, error)
+	onQuery func(query string, args []driver.NamedValue) (driver.Rows, error)
+}
+
+func newFakeDB(t *testing.T, f *fakeDB) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(f)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func (f *fakeDB) record(s string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.log = append(f.log, s)
+}
+
+func (f *fakeDB) entries() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.log...)
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return nil, errors.New("fakedb: use sql.OpenDB") }
+
+type fakeConn struct{ db *fakeDB }
+
+// Prepare фиксирует в журнале "PREPARE <запрос>", выполнение идёт через onExec/onQuery
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.db.record("PREPARE " + compactSQL(query))
+	return &fakeStmt{conn: c, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.db.record("BEGIN")
+	return fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(compactSQL(query))
+	if c.db.onExec == nil {
+		return driver.RowsAffected(0), nil
+	}
+	return c.db.onExec(query, args)
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(compactSQL(query))
+	if c.db.onQuery == nil {
+		return &fakeRows{}, nil
+	}
+	return c.db.onQuery(query, args)
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	s.conn.db.record("CLOSE " + compactSQL(s.query))
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("fakedb: use ExecContext")
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("fakedb: use QueryContext")
+}
+
+func (s *fakeStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *fakeStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+// prepared сколько раз готовился запрос, содержащий fragment
+func (f *fakeDB) prepared(fragment string) int {
+	n := 0
+	for _, e := range f.entries() {
+		if strings.HasPrefix(e, "PREPARE ") && strings.Contains(e, fragment) {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (t fakeTx) Commit() error   { t.db.record("COMMIT"); return nil }
+func (t fakeTx) Rollback() error { t.db.record("ROLLBACK"); return nil }
+
+// fakeResult результат INSERT с заданным LastInsertId
+type fakeResult struct{ id, affected int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.affected, nil }
+
+type fakeRows struct {
+	cols []string
+	vals [][]driver.Value
+	pos  int
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.vals) {
+		return io.EOF
+	}
+	copy(dest, r.vals[r.pos])
+	r.pos++
+	return nil
+}
+
+func compactSQL(q string) string {
+	return strings.Join(strings.Fields(q), " ")
+}
@@ -5,17 +5,24 @@ import (
 	"database/sql"
 	"errors"
 	"service/internal/domain/models"
+	"service/internal/storage"
+	"strings"
 	"time"
 )
 
 type GradeJournalRepository interface {
 	CreateGradeJournal(ctx context.Context, g *models.GradeJournal) error
 	GetGradeJournalByID(ctx context.Context, id int64) (*models.GradeJournal, error)
+	GetGradeJournalsByIDs(ctx context.Context, ids []int64) ([]*models.GradeJournal, error)
 	UpdateGradeJournal(ctx context.Context, g *models.GradeJournal) error
+	PatchGradeJournal(ctx context.Context, id int64, patch *models.GradeJournalPatch) error
 	DeleteGradeJournal(ctx context.Context, id int64) error
-	ListGradeJournal(ctx context.Context, studentID, disciplineID *int64, fromDate, toDate *time.Time, limit, offset int) ([]*models.GradeJournal, error)
-	ListGradeJournalPublic(ctx context.Context, studentID, disciplineID *int64, fromDate, toDate *time.Time, limit, offset int) ([]*models.GradeJournalPublic, error)
+	DeleteGradeJournalBatch(ctx context.Context, ids []int64, teacherID *int64) ([]*models.GradeJournal, error)
+	ListGradeJournal(ctx context.Context, studentID, disciplineID *int64, studentIDs []int64, fromDate, toDate, updatedSince *time.Time, afterID *int64, limit, offset int) ([]*models.GradeJournal, error)
+	ListGradeJournalPublic(ctx context.Context, studentID, disciplineID *int64, studentIDs []int64, fromDate, toDate, updatedSince *time.Time, limit, offset int) ([]*models.GradeJournalPublic, error)
 	GetAverageGrade(ctx context.Context, studentID, disciplineID *int64, fromDate, toDate *time.Time) (float64, error)
+	ListDisciplinesBelowAverage(ctx context.Context, studentID int64, threshold float64) ([]*models.DisciplineAverage, error)
+	CountGradeJournal(ctx context.Context, studentID, disciplineID *int64, studentIDs []int64, fromDate, toDate, updatedSince *time.Time) (int64, error)
 }
 
 type gradeJournalRepository struct {
@@ -31,16 +38,16 @@ func (r *gradeJournalRepository) CreateGradeJournal(ctx context.Context, g *mode
 		INSERT INTO grade_journal (created_at, updated_at, student_id, grade, comment, discipline_id)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	g.CreatedAt = now
-	g.UpdateAt = now
-	res, err := r.db.ExecContext(ctx, query, g.CreatedAt, g.UpdateAt, g.StudentID, g.Grade, g.Comment, g.DisciplineID)
+	g.UpdatedAt = now
+	res, err := r.db.ExecContext(ctx, query, g.CreatedAt, g.UpdatedAt, g.StudentID, g.Grade, g.Comment, g.DisciplineID)
 	if err != nil {
 		return err
 	}
 	id, err := res.LastInsertId()
 	if err == nil {
-		g.GradeJournalID = id
+		g.GradeJournalID = models.ID(id)
 	}
 	return err
 }
@@ -52,7 +59,7 @@ func (r *gradeJournalRepository) GetGradeJournalByID(ctx context.Context, id int
 	`
 	g := &models.GradeJournal{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&g.GradeJournalID, &g.CreatedAt, &g.UpdateAt, &g.StudentID, &g.Grade, &g.Comment, &g.DisciplineID,
+		&g.GradeJournalID, &g.CreatedAt, &g.UpdatedAt, &g.StudentID, &g.Grade, &g.Comment, &g.DisciplineID,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -63,12 +70,78 @@ func (r *gradeJournalRepository) GetGradeJournalByID(ctx context.Context, id int
 	return g, nil
 }
 
+// GetGradeJournalsByIDs загружает записи одним запросом в порядке ids.
+// Отсутствующие id и повторы пропускаются.
+func (r *gradeJournalRepository) GetGradeJournalsByIDs(ctx context.Context, ids []int64) ([]*models.GradeJournal, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	placeholders, args := inClause(ids)
+	query := `
+		SELECT grade_journal_id, created_at, updated_at, student_id, grade, comment, discipline_id
+		FROM grade_journal WHERE grade_journal_id IN (` + placeholders + `)
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*models.GradeJournal, len(ids))
+	for rows.Next() {
+		g := &models.GradeJournal{}
+		if err := rows.Scan(
+			&g.GradeJournalID, &g.CreatedAt, &g.UpdatedAt, &g.StudentID, &g.Grade, &g.Comment, &g.DisciplineID,
+		); err != nil {
+			return nil, err
+		}
+		byID[int64(g.GradeJournalID)] = g
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items := make([]*models.GradeJournal, 0, len(byID))
+	for _, id := range ids {
+		if g, ok := byID[id]; ok {
+			items = append(items, g)
+			delete(byID, id)
+		}
+	}
+	return items, nil
+}
+
 func (r *gradeJournalRepository) UpdateGradeJournal(ctx context.Context, g *models.GradeJournal) error {
 	query := `
 		UPDATE grade_journal SET updated_at = ?, student_id = ?, grade = ?, comment = ?, discipline_id = ?
 		WHERE grade_journal_id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, time.Now(), g.StudentID, g.Grade, g.Comment, g.DisciplineID, g.GradeJournalID)
+	_, err := r.db.ExecContext(ctx, query, time.Now().UTC(), g.StudentID, g.Grade, g.Comment, g.DisciplineID, g.GradeJournalID)
+	return err
+}
+
+// PatchGradeJournal обновляет только переданные поля, явный null записывается как NULL
+func (r *gradeJournalRepository) PatchGradeJournal(ctx context.Context, id int64, patch *models.GradeJournalPatch) error {
+	var (
+		sets []string
+		args []interface{}
+	)
+	if patch.Grade != nil {
+		sets = append(sets, "grade = ?")
+		args = append(args, *patch.Grade)
+	}
+	if patch.Comment.Set {
+		sets = append(sets, "comment = ?")
+		args = append(args, patch.Comment.Value)
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	sets = append(sets, "updated_at = ?")
+	args = append(args, time.Now().UTC(), id)
+
+	query := `UPDATE grade_journal SET ` + strings.Join(sets, ", ") + ` WHERE grade_journal_id = ?`
+	_, err := r.db.ExecContext(ctx, query, args...)
 	return err
 }
 
@@ -78,32 +151,90 @@ func (r *gradeJournalRepository) DeleteGradeJournal(ctx context.Context, id int6
 	return err
 }
 
+// DeleteGradeJournalBatch удаляет записи одной транзакцией и возвращает удалённые,
+// чтобы их можно было записать в аудит. Отсутствующие id пропускаются.
+// При заданном teacherID все найденные записи должны относиться к дисциплинам этого
+// преподавателя, иначе ничего не удаляется и возвращается storage.ErrNotOwner.
+// Владелец проверяется по строкам, заблокированным в той же транзакции.
+func (r *gradeJournalRepository) DeleteGradeJournalBatch(ctx context.Context, ids []int64, teacherID *int64) ([]*models.GradeJournal, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	placeholders, args := inClause(ids)
+	// блокируются и строки дисциплин, чтобы преподаватель не сменился до удаления
+	rows, err := tx.QueryContext(ctx, `
+		SELECT g.grade_journal_id, g.created_at, g.updated_at, g.student_id, g.grade, g.comment, g.discipline_id, d.teacher_id
+		FROM grade_journal g
+		JOIN discipline d ON d.discipline_id = g.discipline_id
+		WHERE g.grade_journal_id IN (`+placeholders+`)
+		ORDER BY g.grade_journal_id
+		FOR UPDATE
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []*models.GradeJournal
+	notOwner := false
+	for rows.Next() {
+		g := &models.GradeJournal{}
+		var owner int64
+		if err := rows.Scan(
+			&g.GradeJournalID, &g.CreatedAt, &g.UpdatedAt, &g.StudentID, &g.Grade, &g.Comment, &g.DisciplineID, &owner,
+		); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if teacherID != nil && owner != *teacherID {
+			notOwner = true
+		}
+		deleted = append(deleted, g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if notOwner {
+		return nil, storage.ErrNotOwner
+	}
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM grade_journal WHERE grade_journal_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// ListGradeJournal при afterID работает в keyset-режиме: записи с id больше afterID,
+// offset игнорируется. Так глубокие страницы не требуют пропуска OFFSET строк.
 func (r *gradeJournalRepository) ListGradeJournal(
 	ctx context.Context,
 	studentID, disciplineID *int64,
-	fromDate, toDate *time.Time,
+	studentIDs []int64,
+	fromDate, toDate, updatedSince *time.Time,
+	afterID *int64,
 	limit, offset int,
 ) ([]*models.GradeJournal, error) {
-	query := `SELECT grade_journal_id, created_at, updated_at, student_id, grade, comment, discipline_id FROM grade_journal WHERE 1=1`
-	var args []interface{}
-	if studentID != nil {
-		query += " AND student_id = ?"
-		args = append(args, *studentID)
+	where, args := gradeJournalConditions("", studentID, disciplineID, studentIDs, fromDate, toDate, updatedSince)
+	query := `SELECT grade_journal_id, created_at, updated_at, student_id, grade, comment, discipline_id FROM grade_journal WHERE 1=1` + where
+	if afterID != nil {
+		query += " AND grade_journal_id > ? ORDER BY grade_journal_id LIMIT ?"
+		args = append(args, *afterID, limit)
+	} else {
+		query += " ORDER BY grade_journal_id LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
 	}
-	if disciplineID != nil {
-		query += " AND discipline_id = ?"
-		args = append(args, *disciplineID)
-	}
-	if fromDate != nil {
-		query += " AND created_at >= ?"
-		args = append(args, *fromDate)
-	}
-	if toDate != nil {
-		query += " AND created_at <= ?"
-		args = append(args, *toDate)
-	}
-	query += " ORDER BY grade_journal_id LIMIT ? OFFSET ?"
-	args = append(args, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -117,7 +248,7 @@ func (r *gradeJournalRepository) ListGradeJournal(
 		err := rows.Scan(
 			&g.GradeJournalID,
 			&g.CreatedAt,
-			&g.UpdateAt,
+			&g.UpdatedAt,
 			&g.StudentID,
 			&g.Grade,
 			&g.Comment,
@@ -135,9 +266,11 @@ func (r *gradeJournalRepository) ListGradeJournal(
 func (r *gradeJournalRepository) ListGradeJournalPublic(
 	ctx context.Context,
 	studentID, disciplineID *int64,
-	fromDate, toDate *time.Time,
+	studentIDs []int64,
+	fromDate, toDate, updatedSince *time.Time,
 	limit, offset int,
 ) ([]*models.GradeJournalPublic, error) {
+	where, args := gradeJournalConditions("gj.", studentID, disciplineID, studentIDs, fromDate, toDate, updatedSince)
 	query := `
 		SELECT 
 			gj.grade_journal_id, gj.created_at, gj.updated_at, gj.student_id,
@@ -148,24 +281,7 @@ func (r *gradeJournalRepository) ListGradeJournalPublic(
 		JOIN user u ON gj.student_id = u.user_id
 		JOIN discipline d ON gj.discipline_id = d.discipline_id
 		WHERE 1=1
-	`
-	var args []interface{}
-	if studentID != nil {
-		query += " AND gj.student_id = ?"
-		args = append(args, *studentID)
-	}
-	if disciplineID != nil {
-		query += " AND gj.discipline_id = ?"
-		args = append(args, *disciplineID)
-	}
-	if fromDate != nil {
-		query += " AND gj.created_at >= ?"
-		args = append(args, *fromDate)
-	}
-	if toDate != nil {
-		query += " AND gj.created_at <= ?"
-		args = append(args, *toDate)
-	}
+	` + where
 	query += " ORDER BY gj.grade_journal_id LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
@@ -181,7 +297,7 @@ func (r *gradeJournalRepository) ListGradeJournalPublic(
 		err := rows.Scan(
 			&g.GradeJournalID,
 			&g.CreatedAt,
-			&g.UpdateAt,
+			&g.UpdatedAt,
 			&g.StudentID,
 			&g.FirstName,
 			&g.LastName,
@@ -198,6 +314,59 @@ func (r *gradeJournalRepository) ListGradeJournalPublic(
 	return items, nil
 }
 
+// gradeJournalConditions собирает общие фильтры списка оценок для List и Count.
+// alias — префикс колонок ("gj." для запросов с join или "").
+func gradeJournalConditions(
+	alias string,
+	studentID, disciplineID *int64,
+	studentIDs []int64,
+	fromDate, toDate, updatedSince *time.Time,
+) (string, []interface{}) {
+	var (
+		where string
+		args  []interface{}
+	)
+	if studentID != nil {
+		where += " AND " + alias + "student_id = ?"
+		args = append(args, *studentID)
+	}
+	if len(studentIDs) > 0 {
+		placeholders, idArgs := inClause(studentIDs)
+		where += " AND " + alias + "student_id IN (" + placeholders + ")"
+		args = append(args, idArgs...)
+	}
+	if disciplineID != nil {
+		where += " AND " + alias + "discipline_id = ?"
+		args = append(args, *disciplineID)
+	}
+	if fromDate != nil {
+		where += " AND " + alias + "created_at >= ?"
+		args = append(args, *fromDate)
+	}
+	if toDate != nil {
+		where += " AND " + alias + "created_at <= ?"
+		args = append(args, *toDate)
+	}
+	if updatedSince != nil {
+		where += " AND " + alias + "updated_at >= ?"
+		args = append(args, *updatedSince)
+	}
+	return where, args
+}
+
+// CountGradeJournal считает оценки с теми же фильтрами, что и ListGradeJournal
+func (r *gradeJournalRepository) CountGradeJournal(
+	ctx context.Context,
+	studentID, disciplineID *int64,
+	studentIDs []int64,
+	fromDate, toDate, updatedSince *time.Time,
+) (int64, error) {
+	where, args := gradeJournalConditions("", studentID, disciplineID, studentIDs, fromDate, toDate, updatedSince)
+	var total int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM grade_journal WHERE 1=1`+where, args...).Scan(&total)
+	return total, err
+}
+
 // Средний балл по студенту/предмету с фильтрацией по датам
 func (r *gradeJournalRepository) GetAverageGrade(
 	ctx context.Context,
@@ -232,3 +401,32 @@ func (r *gradeJournalRepository) GetAverageGrade(
 	}
 	return 0, nil
 }
+
+// ListDisciplinesBelowAverage дисциплины, где средний балл студента строго ниже threshold.
+// Дисциплины без оценок не попадают в выборку.
+func (r *gradeJournalRepository) ListDisciplinesBelowAverage(ctx context.Context, studentID int64, threshold float64) ([]*models.DisciplineAverage, error) {
+	query := `
+		SELECT d.discipline_id, d.discipline_name, AVG(g.grade), COUNT(*)
+		FROM grade_journal g
+		JOIN discipline d ON d.discipline_id = g.discipline_id
+		WHERE g.student_id = ?
+		GROUP BY d.discipline_id, d.discipline_name
+		HAVING AVG(g.grade) < ?
+		ORDER BY AVG(g.grade), d.discipline_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, studentID, threshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*models.DisciplineAverage
+	for rows.Next() {
+		item := &models.DisciplineAverage{}
+		if err := rows.Scan(&item.DisciplineID, &item.DisciplineName, &item.AverageGrade, &item.GradesCount); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
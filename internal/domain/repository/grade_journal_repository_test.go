@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"service/internal/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGradeJournalConditions_StudentIDs(t *testing.T) {
+	single := int64(5)
+	tests := []struct {
+		name      string
+		studentID *int64
+		ids       []int64
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{"несколько студентов", nil, []int64{1, 2, 3}, " AND gj.student_id IN (?, ?, ?)", []interface{}{int64(1), int64(2), int64(3)}},
+		{"один в списке", nil, []int64{4}, " AND gj.student_id IN (?)", []interface{}{int64(4)}},
+		{"вместе с student_id", &single, []int64{1, 2}, " AND gj.student_id = ? AND gj.student_id IN (?, ?)", []interface{}{int64(5), int64(1), int64(2)}},
+		{"пустой список", nil, nil, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := gradeJournalConditions("gj.", tt.studentID, nil, tt.ids, nil, nil, nil)
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestGetGradeJournalsByIDs_KeepsRequestOrder(t *testing.T) {
+	now := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+	row := func(id int64) []driver.Value {
+		return []driver.Value{id, now, now, int64(7), int64(5), nil, int64(3)}
+	}
+	var args []driver.Value
+	db := newFakeDB(t, &fakeDB{onQuery: func(_ string, named []driver.NamedValue) (driver.Rows, error) {
+		for _, a := range named {
+			args = append(args, a.Value)
+		}
+		// MySQL отдаёт строки в порядке первичного ключа, id 9 не существует
+		return &fakeRows{
+			cols: []string{"grade_journal_id", "created_at", "updated_at", "student_id", "grade", "comment", "discipline_id"},
+			vals: [][]driver.Value{row(2), row(5), row(8)},
+		}, nil
+	}})
+	repo := NewGradeJournalRepository(db)
+
+	items, err := repo.GetGradeJournalsByIDs(context.Background(), []int64{8, 9, 2, 5, 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int64
+	for _, g := range items {
+		got = append(got, int64(g.GradeJournalID))
+	}
+	if want := []int64{8, 2, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ids = %v, want %v", got, want)
+	}
+	if want := []driver.Value{int64(8), int64(9), int64(2), int64(5), int64(8)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestGetGradeJournalsByIDs_Empty(t *testing.T) {
+	f := &fakeDB{}
+	repo := NewGradeJournalRepository(newFakeDB(t, f))
+	items, err := repo.GetGradeJournalsByIDs(context.Background(), nil)
+	if err != nil || items != nil {
+		t.Fatalf("items = %v, err = %v, want nil, nil", items, err)
+	}
+	if len(f.entries()) != 0 {
+		t.Errorf("queries = %v, want none", f.entries())
+	}
+}
+
+func TestListDisciplinesBelowAverage_StrictThreshold(t *testing.T) {
+	var query string
+	var args []driver.NamedValue
+	db := newFakeDB(t, &fakeDB{onQuery: func(q string, a []driver.NamedValue) (driver.Rows, error) {
+		query, args = compactSQL(q), a
+		return &fakeRows{}, nil
+	}})
+
+	items, err := NewGradeJournalRepository(db).ListDisciplinesBelowAverage(context.Background(), 7, 3.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items != nil {
+		t.Errorf("items = %+v, want none", items)
+	}
+	// средний балл, равный порогу, не считается отставанием
+	if !strings.Contains(query, "HAVING AVG(g.grade) < ?") {
+		t.Errorf("query = %s, want strict HAVING", query)
+	}
+	if len(args) != 2 || args[0].Value != int64(7) || args[1].Value != 3.5 {
+		t.Errorf("args = %v, want [7 3.5]", args)
+	}
+}
+
+func TestDeleteGradeJournalBatch(t *testing.T) {
+	now := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+	cols := []string{"grade_journal_id", "created_at", "updated_at", "student_id", "grade", "comment", "discipline_id", "teacher_id"}
+	execErr := errors.New("lock wait timeout")
+	teacher := int64(10)
+	tests := []struct {
+		name      string
+		found     [][]driver.Value
+		teacherID *int64
+		execErr   error
+		wantIDs   []int64
+		wantErr   error
+		wantLog   []string
+	}{
+		{
+			name:    "deletes found rows in one transaction",
+			found:   [][]driver.Value{{int64(2), now, now, int64(7), int64(5), nil, int64(3), int64(10)}, {int64(5), now, now, int64(8), int64(4), nil, int64(3), int64(10)}},
+			wantIDs: []int64{2, 5},
+			wantLog: []string{"BEGIN", "SELECT", "DELETE FROM grade_journal WHERE grade_journal_id IN (?, ?, ?)", "COMMIT"},
+		},
+		{
+			name:      "teacher owns all disciplines",
+			found:     [][]driver.Value{{int64(2), now, now, int64(7), int64(5), nil, int64(3), int64(10)}},
+			teacherID: &teacher,
+			wantIDs:   []int64{2},
+			wantLog:   []string{"BEGIN", "SELECT", "DELETE FROM grade_journal WHERE grade_journal_id IN (?, ?, ?)", "COMMIT"},
+		},
+		{
+			// одна чужая запись отменяет весь пакет
+			name:      "other teacher's grade in batch",
+			found:     [][]driver.Value{{int64(2), now, now, int64(7), int64(5), nil, int64(3), int64(10)}, {int64(5), now, now, int64(8), int64(4), nil, int64(4), int64(20)}},
+			teacherID: &teacher,
+			wantErr:   storage.ErrNotOwner,
+			wantLog:   []string{"BEGIN", "SELECT", "ROLLBACK"},
+		},
+		{
+			// ничего не нашлось — DELETE не выполняется
+			name:    "nothing found",
+			wantLog: []string{"BEGIN", "SELECT", "ROLLBACK"},
+		},
+		{
+			name:    "delete fails",
+			found:   [][]driver.Value{{int64(2), now, now, int64(7), int64(5), nil, int64(3), int64(10)}},
+			execErr: execErr,
+			wantErr: execErr,
+			wantLog: []string{"BEGIN", "SELECT", "DELETE FROM grade_journal WHERE grade_journal_id IN (?, ?, ?)", "ROLLBACK"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeDB{
+				onQuery: func(q string, _ []driver.NamedValue) (driver.Rows, error) {
+					if !strings.Contains(compactSQL(q), "JOIN discipline d ON d.discipline_id = g.discipline_id WHERE g.grade_journal_id IN (?, ?, ?) ORDER BY g.grade_journal_id FOR UPDATE") {
+						t.Errorf("select = %s, want grades and disciplines locked before delete", compactSQL(q))
+					}
+					return &fakeRows{cols: cols, vals: tt.found}, nil
+				},
+				onExec: func(string, []driver.NamedValue) (driver.Result, error) {
+					if tt.execErr != nil {
+						return nil, tt.execErr
+					}
+					return fakeResult{affected: int64(len(tt.found))}, nil
+				},
+			}
+			deleted, err := NewGradeJournalRepository(newFakeDB(t, f)).DeleteGradeJournalBatch(context.Background(), []int64{2, 404, 5}, tt.teacherID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			var ids []int64
+			for _, g := range deleted {
+				ids = append(ids, int64(g.GradeJournalID))
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("deleted = %v, want %v", ids, tt.wantIDs)
+			}
+			var log []string
+			for _, e := range f.entries() {
+				if strings.HasPrefix(e, "SELECT") {
+					e = "SELECT"
+				}
+				log = append(log, e)
+			}
+			if !reflect.DeepEqual(log, tt.wantLog) {
+				t.Errorf("log = %q, want %q", log, tt.wantLog)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"service/internal/domain/models"
+	"time"
+)
+
+type idempotencyKeyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyKeyRepository(db *sql.DB) *idempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// GetIdempotencyKey возвращает сохранённый ответ, если ключ ещё не истёк
+func (r *idempotencyKeyRepository) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*models.IdempotencyKey, error) {
+	query := `
+		SELECT user_id, idempotency_key, fingerprint, created_at, expires_at, status_code, content_type, location, response_body
+		FROM idempotency_key
+		WHERE user_id = ? AND idempotency_key = ? AND expires_at > ?
+	`
+	k := &models.IdempotencyKey{}
+	err := r.db.QueryRowContext(ctx, query, userID, key, time.Now().UTC()).Scan(
+		&k.UserID,
+		&k.Key,
+		&k.Fingerprint,
+		&k.CreatedAt,
+		&k.ExpiresAt,
+		&k.StatusCode,
+		&k.ContentType,
+		&k.Location,
+		&k.ResponseBody,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
+// ReserveIdempotencyKey атомарно занимает ключ под выполняющийся запрос (status_code = 0).
+// Истёкший ключ перезаписывается. Если ключ уже занят живой записью, возвращает false.
+//
+// Занятость определяется не по RowsAffected у ON DUPLICATE KEY UPDATE: с clientFoundRows
+// MySQL считает найденную строку затронутой, и живой ключ выглядел бы свободным.
+// Вместо этого новая строка вставляется обычным INSERT, а при дубликате истёкшая запись
+// перезаписывается UPDATE с условием на expires_at — найденная строка и есть занятый ключ.
+func (r *idempotencyKeyRepository) ReserveIdempotencyKey(ctx context.Context, k *models.IdempotencyKey) (bool, error) {
+	k.CreatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO idempotency_key (user_id, idempotency_key, fingerprint, created_at, expires_at, status_code, response_body)
+		VALUES (?, ?, ?, ?, ?, 0, '')
+	`, k.UserID, k.Key, k.Fingerprint, k.CreatedAt, k.ExpiresAt)
+	if err == nil {
+		return true, nil
+	}
+	if !isDuplicateEntry(err) {
+		return false, err
+	}
+
+	// из двух параллельных запросов строку найдёт только первый: второй после
+	// блокировки перечитывает её и видит уже новый expires_at
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE idempotency_key
+		SET fingerprint = ?, created_at = ?, expires_at = ?, status_code = 0, content_type = '', location = '', response_body = ''
+		WHERE user_id = ? AND idempotency_key = ? AND expires_at <= ?
+	`, k.Fingerprint, k.CreatedAt, k.ExpiresAt, k.UserID, k.Key, k.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// CompleteIdempotencyKey сохраняет ответ для зарезервированного ключа
+func (r *idempotencyKeyRepository) CompleteIdempotencyKey(ctx context.Context, k *models.IdempotencyKey) error {
+	query := `
+		UPDATE idempotency_key
+		SET status_code = ?, content_type = ?, location = ?, response_body = ?
+		WHERE user_id = ? AND idempotency_key = ? AND status_code = 0
+	`
+	_, err := r.db.ExecContext(ctx, query, k.StatusCode, k.ContentType, k.Location, k.ResponseBody, k.UserID, k.Key)
+	return err
+}
+
+// ReleaseIdempotencyKey снимает резерв, если запрос не завершился успешно,
+// чтобы клиент мог повторить его с тем же ключом
+func (r *idempotencyKeyRepository) ReleaseIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM idempotency_key WHERE user_id = ? AND idempotency_key = ? AND status_code = 0`, userID, key)
+	return err
+}
+
+// DeleteExpired удаляет ключи, истёкшие к моменту now, и возвращает их число
+func (r *idempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_key WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
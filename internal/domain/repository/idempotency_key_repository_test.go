@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestDeleteExpired_DeletesOnlyExpiredKeys(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	keys := map[string]time.Time{
+		"old":      now.Add(-24 * time.Hour),
+		"boundary": now,
+		"live":     now.Add(time.Second),
+	}
+	f := &fakeDB{onExec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+		if !strings.Contains(compactSQL(query), "DELETE FROM idempotency_key WHERE expires_at <= ?") {
+			t.Fatalf("unexpected query: %s", query)
+		}
+		cutoff := args[0].Value.(time.Time)
+		var n int64
+		for key, expires := range keys {
+			if !expires.After(cutoff) {
+				delete(keys, key)
+				n++
+			}
+		}
+		return fakeResult{affected: n}, nil
+	}}
+
+	n, err := NewIdempotencyKeyRepository(newFakeDB(t, f)).DeleteExpired(context.Background(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ключ, истекающий ровно сейчас, уже не отдаётся GetIdempotencyKey и удаляется
+	if n != 2 {
+		t.Errorf("deleted %d keys, want 2", n)
+	}
+	if _, ok := keys["live"]; !ok || len(keys) != 1 {
+		t.Errorf("remaining keys = %v, want only live", keys)
+	}
+}
+
+// idempotencyTable одна таблица idempotency_key с семантикой clientFoundRows:
+// UPDATE и ON DUPLICATE KEY UPDATE считают затронутыми найденные строки, даже без изменений
+type idempotencyTable struct {
+	rows map[string]*models.IdempotencyKey
+}
+
+func (tb *idempotencyTable) db() *fakeDB {
+	return &fakeDB{onExec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+		q := compactSQL(query)
+		switch {
+		case strings.HasPrefix(q, "INSERT INTO idempotency_key"):
+			key := args[1].Value.(string)
+			if _, ok := tb.rows[key]; ok {
+				if strings.Contains(q, "ON DUPLICATE KEY UPDATE") {
+					// строка найдена, хоть и не изменена
+					return fakeResult{affected: 1}, nil
+				}
+				return nil, &mysql.MySQLError{Number: mysqlErrDuplicateEntry, Message: "Duplicate entry"}
+			}
+			tb.rows[key] = &models.IdempotencyKey{Key: key, Fingerprint: args[2].Value.(string), ExpiresAt: args[4].Value.(time.Time)}
+			return fakeResult{affected: 1}, nil
+		case strings.HasPrefix(q, "UPDATE idempotency_key SET fingerprint"):
+			row, ok := tb.rows[args[4].Value.(string)]
+			if !ok || row.ExpiresAt.After(args[5].Value.(time.Time)) {
+				return fakeResult{}, nil
+			}
+			row.Fingerprint, row.ExpiresAt = args[0].Value.(string), args[2].Value.(time.Time)
+			return fakeResult{affected: 1}, nil
+		}
+		return nil, fmt.Errorf("unexpected query: %s", q)
+	}}
+}
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name        string
+		existing    *models.IdempotencyKey
+		want        bool
+		fingerprint string
+	}{
+		{"new key", nil, true, "new"},
+		// запрос с этим ключом ещё выполняется или уже сохранён ответ — повтор не выполняется
+		{"live key", &models.IdempotencyKey{Key: "k", Fingerprint: "old", ExpiresAt: now.Add(time.Hour)}, false, "old"},
+		{"expired key", &models.IdempotencyKey{Key: "k", Fingerprint: "old", ExpiresAt: now.Add(-time.Minute)}, true, "new"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &idempotencyTable{rows: map[string]*models.IdempotencyKey{}}
+			if tt.existing != nil {
+				table.rows["k"] = tt.existing
+			}
+			repo := NewIdempotencyKeyRepository(newFakeDB(t, table.db()))
+
+			ok, err := repo.ReserveIdempotencyKey(context.Background(), &models.IdempotencyKey{UserID: 1, Key: "k", Fingerprint: "new", ExpiresAt: now.Add(24 * time.Hour)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want {
+				t.Errorf("reserved = %v, want %v", ok, tt.want)
+			}
+			if got := table.rows["k"].Fingerprint; got != tt.fingerprint {
+				t.Errorf("fingerprint = %q, want %q", got, tt.fingerprint)
+			}
+
+			// только что занятый ключ второй раз не занимается
+			again, err := repo.ReserveIdempotencyKey(context.Background(), &models.IdempotencyKey{UserID: 1, Key: "k", Fingerprint: "retry", ExpiresAt: now.Add(24 * time.Hour)})
+			if err != nil || again {
+				t.Errorf("second reserve = %v, %v; want false", again, err)
+			}
+		})
+	}
+}
+
+func TestReserveIdempotencyKey_OtherInsertError(t *testing.T) {
+	fkErr := &mysql.MySQLError{Number: mysqlErrNoReferencedRow, Message: "Cannot add or update a child row"}
+	f := &fakeDB{onExec: func(string, []driver.NamedValue) (driver.Result, error) { return nil, fkErr }}
+	ok, err := NewIdempotencyKeyRepository(newFakeDB(t, f)).ReserveIdempotencyKey(context.Background(), &models.IdempotencyKey{UserID: 404, Key: "k"})
+	if ok || !errors.Is(err, fkErr) {
+		t.Fatalf("reserve = %v, %v; want the insert error", ok, err)
+	}
+	if log := f.entries(); len(log) != 1 {
+		t.Errorf("log = %q, want only the insert", log)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"service/internal/domain/models"
+	"service/internal/storage"
+	"strings"
 	"time"
 )
 
@@ -21,7 +23,7 @@ func (r *PermissionRepository) CreatePermission(ctx context.Context, permission
 		VALUES (?, ?, ?)
 		RETURNING permission_id
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	err := r.db.QueryRowContext(ctx, query, permission.PermissionName, now).Scan(&permission.PermissionID)
 	return err
 }
@@ -37,7 +39,7 @@ func (r *PermissionRepository) GetPermissionByID(ctx context.Context, id int64)
 		&perm.PermissionID,
 		&perm.PermissionName,
 		&perm.CreatedAt,
-		&perm.UpdateAt,
+		&perm.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -56,7 +58,7 @@ func (r *PermissionRepository) GetPermissionByName(ctx context.Context, name str
 		&perm.PermissionID,
 		&perm.PermissionName,
 		&perm.CreatedAt,
-		&perm.UpdateAt,
+		&perm.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -70,13 +72,16 @@ func (r *PermissionRepository) UpdatePermission(ctx context.Context, permission
 		SET permission_name = ?, updated_at = ?
 		WHERE permission_id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, permission.PermissionName, time.Now(), permission.PermissionID)
+	_, err := r.db.ExecContext(ctx, query, permission.PermissionName, time.Now().UTC(), permission.PermissionID)
 	return err
 }
 
 func (r *PermissionRepository) DeletePermission(ctx context.Context, id int64) error {
 	query := `DELETE FROM permissions WHERE permission_id = ?`
 	_, err := r.db.ExecContext(ctx, query, id)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
@@ -96,7 +101,7 @@ func (r *PermissionRepository) ListPermission(ctx context.Context, limit, offset
 	var perms []*models.Permission
 	for rows.Next() {
 		var perm models.Permission
-		if err := rows.Scan(&perm.PermissionID, &perm.PermissionName, &perm.CreatedAt, &perm.UpdateAt); err != nil {
+		if err := rows.Scan(&perm.PermissionID, &perm.PermissionName, &perm.CreatedAt, &perm.UpdatedAt); err != nil {
 			return nil, err
 		}
 		perms = append(perms, &perm)
@@ -106,3 +111,103 @@ func (r *PermissionRepository) ListPermission(ctx context.Context, limit, offset
 	}
 	return perms, nil
 }
+
+// ListPermissionNames имена всех прав по алфавиту
+func (r *PermissionRepository) ListPermissionNames(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT permission_name FROM permissions ORDER BY permission_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func (r *PermissionRepository) CountPermission(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM permissions`).Scan(&total)
+	return total, err
+}
+
+// RenamePermissions переименовывает права на месте в одной транзакции, permission_id
+// не меняются, поэтому связи role_permissions сохраняются.
+// from/to — точные имена либо префиксы вида "resource:" (переименовываются все права ресурса).
+// Нет подходящих прав — sql.ErrNoRows, новое имя уже занято другим правом — storage.ErrDuplicate.
+func (r *PermissionRepository) RenamePermissions(ctx context.Context, from, to string, prefix bool) ([]*models.PermissionRenamed, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT permission_id, permission_name FROM permissions WHERE permission_name = ? ORDER BY permission_id FOR UPDATE`
+	arg := from
+	if prefix {
+		query = `SELECT permission_id, permission_name FROM permissions WHERE permission_name LIKE ? ESCAPE '\\' ORDER BY permission_id FOR UPDATE`
+		arg = escapeLike(from) + "%"
+	}
+	rows, err := tx.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	var renamed []*models.PermissionRenamed
+	for rows.Next() {
+		item := &models.PermissionRenamed{}
+		if err := rows.Scan(&item.PermissionID, &item.From); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		item.To = to + strings.TrimPrefix(item.From, from)
+		renamed = append(renamed, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(renamed) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	// уникального индекса на permission_name нет, поэтому конфликт проверяем сами
+	ids := make([]int64, 0, len(renamed))
+	names := make([]interface{}, 0, len(renamed))
+	for _, item := range renamed {
+		ids = append(ids, item.PermissionID)
+		names = append(names, item.To)
+	}
+	idPlaceholders, idArgs := inClause(ids)
+	namePlaceholders := strings.TrimSuffix(strings.Repeat("?,", len(names)), ",")
+	var taken int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM permissions WHERE permission_name IN (`+namePlaceholders+`) AND permission_id NOT IN (`+idPlaceholders+`)`,
+		append(names, idArgs...)...,
+	).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+	if taken > 0 {
+		return nil, storage.ErrDuplicate
+	}
+
+	now := time.Now().UTC()
+	for _, item := range renamed {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE permissions SET permission_name = ?, updated_at = ? WHERE permission_id = ?`,
+			item.To, now, item.PermissionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return renamed, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/storage"
+	"strings"
+	"testing"
+)
+
+// renameDB права studentgroup:* и счётчик занятых новых имён
+func renameDB(taken int64, execErr error) *fakeDB {
+	return &fakeDB{
+		onQuery: func(q string, _ []driver.NamedValue) (driver.Rows, error) {
+			if strings.Contains(q, "COUNT(*)") {
+				return &fakeRows{cols: []string{"count"}, vals: [][]driver.Value{{taken}}}, nil
+			}
+			return &fakeRows{
+				cols: []string{"permission_id", "permission_name"},
+				vals: [][]driver.Value{{int64(3), "studentgroup:create"}, {int64(5), "studentgroup:list"}},
+			}, nil
+		},
+		onExec: func(q string, args []driver.NamedValue) (driver.Result, error) {
+			if execErr != nil && args[2].Value == int64(5) {
+				return nil, execErr
+			}
+			return fakeResult{affected: 1}, nil
+		},
+	}
+}
+
+func TestRenamePermissions_Prefix(t *testing.T) {
+	f := renameDB(0, nil)
+	renamed, err := NewPermissionRepository(newFakeDB(t, f)).RenamePermissions(context.Background(), "studentgroup:", "student_group:", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*models.PermissionRenamed{
+		{PermissionID: 3, From: "studentgroup:create", To: "student_group:create"},
+		{PermissionID: 5, From: "studentgroup:list", To: "student_group:list"},
+	}
+	if !reflect.DeepEqual(renamed, want) {
+		t.Fatalf("renamed = %+v, want %+v", renamed, want)
+	}
+
+	log := f.entries()
+	if log[0] != "BEGIN" || log[len(log)-1] != "COMMIT" {
+		t.Errorf("log = %q, want one transaction ending with COMMIT", log)
+	}
+	updates := 0
+	for _, e := range log {
+		if strings.HasPrefix(e, "UPDATE permissions") {
+			updates++
+		}
+		if strings.HasPrefix(e, "SELECT permission_id") && !strings.Contains(e, "LIKE ?") {
+			t.Errorf("prefix rename must select by LIKE: %s", e)
+		}
+	}
+	if updates != 2 {
+		t.Errorf("updates = %d, want 2", updates)
+	}
+}
+
+func TestRenamePermissions_Rejected(t *testing.T) {
+	execErr := errors.New("lock wait timeout")
+	tests := []struct {
+		name    string
+		db      *fakeDB
+		wantErr error
+		updates int
+	}{
+		// новое имя занято другим правом — ничего не обновляется
+		{"conflict", renameDB(1, nil), storage.ErrDuplicate, 0},
+		// второе обновление упало — первое откатывается вместе с транзакцией
+		{"update fails", renameDB(0, execErr), execErr, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renamed, err := NewPermissionRepository(newFakeDB(t, tt.db)).RenamePermissions(context.Background(), "studentgroup:", "student_group:", true)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if renamed != nil {
+				t.Errorf("renamed = %+v, want nil", renamed)
+			}
+			log := tt.db.entries()
+			updates := 0
+			for _, e := range log {
+				if e == "COMMIT" {
+					t.Fatalf("log = %q, want no COMMIT", log)
+				}
+				if strings.HasPrefix(e, "UPDATE permissions") {
+					updates++
+				}
+			}
+			if updates != tt.updates || log[len(log)-1] != "ROLLBACK" {
+				t.Errorf("log = %q, want %d updates and ROLLBACK", log, tt.updates)
+			}
+		})
+	}
+}
+
+func TestRenamePermissions_NotFound(t *testing.T) {
+	f := &fakeDB{}
+	_, err := NewPermissionRepository(newFakeDB(t, f)).RenamePermissions(context.Background(), "report:export", "report:download", false)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("err = %v, want sql.ErrNoRows", err)
+	}
+	if log := f.entries(); log[len(log)-1] != "ROLLBACK" {
+		t.Errorf("log = %q, want ROLLBACK", log)
+	}
+}
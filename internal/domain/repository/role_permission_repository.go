@@ -8,11 +8,17 @@ import (
 )
 
 type RolePermissionRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *stmtCache
 }
 
 func NewRolePermissionRepository(db *sql.DB) *RolePermissionRepository {
-	return &RolePermissionRepository{db: db}
+	return &RolePermissionRepository{db: db, stmts: newStmtCache(db)}
+}
+
+// Close закрывает подготовленные запросы репозитория
+func (r *RolePermissionRepository) Close() error {
+	return r.stmts.Close()
 }
 
 func (r *RolePermissionRepository) AssignPermission(ctx context.Context, roleID, permissionID int64) error {
@@ -20,7 +26,7 @@ func (r *RolePermissionRepository) AssignPermission(ctx context.Context, roleID,
 		`INSERT INTO role_permissions (role_id, permission_id, created_at, updated_at)
 		 VALUES (?, ?, ?, ?)
 		 ON CONFLICT (role_id, permission_id) DO NOTHING`,
-		roleID, permissionID, time.Now(),
+		roleID, permissionID, time.Now().UTC(),
 	)
 	return err
 }
@@ -34,11 +40,16 @@ func (r *RolePermissionRepository) RemovePermission(ctx context.Context, roleID,
 }
 
 func (r *RolePermissionRepository) GetPermissionsByRoleID(ctx context.Context, roleID int64) ([]*models.Permission, error) {
-	rows, err := r.db.QueryContext(ctx,
+	// запрос RBAC выполняется на каждом защищённом запросе
+	stmt, err := r.stmts.prepare(ctx,
 		`SELECT p.permission_id, p.permission_name, p.created_at, p.updated_at
 		 FROM permissions p
 		 INNER JOIN role_permissions rp ON rp.permission_id = p.permission_id
-		 WHERE rp.role_id = ?`, roleID)
+		 WHERE rp.role_id = ?`)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +58,7 @@ func (r *RolePermissionRepository) GetPermissionsByRoleID(ctx context.Context, r
 	var perms []*models.Permission
 	for rows.Next() {
 		var perm models.Permission
-		if err := rows.Scan(&perm.PermissionID, &perm.PermissionName, &perm.CreatedAt, &perm.UpdateAt); err != nil {
+		if err := rows.Scan(&perm.PermissionID, &perm.PermissionName, &perm.CreatedAt, &perm.UpdatedAt); err != nil {
 			return nil, err
 		}
 		perms = append(perms, &perm)
@@ -22,7 +22,7 @@ func (r *RoleRepository) CreateRole(ctx context.Context, role *models.Role) (int
 		RETURNING role_id
 	`
 	var id int64
-	now := time.Now()
+	now := time.Now().UTC()
 	err := r.db.QueryRowContext(ctx, query, role.RoleName, now, now).Scan(&id)
 	if err != nil {
 		return 0, err
@@ -41,7 +41,7 @@ func (r *RoleRepository) GetRoleByID(ctx context.Context, id int64) (*models.Rol
 		&role.RoleID,
 		&role.RoleName,
 		&role.CreatedAt,
-		&role.UpdateAt,
+		&role.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -60,7 +60,7 @@ func (r *RoleRepository) GetRoleByName(ctx context.Context, name string) (*model
 		&role.RoleID,
 		&role.RoleName,
 		&role.CreatedAt,
-		&role.UpdateAt,
+		&role.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -74,13 +74,16 @@ func (r *RoleRepository) UpdateRole(ctx context.Context, role *models.Role) erro
 		SET role_name = ?, updated_at = ?
 		WHERE role_id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, role.RoleName, time.Now(), role.RoleID)
+	_, err := r.db.ExecContext(ctx, query, role.RoleName, time.Now().UTC(), role.RoleID)
 	return err
 }
 
 func (r *RoleRepository) DeleteRole(ctx context.Context, id int64) error {
 	query := `DELETE FROM roles WHERE role_id = ?`
 	_, err := r.db.ExecContext(ctx, query, id)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
@@ -99,7 +102,33 @@ func (r *RoleRepository) ListRole(ctx context.Context) ([]*models.Role, error) {
 	var roles []*models.Role
 	for rows.Next() {
 		var role models.Role
-		if err := rows.Scan(&role.RoleID, &role.RoleName, &role.CreatedAt, &role.UpdateAt); err != nil {
+		if err := rows.Scan(&role.RoleID, &role.RoleName, &role.CreatedAt, &role.UpdatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, &role)
+	}
+	return roles, rows.Err()
+}
+
+// ListRoleWithCounts возвращает все роли с числом пользователей, роли без пользователей — с нулём
+func (r *RoleRepository) ListRoleWithCounts(ctx context.Context) ([]*models.RoleWithCount, error) {
+	query := `
+		SELECT r.role_id, r.role_name, r.created_at, r.updated_at, COUNT(ur.user_id) AS user_count
+		FROM roles r
+		LEFT JOIN user_roles ur ON ur.role_id = r.role_id
+		GROUP BY r.role_id, r.role_name, r.created_at, r.updated_at
+		ORDER BY r.role_id
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []*models.RoleWithCount
+	for rows.Next() {
+		var role models.RoleWithCount
+		if err := rows.Scan(&role.RoleID, &role.RoleName, &role.CreatedAt, &role.UpdatedAt, &role.UserCount); err != nil {
 			return nil, err
 		}
 		roles = append(roles, &role)
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListRoleWithCounts(t *testing.T) {
+	now := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	var query string
+	db := newFakeDB(t, &fakeDB{onQuery: func(q string, _ []driver.NamedValue) (driver.Rows, error) {
+		query = compactSQL(q)
+		return &fakeRows{
+			cols: []string{"role_id", "role_name", "created_at", "updated_at", "user_count"},
+			vals: [][]driver.Value{
+				{int64(1), "admin", now, now, int64(2)},
+				{int64(2), "auditor", now, now, int64(0)},
+			},
+		}, nil
+	}})
+
+	roles, err := NewRoleRepository(db).ListRoleWithCounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// роли без пользователей остаются в выборке благодаря LEFT JOIN
+	if !strings.Contains(query, "LEFT JOIN user_roles") || !strings.Contains(query, "COUNT(ur.user_id)") {
+		t.Errorf("query = %s", query)
+	}
+	if len(roles) != 2 || roles[0].RoleName != "admin" || roles[0].UserCount != 2 || roles[1].RoleName != "auditor" || roles[1].UserCount != 0 {
+		t.Fatalf("roles = %+v", roles)
+	}
+}
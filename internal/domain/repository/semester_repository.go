@@ -14,6 +14,8 @@ type SemesterRepository interface {
 	UpdateSemester(ctx context.Context, s *models.Semester) error
 	DeleteSemester(ctx context.Context, id int64) error
 	ListSemester(ctx context.Context, academicYearID *int64, fromDate, toDate *time.Time, limit, offset int) ([]*models.Semester, error)
+	CountSemester(ctx context.Context, academicYearID *int64, fromDate, toDate *time.Time) (int64, error)
+	GetCurrentSemester(ctx context.Context, at time.Time) (*models.Semester, error)
 }
 
 type semesterRepository struct {
@@ -29,10 +31,10 @@ func (r *semesterRepository) CreateSemester(ctx context.Context, s *models.Semes
 		INSERT INTO semester (created_at, updated_at, start_with, ends_with, academic_year_id)
 		VALUES (?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	s.CreatedAt = now
-	s.UpdateAt = now
-	res, err := r.db.ExecContext(ctx, query, s.CreatedAt, s.UpdateAt, s.StartWith, s.EndsWith, s.AcademicYearID)
+	s.UpdatedAt = now
+	res, err := r.db.ExecContext(ctx, query, s.CreatedAt, s.UpdatedAt, s.StartWith, s.EndsWith, s.AcademicYearID)
 	if err != nil {
 		return err
 	}
@@ -53,7 +55,35 @@ func (r *semesterRepository) GetSemesterByID(ctx context.Context, id int64) (*mo
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&s.SemesterID,
 		&s.CreatedAt,
-		&s.UpdateAt,
+		&s.UpdatedAt,
+		&s.StartWith,
+		&s.EndsWith,
+		&s.AcademicYearID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetCurrentSemester возвращает семестр, в который попадает дата at.
+// Если семестры пересекаются, берётся начавшийся позже.
+func (r *semesterRepository) GetCurrentSemester(ctx context.Context, at time.Time) (*models.Semester, error) {
+	query := `
+		SELECT semester_id, created_at, updated_at, start_with, ends_with, academic_year_id
+		FROM semester
+		WHERE ? BETWEEN start_with AND ends_with
+		ORDER BY start_with DESC
+		LIMIT 1
+	`
+	s := &models.Semester{}
+	err := r.db.QueryRowContext(ctx, query, at.Format("2006-01-02")).Scan(
+		&s.SemesterID,
+		&s.CreatedAt,
+		&s.UpdatedAt,
 		&s.StartWith,
 		&s.EndsWith,
 		&s.AcademicYearID,
@@ -73,13 +103,16 @@ func (r *semesterRepository) UpdateSemester(ctx context.Context, s *models.Semes
 		SET updated_at = ?, start_with = ?, ends_with = ?, academic_year_id = ?
 		WHERE semester_id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, time.Now(), s.StartWith, s.EndsWith, s.AcademicYearID, s.SemesterID)
+	_, err := r.db.ExecContext(ctx, query, time.Now().UTC(), s.StartWith, s.EndsWith, s.AcademicYearID, s.SemesterID)
 	return err
 }
 
 func (r *semesterRepository) DeleteSemester(ctx context.Context, id int64) error {
 	query := `DELETE FROM semester WHERE semester_id = ?`
 	_, err := r.db.ExecContext(ctx, query, id)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
@@ -89,20 +122,8 @@ func (r *semesterRepository) ListSemester(
 	fromDate, toDate *time.Time,
 	limit, offset int,
 ) ([]*models.Semester, error) {
-	query := `SELECT semester_id, created_at, updated_at, start_with, ends_with, academic_year_id FROM semester WHERE 1=1`
-	var args []interface{}
-	if academicYearID != nil {
-		query += " AND academic_year_id = ?"
-		args = append(args, *academicYearID)
-	}
-	if fromDate != nil {
-		query += " AND start_with >= ?"
-		args = append(args, *fromDate)
-	}
-	if toDate != nil {
-		query += " AND ends_with <= ?"
-		args = append(args, *toDate)
-	}
+	where, args := semesterConditions(academicYearID, fromDate, toDate)
+	query := `SELECT semester_id, created_at, updated_at, start_with, ends_with, academic_year_id FROM semester WHERE 1=1` + where
 	query += " ORDER BY semester_id LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
@@ -118,7 +139,7 @@ func (r *semesterRepository) ListSemester(
 		err := rows.Scan(
 			&s.SemesterID,
 			&s.CreatedAt,
-			&s.UpdateAt,
+			&s.UpdatedAt,
 			&s.StartWith,
 			&s.EndsWith,
 			&s.AcademicYearID,
@@ -130,3 +151,31 @@ func (r *semesterRepository) ListSemester(
 	}
 	return semesters, nil
 }
+
+// semesterConditions общие фильтры ListSemester и CountSemester
+func semesterConditions(academicYearID *int64, fromDate, toDate *time.Time) (string, []interface{}) {
+	var (
+		where string
+		args  []interface{}
+	)
+	if academicYearID != nil {
+		where += " AND academic_year_id = ?"
+		args = append(args, *academicYearID)
+	}
+	if fromDate != nil {
+		where += " AND start_with >= ?"
+		args = append(args, *fromDate)
+	}
+	if toDate != nil {
+		where += " AND ends_with <= ?"
+		args = append(args, *toDate)
+	}
+	return where, args
+}
+
+func (r *semesterRepository) CountSemester(ctx context.Context, academicYearID *int64, fromDate, toDate *time.Time) (int64, error) {
+	where, args := semesterConditions(academicYearID, fromDate, toDate)
+	var total int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM semester WHERE 1=1`+where, args...).Scan(&total)
+	return total, err
+}
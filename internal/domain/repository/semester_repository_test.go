@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetCurrentSemester(t *testing.T) {
+	var c queryCapture
+	_, err := NewSemesterRepository(newFakeDB(t, c.db(t))).GetCurrentSemester(context.Background(), time.Date(2024, 10, 15, 13, 45, 0, 0, time.UTC))
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("err = %v, want sql.ErrNoRows", err)
+	}
+	// сравнивается только дата, из пересекающихся семестров берётся начавшийся позже
+	if !strings.Contains(c.query, "WHERE ? BETWEEN start_with AND ends_with ORDER BY start_with DESC LIMIT 1") {
+		t.Errorf("query = %s", c.query)
+	}
+	if want := []driver.Value{"2024-10-15"}; !reflect.DeepEqual(c.args, want) {
+		t.Errorf("args = %v, want %v", c.args, want)
+	}
+}
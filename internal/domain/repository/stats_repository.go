@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+type statsRepository struct {
+	db *sql.DB
+}
+
+func NewStatsRepository(db *sql.DB) *statsRepository {
+	return &statsRepository{db: db}
+}
+
+// GetEntityCounts считает записи по основным сущностям одним запросом
+func (r *statsRepository) GetEntityCounts(ctx context.Context) (map[string]int64, error) {
+	query := `
+		SELECT 'students', COUNT(*) FROM student
+		UNION ALL SELECT 'teachers', COUNT(*) FROM teacher
+		UNION ALL SELECT 'student_groups', COUNT(*) FROM student_group
+		UNION ALL SELECT 'disciplines', COUNT(*) FROM discipline
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var (
+			entity string
+			count  int64
+		)
+		if err := rows.Scan(&entity, &count); err != nil {
+			return nil, err
+		}
+		counts[entity] = count
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+var unionCountRe = regexp.MustCompile(`SELECT '(\w+)', COUNT\(\*\) FROM (\w+)`)
+
+func TestGetEntityCounts_SingleUnionQuery(t *testing.T) {
+	tables := map[string]int64{"student": 40, "teacher": 6, "student_group": 3, "discipline": 0}
+	f := &fakeDB{onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		rows := &fakeRows{cols: []string{"entity", "count"}}
+		for _, m := range unionCountRe.FindAllStringSubmatch(query, -1) {
+			rows.vals = append(rows.vals, []driver.Value{m[1], tables[m[2]]})
+		}
+		return rows, nil
+	}}
+	db := newFakeDB(t, f)
+
+	got, err := NewStatsRepository(db).GetEntityCounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{"students": 40, "teachers": 6, "student_groups": 3, "disciplines": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("counts = %v, want %v", got, want)
+	}
+	if n := len(f.entries()); n != 1 {
+		t.Fatalf("executed %d queries, want one UNION ALL query: %v", n, f.entries())
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func rolesDB(t testing.TB) *fakeDB {
+	t.Helper()
+	return &fakeDB{onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		return &fakeRows{cols: []string{"permission_id", "permission_name", "created_at", "updated_at"}}, nil
+	}}
+}
+
+func TestStmtCache_ConcurrentCallsPrepareOnce(t *testing.T) {
+	f := rolesDB(t)
+	repo := NewRolePermissionRepository(newFakeDB(t, f))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(roleID int64) {
+			defer wg.Done()
+			if _, err := repo.GetPermissionsByRoleID(context.Background(), roleID); err != nil {
+				t.Error(err)
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+
+	if n := f.prepared("WHERE rp.role_id = ?"); n != 1 {
+		t.Fatalf("statement prepared %d times, want 1", n)
+	}
+}
+
+func TestStmtCache_Close(t *testing.T) {
+	f := rolesDB(t)
+	cache := newStmtCache(newFakeDB(t, f))
+	if _, err := cache.prepare(context.Background(), "SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatal(err)
+	}
+	closed := false
+	for _, e := range f.entries() {
+		closed = closed || e == "CLOSE SELECT 1"
+	}
+	if !closed {
+		t.Fatalf("statement was not closed: %v", f.entries())
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if _, err := cache.prepare(context.Background(), "SELECT 1"); !errors.Is(err, sql.ErrConnDone) {
+		t.Fatalf("prepare after Close: err = %v, want sql.ErrConnDone", err)
+	}
+}
+
+// BenchmarkGetPermissionsByRoleID сравнивает кэшированный запрос с подготовкой на каждом вызове
+func BenchmarkGetPermissionsByRoleID(b *testing.B) {
+	const query = `SELECT p.permission_id, p.permission_name, p.created_at, p.updated_at
+		 FROM permissions p
+		 INNER JOIN role_permissions rp ON rp.permission_id = p.permission_id
+		 WHERE rp.role_id = ?`
+	ctx := context.Background()
+
+	b.Run("cached", func(b *testing.B) {
+		db := sql.OpenDB(rolesDB(b))
+		defer db.Close()
+		repo := NewRolePermissionRepository(db)
+		defer repo.Close()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetPermissionsByRoleID(ctx, 1); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("prepare per call", func(b *testing.B) {
+		db := sql.OpenDB(rolesDB(b))
+		defer db.Close()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			stmt, err := db.PrepareContext(ctx, query)
+			if err != nil {
+				b.Fatal(err)
+			}
+			rows, err := stmt.QueryContext(ctx, 1)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = rows.Close()
+			_ = stmt.Close()
+		}
+	})
+}
@@ -21,16 +21,16 @@ func (r *StudentGroupRepository) CreateStudentGroup(ctx context.Context, group *
 		INSERT INTO student_group (student_group_name, curator_id, academic_year_id, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	group.CreatedAt = now
-	group.UpdateAt = now
+	group.UpdatedAt = now
 
 	result, err := r.db.ExecContext(ctx, query,
 		group.StudentGroupName,
 		group.CuratorID,
 		group.AcademicYearID,
 		group.CreatedAt,
-		group.UpdateAt,
+		group.UpdatedAt,
 	)
 	if err != nil {
 		return err
@@ -52,7 +52,7 @@ func (r *StudentGroupRepository) GetStudentGroupByID(ctx context.Context, id int
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&group.StudentGroupID,
 		&group.CreatedAt,
-		&group.UpdateAt,
+		&group.UpdatedAt,
 		&group.StudentGroupName,
 		&group.CuratorID,
 		&group.AcademicYearID,
@@ -114,7 +114,7 @@ func (r *StudentGroupRepository) UpdateStudentGroup(ctx context.Context, group *
 		group.StudentGroupName,
 		group.CuratorID,
 		group.AcademicYearID,
-		time.Now(),
+		time.Now().UTC(),
 		group.StudentGroupID,
 	)
 	return err
@@ -123,9 +123,82 @@ func (r *StudentGroupRepository) UpdateStudentGroup(ctx context.Context, group *
 func (r *StudentGroupRepository) DeleteStudentGroup(ctx context.Context, id int64) error {
 	query := `DELETE FROM student_group WHERE student_group_id = ?`
 	_, err := r.db.ExecContext(ctx, query, id)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
+// MergeStudentGroups переводит всех студентов группы fromID в группу toID одной транзакцией
+// и при deleteSource удаляет исходную группу. Обе группы блокируются на время переноса.
+// Если группы нет — sql.ErrNoRows, если исходную группу нельзя удалить — storage.ErrReferenced,
+// в обоих случаях перенос откатывается.
+func (r *StudentGroupRepository) MergeStudentGroups(ctx context.Context, fromID, toID int64, deleteSource bool) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	groups, err := tx.QueryContext(ctx,
+		`SELECT student_group_id FROM student_group WHERE student_group_id IN (?, ?) FOR UPDATE`, fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+	locked := 0
+	for groups.Next() {
+		locked++
+	}
+	groups.Close()
+	if err := groups.Err(); err != nil {
+		return nil, err
+	}
+	if locked != 2 {
+		return nil, sql.ErrNoRows
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT user_id FROM student WHERE student_group_id = ? ORDER BY user_id FOR UPDATE`, fromID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) > 0 {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE student SET student_group_id = ?, updated_at = ? WHERE student_group_id = ?`,
+			toID, time.Now().UTC(), fromID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if deleteSource {
+		_, err = tx.ExecContext(ctx, `DELETE FROM student_group WHERE student_group_id = ?`, fromID)
+		if fkErr := foreignKeyError(err); fkErr != nil {
+			return nil, fkErr
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 func (r *StudentGroupRepository) ListStudentGroups(ctx context.Context, limit, offset int) ([]*models.StudentGroup, error) {
 	query := `
 		SELECT student_group_id, created_at, updated_at, student_group_name, curator_id, academic_year_id
@@ -145,7 +218,7 @@ func (r *StudentGroupRepository) ListStudentGroups(ctx context.Context, limit, o
 		err := rows.Scan(
 			&group.StudentGroupID,
 			&group.CreatedAt,
-			&group.UpdateAt,
+			&group.UpdatedAt,
 			&group.StudentGroupName,
 			&group.CuratorID,
 			&group.AcademicYearID,
@@ -202,3 +275,9 @@ func (r *StudentGroupRepository) ListStudentGroupPublic(ctx context.Context, lim
 	}
 	return groups, nil
 }
+
+func (r *StudentGroupRepository) CountStudentGroups(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM student_group`).Scan(&total)
+	return total, err
+}
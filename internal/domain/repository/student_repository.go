@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"service/internal/domain/models"
+	"service/internal/lib/utils"
+	"service/internal/storage"
+	"strings"
 	"time"
 )
 
@@ -21,9 +24,9 @@ func (r *StudentRepository) CreateStudent(ctx context.Context, student *models.S
 		INSERT INTO student (user_id, phone, birthday, created_at, updated_at, student_group_id)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	student.CreatedAt = now
-	student.UpdateAt = now
+	student.UpdatedAt = now
 
 	_, err := r.db.ExecContext(
 		ctx, query,
@@ -31,17 +34,67 @@ func (r *StudentRepository) CreateStudent(ctx context.Context, student *models.S
 		student.Phone,
 		student.Birthday,
 		student.CreatedAt,
-		student.UpdateAt,
+		student.UpdatedAt,
 		student.StudentGroupID,
 	)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
+// CreateUserWithStudent создаёт пользователя и студента в одной транзакции.
+// Если email занят, возвращает storage.ErrDuplicate и ничего не создаёт.
+func (r *StudentRepository) CreateUserWithStudent(ctx context.Context, user *models.User, student *models.Student) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	user.Email = utils.NormalizeEmail(user.Email)
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO user (first_name, last_name, middle_name, email, password, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, user.FirstName, user.LastName, user.MiddleName, user.Email, user.Password, now, now)
+	if isDuplicateEntry(err) {
+		return storage.ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	userID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO student (user_id, phone, birthday, created_at, updated_at, student_group_id)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, userID, student.Phone, student.Birthday, now, now, student.StudentGroupID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	user.UserID, user.CreatedAt, user.UpdatedAt = models.ID(userID), now, now
+	student.UserID, student.CreatedAt, student.UpdatedAt = models.ID(userID), now, now
+	return nil
+}
+
+// GetStudentByID возвращает студента только вместе с существующим пользователем.
+// student.user_id ссылается на user без каскада (ON DELETE RESTRICT), поэтому пользователя
+// со студентом удалить нельзя; join оставлен, чтобы "осиротевшая" запись при отключённых
+// FK (импорт, ручные правки) давала sql.ErrNoRows и 404, как и публичные запросы.
 func (r *StudentRepository) GetStudentByID(ctx context.Context, userID int64) (*models.Student, error) {
 	query := `
-		SELECT user_id, phone, birthday, created_at, updated_at, student_group_id
-		FROM student
-		WHERE user_id = ?
+		SELECT s.user_id, s.phone, s.birthday, s.created_at, s.updated_at, s.student_group_id
+		FROM student s
+		JOIN user u ON s.user_id = u.user_id
+		WHERE s.user_id = ?
 	`
 	row := r.db.QueryRowContext(ctx, query, userID)
 	student := &models.Student{}
@@ -51,8 +104,41 @@ func (r *StudentRepository) GetStudentByID(ctx context.Context, userID int64) (*
 		&student.Phone,
 		&student.Birthday,
 		&student.CreatedAt,
-		&student.UpdateAt,
+		&student.UpdatedAt,
+		&student.StudentGroupID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	return student, nil
+}
+
+func (r *StudentRepository) GetStudentWithUserByID(ctx context.Context, userID int64) (*models.StudentWithUser, error) {
+	query := `
+		SELECT s.user_id, s.phone, s.birthday, s.created_at, s.updated_at, s.student_group_id,
+			u.first_name, u.last_name, u.middle_name, u.email
+		FROM student s
+		JOIN user u ON s.user_id = u.user_id
+		WHERE s.user_id = ?
+	`
+	row := r.db.QueryRowContext(ctx, query, userID)
+	student := &models.StudentWithUser{}
+	var middleName sql.NullString
+
+	err := row.Scan(
+		&student.UserID,
+		&student.Phone,
+		&student.Birthday,
+		&student.CreatedAt,
+		&student.UpdatedAt,
 		&student.StudentGroupID,
+		&student.FirstName,
+		&student.LastName,
+		&middleName,
+		&student.Email,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -60,6 +146,9 @@ func (r *StudentRepository) GetStudentByID(ctx context.Context, userID int64) (*
 		}
 		return nil, err
 	}
+	if middleName.Valid {
+		student.MiddleName = &middleName.String
+	}
 	return student, nil
 }
 
@@ -94,6 +183,12 @@ func (r *StudentRepository) GetStudentPublicByID(ctx context.Context, userID int
 	return student, nil
 }
 
+func (r *StudentRepository) StudentExists(ctx context.Context, userID int64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM student WHERE user_id = ?)`, userID).Scan(&exists)
+	return exists, err
+}
+
 func (r *StudentRepository) UpdateStudent(ctx context.Context, student *models.Student) error {
 	query := `
 		UPDATE student SET
@@ -104,26 +199,89 @@ func (r *StudentRepository) UpdateStudent(ctx context.Context, student *models.S
 		ctx, query,
 		student.Phone,
 		student.Birthday,
-		time.Now(),
+		time.Now().UTC(),
 		student.StudentGroupID,
 		student.UserID,
 	)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
+	return err
+}
+
+// PatchStudent обновляет только переданные поля
+func (r *StudentRepository) PatchStudent(ctx context.Context, userID int64, patch *models.StudentPatch) error {
+	var (
+		sets []string
+		args []interface{}
+	)
+	if patch.Phone != nil {
+		sets = append(sets, "phone = ?")
+		args = append(args, *patch.Phone)
+	}
+	if patch.Birthday != nil {
+		sets = append(sets, "birthday = ?")
+		args = append(args, *patch.Birthday)
+	}
+	if patch.StudentGroupID != nil {
+		sets = append(sets, "student_group_id = ?")
+		args = append(args, *patch.StudentGroupID)
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	sets = append(sets, "updated_at = ?")
+	args = append(args, time.Now().UTC(), userID)
+
+	query := `UPDATE student SET ` + strings.Join(sets, ", ") + ` WHERE user_id = ?`
+	_, err := r.db.ExecContext(ctx, query, args...)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
 func (r *StudentRepository) DeleteStudent(ctx context.Context, userID int64) error {
 	query := `DELETE FROM student WHERE user_id = ?`
 	_, err := r.db.ExecContext(ctx, query, userID)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
-func (r *StudentRepository) ListStudent(ctx context.Context, limit, offset int) ([]*models.Student, error) {
+func (r *StudentRepository) ListStudentWithFilters(
+	ctx context.Context,
+	studentGroupID *int64,
+	fromDate, toDate, updatedSince *time.Time,
+	limit, offset int,
+) ([]*models.Student, error) {
 	query := `
 		SELECT user_id, phone, birthday, created_at, updated_at, student_group_id
 		FROM student
-		LIMIT ? OFFSET ?
+		WHERE 1=1
 	`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	var args []interface{}
+	if studentGroupID != nil {
+		query += " AND student_group_id = ?"
+		args = append(args, *studentGroupID)
+	}
+	if fromDate != nil {
+		query += " AND created_at >= ?"
+		args = append(args, *fromDate)
+	}
+	if toDate != nil {
+		query += " AND created_at <= ?"
+		args = append(args, *toDate)
+	}
+	if updatedSince != nil {
+		query += " AND updated_at >= ?"
+		args = append(args, *updatedSince)
+	}
+	query += " ORDER BY user_id LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +295,7 @@ func (r *StudentRepository) ListStudent(ctx context.Context, limit, offset int)
 			&student.Phone,
 			&student.Birthday,
 			&student.CreatedAt,
-			&student.UpdateAt,
+			&student.UpdatedAt,
 			&student.StudentGroupID,
 		)
 		if err != nil {
@@ -148,14 +306,39 @@ func (r *StudentRepository) ListStudent(ctx context.Context, limit, offset int)
 	return students, nil
 }
 
-func (r *StudentRepository) ListStudentPublic(ctx context.Context, limit, offset int) ([]*models.StudentPublic, error) {
+func (r *StudentRepository) ListStudentPublicWithFilters(
+	ctx context.Context,
+	studentGroupID *int64,
+	fromDate, toDate, updatedSince *time.Time,
+	limit, offset int,
+) ([]*models.StudentPublic, error) {
 	query := `
 		SELECT s.user_id, u.first_name, u.last_name, u.middle_name, s.birthday, s.student_group_id
 		FROM student s
 		INNER JOIN user u ON s.user_id = u.user_id
-		ORDER BY s.user_id LIMIT ? OFFSET ?
+		WHERE 1=1
 	`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	var args []interface{}
+	if studentGroupID != nil {
+		query += " AND s.student_group_id = ?"
+		args = append(args, *studentGroupID)
+	}
+	if fromDate != nil {
+		query += " AND s.created_at >= ?"
+		args = append(args, *fromDate)
+	}
+	if toDate != nil {
+		query += " AND s.created_at <= ?"
+		args = append(args, *toDate)
+	}
+	if updatedSince != nil {
+		query += " AND s.updated_at >= ?"
+		args = append(args, *updatedSince)
+	}
+	query += " ORDER BY s.user_id LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -183,3 +366,153 @@ func (r *StudentRepository) ListStudentPublic(ctx context.Context, limit, offset
 	}
 	return students, nil
 }
+
+// ListStudentsByGroup возвращает всех студентов группы вместе с ФИО, без пагинации
+func (r *StudentRepository) ListStudentsByGroup(ctx context.Context, studentGroupID int64) ([]*models.StudentWithUser, error) {
+	query := `
+		SELECT s.user_id, s.phone, s.birthday, s.created_at, s.updated_at, s.student_group_id,
+			u.first_name, u.last_name, u.middle_name, u.email
+		FROM student s
+		INNER JOIN user u ON s.user_id = u.user_id
+		WHERE s.student_group_id = ?
+		ORDER BY u.last_name, u.first_name, s.user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, studentGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var students []*models.StudentWithUser
+	for rows.Next() {
+		student := &models.StudentWithUser{}
+		var middleName sql.NullString
+		err := rows.Scan(
+			&student.UserID,
+			&student.Phone,
+			&student.Birthday,
+			&student.CreatedAt,
+			&student.UpdatedAt,
+			&student.StudentGroupID,
+			&student.FirstName,
+			&student.LastName,
+			&middleName,
+			&student.Email,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if middleName.Valid {
+			student.MiddleName = &middleName.String
+		}
+		students = append(students, student)
+	}
+	return students, rows.Err()
+}
+
+// ListGroupRanking рейтинг студентов группы по среднему баллу за период.
+// Границы периода необязательны и включаются. Места считаются через RANK(),
+// поэтому при равном среднем место общее; внутри места порядок по ФИО и id.
+func (r *StudentRepository) ListGroupRanking(ctx context.Context, studentGroupID int64, from, to *time.Time) ([]*models.GroupRankingEntry, error) {
+	join := "LEFT JOIN grade_journal gj ON gj.student_id = s.user_id"
+	var args []interface{}
+	if from != nil {
+		join += " AND gj.created_at >= ?"
+		args = append(args, *from)
+	}
+	if to != nil {
+		join += " AND gj.created_at < ?"
+		args = append(args, to.AddDate(0, 0, 1))
+	}
+	query := `
+		SELECT CASE WHEN t.avg_grade IS NULL THEN NULL
+				ELSE RANK() OVER (ORDER BY t.avg_grade IS NULL, t.avg_grade DESC) END,
+			t.user_id, t.first_name, t.last_name, t.middle_name, t.avg_grade, t.grades_count
+		FROM (
+			SELECT s.user_id, u.first_name, u.last_name, u.middle_name,
+				AVG(gj.grade) AS avg_grade, COUNT(gj.grade_journal_id) AS grades_count
+			FROM student s
+			INNER JOIN user u ON s.user_id = u.user_id
+			` + join + `
+			WHERE s.student_group_id = ?
+			GROUP BY s.user_id, u.first_name, u.last_name, u.middle_name
+		) t
+		ORDER BY t.avg_grade IS NULL, t.avg_grade DESC, t.last_name, t.first_name, t.user_id
+	`
+	args = append(args, studentGroupID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*models.GroupRankingEntry
+	for rows.Next() {
+		item := &models.GroupRankingEntry{}
+		var rank sql.NullInt64
+		var middleName sql.NullString
+		var avg sql.NullFloat64
+		if err := rows.Scan(
+			&rank, &item.StudentID, &item.FirstName, &item.LastName, &middleName, &avg, &item.GradesCount,
+		); err != nil {
+			return nil, err
+		}
+		if rank.Valid {
+			item.Rank = &rank.Int64
+		}
+		if middleName.Valid {
+			item.MiddleName = &middleName.String
+		}
+		if avg.Valid {
+			item.AverageGrade = &avg.Float64
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ListStudentsByTeacher возвращает студентов всех групп, в которых ведёт дисциплины преподаватель.
+// Группа может встречаться в нескольких дисциплинах, поэтому студенты схлопываются DISTINCT.
+func (r *StudentRepository) ListStudentsByTeacher(ctx context.Context, teacherID int64) ([]*models.StudentWithUser, error) {
+	query := `
+		SELECT DISTINCT s.user_id, s.phone, s.birthday, s.created_at, s.updated_at, s.student_group_id,
+			u.first_name, u.last_name, u.middle_name, u.email
+		FROM discipline d
+		INNER JOIN student_group sg ON d.student_group_id = sg.student_group_id
+		INNER JOIN student s ON s.student_group_id = sg.student_group_id
+		INNER JOIN user u ON s.user_id = u.user_id
+		WHERE d.teacher_id = ?
+		ORDER BY u.last_name, u.first_name, s.user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var students []*models.StudentWithUser
+	for rows.Next() {
+		student := &models.StudentWithUser{}
+		var middleName sql.NullString
+		err := rows.Scan(
+			&student.UserID,
+			&student.Phone,
+			&student.Birthday,
+			&student.CreatedAt,
+			&student.UpdatedAt,
+			&student.StudentGroupID,
+			&student.FirstName,
+			&student.LastName,
+			&middleName,
+			&student.Email,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if middleName.Valid {
+			student.MiddleName = &middleName.String
+		}
+		students = append(students, student)
+	}
+	return students, rows.Err()
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// queryCapture запоминает последний запрос и его аргументы
+type queryCapture struct {
+	query string
+	args  []driver.Value
+}
+
+func (c *queryCapture) db(t *testing.T) *fakeDB {
+	t.Helper()
+	return &fakeDB{onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		c.query = compactSQL(query)
+		c.args = nil
+		for _, a := range args {
+			c.args = append(c.args, a.Value)
+		}
+		return &fakeRows{}, nil
+	}}
+}
+
+// where условия после WHERE 1=1 без ORDER BY
+func (c *queryCapture) where() string {
+	_, after, _ := strings.Cut(c.query, "WHERE 1=1")
+	before, _, _ := strings.Cut(after, " ORDER BY")
+	return strings.TrimSpace(before)
+}
+
+func TestListStudentWithFilters_CombinedFilters(t *testing.T) {
+	group := int64(4)
+	from := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+	since := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		group           *int64
+		from, to, since *time.Time
+		wantWhere       string
+		wantArgs        []driver.Value
+	}{
+		{
+			name:     "без фильтров",
+			wantArgs: []driver.Value{int64(20), int64(0)},
+		},
+		{
+			name:      "группа и период зачисления",
+			group:     &group,
+			from:      &from,
+			to:        &to,
+			wantWhere: "AND {p}student_group_id = ? AND {p}created_at >= ? AND {p}created_at <= ?",
+			wantArgs:  []driver.Value{int64(4), from, to, int64(20), int64(0)},
+		},
+		{
+			name:      "группа и начало периода",
+			group:     &group,
+			from:      &from,
+			wantWhere: "AND {p}student_group_id = ? AND {p}created_at >= ?",
+			wantArgs:  []driver.Value{int64(4), from, int64(20), int64(0)},
+		},
+		{
+			name:      "конец периода и updated_since",
+			to:        &to,
+			since:     &since,
+			wantWhere: "AND {p}created_at <= ? AND {p}updated_at >= ?",
+			wantArgs:  []driver.Value{to, since, int64(20), int64(0)},
+		},
+		{
+			name:      "все фильтры",
+			group:     &group,
+			from:      &from,
+			to:        &to,
+			since:     &since,
+			wantWhere: "AND {p}student_group_id = ? AND {p}created_at >= ? AND {p}created_at <= ? AND {p}updated_at >= ?",
+			wantArgs:  []driver.Value{int64(4), from, to, since, int64(20), int64(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c queryCapture
+			repo := NewStudentRepository(newFakeDB(t, c.db(t)))
+
+			// приватный и публичный варианты фильтруют одинаково, отличается только алиас таблицы
+			for _, v := range []struct {
+				prefix string
+				list   func() error
+			}{
+				{"", func() error {
+					_, err := repo.ListStudentWithFilters(context.Background(), tt.group, tt.from, tt.to, tt.since, 20, 0)
+					return err
+				}},
+				{"s.", func() error {
+					_, err := repo.ListStudentPublicWithFilters(context.Background(), tt.group, tt.from, tt.to, tt.since, 20, 0)
+					return err
+				}},
+			} {
+				if err := v.list(); err != nil {
+					t.Fatal(err)
+				}
+				if want := strings.ReplaceAll(tt.wantWhere, "{p}", v.prefix); c.where() != want {
+					t.Errorf("where = %q, want %q", c.where(), want)
+				}
+				if !reflect.DeepEqual(c.args, tt.wantArgs) {
+					t.Errorf("args = %v, want %v", c.args, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestPatchStudent_SetsOnlyProvidedColumns(t *testing.T) {
+	phone := "+79001234567"
+	group := int64(3)
+	tests := []struct {
+		name     string
+		patch    models.StudentPatch
+		wantSets string
+	}{
+		{"телефон", models.StudentPatch{Phone: &phone}, "phone = ?, updated_at = ?"},
+		{"телефон и группа", models.StudentPatch{Phone: &phone, StudentGroupID: &group}, "phone = ?, student_group_id = ?, updated_at = ?"},
+		{"пустой patch", models.StudentPatch{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeDB{}
+			if err := NewStudentRepository(newFakeDB(t, f)).PatchStudent(context.Background(), 7, &tt.patch); err != nil {
+				t.Fatal(err)
+			}
+			entries := f.entries()
+			if tt.wantSets == "" {
+				if len(entries) != 0 {
+					t.Fatalf("empty patch executed %v", entries)
+				}
+				return
+			}
+			if want := "UPDATE student SET " + tt.wantSets + " WHERE user_id = ?"; len(entries) != 1 || entries[0] != want {
+				t.Fatalf("queries = %v, want %s", entries, want)
+			}
+		})
+	}
+}
+
+func TestListGroupRanking_TiesAndOrder(t *testing.T) {
+	var query string
+	var args []driver.Value
+	db := newFakeDB(t, &fakeDB{onQuery: func(q string, a []driver.NamedValue) (driver.Rows, error) {
+		query = compactSQL(q)
+		for _, v := range a {
+			args = append(args, v.Value)
+		}
+		return &fakeRows{
+			cols: []string{"rank", "user_id", "first_name", "last_name", "middle_name", "avg_grade", "grades_count"},
+			vals: [][]driver.Value{
+				{int64(1), int64(8), "Анна", "Антонова", nil, 4.5, int64(2)},
+				{int64(1), int64(7), "Иван", "Борисов", "Петрович", 4.5, int64(4)},
+				{int64(3), int64(9), "Олег", "Васильев", nil, 3.0, int64(1)},
+				{nil, int64(10), "Ирина", "Григорьева", nil, nil, int64(0)},
+			},
+		}, nil
+	}})
+
+	to := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+	items, err := NewStudentRepository(db).ListGroupRanking(context.Background(), 2, nil, &to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ranks []interface{}
+	for _, it := range items {
+		if it.Rank == nil {
+			ranks = append(ranks, nil)
+		} else {
+			ranks = append(ranks, *it.Rank)
+		}
+	}
+	if want := []interface{}{int64(1), int64(1), int64(3), nil}; !reflect.DeepEqual(ranks, want) {
+		t.Errorf("ranks = %v, want %v", ranks, want)
+	}
+	if last := items[3]; last.AverageGrade != nil || last.GradesCount != 0 {
+		t.Errorf("student without grades = %+v, want null average", last)
+	}
+	if items[1].MiddleName == nil || *items[1].MiddleName != "Петрович" || items[0].MiddleName != nil {
+		t.Errorf("middle names = %v, %v", items[0].MiddleName, items[1].MiddleName)
+	}
+
+	// RANK() даёт общее место при равном среднем, студенты без оценок в конце
+	for _, frag := range []string{
+		"RANK() OVER (ORDER BY t.avg_grade IS NULL, t.avg_grade DESC)",
+		"ORDER BY t.avg_grade IS NULL, t.avg_grade DESC, t.last_name, t.first_name, t.user_id",
+		"AND gj.created_at < ?",
+	} {
+		if !strings.Contains(query, frag) {
+			t.Errorf("query lacks %q: %s", frag, query)
+		}
+	}
+	// to_date включается: граница — начало следующего дня
+	if want := []driver.Value{to.AddDate(0, 0, 1), int64(2)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestGetStudent_OrphanedUser(t *testing.T) {
+	// строка student есть, а user удалён в обход FK: без join строку бы нашли
+	now := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	db := newFakeDB(t, &fakeDB{onQuery: func(q string, _ []driver.NamedValue) (driver.Rows, error) {
+		if strings.Contains(compactSQL(q), "JOIN user u ON s.user_id = u.user_id") {
+			return &fakeRows{}, nil
+		}
+		return &fakeRows{
+			cols: []string{"user_id", "phone", "birthday", "created_at", "updated_at", "student_group_id"},
+			vals: [][]driver.Value{{int64(7), "+79001234567", now, now, now, int64(2)}},
+		}, nil
+	}})
+	repo := NewStudentRepository(db)
+	ctx := context.Background()
+
+	if _, err := repo.GetStudentByID(ctx, 7); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetStudentByID: err = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := repo.GetStudentWithUserByID(ctx, 7); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetStudentWithUserByID: err = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := repo.GetStudentPublicByID(ctx, 7); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetStudentPublicByID: err = %v, want sql.ErrNoRows", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"service/internal/domain/models"
+	"strings"
 	"time"
 )
 
@@ -21,9 +22,9 @@ func (r *TeacherRepository) CreateTeacher(ctx context.Context, teacher *models.T
 		INSERT INTO teacher (user_id, phone, working_experience, education, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	teacher.CreatedAt = now
-	teacher.UpdateAt = now
+	teacher.UpdatedAt = now
 
 	_, err := r.db.ExecContext(
 		ctx, query,
@@ -32,7 +33,7 @@ func (r *TeacherRepository) CreateTeacher(ctx context.Context, teacher *models.T
 		teacher.WorkingExperience,
 		teacher.Education,
 		teacher.CreatedAt,
-		teacher.UpdateAt,
+		teacher.UpdatedAt,
 	)
 	return err
 }
@@ -65,7 +66,7 @@ func (r *TeacherRepository) GetTeacherPublicByID(ctx context.Context, userID int
 	query := `
 		SELECT t.user_id, u.first_name, u.last_name, u.middle_name, t.education
 		FROM teacher t
-		JOIN "user" u ON t.user_id = u.user_id
+		JOIN user u ON t.user_id = u.user_id
 		WHERE t.user_id = ?
 	`
 	row := r.db.QueryRowContext(ctx, query, userID)
@@ -102,15 +103,47 @@ func (r *TeacherRepository) UpdateTeacher(ctx context.Context, teacher *models.T
 		teacher.Phone,
 		teacher.WorkingExperience,
 		teacher.Education,
-		time.Now(),
+		time.Now().UTC(),
 		teacher.UserID,
 	)
 	return err
 }
 
+// PatchTeacher обновляет только переданные поля, явный null записывается как NULL
+func (r *TeacherRepository) PatchTeacher(ctx context.Context, userID int64, patch *models.TeacherPatch) error {
+	var (
+		sets []string
+		args []interface{}
+	)
+	if patch.Phone != nil {
+		sets = append(sets, "phone = ?")
+		args = append(args, *patch.Phone)
+	}
+	if patch.WorkingExperience.Set {
+		sets = append(sets, "working_experience = ?")
+		args = append(args, patch.WorkingExperience.Value)
+	}
+	if patch.Education.Set {
+		sets = append(sets, "education = ?")
+		args = append(args, patch.Education.Value)
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	sets = append(sets, "updated_at = ?")
+	args = append(args, time.Now().UTC(), userID)
+
+	query := `UPDATE teacher SET ` + strings.Join(sets, ", ") + ` WHERE user_id = ?`
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
 func (r *TeacherRepository) DeleteTeacher(ctx context.Context, userID int64) error {
 	query := `DELETE FROM teacher WHERE user_id = ?`
 	_, err := r.db.ExecContext(ctx, query, userID)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
@@ -147,7 +180,7 @@ func (r *TeacherRepository) ListTeacherPublic(ctx context.Context, limit, offset
 	query := `
 		SELECT t.user_id, u.first_name, u.last_name, u.middle_name, t.education
 		FROM teacher t
-		INNER JOIN "user" u ON t.user_id = u.user_id
+		INNER JOIN user u ON t.user_id = u.user_id
 		ORDER BY t.user_id LIMIT ? OFFSET ?
 	`
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
@@ -177,3 +210,97 @@ func (r *TeacherRepository) ListTeacherPublic(ctx context.Context, limit, offset
 	}
 	return teachers, nil
 }
+
+func (r *TeacherRepository) GetTeacherPublicWithPhoneByID(ctx context.Context, userID int64) (*models.TeacherPublicWithPhone, error) {
+	query := `
+		SELECT t.user_id, u.first_name, u.last_name, u.middle_name, t.education, t.phone
+		FROM teacher t
+		JOIN user u ON t.user_id = u.user_id
+		WHERE t.user_id = ?
+	`
+	row := r.db.QueryRowContext(ctx, query, userID)
+	teacher := &models.TeacherPublicWithPhone{}
+	var middleName sql.NullString
+
+	err := row.Scan(
+		&teacher.UserID,
+		&teacher.FirstName,
+		&teacher.LastName,
+		&middleName,
+		&teacher.Education,
+		&teacher.Phone,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	if middleName.Valid {
+		teacher.MiddleName = &middleName.String
+	}
+	return teacher, nil
+}
+
+func (r *TeacherRepository) ListTeacherPublicWithPhone(ctx context.Context, limit, offset int) ([]*models.TeacherPublicWithPhone, error) {
+	query := `
+		SELECT t.user_id, u.first_name, u.last_name, u.middle_name, t.education, t.phone
+		FROM teacher t
+		INNER JOIN user u ON t.user_id = u.user_id
+		ORDER BY t.user_id LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teachers []*models.TeacherPublicWithPhone
+	for rows.Next() {
+		teacher := &models.TeacherPublicWithPhone{}
+		var middleName sql.NullString
+		err := rows.Scan(
+			&teacher.UserID,
+			&teacher.FirstName,
+			&teacher.LastName,
+			&middleName,
+			&teacher.Education,
+			&teacher.Phone,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if middleName.Valid {
+			teacher.MiddleName = &middleName.String
+		}
+		teachers = append(teachers, teacher)
+	}
+	return teachers, nil
+}
+
+func (r *TeacherRepository) CountTeacher(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teacher`).Scan(&total)
+	return total, err
+}
+
+// GetTeacherWorkload считает дисциплины преподавателя, различные группы в них
+// и студентов этих групп. Студент учитывается один раз, даже если у него
+// несколько дисциплин этого преподавателя.
+func (r *TeacherRepository) GetTeacherWorkload(ctx context.Context, teacherID int64) (*models.TeacherWorkload, error) {
+	query := `
+		SELECT
+			COUNT(DISTINCT d.discipline_id),
+			COUNT(DISTINCT d.student_group_id),
+			COUNT(DISTINCT s.user_id)
+		FROM discipline d
+		LEFT JOIN student s ON s.student_group_id = d.student_group_id
+		WHERE d.teacher_id = ?
+	`
+	wl := &models.TeacherWorkload{TeacherID: teacherID}
+	err := r.db.QueryRowContext(ctx, query, teacherID).Scan(&wl.Disciplines, &wl.Groups, &wl.Students)
+	if err != nil {
+		return nil, err
+	}
+	return wl, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestGetTeacherWorkload(t *testing.T) {
+	var query string
+	var args []driver.NamedValue
+	db := newFakeDB(t, &fakeDB{onQuery: func(q string, a []driver.NamedValue) (driver.Rows, error) {
+		query, args = compactSQL(q), a
+		return &fakeRows{cols: []string{"d", "g", "s"}, vals: [][]driver.Value{{int64(3), int64(2), int64(40)}}}, nil
+	}})
+
+	wl, err := NewTeacherRepository(db).GetTeacherWorkload(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wl.TeacherID != 10 || wl.Disciplines != 3 || wl.Groups != 2 || wl.Students != 40 {
+		t.Fatalf("workload = %+v", wl)
+	}
+	// студент с несколькими дисциплинами преподавателя считается один раз,
+	// преподаватель без студентов в группах остаётся в выборке
+	for _, frag := range []string{"COUNT(DISTINCT d.student_group_id)", "COUNT(DISTINCT s.user_id)", "LEFT JOIN student s"} {
+		if !strings.Contains(query, frag) {
+			t.Errorf("query %s lacks %s", query, frag)
+		}
+	}
+	if len(args) != 1 || args[0].Value != int64(10) {
+		t.Errorf("args = %v, want [10]", args)
+	}
+}
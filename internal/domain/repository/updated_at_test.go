@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// studentGroupTable одна строка student_group: INSERT и UPDATE пишут её,
+// SELECT читает по колонкам из запроса
+type studentGroupTable struct {
+	row map[string]driver.Value
+}
+
+func (s *studentGroupTable) db() *fakeDB {
+	return &fakeDB{
+		onExec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+			q := compactSQL(query)
+			switch {
+			case strings.HasPrefix(q, "INSERT INTO student_group"):
+				cols := strings.Split(between(q, "(", ")"), ", ")
+				s.row = map[string]driver.Value{"student_group_id": int64(1)}
+				for i, c := range cols {
+					s.row[c] = args[i].Value
+				}
+				return fakeResult{id: 1, affected: 1}, nil
+			case strings.HasPrefix(q, "UPDATE student_group"):
+				sets := strings.Split(between(q, "SET ", " WHERE"), ", ")
+				for i, set := range sets {
+					s.row[strings.TrimSuffix(set, " = ?")] = args[i].Value
+				}
+				return fakeResult{affected: 1}, nil
+			}
+			return fakeResult{}, nil
+		},
+		onQuery: func(query string, _ []driver.NamedValue) (driver.Rows, error) {
+			cols := strings.Split(between(compactSQL(query), "SELECT ", " FROM"), ", ")
+			vals := make([]driver.Value, len(cols))
+			for i, c := range cols {
+				vals[i] = s.row[c]
+			}
+			return &fakeRows{cols: cols, vals: [][]driver.Value{vals}}, nil
+		},
+	}
+}
+
+func between(s, from, to string) string {
+	_, after, _ := strings.Cut(s, from)
+	before, _, _ := strings.Cut(after, to)
+	return before
+}
+
+func TestStudentGroup_UpdatedAtReadWrite(t *testing.T) {
+	table := &studentGroupTable{}
+	f := table.db()
+	repo := NewStudentGroupRepository(newFakeDB(t, f))
+	ctx := context.Background()
+
+	group := &models.StudentGroup{StudentGroupName: "ИС-21", CuratorID: 10, AcademicYearID: 2}
+	if err := repo.CreateStudentGroup(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+	created, ok := table.row["updated_at"].(time.Time)
+	if !ok {
+		t.Fatalf("insert did not write updated_at: %v", table.row)
+	}
+
+	got, err := repo.GetStudentGroupByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.UpdatedAt.Equal(created) || !got.UpdatedAt.Equal(group.UpdatedAt) {
+		t.Fatalf("read updated_at = %s, want %s", got.UpdatedAt, created)
+	}
+
+	time.Sleep(time.Millisecond)
+	got.StudentGroupName = "ИС-22"
+	if err := repo.UpdateStudentGroup(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := repo.GetStudentGroupByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.StudentGroupName != "ИС-22" || !updated.UpdatedAt.After(created) || !updated.CreatedAt.Equal(created) {
+		t.Fatalf("after update: %+v, created at %s", updated, created)
+	}
+
+	for _, q := range f.entries() {
+		if legacyUpdateAtRe.MatchString(q) {
+			t.Errorf("query uses update_at: %s", q)
+		}
+	}
+}
+
+func TestRole_UpdatedAtRead(t *testing.T) {
+	at := time.Date(2024, 9, 1, 8, 0, 0, 0, time.UTC)
+	var insertArgs []driver.NamedValue
+	f := &fakeDB{onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		if strings.HasPrefix(compactSQL(query), "INSERT INTO roles") {
+			insertArgs = args
+			return &fakeRows{cols: []string{"role_id"}, vals: [][]driver.Value{{int64(4)}}}, nil
+		}
+		return &fakeRows{
+			cols: []string{"role_id", "role_name", "created_at", "updated_at"},
+			vals: [][]driver.Value{{int64(4), "curator", at, at.Add(time.Hour)}},
+		}, nil
+	}}
+	repo := NewRoleRepository(newFakeDB(t, f))
+
+	if _, err := repo.CreateRole(context.Background(), &models.Role{RoleName: "curator"}); err != nil {
+		t.Fatal(err)
+	}
+	if q := f.entries()[0]; !strings.Contains(q, "(role_name, created_at, updated_at)") || len(insertArgs) != 3 {
+		t.Fatalf("insert = %s with %d args", q, len(insertArgs))
+	}
+
+	for name, get := range map[string]func() (*models.Role, error){
+		"by id":   func() (*models.Role, error) { return repo.GetRoleByID(context.Background(), 4) },
+		"by name": func() (*models.Role, error) { return repo.GetRoleByName(context.Background(), "curator") },
+	} {
+		role, err := get()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !role.CreatedAt.Equal(at) || !role.UpdatedAt.Equal(at.Add(time.Hour)) {
+			t.Errorf("%s: created_at %s, updated_at %s", name, role.CreatedAt, role.UpdatedAt)
+		}
+	}
+}
+
+func TestStudentGroup_TimestampsUTC(t *testing.T) {
+	// сервер в часовом поясе, отличном от UTC: time.Now() без UTC() дал бы +03:00
+	local := time.Local
+	time.Local = time.FixedZone("MSK", 3*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	table := &studentGroupTable{}
+	repo := NewStudentGroupRepository(newFakeDB(t, table.db()))
+	ctx := context.Background()
+
+	if err := repo.CreateStudentGroup(ctx, &models.StudentGroup{StudentGroupName: "ИС-21", CuratorID: 10, AcademicYearID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	for _, col := range []string{"created_at", "updated_at"} {
+		if at, _ := table.row[col].(time.Time); at.Location() != time.UTC {
+			t.Errorf("insert %s = %v, want UTC", col, table.row[col])
+		}
+	}
+
+	group, err := repo.GetStudentGroupByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	for col, s := range map[string]string{"created_at": got.CreatedAt, "updated_at": got.UpdatedAt} {
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil || !strings.HasSuffix(s, "Z") {
+			t.Errorf("%s = %q, want RFC3339 in UTC", col, s)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var legacyUpdateAtRe = regexp.MustCompile(`\bupdate_at\b`)
+
+func TestListFilters_UpdatedSincePerEntity(t *testing.T) {
+	since := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		entity    string
+		condition string
+		list      func(db *fakeDB, t *testing.T, updatedSince *time.Time) error
+	}{
+		{"grade_journal", "AND updated_at >= ?", func(db *fakeDB, t *testing.T, s *time.Time) error {
+			_, err := NewGradeJournalRepository(newFakeDB(t, db)).ListGradeJournal(context.Background(), nil, nil, nil, nil, nil, s, nil, 20, 0)
+			return err
+		}},
+		{"grade_journal public", "AND gj.updated_at >= ?", func(db *fakeDB, t *testing.T, s *time.Time) error {
+			_, err := NewGradeJournalRepository(newFakeDB(t, db)).ListGradeJournalPublic(context.Background(), nil, nil, nil, nil, nil, s, 20, 0)
+			return err
+		}},
+		{"attendance", "AND a.updated_at >= ?", func(db *fakeDB, t *testing.T, s *time.Time) error {
+			_, err := NewAttendanceRepository(newFakeDB(t, db)).ListAttendanceWithFilters(context.Background(), nil, nil, nil, nil, s, nil, 20, 0)
+			return err
+		}},
+		{"student", "AND updated_at >= ?", func(db *fakeDB, t *testing.T, s *time.Time) error {
+			_, err := NewStudentRepository(newFakeDB(t, db)).ListStudentWithFilters(context.Background(), nil, nil, nil, s, 20, 0)
+			return err
+		}},
+		{"student public", "AND s.updated_at >= ?", func(db *fakeDB, t *testing.T, s *time.Time) error {
+			_, err := NewStudentRepository(newFakeDB(t, db)).ListStudentPublicWithFilters(context.Background(), nil, nil, nil, s, 20, 0)
+			return err
+		}},
+		{"discipline", "AND d.updated_at >= ?", func(db *fakeDB, t *testing.T, s *time.Time) error {
+			_, err := NewDisciplineRepository(newFakeDB(t, db)).ListDiscipline(context.Background(), nil, nil, nil, s, nil, 20, 0)
+			return err
+		}},
+		{"discipline public", "d.updated_at >= ?", func(db *fakeDB, t *testing.T, s *time.Time) error {
+			_, err := NewDisciplineRepository(newFakeDB(t, db)).ListDisciplinePublic(context.Background(), 20, 0, nil, nil, nil, s)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entity, func(t *testing.T) {
+			var c queryCapture
+			if err := tt.list(c.db(t), t, &since); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(c.query, tt.condition) {
+				t.Errorf("query without %q: %s", tt.condition, c.query)
+			}
+			if !containsArg(c.args, since) {
+				t.Errorf("args %v do not contain updated_since", c.args)
+			}
+			// во всех таблицах колонка называется updated_at
+			if legacyUpdateAtRe.MatchString(c.query) {
+				t.Errorf("query uses update_at: %s", c.query)
+			}
+
+			if err := tt.list(c.db(t), t, nil); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(c.query, "updated_at >=") {
+				t.Errorf("filter applied without updated_since: %s", c.query)
+			}
+		})
+	}
+}
+
+func containsArg(args []driver.Value, want time.Time) bool {
+	for _, a := range args {
+		if v, ok := a.(time.Time); ok && v.Equal(want) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,28 +5,55 @@ import (
 	"database/sql"
 	"errors"
 	"service/internal/domain/models"
+	"service/internal/lib/utils"
+	"service/internal/storage"
+	"strings"
 	"time"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *stmtCache
 }
 
 func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+	return &UserRepository{db: db, stmts: newStmtCache(db)}
+}
+
+// Close закрывает подготовленные запросы репозитория
+func (r *UserRepository) Close() error {
+	return r.stmts.Close()
 }
 
 func (r *UserRepository) CreateClient(ctx context.Context, user *models.User) error {
+	return insertClient(ctx, r.db, user)
+}
+
+// CreateClientTx создаёт пользователя в транзакции tx, фиксация остаётся за вызывающим.
+// Поля user (id, даты) заполняются сразу, до Commit.
+func (r *UserRepository) CreateClientTx(ctx context.Context, tx *sql.Tx, user *models.User) error {
+	return insertClient(ctx, tx, user)
+}
+
+// execer общий интерфейс *sql.DB и *sql.Tx для запросов без результата
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertClient(ctx context.Context, db execer, user *models.User) error {
 	query := `
 		INSERT INTO user (
 			first_name, last_name, middle_name, email, password, created_at, updated_at
 		) VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
-	now := time.Now()
+	now := time.Now().UTC()
 	user.CreatedAt = now
-	user.UpdateAt = now
+	user.UpdatedAt = now
+	user.Email = utils.NormalizeEmail(user.Email)
+	// is_active в INSERT не передаётся, в БД по умолчанию TRUE
+	user.IsActive = true
 
-	res, err := r.db.ExecContext(
+	res, err := db.ExecContext(
 		ctx, query,
 		user.FirstName,
 		user.LastName,
@@ -34,8 +61,11 @@ func (r *UserRepository) CreateClient(ctx context.Context, user *models.User) er
 		user.Email,
 		user.Password,
 		user.CreatedAt,
-		user.UpdateAt,
+		user.UpdatedAt,
 	)
+	if isDuplicateEntry(err) {
+		return storage.ErrDuplicate
+	}
 	if err != nil {
 		return err
 	}
@@ -44,13 +74,50 @@ func (r *UserRepository) CreateClient(ctx context.Context, user *models.User) er
 		return err
 	}
 
-	user.UserID = id
+	user.UserID = models.ID(id)
 	return nil
 }
 
+// CreateClientWithRole создаёт пользователя и назначает ему роль roleName в одной транзакции:
+// если назначить роль не удалось, пользователь тоже не сохраняется.
+// Если роли нет, пользователь создаётся без неё и возвращается false.
+func (r *UserRepository) CreateClientWithRole(ctx context.Context, user *models.User, roleName string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var roleID int64
+	err = tx.QueryRowContext(ctx, `SELECT role_id FROM roles WHERE role_name = ?`, roleName).Scan(&roleID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	assigned := err == nil
+
+	if err := r.CreateClientTx(ctx, tx, user); err != nil {
+		user.UserID = 0
+		return false, err
+	}
+	if assigned {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO user_roles (user_id, role_id, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+			user.UserID, roleID, user.CreatedAt, user.UpdatedAt)
+		if err != nil {
+			user.UserID = 0
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		user.UserID = 0
+		return false, err
+	}
+	return assigned, nil
+}
+
 func (r *UserRepository) GetClientByID(ctx context.Context, id int64) (*models.User, error) {
 	query := `
-		SELECT user_id, created_at, updated_at, first_name, last_name, middle_name, email, password
+		SELECT user_id, created_at, updated_at, first_name, last_name, middle_name, email, password, is_active
 		FROM user WHERE user_id = ?
 	`
 	row := r.db.QueryRowContext(ctx, query, id)
@@ -60,12 +127,13 @@ func (r *UserRepository) GetClientByID(ctx context.Context, id int64) (*models.U
 	err := row.Scan(
 		&user.UserID,
 		&user.CreatedAt,
-		&user.UpdateAt,
+		&user.UpdatedAt,
 		&user.FirstName,
 		&user.LastName,
 		&middleName,
 		&user.Email,
 		&user.Password,
+		&user.IsActive,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -79,24 +147,85 @@ func (r *UserRepository) GetClientByID(ctx context.Context, id int64) (*models.U
 	return user, nil
 }
 
+// GetUserProfile пользователь с данными из student и teacher одним запросом, без ролей.
+// Если пользователя нет — sql.ErrNoRows.
+func (r *UserRepository) GetUserProfile(ctx context.Context, id int64) (*models.UserProfile, error) {
+	query := `
+		SELECT u.user_id, u.created_at, u.updated_at, u.first_name, u.last_name, u.middle_name, u.email, u.is_active,
+			s.user_id, s.phone, s.birthday, s.student_group_id, sg.student_group_name,
+			t.user_id, t.phone, t.working_experience, t.education
+		FROM user u
+		LEFT JOIN student s ON s.user_id = u.user_id
+		LEFT JOIN student_group sg ON sg.student_group_id = s.student_group_id
+		LEFT JOIN teacher t ON t.user_id = u.user_id
+		WHERE u.user_id = ?
+	`
+	p := &models.UserProfile{}
+	var (
+		middleName                                            sql.NullString
+		studentID, groupID, teacherID                         sql.NullInt64
+		studentPhone, groupName, teacherPhone, exp, education sql.NullString
+		birthday                                              sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&p.UserID, &p.CreatedAt, &p.UpdatedAt, &p.FirstName, &p.LastName, &middleName, &p.Email, &p.IsActive,
+		&studentID, &studentPhone, &birthday, &groupID, &groupName,
+		&teacherID, &teacherPhone, &exp, &education,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if middleName.Valid {
+		p.MiddleName = &middleName.String
+	}
+
+	p.Type = models.UserTypeUser
+	if studentID.Valid {
+		p.Type = models.UserTypeStudent
+		p.Student = &models.StudentProfile{
+			Phone:            studentPhone.String,
+			Birthday:         birthday.Time,
+			StudentGroupID:   groupID.Int64,
+			StudentGroupName: groupName.String,
+		}
+	}
+	if teacherID.Valid {
+		p.Type = models.UserTypeTeacher
+		p.Teacher = &models.TeacherProfile{Phone: teacherPhone.String}
+		if exp.Valid {
+			p.Teacher.WorkingExperience = &exp.String
+		}
+		if education.Valid {
+			p.Teacher.Education = &education.String
+		}
+	}
+	return p, nil
+}
+
 func (r *UserRepository) GetClientByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT user_id, created_at, updated_at, first_name, last_name, middle_name, email, password
-		FROM user WHERE email = ?
+		SELECT user_id, created_at, updated_at, first_name, last_name, middle_name, email, password, is_active
+		FROM user WHERE LOWER(email) = ?
 	`
-	row := r.db.QueryRowContext(ctx, query, email)
+	// вызывается на каждом логине и проверке email, поэтому запрос подготовлен заранее
+	stmt, err := r.stmts.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	row := stmt.QueryRowContext(ctx, utils.NormalizeEmail(email))
 	user := &models.User{}
 	var middleName sql.NullString
 
-	err := row.Scan(
+	err = row.Scan(
 		&user.UserID,
 		&user.CreatedAt,
-		&user.UpdateAt,
+		&user.UpdatedAt,
 		&user.FirstName,
 		&user.LastName,
 		&middleName,
 		&user.Email,
 		&user.Password,
+		&user.IsActive,
 	)
 
 	if err != nil {
@@ -112,37 +241,154 @@ func (r *UserRepository) GetClientByEmail(ctx context.Context, email string) (*m
 }
 
 func (r *UserRepository) UpdateClient(ctx context.Context, user *models.User) error {
-	query := `
-		UPDATE user SET
-			first_name = ?, last_name = ?, middle_name = ?, email = ?, password = ?, updated_at = ?
-		WHERE user_id = ?
-	`
-	user.UpdateAt = time.Now()
-	_, err := r.db.ExecContext(
-		ctx, query,
+	user.UpdatedAt = time.Now().UTC()
+	user.Email = utils.NormalizeEmail(user.Email)
+	args := []interface{}{
 		user.FirstName,
 		user.LastName,
 		user.MiddleName,
 		user.Email,
-		user.Password,
-		user.UpdateAt,
-		user.UserID,
+	}
+	query := `
+		UPDATE user SET
+			first_name = ?, last_name = ?, middle_name = ?, email = ?,`
+	// пустой пароль означает "не менять", иначе PUT без пароля затирал бы хеш
+	if len(user.Password) > 0 {
+		query += ` password = ?,`
+		args = append(args, user.Password)
+	}
+	query += ` updated_at = ?
+		WHERE user_id = ?
+	`
+	args = append(args, user.UpdatedAt, user.UserID)
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if isDuplicateEntry(err) {
+		return storage.ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PatchClient обновляет только переданные поля. Пароль ожидается уже захешированным.
+func (r *UserRepository) PatchClient(ctx context.Context, id int64, patch *models.UserPatch, passwordHash []byte) error {
+	var (
+		sets []string
+		args []interface{}
 	)
+	if patch.FirstName != nil {
+		sets = append(sets, "first_name = ?")
+		args = append(args, *patch.FirstName)
+	}
+	if patch.LastName != nil {
+		sets = append(sets, "last_name = ?")
+		args = append(args, *patch.LastName)
+	}
+	if patch.MiddleName.Set {
+		sets = append(sets, "middle_name = ?")
+		args = append(args, patch.MiddleName.Value)
+	}
+	if patch.Email != nil {
+		sets = append(sets, "email = ?")
+		args = append(args, utils.NormalizeEmail(*patch.Email))
+	}
+	if passwordHash != nil {
+		sets = append(sets, "password = ?")
+		args = append(args, passwordHash)
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	sets = append(sets, "updated_at = ?")
+	args = append(args, time.Now().UTC(), id)
+
+	query := `UPDATE user SET ` + strings.Join(sets, ", ") + ` WHERE user_id = ?`
+	_, err := r.db.ExecContext(ctx, query, args...)
+	if isDuplicateEntry(err) {
+		return storage.ErrDuplicate
+	}
 	return err
 }
 
+func (r *UserRepository) UpdateClientPassword(ctx context.Context, id int64, password []byte) error {
+	query := `UPDATE user SET password = ?, updated_at = ? WHERE user_id = ?`
+	res, err := r.db.ExecContext(ctx, query, password, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetClientActive включает или выключает пользователя без удаления записи
+func (r *UserRepository) SetClientActive(ctx context.Context, id int64, active bool) error {
+	query := `UPDATE user SET is_active = ?, updated_at = ? WHERE user_id = ?`
+	res, err := r.db.ExecContext(ctx, query, active, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// IsClientActive статус пользователя для проверки токена, sql.ErrNoRows — пользователя нет
+func (r *UserRepository) IsClientActive(ctx context.Context, id int64) (bool, error) {
+	// вызывается на каждом защищённом запросе, поэтому запрос подготовлен заранее
+	stmt, err := r.stmts.prepare(ctx, `SELECT is_active FROM user WHERE user_id = ?`)
+	if err != nil {
+		return false, err
+	}
+	var active bool
+	if err := stmt.QueryRowContext(ctx, id).Scan(&active); err != nil {
+		return false, err
+	}
+	return active, nil
+}
+
 func (r *UserRepository) DeleteClient(ctx context.Context, id int64) error {
 	query := `DELETE FROM user WHERE user_id = ?`
 	_, err := r.db.ExecContext(ctx, query, id)
+	if fkErr := foreignKeyError(err); fkErr != nil {
+		return fkErr
+	}
 	return err
 }
 
-func (r *UserRepository) ListClient(ctx context.Context, limit, offset int) ([]*models.User, error) {
+// ListClient возвращает пользователей; active != nil фильтрует по is_active
+func (r *UserRepository) ListClient(ctx context.Context, active *bool, limit, offset int) ([]*models.User, error) {
 	query := `
-		SELECT user_id, created_at, updated_at, first_name, last_name, middle_name, email, password
-		FROM user ORDER BY user_id LIMIT ? OFFSET ?
-	`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+		SELECT user_id, created_at, updated_at, first_name, last_name, middle_name, email, password, is_active
+		FROM user WHERE 1=1`
+	var args []interface{}
+	if active != nil {
+		query += " AND is_active = ?"
+		args = append(args, *active)
+	}
+	query += " ORDER BY user_id LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -155,12 +401,13 @@ func (r *UserRepository) ListClient(ctx context.Context, limit, offset int) ([]*
 		err := rows.Scan(
 			&user.UserID,
 			&user.CreatedAt,
-			&user.UpdateAt,
+			&user.UpdatedAt,
 			&user.FirstName,
 			&user.LastName,
 			&middleName,
 			&user.Email,
 			&user.Password,
+			&user.IsActive,
 		)
 		if err != nil {
 			return nil, err
@@ -172,3 +419,60 @@ func (r *UserRepository) ListClient(ctx context.Context, limit, offset int) ([]*
 	}
 	return users, nil
 }
+
+// GetClientsByIDs загружает пользователей одним запросом, ключ — user_id.
+// Отсутствующие id в результат не попадают.
+func (r *UserRepository) GetClientsByIDs(ctx context.Context, ids []int64) (map[int64]*models.User, error) {
+	users := make(map[int64]*models.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	placeholders, args := inClause(ids)
+	query := `
+		SELECT user_id, created_at, updated_at, first_name, last_name, middle_name, email, password, is_active
+		FROM user WHERE user_id IN (` + placeholders + `)
+	`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := &models.User{}
+		var middleName sql.NullString
+		err := rows.Scan(
+			&user.UserID,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.FirstName,
+			&user.LastName,
+			&middleName,
+			&user.Email,
+			&user.Password,
+			&user.IsActive,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if middleName.Valid {
+			user.MiddleName = &middleName.String
+		}
+		users[int64(user.UserID)] = user
+	}
+	return users, rows.Err()
+}
+
+// CountClient считает пользователей с тем же фильтром, что и ListClient
+func (r *UserRepository) CountClient(ctx context.Context, active *bool) (int64, error) {
+	query := `SELECT COUNT(*) FROM user WHERE 1=1`
+	var args []interface{}
+	if active != nil {
+		query += " AND is_active = ?"
+		args = append(args, *active)
+	}
+	var total int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&total)
+	return total, err
+}
@@ -0,0 +1,388 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// roleDB fakeDB, где роль находится, а вставка в user_roles завершается ошибкой roleErr
+func roleDB(t *testing.T, roleErr error) (*fakeDB, *UserRepository) {
+	t.Helper()
+	f := &fakeDB{
+		onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+			return &fakeRows{cols: []string{"role_id"}, vals: [][]driver.Value{{int64(3)}}}, nil
+		},
+		onExec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+			if strings.Contains(query, "INSERT INTO user_roles") {
+				if roleErr != nil {
+					return nil, roleErr
+				}
+				return fakeResult{affected: 1}, nil
+			}
+			return fakeResult{id: 10, affected: 1}, nil
+		},
+	}
+	return f, NewUserRepository(newFakeDB(t, f))
+}
+
+func TestCreateClientWithRole_RollsBackWhenRoleInsertFails(t *testing.T) {
+	roleErr := errors.New("role insert failed")
+	f, repo := roleDB(t, roleErr)
+
+	user := &models.User{Email: "New@Example.com"}
+	assigned, err := repo.CreateClientWithRole(context.Background(), user, "student")
+	if !errors.Is(err, roleErr) {
+		t.Fatalf("err = %v, want %v", err, roleErr)
+	}
+	if assigned {
+		t.Fatal("assigned = true on failure")
+	}
+	if user.UserID != 0 {
+		t.Fatalf("user id = %d, must be reset after rollback", user.UserID)
+	}
+
+	log := f.entries()
+	if last := log[len(log)-1]; last != "ROLLBACK" {
+		t.Fatalf("transaction ended with %q, want ROLLBACK: %v", last, log)
+	}
+	for _, e := range log {
+		if e == "COMMIT" {
+			t.Fatalf("transaction was committed: %v", log)
+		}
+	}
+}
+
+func TestCreateClientWithRole_CommitsUserAndRole(t *testing.T) {
+	f, repo := roleDB(t, nil)
+
+	user := &models.User{Email: "new@example.com"}
+	assigned, err := repo.CreateClientWithRole(context.Background(), user, "student")
+	if err != nil || !assigned {
+		t.Fatalf("assigned %v, err %v", assigned, err)
+	}
+	if user.UserID != 10 {
+		t.Fatalf("user id = %d, want 10", user.UserID)
+	}
+
+	log := f.entries()
+	if log[0] != "BEGIN" || log[len(log)-1] != "COMMIT" {
+		t.Fatalf("unexpected transaction log: %v", log)
+	}
+	var inserts int
+	for _, e := range log {
+		if strings.HasPrefix(e, "INSERT") {
+			inserts++
+		}
+	}
+	if inserts != 2 {
+		t.Fatalf("inserts = %d, want user and user_roles: %v", inserts, log)
+	}
+}
+
+func TestUpdateClient_PasswordOptionalAndMissingUser(t *testing.T) {
+	var query string
+	affected := int64(1)
+	f := &fakeDB{onExec: func(q string, args []driver.NamedValue) (driver.Result, error) {
+		query = compactSQL(q)
+		return fakeResult{affected: affected}, nil
+	}}
+	repo := NewUserRepository(newFakeDB(t, f))
+
+	if err := repo.UpdateClient(context.Background(), &models.User{UserID: 1, Email: "a@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(query, "password") {
+		t.Fatalf("empty password must not be written: %q", query)
+	}
+	if err := repo.UpdateClient(context.Background(), &models.User{UserID: 1, Password: []byte("hash")}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(query, "password = ?") {
+		t.Fatalf("password is not updated: %q", query)
+	}
+
+	affected = 0
+	if err := repo.UpdateClient(context.Background(), &models.User{UserID: 404}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+// seededUsers fakeDB с таблицей user, отвечающий на поиск по LOWER(email)
+func seededUsers(t testing.TB, emails ...string) *fakeDB {
+	t.Helper()
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &fakeDB{onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		rows := &fakeRows{cols: []string{"user_id", "created_at", "updated_at", "first_name", "last_name",
+			"middle_name", "email", "password", "is_active"}}
+		for i, email := range emails {
+			if email == args[0].Value {
+				rows.vals = append(rows.vals, []driver.Value{int64(i + 1), created, created, "Иван", "Иванов",
+					nil, email, []byte("hash"), true})
+			}
+		}
+		return rows, nil
+	}}
+}
+
+func TestGetClientByEmail_PreparedOnce(t *testing.T) {
+	f := seededUsers(t, "ivanov@example.com", "petrov@example.com")
+	repo := NewUserRepository(newFakeDB(t, f))
+	t.Cleanup(func() { _ = repo.Close() })
+
+	for _, email := range []string{"ivanov@example.com", "IVANOV@example.com", " Petrov@Example.com "} {
+		user, err := repo.GetClientByEmail(context.Background(), email)
+		if err != nil {
+			t.Fatalf("%q: %v", email, err)
+		}
+		if !strings.EqualFold(user.Email, strings.TrimSpace(email)) {
+			t.Fatalf("%q: got user %s", email, user.Email)
+		}
+	}
+	if _, err := repo.GetClientByEmail(context.Background(), "nobody@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unknown email: err = %v, want sql.ErrNoRows", err)
+	}
+	if n := f.prepared("LOWER(email) = ?"); n != 1 {
+		t.Fatalf("LOWER(email) statement prepared %d times, want 1", n)
+	}
+}
+
+func BenchmarkGetClientByEmail(b *testing.B) {
+	emails := make([]string, 1000)
+	for i := range emails {
+		emails[i] = fmt.Sprintf("user%d@example.com", i)
+	}
+	db := sql.OpenDB(seededUsers(b, emails...))
+	defer db.Close()
+	repo := NewUserRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetClientByEmail(ctx, emails[i%len(emails)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEmailCasing_RegisterAndLoginWithDifferentCase(t *testing.T) {
+	var stored []string
+	f := &fakeDB{
+		onExec: func(query string, args []driver.NamedValue) (driver.Result, error) {
+			stored = append(stored, args[3].Value.(string))
+			return fakeResult{id: int64(len(stored)), affected: 1}, nil
+		},
+		onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+			return seededUsers(t, stored...).onQuery(query, args)
+		},
+	}
+	repo := NewUserRepository(newFakeDB(t, f))
+	t.Cleanup(func() { _ = repo.Close() })
+
+	if err := repo.CreateClient(context.Background(), &models.User{Email: " Ivanov@Example.COM"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0] != "ivanov@example.com" {
+		t.Fatalf("stored emails %q, want lowercase", stored)
+	}
+	for _, email := range []string{"ivanov@example.com", "IVANOV@EXAMPLE.COM", "Ivanov@example.com "} {
+		user, err := repo.GetClientByEmail(context.Background(), email)
+		if err != nil {
+			t.Fatalf("login as %q: %v", email, err)
+		}
+		if user.UserID != 1 {
+			t.Fatalf("login as %q: user %d", email, user.UserID)
+		}
+	}
+}
+
+func TestInClause(t *testing.T) {
+	tests := []struct {
+		ids  []int64
+		want string
+	}{
+		{[]int64{7}, "?"},
+		{[]int64{3, 1, 2}, "?, ?, ?"},
+		{[]int64{5, 5}, "?, ?"},
+	}
+	for _, tt := range tests {
+		placeholders, args := inClause(tt.ids)
+		if placeholders != tt.want {
+			t.Errorf("inClause(%v) placeholders = %q, want %q", tt.ids, placeholders, tt.want)
+		}
+		if len(args) != len(tt.ids) {
+			t.Fatalf("inClause(%v) args = %v", tt.ids, args)
+		}
+		for i, id := range tt.ids {
+			if args[i] != id {
+				t.Errorf("inClause(%v) args[%d] = %v, want %d", tt.ids, i, args[i], id)
+			}
+		}
+	}
+}
+
+func TestGetClientsByIDs_SingleQuery(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var gotArgs []driver.Value
+	f := &fakeDB{onQuery: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+		rows := &fakeRows{cols: []string{"user_id", "created_at", "updated_at", "first_name", "last_name", "middle_name", "email", "password", "is_active"}}
+		for _, a := range args {
+			gotArgs = append(gotArgs, a.Value)
+			// пользователя 9 нет в БД
+			if id := a.Value.(int64); id != 9 {
+				rows.vals = append(rows.vals, []driver.Value{id, created, created, "Имя", "Фамилия", nil, fmt.Sprintf("u%d@example.com", id), []byte("hash"), true})
+			}
+		}
+		return rows, nil
+	}}
+	repo := NewUserRepository(newFakeDB(t, f))
+	t.Cleanup(func() { _ = repo.Close() })
+
+	users, err := repo.GetClientsByIDs(context.Background(), []int64{3, 9, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.entries()); n != 1 {
+		t.Fatalf("executed %d queries, want one: %v", n, f.entries())
+	}
+	if q := f.entries()[0]; !strings.Contains(q, "WHERE user_id IN (?, ?, ?)") {
+		t.Fatalf("query = %s", q)
+	}
+	if !reflect.DeepEqual(gotArgs, []driver.Value{int64(3), int64(9), int64(1)}) {
+		t.Fatalf("args = %v", gotArgs)
+	}
+	if len(users) != 2 || users[3].Email != "u3@example.com" || users[1].Email != "u1@example.com" {
+		t.Fatalf("users = %v", users)
+	}
+	if _, ok := users[9]; ok {
+		t.Fatal("missing user present in result")
+	}
+
+	// пустой список не ходит в БД
+	users, err = repo.GetClientsByIDs(context.Background(), nil)
+	if err != nil || len(users) != 0 || len(f.entries()) != 1 {
+		t.Fatalf("empty ids: users %v, err %v, queries %d", users, err, len(f.entries()))
+	}
+}
+
+func TestUpdateClient_DuplicateEmail(t *testing.T) {
+	f := &fakeDB{onExec: func(string, []driver.NamedValue) (driver.Result, error) {
+		return nil, &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'petrov@example.com' for key 'user.email'"}
+	}}
+	repo := NewUserRepository(newFakeDB(t, f))
+	t.Cleanup(func() { _ = repo.Close() })
+
+	err := repo.UpdateClient(context.Background(), &models.User{UserID: 5, Email: "petrov@example.com"})
+	if !errors.Is(err, storage.ErrDuplicate) {
+		t.Fatalf("err = %v, want storage.ErrDuplicate", err)
+	}
+}
+
+func TestPatchClient_SetsOnlyProvidedColumns(t *testing.T) {
+	name := "Иоанн"
+	email := "Ivan@Example.com"
+	tests := []struct {
+		name     string
+		patch    models.UserPatch
+		hash     []byte
+		wantSets string
+		wantArgs int
+	}{
+		{"только имя", models.UserPatch{FirstName: &name}, nil, "first_name = ?, updated_at = ?", 3},
+		{"email и пароль", models.UserPatch{Email: &email}, []byte("hash"), "email = ?, password = ?, updated_at = ?", 4},
+		{"очистка отчества", models.UserPatch{MiddleName: models.Nullable[string]{Set: true}}, nil, "middle_name = ?, updated_at = ?", 3},
+		{"пустой patch", models.UserPatch{}, nil, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []driver.NamedValue
+			f := &fakeDB{onExec: func(_ string, a []driver.NamedValue) (driver.Result, error) {
+				args = a
+				return fakeResult{affected: 1}, nil
+			}}
+			repo := NewUserRepository(newFakeDB(t, f))
+			t.Cleanup(func() { _ = repo.Close() })
+
+			if err := repo.PatchClient(context.Background(), 5, &tt.patch, tt.hash); err != nil {
+				t.Fatal(err)
+			}
+			entries := f.entries()
+			if tt.wantSets == "" {
+				if len(entries) != 0 {
+					t.Fatalf("empty patch executed %v", entries)
+				}
+				return
+			}
+			if want := "UPDATE user SET " + tt.wantSets + " WHERE user_id = ?"; entries[len(entries)-1] != want {
+				t.Fatalf("query = %s, want %s", entries[len(entries)-1], want)
+			}
+			if len(args) != tt.wantArgs || args[len(args)-1].Value != int64(5) {
+				t.Fatalf("args = %v", args)
+			}
+			if tt.patch.Email != nil && args[0].Value != "ivan@example.com" {
+				t.Errorf("email arg = %v, want normalized", args[0].Value)
+			}
+		})
+	}
+}
+
+func TestGetUserProfile(t *testing.T) {
+	now := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	birthday := time.Date(2005, 3, 14, 0, 0, 0, 0, time.UTC)
+	// колонки: user (8), student (5), teacher (4); отсутствующая сторона LEFT JOIN — NULL
+	rows := map[int64][]driver.Value{
+		7: {int64(7), now, now, "Анна", "Иванова", nil, "anna@example.com", true,
+			int64(7), "+79001234567", birthday, int64(2), "ИВТ-21",
+			nil, nil, nil, nil},
+		10: {int64(10), now, now, "Олег", "Петров", "Ильич", "oleg@example.com", true,
+			nil, nil, nil, nil, nil,
+			int64(10), "+79007654321", nil, "МГУ"},
+	}
+	db := newFakeDB(t, &fakeDB{onQuery: func(_ string, args []driver.NamedValue) (driver.Rows, error) {
+		cols := make([]string, 17)
+		for i := range cols {
+			cols[i] = fmt.Sprintf("c%d", i)
+		}
+		r := &fakeRows{cols: cols}
+		if row, ok := rows[args[0].Value.(int64)]; ok {
+			r.vals = [][]driver.Value{row}
+		}
+		return r, nil
+	}})
+	repo := NewUserRepository(db)
+
+	student, err := repo.GetUserProfile(context.Background(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantStudent := &models.StudentProfile{Phone: "+79001234567", Birthday: birthday, StudentGroupID: 2, StudentGroupName: "ИВТ-21"}
+	if student.Type != models.UserTypeStudent || student.Teacher != nil || !reflect.DeepEqual(student.Student, wantStudent) || student.MiddleName != nil {
+		t.Errorf("student profile = %+v, student block %+v", student, student.Student)
+	}
+
+	teacher, err := repo.GetUserProfile(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if teacher.Type != models.UserTypeTeacher || teacher.Student != nil || teacher.Teacher == nil ||
+		teacher.Teacher.Phone != "+79007654321" || teacher.Teacher.WorkingExperience != nil ||
+		teacher.Teacher.Education == nil || *teacher.Teacher.Education != "МГУ" || *teacher.MiddleName != "Ильич" {
+		t.Errorf("teacher profile = %+v, teacher block %+v", teacher, teacher.Teacher)
+	}
+
+	if _, err := repo.GetUserProfile(context.Background(), 404); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing user: err = %v, want sql.ErrNoRows", err)
+	}
+}
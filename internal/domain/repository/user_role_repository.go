@@ -8,19 +8,28 @@ import (
 )
 
 type UserRoleRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts *stmtCache
 }
 
 func NewUserRoleRepository(db *sql.DB) *UserRoleRepository {
-	return &UserRoleRepository{db: db}
+	return &UserRoleRepository{db: db, stmts: newStmtCache(db)}
 }
 
+// Close закрывает подготовленные запросы репозитория
+func (r *UserRoleRepository) Close() error {
+	return r.stmts.Close()
+}
+
+// AssignRole идемпотентна: повторное назначение той же роли ничего не меняет.
+// INSERT IGNORE не подходит — он глушит и ошибки внешних ключей.
 func (r *UserRoleRepository) AssignRole(ctx context.Context, userID, roleID int64) error {
+	now := time.Now().UTC()
 	_, err := r.db.ExecContext(ctx,
 		`INSERT INTO user_roles (user_id, role_id, created_at, updated_at)
 		 VALUES (?, ?, ?, ?)
-		 ON CONFLICT (user_id, role_id) DO NOTHING`,
-		userID, roleID, time.Now(),
+		 ON DUPLICATE KEY UPDATE user_id = user_id`,
+		userID, roleID, now, now,
 	)
 	if err != nil {
 		return err
@@ -35,10 +44,15 @@ func (r *UserRoleRepository) RemoveRole(ctx context.Context, userID, roleID int6
 }
 
 func (r *UserRoleRepository) GetRolesByUserID(ctx context.Context, userID int64) ([]*models.UserRole, error) {
-	rows, err := r.db.QueryContext(ctx,
+	// запрос RBAC выполняется на каждом защищённом запросе
+	stmt, err := r.stmts.prepare(ctx,
 		`SELECT created_at, updated_at, role_id, user_id
 		 FROM user_roles
-		 WHERE user_id = ?`, userID)
+		 WHERE user_id = ?`)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +61,7 @@ func (r *UserRoleRepository) GetRolesByUserID(ctx context.Context, userID int64)
 	var roles []*models.UserRole
 	for rows.Next() {
 		var ur models.UserRole
-		if err := rows.Scan(&ur.CreatedAt, &ur.UpdateAt, &ur.RoleID, &ur.UserID); err != nil {
+		if err := rows.Scan(&ur.CreatedAt, &ur.UpdatedAt, &ur.RoleID, &ur.UserID); err != nil {
 			return nil, err
 		}
 		roles = append(roles, &ur)
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssignRole(t *testing.T) {
+	var query string
+	var args []driver.NamedValue
+	f := &fakeDB{
+		onExec: func(q string, a []driver.NamedValue) (driver.Result, error) {
+			query, args = compactSQL(q), a
+			return fakeResult{affected: 1}, nil
+		},
+	}
+	if err := NewUserRoleRepository(newFakeDB(t, f)).AssignRole(context.Background(), 7, 3); err != nil {
+		t.Fatal(err)
+	}
+	// повтор не должен падать на первичном ключе (role_id, user_id)
+	if !strings.Contains(query, "ON DUPLICATE KEY UPDATE") {
+		t.Errorf("query = %s, want MySQL upsert", query)
+	}
+	if n := strings.Count(query, "?"); n != len(args) || n != 4 {
+		t.Fatalf("query = %s with %d args, want 4 placeholders and 4 args", query, len(args))
+	}
+	if args[0].Value != int64(7) || args[1].Value != int64(3) {
+		t.Errorf("args = %v, want user 7, role 3", args)
+	}
+	created, ok := args[2].Value.(time.Time)
+	if !ok || created.IsZero() || args[3].Value != created {
+		t.Errorf("created_at = %v, updated_at = %v; want the same non-zero time", args[2].Value, args[3].Value)
+	}
+}
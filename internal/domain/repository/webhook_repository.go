@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"service/internal/domain/models"
+	"strings"
+	"time"
+)
+
+type webhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *webhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateWebhook(ctx context.Context, wh *models.Webhook) error {
+	query := `
+		INSERT INTO webhook (url, event_types, secret, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	now := time.Now().UTC()
+	wh.CreatedAt = now
+	wh.UpdatedAt = now
+
+	res, err := r.db.ExecContext(ctx, query, wh.URL, strings.Join(wh.EventTypes, ","), wh.Secret, wh.CreatedAt, wh.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err == nil {
+		wh.WebhookID = id
+	}
+	return err
+}
+
+func (r *webhookRepository) GetWebhookByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	query := `
+		SELECT webhook_id, created_at, updated_at, url, event_types, secret
+		FROM webhook
+		WHERE webhook_id = ?
+	`
+	wh := &models.Webhook{}
+	var eventTypes string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&wh.WebhookID,
+		&wh.CreatedAt,
+		&wh.UpdatedAt,
+		&wh.URL,
+		&eventTypes,
+		&wh.Secret,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	wh.EventTypes = splitEventTypes(eventTypes)
+	return wh, nil
+}
+
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, wh *models.Webhook) error {
+	query := `
+		UPDATE webhook
+		SET url = ?, event_types = ?, secret = ?, updated_at = ?
+		WHERE webhook_id = ?
+	`
+	_, err := r.db.ExecContext(ctx, query, wh.URL, strings.Join(wh.EventTypes, ","), wh.Secret, time.Now().UTC(), wh.WebhookID)
+	return err
+}
+
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhook WHERE webhook_id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *webhookRepository) ListWebhook(ctx context.Context, limit, offset int) ([]*models.Webhook, error) {
+	query := `
+		SELECT webhook_id, created_at, updated_at, url, event_types, secret
+		FROM webhook
+		ORDER BY webhook_id
+		LIMIT ? OFFSET ?
+	`
+	return r.queryWebhooks(ctx, query, limit, offset)
+}
+
+// ListWebhookByEvent возвращает вебхуки, подписанные на событие
+func (r *webhookRepository) ListWebhookByEvent(ctx context.Context, event string) ([]*models.Webhook, error) {
+	query := `
+		SELECT webhook_id, created_at, updated_at, url, event_types, secret
+		FROM webhook
+		WHERE FIND_IN_SET(?, event_types) > 0
+		ORDER BY webhook_id
+	`
+	return r.queryWebhooks(ctx, query, event)
+}
+
+func (r *webhookRepository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]*models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hooks []*models.Webhook
+	for rows.Next() {
+		wh := &models.Webhook{}
+		var eventTypes string
+		err := rows.Scan(
+			&wh.WebhookID,
+			&wh.CreatedAt,
+			&wh.UpdatedAt,
+			&wh.URL,
+			&eventTypes,
+			&wh.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		wh.EventTypes = splitEventTypes(eventTypes)
+		hooks = append(hooks, wh)
+	}
+	return hooks, rows.Err()
+}
+
+func splitEventTypes(s string) []string {
+	var types []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
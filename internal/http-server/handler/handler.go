@@ -1,18 +1,33 @@
 package handler
 
 import (
+	"crypto/tls"
 	"database/sql"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"service/internal/config"
 	"service/internal/domain/repository"
 	v1 "service/internal/http-server/handler/v1"
 	middle "service/internal/http-server/middleware"
+	"service/internal/http-server/middleware/clientip"
+	"service/internal/http-server/middleware/idempotency"
 	"service/internal/http-server/middleware/logger"
 	"service/internal/http-server/middleware/permissions"
+	"service/internal/http-server/middleware/ratelimit"
+	"service/internal/http-server/middleware/timeout"
+	resp "service/internal/lib/api/response"
+	"service/internal/lib/jwt/blocklist"
+	"service/internal/lib/notifier"
+	"service/internal/lib/notifier/smtp"
+	"service/internal/lib/phone"
+	"service/internal/lib/webhook"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
 
 	_ "service/internal/docs"
 
@@ -24,30 +39,57 @@ func NewServer(
 	cfg *config.Config,
 	db *sql.DB,
 ) (*http.Server, error) {
+	trustedProxies, err := clientip.ParseTrusted(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
+	router.Use(clientip.New(trustedProxies))
 	router.Use(middleware.Logger)
 	router.Use(logger.New(log))
 	router.Use(middleware.Recoverer)
+	router.Use(ratelimit.New(cfg.GlobalRateLimit.RPS, cfg.GlobalRateLimit.Burst, log))
 	router.Use(middleware.URLFormat)
+	// HEAD обслуживается GET-обработчиком без тела ответа
+	router.Use(getHead)
+	router.MethodNotAllowed(methodNotAllowed(router))
 
+	userRoleRepository := repository.NewUserRoleRepository(db)
+	rolePermissionRepository := repository.NewRolePermissionRepository(db)
 	rbacMiddleware := permissions.NewRBACMiddleware(
-		repository.NewUserRoleRepository(db),
-		repository.NewRolePermissionRepository(db),
+		userRoleRepository,
+		rolePermissionRepository,
 		repository.NewPermissionRepository(db),
 		log,
+		cfg.RBAC.TokenClaims,
 	)
 
-	auditLogRepository := repository.NewAuditLogRepository(db)
+	auditLogRepository := repository.NewAuditLogRepository(db, cfg.Audit.StoreSnapshots)
+
+	webhookRepository := repository.NewWebhookRepository(db)
+	webhookHandler := v1.NewWebhookHandler(webhookRepository, auditLogRepository)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepository, 100, log)
 
 	userRepository := repository.NewUserRepository(db)
-	userHandler := v1.NewUserHandler(userRepository, auditLogRepository)
+	userHandler := v1.NewUserHandler(userRepository, userRoleRepository, auditLogRepository)
+	auditLogHandler := v1.NewAuditLogHandler(auditLogRepository, auditLogRepository, auditLogRepository, userRepository)
+
+	tokenBlocklist := blocklist.NewMemory()
+	var tokenAccess v1.TokenAccessLoader
+	if cfg.RBAC.TokenClaims {
+		tokenAccess = rbacMiddleware
+	}
+	authHandler := v1.NewAuthHandler(userRepository, tokenBlocklist, tokenAccess, cfg.JwtSecret, cfg.JwtTTL, cfg.DefaultRole)
+
+	phones := phone.Normalizer{Strict: cfg.Validation.PhoneStrict}
 
-	authHandler := v1.NewAuthHandler(userRepository, cfg.JwtSecret)
+	studentRepository := repository.NewStudentRepository(db)
 
 	teacherRepository := repository.NewTeacherRepository(db)
-	teacherHandler := v1.NewTeacherHandler(teacherRepository, auditLogRepository)
+	teacherHandler := v1.NewTeacherHandler(teacherRepository, studentRepository, auditLogRepository, phones)
 
 	permissionRepository := repository.NewPermissionRepository(db)
 	permissionHandler := v1.NewPermissionHandler(permissionRepository, auditLogRepository)
@@ -55,35 +97,53 @@ func NewServer(
 	roleRepository := repository.NewRoleRepository(db)
 	roleHandler := v1.NewRoleHandler(roleRepository, auditLogRepository)
 
-	userRoleRepository := repository.NewUserRoleRepository(db)
 	userRoleHandler := v1.NewUserRoleHandler(userRoleRepository, auditLogRepository)
 
-	rolePermissionRepository := repository.NewRolePermissionRepository(db)
+	meHandler := v1.NewMeHandler(userRepository, userRoleRepository)
+
 	rolePermissionHandler := v1.NewRolePermissionHandler(rolePermissionRepository)
 
-	studentRepository := repository.NewStudentRepository(db)
-	studentHandler := v1.NewStudentHandler(studentRepository, auditLogRepository)
+	disciplineRepository := repository.NewDisciplineRepository(db)
 
 	studentGroupRepository := repository.NewStudentGroupRepository(db)
-	studentGroupHandler := v1.NewStudentGroupHandler(studentGroupRepository, auditLogRepository)
+	academicYearRepository := repository.NewAcademicYearRepository(db)
+
+	studentHandler := v1.NewStudentHandler(studentRepository, disciplineRepository, studentGroupRepository, academicYearRepository, auditLogRepository, v1.StudentYearPolicy{
+		Enabled:               cfg.Validation.StudentAcademicYear,
+		CurrentAcademicYearID: cfg.Validation.CurrentAcademicYearID,
+	}, phones, cfg.MaxBatchSize)
+
+	studentGroupHandler := v1.NewStudentGroupHandler(studentGroupRepository, studentRepository, auditLogRepository)
+
+	semesterRepository := repository.NewSemesterRepository(db)
 
 	curriculumRepository := repository.NewCurriculumRepository(db)
-	curriculumHandler := v1.NewCurriculumHandler(curriculumRepository, auditLogRepository)
+	curriculumHandler := v1.NewCurriculumHandler(curriculumRepository, semesterRepository, auditLogRepository)
 
 	gradeJournalRepository := repository.NewGradeJournalRepository(db)
-	gradeJournalHandler := v1.NewGradeJournalHandler(gradeJournalRepository, auditLogRepository)
+	var gradeNotifier notifier.Notifier
+	if cfg.SMTP.Host != "" {
+		gradeNotifier = notifier.NewAsync(smtp.New(cfg.SMTP), 100, log)
+	}
+	gradeJournalHandler := v1.NewGradeJournalHandler(gradeJournalRepository, studentRepository, disciplineRepository, auditLogRepository, userRepository, gradeNotifier, webhookDispatcher, cfg.MaxBatchSize)
 
 	attendanceRepository := repository.NewAttendanceRepository(db)
-	attendanceHandler := v1.NewAttendanceHandler(attendanceRepository, auditLogRepository)
+	attendanceHandler := v1.NewAttendanceHandler(attendanceRepository, studentRepository, semesterRepository, auditLogRepository, webhookDispatcher)
 
-	semesterRepository := repository.NewSemesterRepository(db)
 	semesterHandler := v1.NewSemesterHandler(semesterRepository, auditLogRepository)
 
-	disciplineRepository := repository.NewDisciplineRepository(db)
-	disciplineHandler := v1.NewDisciplineHandler(disciplineRepository, auditLogRepository)
+	disciplineHandler := v1.NewDisciplineHandler(disciplineRepository, auditLogRepository, cfg.Validation.DisciplineAcademicYear)
 
-	academicYearRepository := repository.NewAcademicYearRepository(db)
-	academicYearHandler := v1.NewAcademicYearHandler(academicYearRepository, auditLogRepository)
+	academicYearHandler := v1.NewAcademicYearHandler(academicYearRepository, semesterRepository, auditLogRepository)
+
+	dashboardRepository := repository.NewDashboardRepository(db)
+	dashboardHandler := v1.NewDashboardHandler(dashboardRepository)
+
+	statsHandler := v1.NewStatsHandler(repository.NewStatsRepository(db))
+
+	// idempotent подключается к POST-маршрутам после проверки прав, чтобы повтор ответа не обходил RBAC.
+	// Сброс пароля не подключён: сгенерированный пароль попал бы в таблицу ключей
+	idempotent := idempotency.New(repository.NewIdempotencyKeyRepository(db), log)
 
 	router.Get("/swagger/*", httpSwagger.WrapHandler)
 
@@ -93,77 +153,116 @@ func NewServer(
 	})
 
 	router.Group(func(r chi.Router) {
-		r.Use(middle.JWTAuth(cfg.JwtSecret))
+		r.Use(timeout.New(cfg.HandlerTimeout, log))
+		r.Use(middle.JWTAuth(cfg.JwtSecret, tokenBlocklist))
 		r.Use(middle.AuthRequired())
+		// деактивация действует и на уже выданные токены
+		r.Use(middle.ActiveUser(userRepository))
+		r.Use(rbacMiddleware.Preload())
+
+		r.Post("/api/v1/logout", authHandler.Logout(log))
+		r.Get("/api/v1/me", meHandler.GetMe(log))
+
+		r.With(rbacMiddleware.RequirePermission("auditlog:list")).Get("/api/v1/audit-logs", auditLogHandler.ListAuditLogs(log))
+		r.With(rbacMiddleware.RequirePermission("auditlog:list")).Get("/api/v1/audit-logs/stream", auditLogHandler.StreamAuditLogs(log))
 
 		r.Route("/api/v1/users", func(rr chi.Router) {
 			rr.With(rbacMiddleware.RequirePermission("user:list")).Get("/", userHandler.ListUsers(log))
 			rr.With(rbacMiddleware.RequirePermission("user:view")).Get("/{id}", userHandler.GetUserByID(log))
+			rr.With(rbacMiddleware.RequirePermission("user:view")).Get("/{id}/profile", userHandler.GetUserProfile(log))
 			rr.With(rbacMiddleware.RequirePermission("user:update")).Put("/{id}", userHandler.UpdateUser(log))
+			rr.With(rbacMiddleware.RequirePermission("user:update")).Patch("/{id}", userHandler.PatchUser(log))
 			rr.With(rbacMiddleware.RequirePermission("user:delete")).Delete("/{id}", userHandler.DeleteUser(log))
+			rr.With(rbacMiddleware.RequirePermission("user:reset_password")).Post("/{id}/password-reset", userHandler.ResetPassword(log))
+			rr.With(rbacMiddleware.RequirePermission("user:deactivate"), idempotent).Post("/{id}/deactivate", userHandler.DeactivateUser(log))
+			rr.With(rbacMiddleware.RequirePermission("user:deactivate"), idempotent).Post("/{id}/activate", userHandler.ActivateUser(log))
 		})
 
 		r.Route("/api/v1/teacher", func(rr chi.Router) {
 			rr.With(rbacMiddleware.RequirePermission("teacher:view_self")).Get("/me", teacherHandler.GetMyTeacherProfile(log))
-			rr.With(rbacMiddleware.RequirePermission("teacher:view_self")).Get("/{id}", teacherHandler.GetTeacherPublicByID(log))
+			rr.With(rbacMiddleware.RequirePermission("teacher:view_self")).Get("/me/students", teacherHandler.ListMyStudents(log))
+			rr.With(rbacMiddleware.RequirePermission("teacher:view_self")).Get("/me/workload", teacherHandler.GetMyTeacherWorkload(log))
+			rr.With(rbacMiddleware.RequirePermission("teacher:view_public")).Get("/public/{id}", teacherHandler.GetTeacherPublicByID(log))
+			rr.With(rbacMiddleware.RequirePermission("teacher:list_public")).Get("/public", teacherHandler.ListTeacherPublic(log))
 			rr.With(rbacMiddleware.RequirePermission("teacher:update_self")).Put("/me", teacherHandler.UpdateMyTeacherProfile(log))
-			rr.With(rbacMiddleware.RequirePermission("teacher:create")).Post("/", teacherHandler.CreateTeacher(log))
+			rr.With(rbacMiddleware.RequirePermission("teacher:create"), idempotent).Post("/", teacherHandler.CreateTeacher(log))
 			rr.With(rbacMiddleware.RequirePermission("teacher:list")).Get("/", teacherHandler.ListTeacher(log))
 			rr.With(rbacMiddleware.RequirePermission("teacher:view")).Get("/{id}", teacherHandler.GetTeacherByID(log))
+			rr.With(rbacMiddleware.RequirePermission("teacher:view")).Get("/{id}/workload", teacherHandler.GetTeacherWorkload(log))
 			rr.With(rbacMiddleware.RequirePermission("teacher:update")).Put("/{id}", teacherHandler.UpdateTeacher(log))
+			rr.With(rbacMiddleware.RequirePermission("teacher:update")).Patch("/{id}", teacherHandler.PatchTeacher(log))
 			rr.With(rbacMiddleware.RequirePermission("teacher:delete")).Delete("/{id}", teacherHandler.DeleteTeacher(log))
 		})
 
 		r.Route("/api/v1/students", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("student:create")).Post("/", studentHandler.CreateStudent(log))
+			rr.With(rbacMiddleware.RequirePermission("student:create"), idempotent).Post("/", studentHandler.CreateStudent(log))
+			rr.With(rbacMiddleware.RequirePermission("student:create"), idempotent).Post("/import", studentHandler.ImportStudents(log))
 			rr.With(rbacMiddleware.RequirePermission("student:view")).Get("/{id}", studentHandler.GetStudentByID(log))
 			rr.With(rbacMiddleware.RequirePermission("student:update")).Put("/{id}", studentHandler.UpdateStudent(log))
+			rr.With(rbacMiddleware.RequirePermission("student:update")).Patch("/{id}", studentHandler.PatchStudent(log))
 			rr.With(rbacMiddleware.RequirePermission("student:delete")).Delete("/{id}", studentHandler.DeleteStudent(log))
+			rr.With(rbacMiddleware.RequirePermission("auditlog:list")).Get("/{id}/grade-history", auditLogHandler.ListStudentGradeHistory(log))
 			rr.With(rbacMiddleware.RequirePermission("student:list")).Get("/", studentHandler.ListStudent(log))
+			rr.With(rbacMiddleware.RequirePermission("student:view_self")).Get("/me/disciplines", studentHandler.ListMyDisciplines(log))
+			rr.With(rbacMiddleware.RequirePermission("gradejournal:list")).Get("/{id}/grades", gradeJournalHandler.ListStudentGrades(log))
+			rr.With(rbacMiddleware.RequirePermission("gradejournal:avg")).Get("/{id}/at-risk", gradeJournalHandler.ListStudentAtRisk(log))
+			rr.With(rbacMiddleware.RequirePermission("attendance:view")).Get("/{id}/attendance-summary", attendanceHandler.GetStudentAttendanceSummary(log))
 			rr.With(rbacMiddleware.RequirePermission("student:view_public")).Get("/public/{id}", studentHandler.GetStudentPublicByID(log))
 			rr.With(rbacMiddleware.RequirePermission("student:list_public")).Get("/public", studentHandler.ListStudentPublic(log))
 		})
 
 		r.Route("/api/v1/student-groups", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("studentgroup:create")).Post("/", studentGroupHandler.CreateStudentGroup(log))
+			rr.With(rbacMiddleware.RequirePermission("studentgroup:create"), idempotent).Post("/", studentGroupHandler.CreateStudentGroup(log))
 			rr.With(rbacMiddleware.RequirePermission("studentgroup:view")).Get("/{id}", studentGroupHandler.GetStudentGroupByID(log))
 			rr.With(rbacMiddleware.RequirePermission("studentgroup:update")).Put("/{id}", studentGroupHandler.UpdateStudentGroup(log))
 			rr.With(rbacMiddleware.RequirePermission("studentgroup:delete")).Delete("/{id}", studentGroupHandler.DeleteStudentGroup(log))
 			rr.With(rbacMiddleware.RequirePermission("studentgroup:list")).Get("/", studentGroupHandler.ListStudentGroups(log))
+			rr.With(
+				rbacMiddleware.RequirePermission("studentgroup:update"),
+				rbacMiddleware.RequirePermission("student:update"),
+				idempotent,
+			).Post("/merge", studentGroupHandler.MergeStudentGroups(log))
+			// /{id}/students.csv: расширение .csv срезает middleware.URLFormat
+			rr.With(rbacMiddleware.RequirePermission("student:list")).Get("/{id}/students", studentGroupHandler.ExportGroupStudentsCSV(log))
+			rr.With(rbacMiddleware.RequirePermission("gradejournal:avg")).Get("/{id}/ranking", studentGroupHandler.GetGroupRanking(log))
 			rr.With(rbacMiddleware.RequirePermission("studentgroup:view_public")).Get("/public/{id}", studentGroupHandler.GetStudentGroupPublicByID(log))
 			rr.With(rbacMiddleware.RequirePermission("studentgroup:list_public")).Get("/public", studentGroupHandler.ListStudentGroupPublic(log))
 		})
 
 		r.Route("/api/v1/permissions", func(rr chi.Router) {
 			rr.With(rbacMiddleware.RequirePermission("permission:list")).Get("/", permissionHandler.ListPermissions(log))
-			rr.With(rbacMiddleware.RequirePermission("permission:create")).Post("/", permissionHandler.CreatePermission(log))
+			rr.With(rbacMiddleware.RequirePermission("permission:list")).Get("/catalog", permissionHandler.GetPermissionCatalog(log))
+			rr.With(rbacMiddleware.RequirePermission("permission:create"), idempotent).Post("/", permissionHandler.CreatePermission(log))
 			rr.With(rbacMiddleware.RequirePermission("permission:view")).Get("/{id}", permissionHandler.GetPermissionByID(log))
 			rr.With(rbacMiddleware.RequirePermission("permission:update")).Put("/{id}", permissionHandler.UpdatePermission(log))
 			rr.With(rbacMiddleware.RequirePermission("permission:delete")).Delete("/{id}", permissionHandler.DeletePermission(log))
+			rr.With(rbacMiddleware.RequirePermission("permission:update"), idempotent).Post("/rename", permissionHandler.RenamePermissions(log))
 		})
 
 		r.Route("/api/v1/roles", func(rr chi.Router) {
 			rr.With(rbacMiddleware.RequirePermission("role:list")).Get("/", roleHandler.ListRoles(log))
-			rr.With(rbacMiddleware.RequirePermission("role:create")).Post("/", roleHandler.CreateRole(log))
+			rr.With(rbacMiddleware.RequirePermission("role:list")).Get("/with-counts", roleHandler.ListRolesWithCounts(log))
+			rr.With(rbacMiddleware.RequirePermission("role:create"), idempotent).Post("/", roleHandler.CreateRole(log))
 			rr.With(rbacMiddleware.RequirePermission("role:view")).Get("/{id}", roleHandler.GetRoleByID(log))
 			rr.With(rbacMiddleware.RequirePermission("role:update")).Put("/{id}", roleHandler.UpdateRole(log))
 			rr.With(rbacMiddleware.RequirePermission("role:delete")).Delete("/{id}", roleHandler.DeleteRole(log))
 		})
 
 		r.Route("/api/v1/user-roles", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("userrole:assign")).Post("/assign", userRoleHandler.AssignRole(log))
-			rr.With(rbacMiddleware.RequirePermission("userrole:remove")).Post("/remove", userRoleHandler.RemoveRole(log))
+			rr.With(rbacMiddleware.RequirePermission("userrole:assign"), idempotent).Post("/assign", userRoleHandler.AssignRole(log))
+			rr.With(rbacMiddleware.RequirePermission("userrole:remove"), idempotent).Post("/remove", userRoleHandler.RemoveRole(log))
 			rr.With(rbacMiddleware.RequirePermission("userrole:view")).Get("/{id}", userRoleHandler.GetRolesByUserID(log))
 		})
 
 		r.Route("/api/v1/role-permissions", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("rolepermission:assign")).Post("/assign", rolePermissionHandler.AssignPermission(log))
-			rr.With(rbacMiddleware.RequirePermission("rolepermission:remove")).Post("/remove", rolePermissionHandler.RemovePermission(log))
+			rr.With(rbacMiddleware.RequirePermission("rolepermission:assign"), idempotent).Post("/assign", rolePermissionHandler.AssignPermission(log))
+			rr.With(rbacMiddleware.RequirePermission("rolepermission:remove"), idempotent).Post("/remove", rolePermissionHandler.RemovePermission(log))
 			rr.With(rbacMiddleware.RequirePermission("rolepermission:view")).Get("/{id}", rolePermissionHandler.GetPermissionsByRoleID(log))
 		})
 
 		r.Route("/api/v1/curriculums", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("curriculum:create")).Post("/", curriculumHandler.CreateCurriculum(log))
+			rr.With(rbacMiddleware.RequirePermission("curriculum:create"), idempotent).Post("/", curriculumHandler.CreateCurriculum(log))
+			rr.With(rbacMiddleware.RequirePermission("curriculum:create"), idempotent).Post("/{id}/copy", curriculumHandler.CopyCurriculum(log))
 			rr.With(rbacMiddleware.RequirePermission("curriculum:view")).Get("/{id}", curriculumHandler.GetCurriculumByID(log))
 			rr.With(rbacMiddleware.RequirePermission("curriculum:update")).Put("/{id}", curriculumHandler.UpdateCurriculum(log))
 			rr.With(rbacMiddleware.RequirePermission("curriculum:delete")).Delete("/{id}", curriculumHandler.DeleteCurriculum(log))
@@ -171,25 +270,29 @@ func NewServer(
 		})
 
 		r.Route("/api/v1/gradejournals", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("gradejournal:create")).Post("/", gradeJournalHandler.CreateGradeJournal(log))
+			rr.With(rbacMiddleware.RequirePermission("gradejournal:create"), idempotent).Post("/", gradeJournalHandler.CreateGradeJournal(log))
 			rr.With(rbacMiddleware.RequirePermission("gradejournal:view")).Get("/{id}", gradeJournalHandler.GetGradeJournalByID(log))
 			rr.With(rbacMiddleware.RequirePermission("gradejournal:update")).Put("/{id}", gradeJournalHandler.UpdateGradeJournal(log))
+			rr.With(rbacMiddleware.RequirePermission("gradejournal:update")).Patch("/{id}", gradeJournalHandler.PatchGradeJournal(log))
 			rr.With(rbacMiddleware.RequirePermission("gradejournal:delete")).Delete("/{id}", gradeJournalHandler.DeleteGradeJournal(log))
+			rr.With(rbacMiddleware.RequirePermission("gradejournal:delete"), idempotent).Post("/bulk-delete", gradeJournalHandler.BulkDeleteGradeJournals(log))
 			rr.With(rbacMiddleware.RequirePermission("gradejournal:list")).Get("/", gradeJournalHandler.ListGradeJournal(log))
 			rr.With(rbacMiddleware.RequirePermission("gradejournal:list_public")).Get("/public", gradeJournalHandler.ListGradeJournalPublic(log))
 			rr.With(rbacMiddleware.RequirePermission("gradejournal:avg")).Get("/average", gradeJournalHandler.GetAverageGrade(log))
 		})
 
 		r.Route("/api/v1/attendances", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("attendance:create")).Post("/", attendanceHandler.CreateAttendance(log))
+			rr.With(rbacMiddleware.RequirePermission("attendance:create"), idempotent).Post("/", attendanceHandler.CreateAttendance(log))
 			rr.With(rbacMiddleware.RequirePermission("attendance:view")).Get("/{id}", attendanceHandler.GetAttendanceByID(log))
 			rr.With(rbacMiddleware.RequirePermission("attendance:update")).Put("/{id}", attendanceHandler.UpdateAttendance(log))
 			rr.With(rbacMiddleware.RequirePermission("attendance:delete")).Delete("/{id}", attendanceHandler.DeleteAttendance(log))
 			rr.With(rbacMiddleware.RequirePermission("attendance:list")).Get("/", attendanceHandler.ListAttendance(log))
+			rr.With(rbacMiddleware.RequirePermission("attendance:view_self")).Get("/me", attendanceHandler.ListMyAttendance(log))
+			rr.With(rbacMiddleware.RequirePermission("attendance:list")).Get("/low", attendanceHandler.ListLowAttendance(log))
 		})
 
 		r.Route("/api/v1/semesters", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("semester:create")).Post("/", semesterHandler.CreateSemester(log))
+			rr.With(rbacMiddleware.RequirePermission("semester:create"), idempotent).Post("/", semesterHandler.CreateSemester(log))
 			rr.With(rbacMiddleware.RequirePermission("semester:view")).Get("/{id}", semesterHandler.GetSemesterByID(log))
 			rr.With(rbacMiddleware.RequirePermission("semester:update")).Put("/{id}", semesterHandler.UpdateSemester(log))
 			rr.With(rbacMiddleware.RequirePermission("semester:delete")).Delete("/{id}", semesterHandler.DeleteSemester(log))
@@ -197,31 +300,119 @@ func NewServer(
 		})
 
 		r.Route("/api/v1/disciplines", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("discipline:create")).Post("/", disciplineHandler.CreateDiscipline(log))
+			rr.With(rbacMiddleware.RequirePermission("discipline:create"), idempotent).Post("/", disciplineHandler.CreateDiscipline(log))
+			rr.With(rbacMiddleware.RequirePermission("discipline:update"), idempotent).Post("/reassign", disciplineHandler.ReassignDisciplines(log))
 			rr.With(rbacMiddleware.RequirePermission("discipline:view")).Get("/{id}", disciplineHandler.GetDisciplineByID(log))
+			rr.With(rbacMiddleware.RequirePermission("discipline:view")).Get("/{id}/student-count", disciplineHandler.GetDisciplineStudentCount(log))
 			rr.With(rbacMiddleware.RequirePermission("discipline:update")).Put("/{id}", disciplineHandler.UpdateDiscipline(log))
 			rr.With(rbacMiddleware.RequirePermission("discipline:delete")).Delete("/{id}", disciplineHandler.DeleteDiscipline(log))
 			rr.With(rbacMiddleware.RequirePermission("discipline:list")).Get("/", disciplineHandler.ListDiscipline(log))
 			rr.With(rbacMiddleware.RequirePermission("discipline:list_public")).Get("/public", disciplineHandler.ListDisciplinePublic(log))
+			rr.With(rbacMiddleware.RequirePermission("discipline:list_public")).Get("/search", disciplineHandler.SearchDisciplines(log))
 			rr.With(rbacMiddleware.RequirePermission("discipline:view_public")).Get("/public/{id}", disciplineHandler.GetDisciplinePublicByID(log))
+			rr.With(rbacMiddleware.RequirePermission("curriculum:list")).Get("/{id}/curriculum", curriculumHandler.ListDisciplineCurriculum(log))
 		})
 
 		r.Route("/api/v1/academic-years", func(rr chi.Router) {
-			rr.With(rbacMiddleware.RequirePermission("academicyear:create")).Post("/", academicYearHandler.CreateAcademicYear(log))
+			rr.With(rbacMiddleware.RequirePermission("academicyear:create"), idempotent).Post("/", academicYearHandler.CreateAcademicYear(log))
 			rr.With(rbacMiddleware.RequirePermission("academicyear:view")).Get("/{id}", academicYearHandler.GetAcademicYearByID(log))
 			rr.With(rbacMiddleware.RequirePermission("academicyear:update")).Put("/{id}", academicYearHandler.UpdateAcademicYear(log))
 			rr.With(rbacMiddleware.RequirePermission("academicyear:delete")).Delete("/{id}", academicYearHandler.DeleteAcademicYear(log))
+			rr.With(rbacMiddleware.RequirePermission("academicyear:create"), idempotent).Post("/{id}/clone", academicYearHandler.CloneAcademicYear(log))
 			rr.With(rbacMiddleware.RequirePermission("academicyear:list")).Get("/", academicYearHandler.ListAcademicYear(log))
+			rr.With(rbacMiddleware.RequirePermission("semester:list")).Get("/current/semesters", academicYearHandler.ListCurrentSemesters(log))
 		})
+
+		r.Route("/api/v1/webhooks", func(rr chi.Router) {
+			rr.With(rbacMiddleware.RequirePermission("webhook:create"), idempotent).Post("/", webhookHandler.CreateWebhook(log))
+			rr.With(rbacMiddleware.RequirePermission("webhook:view")).Get("/{id}", webhookHandler.GetWebhookByID(log))
+			rr.With(rbacMiddleware.RequirePermission("webhook:update")).Put("/{id}", webhookHandler.UpdateWebhook(log))
+			rr.With(rbacMiddleware.RequirePermission("webhook:delete")).Delete("/{id}", webhookHandler.DeleteWebhook(log))
+			rr.With(rbacMiddleware.RequirePermission("webhook:list")).Get("/", webhookHandler.ListWebhook(log))
+		})
+
+		r.With(rbacMiddleware.RequirePermission("dashboard:view")).Get("/api/v1/dashboard", dashboardHandler.GetDashboard(log))
+		r.With(rbacMiddleware.RequirePermission("stats:view")).Get("/api/v1/stats/overview", statsHandler.GetOverview(log))
 	})
 
 	srv := &http.Server{
 		Addr:         cfg.Address,
 		Handler:      router,
-		ReadTimeout:  cfg.Timeout,
-		WriteTimeout: cfg.Timeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 		IdleTimeout:  cfg.IdleTimeout,
 	}
+	if cfg.TLS.Enabled() {
+		// сертификат проверяется при старте, а не при первом подключении
+		cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load tls certificate: %w", err)
+		}
+		// HTTP/2 объявляется явно: при своём TLSConfig не полагаемся на то, что его допишет http.Server
+		srv.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+			NextProtos:   []string{"h2", "http/1.1"},
+		}
+	}
+
+	// подготовленные запросы горячих путей закрываются вместе с сервером
+	for _, c := range []io.Closer{userRepository, userRoleRepository, rolePermissionRepository} {
+		srv.RegisterOnShutdown(func() {
+			if err := c.Close(); err != nil {
+				log.Error("failed to close prepared statements", slog.String("err", err.Error()))
+			}
+		})
+	}
 
 	return srv, nil
 }
+
+// methodNotAllowed ответ 405 в JSON-формате API с заголовком Allow,
+// перечисляющим методы, для которых путь зарегистрирован
+func methodNotAllowed(routes chi.Routes) http.HandlerFunc {
+	methods := []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, m := range methods {
+			// HEAD обслуживается getHead для любого GET-маршрута
+			if matchRoute(routes, m, r.URL.Path) || m == http.MethodHead && matchRoute(routes, http.MethodGet, r.URL.Path) {
+				allowed = append(allowed, m)
+			}
+		}
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		render.JSON(w, r, resp.ErrorFor(r, resp.MsgMethodNotAllowed))
+	}
+}
+
+// getHead как middleware.GetHead из chi: HEAD без своего маршрута обслуживается
+// GET-обработчиком, тело ответа отбрасывает http.Server. Поиск маршрута через matchRoute
+func getHead(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			rctx := chi.RouteContext(r.Context())
+			if !matchRoute(rctx.Routes, http.MethodHead, r.URL.Path) {
+				rctx.RouteMethod = http.MethodGet
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// matchRoute то же, что routes.Match, кроме пути самого подроутера ("/api/v1/disciplines"):
+// на него chi отвечает совпадением для любого метода, поэтому ищем "/" в подроутере
+func matchRoute(routes chi.Routes, method, path string) bool {
+	for _, rt := range routes.Routes() {
+		prefix := strings.TrimSuffix(rt.Pattern, "/*")
+		if rt.SubRoutes != nil && (path == prefix || path == prefix+"/") {
+			return matchRoute(rt.SubRoutes, method, "/")
+		}
+	}
+	return routes.Match(chi.NewRouteContext(), method, path)
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"service/internal/config"
+	"service/internal/domain/models"
+	"service/internal/lib/jwt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testJWTSecret = "test-secret"
+
+// activeOnlyDB отвечает только на проверку статуса пользователя (все активны),
+// остальные запросы падают с обрывом соединения: маршрут, дошедший до репозитория, отвечает 503
+type activeOnlyDB struct{}
+
+func (activeOnlyDB) Connect(context.Context) (driver.Conn, error) { return activeOnlyConn{}, nil }
+func (activeOnlyDB) Driver() driver.Driver                        { return activeOnlyDriver{} }
+
+type activeOnlyDriver struct{}
+
+func (activeOnlyDriver) Open(string) (driver.Conn, error) { return activeOnlyConn{}, nil }
+
+type activeOnlyConn struct{}
+
+func (activeOnlyConn) Prepare(query string) (driver.Stmt, error) {
+	if !strings.Contains(query, "SELECT is_active FROM user") {
+		return nil, driver.ErrBadConn
+	}
+	return activeStmt{}, nil
+}
+func (activeOnlyConn) Close() error              { return nil }
+func (activeOnlyConn) Begin() (driver.Tx, error) { return nil, driver.ErrBadConn }
+
+type activeStmt struct{}
+
+func (activeStmt) Close() error                               { return nil }
+func (activeStmt) NumInput() int                              { return -1 }
+func (activeStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrBadConn }
+func (activeStmt) Query([]driver.Value) (driver.Rows, error)  { return &activeRows{}, nil }
+
+type activeRows struct{ done bool }
+
+func (*activeRows) Columns() []string { return []string{"is_active"} }
+func (*activeRows) Close() error      { return nil }
+func (r *activeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = true
+	return nil
+}
+
+// newTestServer сервер с правами из токена, чтобы проверка прав не ходила в БД
+func newTestServer(t *testing.T, cfg *config.Config) *http.Server {
+	t.Helper()
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	cfg.JwtSecret = testJWTSecret
+	cfg.JwtTTL = time.Hour
+	cfg.RBAC.TokenClaims = true
+	cfg.MaxBatchSize = 100
+
+	db := sql.OpenDB(activeOnlyDB{})
+	t.Cleanup(func() { _ = db.Close() })
+	srv, err := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+// serve выполняет запрос от имени пользователя 1 с правами perms
+func serve(t *testing.T, srv *http.Server, method, target string, perms ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	if perms == nil {
+		perms = []string{}
+	}
+	token, err := jwt.NewToken(models.User{UserID: 1}, &jwt.Access{RoleIDs: []int64{}, Permissions: perms}, time.Hour, testJWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(method, target, nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestRoutes_MyDisciplinesRequiresStudentViewSelf(t *testing.T) {
+	srv := newTestServer(t, nil)
+
+	if rec := serve(t, srv, http.MethodGet, "/api/v1/students/me/disciplines", "teacher:view_self", "student:list"); rec.Code != http.StatusForbidden {
+		t.Fatalf("without student:view_self: status = %d, want 403: %s", rec.Code, rec.Body)
+	}
+	// с правом запрос доходит до обработчика, а тот до (недоступной) БД
+	if rec := serve(t, srv, http.MethodGet, "/api/v1/students/me/disciplines", "student:view_self"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("with student:view_self: status = %d, want 503 from handler: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestRoutes_MethodNotAllowed(t *testing.T) {
+	srv := newTestServer(t, nil)
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		wantAllow string
+	}{
+		{"list route", http.MethodDelete, "/api/v1/disciplines/", "GET, HEAD, POST"},
+		{"list route without trailing slash", http.MethodPut, "/api/v1/disciplines", "GET, HEAD, POST"},
+		{"detail route", http.MethodPost, "/api/v1/disciplines/5", "GET, HEAD, PUT, DELETE"},
+		{"post-only route", http.MethodGet, "/api/v1/login", "POST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, srv, tt.method, tt.target)
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("status = %d, want 405: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if !strings.Contains(rec.Body.String(), `"error":"method not allowed"`) {
+				t.Errorf("body = %s, want JSON error", rec.Body)
+			}
+		})
+	}
+}
+
+func TestRoutes_HeadServedByGet(t *testing.T) {
+	srv := newTestServer(t, nil)
+	// тело ответа на HEAD отбрасывает http.Server, поэтому через настоящий сервер
+	ts := httptest.NewServer(srv.Handler)
+	defer ts.Close()
+
+	for _, target := range []string{"/api/v1/disciplines", "/api/v1/disciplines/", "/api/v1/disciplines/5"} {
+		t.Run(target, func(t *testing.T) {
+			// права проверяются так же, как для GET
+			if rec := serve(t, srv, http.MethodHead, target); rec.Code != http.StatusForbidden {
+				t.Fatalf("HEAD without permission: status = %d, want 403", rec.Code)
+			}
+			get := serve(t, srv, http.MethodGet, target, "discipline:list", "discipline:view")
+
+			token, err := jwt.NewToken(models.User{UserID: 1}, &jwt.Access{RoleIDs: []int64{}, Permissions: []string{"discipline:list", "discipline:view"}}, time.Hour, testJWTSecret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, _ := http.NewRequest(http.MethodHead, ts.URL+target, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			head, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(head.Body)
+			head.Body.Close()
+
+			// запрос дошёл до GET-обработчика: тот же статус (503 от недоступной БД)
+			if head.StatusCode != get.Code {
+				t.Fatalf("HEAD status = %d, GET status = %d", head.StatusCode, get.Code)
+			}
+			if len(body) != 0 {
+				t.Errorf("HEAD body = %q, want empty", body)
+			}
+		})
+	}
+}
+
+// writeTestCert пишет самоподписанный сертификат и ключ в PEM во временный каталог
+func writeTestCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewServer_TLS(t *testing.T) {
+	certFile, keyFile := writeTestCert(t)
+	srv := newTestServer(t, &config.Config{TLS: config.TLS{CertFile: certFile, KeyFile: keyFile}})
+
+	if srv.TLSConfig == nil || len(srv.TLSConfig.Certificates) != 1 {
+		t.Fatalf("TLSConfig = %+v, want loaded certificate", srv.TLSConfig)
+	}
+	if want := []string{"h2", "http/1.1"}; !reflect.DeepEqual(srv.TLSConfig.NextProtos, want) {
+		t.Errorf("NextProtos = %v, want %v", srv.TLSConfig.NextProtos, want)
+	}
+}
+
+func TestNewServer_TLSMissingFiles(t *testing.T) {
+	certFile, keyFile := writeTestCert(t)
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	tests := []struct {
+		name      string
+		cert, key string
+	}{
+		{"missing cert", missing, keyFile},
+		{"missing key", certFile, missing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{TLS: config.TLS{CertFile: tt.cert, KeyFile: tt.key}}
+			cfg.JwtSecret = testJWTSecret
+			db := sql.OpenDB(activeOnlyDB{})
+			t.Cleanup(func() { _ = db.Close() })
+			// сервер не должен стартовать с битым сертификатом
+			if _, err := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg, db); err == nil || !strings.Contains(err.Error(), "load tls certificate") {
+				t.Fatalf("err = %v, want load tls certificate error", err)
+			}
+		})
+	}
+}
+
+func TestNewServer_Plain(t *testing.T) {
+	if srv := newTestServer(t, nil); srv.TLSConfig != nil {
+		t.Errorf("TLSConfig = %+v, want nil without tls config", srv.TLSConfig)
+	}
+}
+
+func TestNewServer_Timeouts(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Timeout = 4 * time.Second
+	cfg.ReadTimeout = 5 * time.Second
+	cfg.WriteTimeout = 30 * time.Second
+	cfg.IdleTimeout = time.Minute
+	srv := newTestServer(t, cfg)
+
+	if srv.ReadTimeout != 5*time.Second || srv.WriteTimeout != 30*time.Second || srv.IdleTimeout != time.Minute {
+		t.Errorf("read/write/idle = %s/%s/%s, want 5s/30s/1m", srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"service/internal/domain/models"
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -23,15 +27,19 @@ type AcademicYearRepository interface {
 	UpdateAcademicYear(ctx context.Context, year *models.AcademicYear) error
 	DeleteAcademicYear(ctx context.Context, id int64) error
 	ListAcademicYear(ctx context.Context, limit, offset int) ([]*models.AcademicYear, error)
+	CountAcademicYear(ctx context.Context) (int64, error)
+	GetCurrentAcademicYear(ctx context.Context, at time.Time) (*models.AcademicYear, error)
+	CloneAcademicYear(ctx context.Context, sourceID int64, year *models.AcademicYear) (*models.AcademicYearCloneResponse, error)
 }
 
 type AcademicYearHandler struct {
-	repo      AcademicYearRepository
-	auditRepo AuditLogRepository
+	repo         AcademicYearRepository
+	semesterRepo SemesterRepository
+	auditRepo    AuditLogRepository
 }
 
-func NewAcademicYearHandler(repo AcademicYearRepository, auditRepo AuditLogRepository) *AcademicYearHandler {
-	return &AcademicYearHandler{repo: repo, auditRepo: auditRepo}
+func NewAcademicYearHandler(repo AcademicYearRepository, semesterRepo SemesterRepository, auditRepo AuditLogRepository) *AcademicYearHandler {
+	return &AcademicYearHandler{repo: repo, semesterRepo: semesterRepo, auditRepo: auditRepo}
 }
 
 // @Summary Создать учебный год
@@ -53,13 +61,12 @@ func (h *AcademicYearHandler) CreateAcademicYear(log *slog.Logger) http.HandlerF
 		if err := json.NewDecoder(r.Body).Decode(&year); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		if err := h.repo.CreateAcademicYear(r.Context(), &year); err != nil {
 			log.Error("failed to create academic year", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create academic year"))
+			renderServerError(w, r, err, "failed to create academic year")
 			return
 		}
 
@@ -72,6 +79,7 @@ func (h *AcademicYearHandler) CreateAcademicYear(log *slog.Logger) http.HandlerF
 			Comment:    utils.PtrToStr("Academic year created"),
 		})
 
+		setLocation(w, "academic-years", year.AcademicYearID)
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, year)
 	}
@@ -97,7 +105,7 @@ func (h *AcademicYearHandler) GetAcademicYearByID(log *slog.Logger) http.Handler
 		if err != nil {
 			log.Info("invalid academic year id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid academic year id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid academic year id"))
 			return
 		}
 		year, err := h.repo.GetAcademicYearByID(r.Context(), id)
@@ -105,12 +113,11 @@ func (h *AcademicYearHandler) GetAcademicYearByID(log *slog.Logger) http.Handler
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("academic year not found", slog.Int64("academic_year_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("academic year not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "academic year not found"))
 				return
 			}
 			log.Error("failed to get academic year", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get academic year"))
+			renderServerError(w, r, err, "failed to get academic year")
 			return
 		}
 		render.JSON(w, r, year)
@@ -138,14 +145,14 @@ func (h *AcademicYearHandler) UpdateAcademicYear(log *slog.Logger) http.HandlerF
 		if err != nil {
 			log.Info("invalid academic year id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid academic year id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid academic year id"))
 			return
 		}
 		var year models.AcademicYear
 		if err := json.NewDecoder(r.Body).Decode(&year); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		oldYear, _ := h.repo.GetAcademicYearByID(r.Context(), id)
@@ -154,12 +161,11 @@ func (h *AcademicYearHandler) UpdateAcademicYear(log *slog.Logger) http.HandlerF
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("academic year not found for update", slog.Int64("academic_year_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("academic year not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "academic year not found"))
 				return
 			}
 			log.Error("failed to update academic year", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update academic year"))
+			renderServerError(w, r, err, "failed to update academic year")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -182,6 +188,7 @@ func (h *AcademicYearHandler) UpdateAcademicYear(log *slog.Logger) http.HandlerF
 // @Produce json
 // @Param id path int true "ID учебного года"
 // @Success 204 {string} string "No Content"
+// @Failure 409 {object} resp.Response "В учебном году есть группы или семестры"
 // @Router /api/v1/academic-years/{id} [delete]
 // @Security BearerAuth
 func (h *AcademicYearHandler) DeleteAcademicYear(log *slog.Logger) http.HandlerFunc {
@@ -196,20 +203,25 @@ func (h *AcademicYearHandler) DeleteAcademicYear(log *slog.Logger) http.HandlerF
 		if err != nil {
 			log.Info("invalid academic year id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid academic year id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid academic year id"))
 			return
 		}
 		oldYear, _ := h.repo.GetAcademicYearByID(r.Context(), id)
 		if err := h.repo.DeleteAcademicYear(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("academic year is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("academic year", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("academic year not found for delete", slog.Int64("academic_year_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("academic year not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "academic year not found"))
 				return
 			}
 			log.Error("failed to delete academic year", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete academic year"))
+			renderServerError(w, r, err, "failed to delete academic year")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -228,9 +240,11 @@ func (h *AcademicYearHandler) DeleteAcademicYear(log *slog.Logger) http.HandlerF
 // @Tags academic-years
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.AcademicYear
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/academic-years [get]
 // @Security BearerAuth
 func (h *AcademicYearHandler) ListAcademicYear(log *slog.Logger) http.HandlerFunc {
@@ -240,18 +254,144 @@ func (h *AcademicYearHandler) ListAcademicYear(log *slog.Logger) http.HandlerFun
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+		if err := setTotalCount(w, r, func() (int64, error) { return h.repo.CountAcademicYear(r.Context()) }); err != nil {
+			log.Error("failed to count academic years", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list academic years")
+			return
 		}
 		years, err := h.repo.ListAcademicYear(r.Context(), limit, offset)
 		if err != nil {
 			log.Error("failed to list academic years", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list academic years"))
+			renderServerError(w, r, err, "failed to list academic years")
 			return
 		}
 		render.JSON(w, r, years)
 	}
 }
+
+// @Summary Скопировать структуру учебного года
+// @Description Создаёт новый учебный год и копирует в него семестры (со сдвигом дат на разницу начал годов) и группы исходного года одной транзакцией. Студенты не переносятся
+// @Tags academic-years
+// @Accept json
+// @Produce json
+// @Param id path int true "ID исходного учебного года"
+// @Param input body models.AcademicYearCloneRequest true "Название и даты нового года"
+// @Success 201 {object} models.AcademicYearCloneResponse
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/academic-years/{id}/clone [post]
+// @Security BearerAuth
+func (h *AcademicYearHandler) CloneAcademicYear(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.academicyear_handler.CloneAcademicYear"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid academic year id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid academic year id"))
+			return
+		}
+		var req models.AcademicYearCloneRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" || req.StartWith.IsZero() || req.EndsWith.IsZero() {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "name_academic_year, start_with and ends_with are required"))
+			return
+		}
+		if req.StartWith.After(req.EndsWith) {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "start_with must not be after ends_with"))
+			return
+		}
+
+		year := &models.AcademicYear{Name: req.Name, StartWith: req.StartWith, EndsWith: req.EndsWith}
+		out, err := h.repo.CloneAcademicYear(r.Context(), id, year)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("academic year not found for clone", slog.Int64("academic_year_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "academic year not found"))
+				return
+			}
+			if errors.Is(err, storage.ErrDuplicate) {
+				log.Info("target academic year already exists", slog.String("name", year.Name))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "academic year already exists"))
+				return
+			}
+			log.Error("failed to clone academic year", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to clone academic year")
+			return
+		}
+
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "academic_year",
+			RowID:      year.AcademicYearID,
+			ActionType: "INSERT",
+			NewData:    utils.PtrToJSON(out),
+			Comment:    utils.PtrToStr(fmt.Sprintf("Academic year cloned from %d", id)),
+		})
+
+		setLocation(w, "academic-years", year.AcademicYearID)
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, out)
+	}
+}
+
+// @Summary Семестры текущего учебного года
+// @Tags academic-years
+// @Produce json
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.Semester
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/academic-years/current/semesters [get]
+// @Security BearerAuth
+func (h *AcademicYearHandler) ListCurrentSemesters(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.academic_year.ListCurrentSemesters"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+
+		year, err := h.repo.GetCurrentAcademicYear(r.Context(), time.Now())
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("no current academic year")
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "current academic year not found"))
+				return
+			}
+			log.Error("failed to get current academic year", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get current academic year")
+			return
+		}
+
+		limit, offset := parsePagination(r)
+
+		semesters, err := h.semesterRepo.ListSemester(r.Context(), &year.AcademicYearID, nil, nil, limit, offset)
+		if err != nil {
+			log.Error("failed to list semesters", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list semesters")
+			return
+		}
+		if semesters == nil {
+			semesters = []*models.Semester{}
+		}
+		render.JSON(w, r, semesters)
+	}
+}
@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memYearRepo учебные годы в памяти
+type memYearRepo struct {
+	AcademicYearRepository
+	years []*models.AcademicYear
+}
+
+func (m *memYearRepo) GetCurrentAcademicYear(_ context.Context, at time.Time) (*models.AcademicYear, error) {
+	for _, y := range m.years {
+		if !at.Before(y.StartWith) && !at.After(y.EndsWith) {
+			return y, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// CloneAcademicYear копирует только сам год: структуру проверяют тесты репозитория
+func (m *memYearRepo) CloneAcademicYear(_ context.Context, sourceID int64, year *models.AcademicYear) (*models.AcademicYearCloneResponse, error) {
+	found := false
+	for _, y := range m.years {
+		if y.Name == year.Name {
+			return nil, storage.ErrDuplicate
+		}
+		found = found || y.AcademicYearID == sourceID
+	}
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	year.AcademicYearID = int64(len(m.years) + 1)
+	m.years = append(m.years, year)
+	return &models.AcademicYearCloneResponse{AcademicYear: year, Semesters: []*models.Semester{}, StudentGroups: []*models.StudentGroup{}}, nil
+}
+
+// memSemesterRepo семестры в памяти, список фильтруется по учебному году
+type memSemesterRepo struct {
+	SemesterRepository
+	semesters []*models.Semester
+}
+
+func (m *memSemesterRepo) ListSemester(_ context.Context, academicYearID *int64, _, _ *time.Time, limit, offset int) ([]*models.Semester, error) {
+	var items []*models.Semester
+	for _, s := range m.semesters {
+		if academicYearID == nil || s.AcademicYearID == *academicYearID {
+			items = append(items, s)
+		}
+	}
+	if offset >= len(items) {
+		return nil, nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func TestListCurrentSemesters(t *testing.T) {
+	now := time.Now().UTC()
+	current := &models.AcademicYear{AcademicYearID: 2, StartWith: now.AddDate(0, -1, 0), EndsWith: now.AddDate(0, 10, 0)}
+	past := &models.AcademicYear{AcademicYearID: 1, StartWith: now.AddDate(-1, -1, 0), EndsWith: now.AddDate(0, -2, 0)}
+	semesters := &memSemesterRepo{semesters: []*models.Semester{
+		{SemesterID: 1, AcademicYearID: 1},
+		{SemesterID: 2, AcademicYearID: 1},
+		{SemesterID: 3, AcademicYearID: 2},
+		{SemesterID: 4, AcademicYearID: 2},
+	}}
+
+	tests := []struct {
+		name    string
+		years   []*models.AcademicYear
+		want    int
+		wantIDs []int64
+	}{
+		{"current year", []*models.AcademicYear{past, current}, http.StatusOK, []int64{3, 4}},
+		{"no current year", []*models.AcademicYear{past}, http.StatusNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAcademicYearHandler(&memYearRepo{years: tt.years}, semesters, &recordingAudit{})
+			rec := httptest.NewRecorder()
+			h.ListCurrentSemesters(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/academic-years/current/semesters", nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var items []models.Semester
+			if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+				t.Fatal(err)
+			}
+			var ids []int64
+			for _, s := range items {
+				ids = append(ids, s.SemesterID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("semesters = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestCloneAcademicYear(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		body string
+		want int
+	}{
+		{"created", "1", `{"name_academic_year":" 2025/2026 ","start_with":"2025-09-01T00:00:00Z","ends_with":"2026-06-30T00:00:00Z"}`, http.StatusCreated},
+		{"target exists", "1", `{"name_academic_year":"2024/2025","start_with":"2025-09-01T00:00:00Z","ends_with":"2026-06-30T00:00:00Z"}`, http.StatusConflict},
+		{"source missing", "404", `{"name_academic_year":"2025/2026","start_with":"2025-09-01T00:00:00Z","ends_with":"2026-06-30T00:00:00Z"}`, http.StatusNotFound},
+		{"no name", "1", `{"start_with":"2025-09-01T00:00:00Z","ends_with":"2026-06-30T00:00:00Z"}`, http.StatusBadRequest},
+		{"reversed dates", "1", `{"name_academic_year":"2025/2026","start_with":"2026-09-01T00:00:00Z","ends_with":"2026-06-30T00:00:00Z"}`, http.StatusBadRequest},
+		{"invalid id", "x", `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memYearRepo{years: []*models.AcademicYear{{AcademicYearID: 1, Name: "2024/2025"}}}
+			audit := &recordingAudit{}
+			h := NewAcademicYearHandler(repo, nil, audit)
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/academic-years/"+tt.id+"/clone", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			h.CloneAcademicYear(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusCreated {
+				if len(repo.years) != 1 || len(audit.entries) != 0 {
+					t.Errorf("years = %d, audit entries = %d, want nothing created", len(repo.years), len(audit.entries))
+				}
+				return
+			}
+			var out models.AcademicYearCloneResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.AcademicYear.AcademicYearID != 2 || out.AcademicYear.Name != "2025/2026" {
+				t.Errorf("academic year = %+v", out.AcademicYear)
+			}
+			if loc := rec.Header().Get("Location"); !strings.HasSuffix(loc, "/academic-years/2") {
+				t.Errorf("Location = %q", loc)
+			}
+			if len(audit.entries) != 1 || audit.entries[0].ActionType != "INSERT" || audit.entries[0].RowID != 2 {
+				t.Errorf("audit entries = %+v", audit.entries)
+			}
+		})
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"math"
 	"net/http"
 	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
 	"time"
 
@@ -24,41 +27,60 @@ type AttendanceRepository interface {
 	UpdateAttendance(ctx context.Context, attendance *models.Attendance) error
 	DeleteAttendance(ctx context.Context, id int64) error
 	ListAttendance(ctx context.Context, limit, offset int) ([]*models.Attendance, error)
-	ListAttendanceWithFilters(ctx context.Context, studentID, disciplineID *int64, date *time.Time, limit, offset int) ([]*models.Attendance, error)
+	ListAttendanceWithFilters(ctx context.Context, studentID, disciplineID, academicYearID *int64, date, updatedSince *time.Time, afterID *int64, limit, offset int) ([]*models.Attendance, error)
+	CountAttendanceWithFilters(ctx context.Context, studentID, disciplineID, academicYearID *int64, date, updatedSince *time.Time) (int64, error)
+	GetAttendanceSummary(ctx context.Context, studentID int64, from, to time.Time) ([]*models.AttendanceDisciplineSummary, error)
+	ListLowAttendance(ctx context.Context, threshold float64, from, to *time.Time, limit, offset int) ([]*models.AttendanceLowRate, error)
 }
 
 type AttendanceHandler struct {
-	repo      AttendanceRepository
-	auditRepo AuditLogRepository
+	repo         AttendanceRepository
+	studentRepo  StudentChecker
+	semesterRepo SemesterRepository
+	auditRepo    AuditLogRepository
+	events       EventDispatcher
 }
 
-func NewAttendanceHandler(repo AttendanceRepository, auditRepo AuditLogRepository) *AttendanceHandler {
-	return &AttendanceHandler{repo: repo, auditRepo: auditRepo}
+func NewAttendanceHandler(repo AttendanceRepository, studentRepo StudentChecker, semesterRepo SemesterRepository, auditRepo AuditLogRepository, events EventDispatcher) *AttendanceHandler {
+	return &AttendanceHandler{repo: repo, studentRepo: studentRepo, semesterRepo: semesterRepo, auditRepo: auditRepo, events: events}
 }
 
 // @Summary Добавить посещаемость
 // @Tags attendances
 // @Accept json
 // @Produce json
-// @Param input body models.Attendance true "Посещаемость"
+// @Param input body models.CreateAttendanceRequest true "Посещаемость"
 // @Success 201 {object} models.Attendance
+// @Failure 409 {object} resp.Response
 // @Router /api/v1/attendances [post]
 // @Security BearerAuth
 func (h *AttendanceHandler) CreateAttendance(log *slog.Logger) http.HandlerFunc {
 	const op = "handler.v1.attendance_handler.CreateAttendance"
 	return func(w http.ResponseWriter, r *http.Request) {
 		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
-		var a models.Attendance
-		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		var req models.CreateAttendanceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
+		a := models.Attendance{
+			StudentID:    req.StudentID,
+			DisciplineID: req.DisciplineID,
+			Visit:        req.Visit,
+			ClassDate:    req.ClassDate,
+			Comment:      req.Comment,
+		}
 		if err := h.repo.CreateAttendance(r.Context(), &a); err != nil {
+			if errors.Is(err, storage.ErrDuplicate) {
+				log.Info("attendance already recorded", slog.Int64("student_id", a.StudentID), slog.Int64("discipline_id", a.DisciplineID))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "attendance for this student and discipline already recorded on this day"))
+				return
+			}
 			log.Error("failed to create attendance", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create attendance"))
+			renderServerError(w, r, err, "failed to create attendance")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -69,6 +91,8 @@ func (h *AttendanceHandler) CreateAttendance(log *slog.Logger) http.HandlerFunc
 			NewData:    utils.PtrToJSON(a),
 			Comment:    utils.PtrToStr("Attendance created"),
 		})
+		h.events.Dispatch(r.Context(), "attendance.created", a)
+		setLocation(w, "attendances", a.AttendanceID)
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, a)
 	}
@@ -91,7 +115,7 @@ func (h *AttendanceHandler) GetAttendanceByID(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid attendance id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid attendance id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid attendance id"))
 			return
 		}
 		a, err := h.repo.GetAttendanceByID(r.Context(), id)
@@ -99,12 +123,11 @@ func (h *AttendanceHandler) GetAttendanceByID(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("attendance not found", slog.Int64("attendance_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("attendance not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "attendance not found"))
 				return
 			}
 			log.Error("failed to get attendance", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get attendance"))
+			renderServerError(w, r, err, "failed to get attendance")
 			return
 		}
 
@@ -119,6 +142,7 @@ func (h *AttendanceHandler) GetAttendanceByID(log *slog.Logger) http.HandlerFunc
 // @Param id path int true "ID посещаемости"
 // @Param input body models.Attendance true "Посещаемость"
 // @Success 200 {object} models.Attendance
+// @Failure 409 {object} resp.Response
 // @Router /api/v1/attendances/{id} [put]
 // @Security BearerAuth
 func (h *AttendanceHandler) UpdateAttendance(log *slog.Logger) http.HandlerFunc {
@@ -130,28 +154,33 @@ func (h *AttendanceHandler) UpdateAttendance(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid attendance id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid attendance id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid attendance id"))
 			return
 		}
 		var a models.Attendance
 		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		oldAttendance, _ := h.repo.GetAttendanceByID(r.Context(), id)
 		a.AttendanceID = id
 		if err := h.repo.UpdateAttendance(r.Context(), &a); err != nil {
+			if errors.Is(err, storage.ErrDuplicate) {
+				log.Info("attendance already recorded", slog.Int64("attendance_id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "attendance for this student and discipline already recorded on this day"))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("attendance not found for update", slog.Int64("attendance_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("attendance not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "attendance not found"))
 				return
 			}
 			log.Error("failed to update attendance", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update attendance"))
+			renderServerError(w, r, err, "failed to update attendance")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -163,6 +192,7 @@ func (h *AttendanceHandler) UpdateAttendance(log *slog.Logger) http.HandlerFunc
 			NewData:    utils.PtrToJSON(a),
 			Comment:    utils.PtrToStr("Attendance updated"),
 		})
+		h.events.Dispatch(r.Context(), "attendance.updated", a)
 		w.WriteHeader(http.StatusOK)
 		render.JSON(w, r, a)
 	}
@@ -185,7 +215,7 @@ func (h *AttendanceHandler) DeleteAttendance(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid attendance id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid attendance id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid attendance id"))
 			return
 		}
 		oldAttendance, _ := h.repo.GetAttendanceByID(r.Context(), id)
@@ -193,12 +223,11 @@ func (h *AttendanceHandler) DeleteAttendance(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("attendance not found for delete", slog.Int64("attendance_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("attendance not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "attendance not found"))
 				return
 			}
 			log.Error("failed to delete attendance", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete attendance"))
+			renderServerError(w, r, err, "failed to delete attendance")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -209,6 +238,7 @@ func (h *AttendanceHandler) DeleteAttendance(log *slog.Logger) http.HandlerFunc
 			OldData:    utils.PtrToJSON(oldAttendance),
 			Comment:    utils.PtrToStr("Attendance deleted"),
 		})
+		h.events.Dispatch(r.Context(), "attendance.deleted", map[string]int64{"attendance_id": id})
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
@@ -216,14 +246,19 @@ func (h *AttendanceHandler) DeleteAttendance(log *slog.Logger) http.HandlerFunc
 // @Summary Получить список посещаемости с фильтрацией
 // @Tags attendances
 // @Accept json
-// @Produce json
+// @Produce json,text/csv
 // @Param student_id query int false "ID студента"
 // @Param discipline_id query int false "ID дисциплины"
-// @Param from_date query string false "С даты (YYYY-MM-DD)"
-// @Param to_date query string false "По дату (YYYY-MM-DD)"
-// @Param limit query int false "Ограничение"
-// @Param offset query int false "Смещение"
+// @Param academic_year_id query int false "ID учебного года (через группу дисциплины)"
+// @Param date query string false "Дата занятия (YYYY-MM-DD)"
+// @Param updated_since query string false "Изменённые начиная с (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение (игнорируется при after_id)"
+// @Param after_id query int false "Keyset-пагинация: записи с id больше after_id, для выгрузок и глубоких страниц"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.Attendance
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
+// @Header 200 {integer} X-Next-After-Id "after_id следующей страницы в keyset-режиме"
 // @Router /api/v1/attendances [get]
 // @Security BearerAuth
 func (h *AttendanceHandler) ListAttendance(log *slog.Logger) http.HandlerFunc {
@@ -232,8 +267,8 @@ func (h *AttendanceHandler) ListAttendance(log *slog.Logger) http.HandlerFunc {
 		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
 
 		var (
-			studentID, disciplineID *int64
-			date                    *time.Time
+			studentID, disciplineID, academicYearID *int64
+			date                                    *time.Time
 		)
 
 		studentIDStr := r.URL.Query().Get("student_id")
@@ -250,6 +285,12 @@ func (h *AttendanceHandler) ListAttendance(log *slog.Logger) http.HandlerFunc {
 				disciplineID = &id
 			}
 		}
+		if val := r.URL.Query().Get("academic_year_id"); val != "" {
+			id, err := strconv.ParseInt(val, 10, 64)
+			if err == nil {
+				academicYearID = &id
+			}
+		}
 		dateStr := r.URL.Query().Get("date")
 		if dateStr != "" {
 			parsed, err := time.Parse("2006-01-02", dateStr)
@@ -258,19 +299,263 @@ func (h *AttendanceHandler) ListAttendance(log *slog.Logger) http.HandlerFunc {
 			}
 		}
 
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+		afterID, ok := parseAfterID(r)
+		if !ok {
+			log.Info("invalid after_id", slog.String("after_id", r.URL.Query().Get("after_id")))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid after_id"))
+			return
 		}
 
-		items, err := h.repo.ListAttendanceWithFilters(r.Context(), studentID, disciplineID, date, limit, offset)
+		if err := setTotalCount(w, r, func() (int64, error) {
+			return h.repo.CountAttendanceWithFilters(r.Context(), studentID, disciplineID, academicYearID, date, parseUpdatedSince(r))
+		}); err != nil {
+			log.Error("failed to count attendance", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list attendance")
+			return
+		}
+		items, err := h.repo.ListAttendanceWithFilters(r.Context(), studentID, disciplineID, academicYearID, date, parseUpdatedSince(r), afterID, limit, offset)
 		if err != nil {
 			log.Error("failed to list attendance with filters", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list attendance"))
+			renderServerError(w, r, err, "failed to list attendance")
+			return
+		}
+		if afterID != nil && len(items) > 0 {
+			setNextAfterID(w, len(items), limit, items[len(items)-1].AttendanceID)
+		}
+		if wantsCSV(r) {
+			rows := make([][]string, 0, len(items))
+			for _, a := range items {
+				rows = append(rows, []string{
+					strconv.FormatInt(a.AttendanceID, 10),
+					csvTime(a.CreatedAt),
+					csvTime(a.UpdatedAt),
+					strconv.FormatInt(a.StudentID, 10),
+					strconv.FormatInt(a.DisciplineID, 10),
+					strconv.FormatBool(a.Visit),
+					a.ClassDate.Format("2006-01-02"),
+					csvString(a.Comment),
+				})
+			}
+			header := []string{"attendance_id", "created_at", "updated_at", "student_id", "discipline_id", "visit", "class_date", "comment"}
+			if err := renderCSV(w, header, rows); err != nil {
+				log.Error("failed to write csv", slog.String("err", err.Error()))
+			}
 			return
 		}
 		render.JSON(w, r, items)
 	}
 }
+
+// @Summary Сводка посещаемости студента за семестр
+// @Tags attendances
+// @Produce json
+// @Param id path int true "ID студента"
+// @Param semester_id query int true "ID семестра"
+// @Success 200 {object} models.AttendanceSummary
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/students/{id}/attendance-summary [get]
+// @Security BearerAuth
+func (h *AttendanceHandler) GetStudentAttendanceSummary(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.attendance_handler.GetStudentAttendanceSummary"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+
+		idStr := chi.URLParam(r, "id")
+		studentID, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid student id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
+			return
+		}
+		semesterIDStr := r.URL.Query().Get("semester_id")
+		semesterID, err := strconv.ParseInt(semesterIDStr, 10, 64)
+		if err != nil {
+			log.Info("invalid semester id", slog.String("semester_id", semesterIDStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "semester_id is required"))
+			return
+		}
+
+		exists, err := h.studentRepo.StudentExists(r.Context(), studentID)
+		if err != nil {
+			log.Error("failed to check student", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get attendance summary")
+			return
+		}
+		if !exists {
+			log.Info("student not found", slog.Int64("student_id", studentID))
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.ErrorFor(r, "student not found"))
+			return
+		}
+
+		semester, err := h.semesterRepo.GetSemesterByID(r.Context(), semesterID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("semester not found", slog.Int64("semester_id", semesterID))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "semester not found"))
+				return
+			}
+			log.Error("failed to get semester", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get attendance summary")
+			return
+		}
+
+		items, err := h.repo.GetAttendanceSummary(r.Context(), studentID, semester.StartWith, semester.EndsWith)
+		if err != nil {
+			log.Error("failed to get attendance summary", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get attendance summary")
+			return
+		}
+
+		summary := models.AttendanceSummary{
+			StudentID:   studentID,
+			SemesterID:  semesterID,
+			StartWith:   semester.StartWith,
+			EndsWith:    semester.EndsWith,
+			Disciplines: items,
+		}
+		if summary.Disciplines == nil {
+			summary.Disciplines = []*models.AttendanceDisciplineSummary{}
+		}
+		for _, d := range items {
+			summary.Present += d.Present
+			summary.Absent += d.Absent
+			summary.Total += d.Total
+		}
+		render.JSON(w, r, summary)
+	}
+}
+
+// defaultLowAttendanceThreshold доля посещённых занятий, ниже которой студент попадает в список
+const defaultLowAttendanceThreshold = 0.75
+
+// @Summary Студенты с низкой посещаемостью
+// @Description Студенты, у которых доля посещённых занятий за период меньше порога, от худших к лучшим
+// @Tags attendances
+// @Produce json
+// @Param threshold query number false "Порог доли посещений, (0, 1] (по умолчанию 0.75)"
+// @Param from_date query string false "С даты (YYYY-MM-DD)"
+// @Param to_date query string false "По дату включительно (YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.AttendanceLowRate
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/attendances/low [get]
+// @Security BearerAuth
+func (h *AttendanceHandler) ListLowAttendance(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.attendance_handler.ListLowAttendance"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		q := r.URL.Query()
+
+		threshold := defaultLowAttendanceThreshold
+		if val := q.Get("threshold"); val != "" {
+			var err error
+			threshold, err = strconv.ParseFloat(val, 64)
+			if err != nil || math.IsNaN(threshold) || threshold <= 0 || threshold > 1 {
+				log.Info("invalid threshold", slog.String("threshold", val))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid threshold"))
+				return
+			}
+		}
+		from, ok := parseDateParam(r, "from_date")
+		if !ok {
+			log.Info("invalid from_date", slog.String("from_date", q.Get("from_date")))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid from_date"))
+			return
+		}
+		to, ok := parseDateParam(r, "to_date")
+		if !ok {
+			log.Info("invalid to_date", slog.String("to_date", q.Get("to_date")))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid to_date"))
+			return
+		}
+		if from != nil && to != nil && from.After(*to) {
+			log.Info("from_date is after to_date")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "from_date must not be after to_date"))
+			return
+		}
+		limit, offset := parsePagination(r)
+
+		items, err := h.repo.ListLowAttendance(r.Context(), threshold, from, to, limit, offset)
+		if err != nil {
+			log.Error("failed to list low attendance", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list low attendance")
+			return
+		}
+		if items == nil {
+			items = []*models.AttendanceLowRate{}
+		}
+		render.JSON(w, r, items)
+	}
+}
+
+// @Summary Посещаемость текущего студента
+// @Tags attendances
+// @Produce json
+// @Param discipline_id query int false "ID дисциплины"
+// @Param date query string false "Дата занятия (YYYY-MM-DD)"
+// @Param updated_since query string false "Изменённые начиная с (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.Attendance
+// @Failure 401 {object} resp.Response
+// @Router /api/v1/attendances/me [get]
+// @Security BearerAuth
+func (h *AttendanceHandler) ListMyAttendance(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.attendance_handler.ListMyAttendance"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+
+		// student_id из запроса игнорируется, студент видит только свои отметки
+		studentID, ok := ware.GetUserID(r)
+		if !ok {
+			log.Info("user id not found in claims")
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnauthorized))
+			return
+		}
+
+		var (
+			disciplineID *int64
+			date         *time.Time
+		)
+		if val := r.URL.Query().Get("discipline_id"); val != "" {
+			id, err := strconv.ParseInt(val, 10, 64)
+			if err == nil {
+				disciplineID = &id
+			}
+		}
+		if val := r.URL.Query().Get("date"); val != "" {
+			parsed, err := time.Parse("2006-01-02", val)
+			if err == nil {
+				date = &parsed
+			}
+		}
+
+		limit, offset := parsePagination(r)
+
+		items, err := h.repo.ListAttendanceWithFilters(r.Context(), &studentID, disciplineID, nil, date, parseUpdatedSince(r), nil, limit, offset)
+		if err != nil {
+			log.Error("failed to list attendance", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list attendance")
+			return
+		}
+		if items == nil {
+			items = []*models.Attendance{}
+		}
+		render.JSON(w, r, items)
+	}
+}
@@ -0,0 +1,534 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/storage"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memAttendanceRepo отметки посещаемости в памяти
+type memAttendanceRepo struct {
+	AttendanceRepository
+	items []*models.Attendance
+}
+
+// ListAttendanceWithFilters фильтры по студенту, дисциплине и дню; порядок — по id,
+// при afterID offset игнорируется, как в репозитории
+func (m *memAttendanceRepo) ListAttendanceWithFilters(_ context.Context, studentID, disciplineID, _ *int64, date, _ *time.Time, afterID *int64, limit, offset int) ([]*models.Attendance, error) {
+	if afterID != nil {
+		offset = 0
+	}
+	var items []*models.Attendance
+	for _, a := range m.items {
+		switch {
+		case studentID != nil && a.StudentID != *studentID,
+			disciplineID != nil && a.DisciplineID != *disciplineID,
+			date != nil && !a.ClassDate.Equal(*date),
+			afterID != nil && a.AttendanceID <= *afterID:
+			continue
+		}
+		items = append(items, a)
+	}
+	if offset >= len(items) {
+		return nil, nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (m *memAttendanceRepo) GetAttendanceSummary(_ context.Context, studentID int64, from, to time.Time) ([]*models.AttendanceDisciplineSummary, error) {
+	// границы включаются, сравниваются только даты, как DATE BETWEEN в репозитории
+	fromDay, toDay := from.Format("2006-01-02"), to.Format("2006-01-02")
+	byDiscipline := map[int64]*models.AttendanceDisciplineSummary{}
+	for _, a := range m.items {
+		day := a.ClassDate.Format("2006-01-02")
+		if a.StudentID != studentID || day < fromDay || day > toDay {
+			continue
+		}
+		s, ok := byDiscipline[a.DisciplineID]
+		if !ok {
+			s = &models.AttendanceDisciplineSummary{DisciplineID: a.DisciplineID}
+			byDiscipline[a.DisciplineID] = s
+		}
+		if a.Visit {
+			s.Present++
+		} else {
+			s.Absent++
+		}
+		s.Total++
+	}
+	var items []*models.AttendanceDisciplineSummary
+	for _, s := range byDiscipline {
+		items = append(items, s)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].DisciplineID < items[j].DisciplineID })
+	return items, nil
+}
+
+// ListLowAttendance доля посещений студента за период строго ниже threshold, от худших к лучшим
+func (m *memAttendanceRepo) ListLowAttendance(_ context.Context, threshold float64, from, to *time.Time, limit, offset int) ([]*models.AttendanceLowRate, error) {
+	byStudent := map[int64]*models.AttendanceLowRate{}
+	for _, a := range m.items {
+		if from != nil && a.ClassDate.Before(*from) || to != nil && a.ClassDate.After(*to) {
+			continue
+		}
+		s, ok := byStudent[a.StudentID]
+		if !ok {
+			s = &models.AttendanceLowRate{StudentID: a.StudentID}
+			byStudent[a.StudentID] = s
+		}
+		if a.Visit {
+			s.Present++
+		}
+		s.Total++
+	}
+	var items []*models.AttendanceLowRate
+	for _, s := range byStudent {
+		s.Rate = float64(s.Present) / float64(s.Total)
+		if s.Rate < threshold {
+			items = append(items, s)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Rate != items[j].Rate {
+			return items[i].Rate < items[j].Rate
+		}
+		return items[i].StudentID < items[j].StudentID
+	})
+	if offset >= len(items) {
+		return nil, nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// CreateAttendance повторяет уникальный индекс (student_id, discipline_id, class_date)
+// и дату занятия по умолчанию из репозитория: сегодняшний день, без времени
+func (m *memAttendanceRepo) CreateAttendance(_ context.Context, a *models.Attendance) error {
+	if a.ClassDate.IsZero() {
+		a.ClassDate = time.Now().UTC()
+	}
+	a.ClassDate = a.ClassDate.Truncate(24 * time.Hour)
+	for _, old := range m.items {
+		if old.StudentID == a.StudentID && old.DisciplineID == a.DisciplineID && old.ClassDate.Equal(a.ClassDate) {
+			return storage.ErrDuplicate
+		}
+	}
+	a.AttendanceID = int64(len(m.items) + 1)
+	a.CreatedAt = time.Now().UTC()
+	a.UpdatedAt = a.CreatedAt
+	cp := *a
+	m.items = append(m.items, &cp)
+	return nil
+}
+
+func (m *memAttendanceRepo) GetAttendanceByID(_ context.Context, id int64) (*models.Attendance, error) {
+	for _, a := range m.items {
+		if a.AttendanceID == id {
+			return a, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// existingStudents StudentChecker по набору id
+type existingStudents map[int64]bool
+
+func (e existingStudents) StudentExists(_ context.Context, id int64) (bool, error) {
+	return e[id], nil
+}
+
+func day(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestGetStudentAttendanceSummary_SemesterDateRange(t *testing.T) {
+	repo := &memAttendanceRepo{items: []*models.Attendance{
+		// до начала семестра
+		{StudentID: 7, DisciplineID: 3, Visit: true, ClassDate: day("2024-08-31")},
+		// первый и последний день семестра входят в сводку
+		{StudentID: 7, DisciplineID: 3, Visit: true, ClassDate: day("2024-09-01")},
+		{StudentID: 7, DisciplineID: 3, Visit: false, ClassDate: day("2024-10-15")},
+		{StudentID: 7, DisciplineID: 4, Visit: true, ClassDate: day("2024-12-31").Add(15 * time.Hour)},
+		// после окончания семестра
+		{StudentID: 7, DisciplineID: 4, Visit: false, ClassDate: day("2025-01-01")},
+		// другой студент
+		{StudentID: 8, DisciplineID: 3, Visit: false, ClassDate: day("2024-10-15")},
+	}}
+	semesters := knownSemesters{byID: map[int64]*models.Semester{
+		1: {SemesterID: 1, StartWith: day("2024-09-01"), EndsWith: day("2024-12-31")},
+	}}
+	h := NewAttendanceHandler(repo, existingStudents{7: true}, semesters, &recordingAudit{}, noopEvents{})
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/students/7/attendance-summary?semester_id=1", nil)
+	rec := httptest.NewRecorder()
+	h.GetStudentAttendanceSummary(discardLogger())(rec, withURLParams(r, "id", "7"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var got models.AttendanceSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Present != 2 || got.Absent != 1 || got.Total != 3 {
+		t.Errorf("totals present/absent/total = %d/%d/%d, want 2/1/3", got.Present, got.Absent, got.Total)
+	}
+	if len(got.Disciplines) != 2 || got.Disciplines[0].Total != 2 || got.Disciplines[1].Total != 1 {
+		t.Errorf("disciplines = %+v", got.Disciplines)
+	}
+	if !got.StartWith.Equal(day("2024-09-01")) || !got.EndsWith.Equal(day("2024-12-31")) {
+		t.Errorf("range = %s..%s", got.StartWith, got.EndsWith)
+	}
+}
+
+func TestGetStudentAttendanceSummary_Errors(t *testing.T) {
+	semesters := knownSemesters{byID: map[int64]*models.Semester{
+		1: {SemesterID: 1, StartWith: day("2024-09-01"), EndsWith: day("2024-12-31")},
+	}}
+	h := NewAttendanceHandler(&memAttendanceRepo{}, existingStudents{7: true}, semesters, &recordingAudit{}, noopEvents{})
+
+	tests := []struct {
+		name  string
+		id    string
+		query string
+		want  int
+		empty bool
+	}{
+		{"семестр без отметок", "7", "?semester_id=1", http.StatusOK, true},
+		{"нет semester_id", "7", "", http.StatusBadRequest, false},
+		{"нет семестра", "7", "?semester_id=9", http.StatusNotFound, false},
+		{"нет студента", "8", "?semester_id=1", http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/students/"+tt.id+"/attendance-summary"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.GetStudentAttendanceSummary(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.empty {
+				var got struct {
+					Disciplines json.RawMessage `json:"disciplines"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || string(got.Disciplines) != "[]" {
+					t.Errorf("disciplines = %s, want []", got.Disciplines)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateAttendance_DuplicateDay(t *testing.T) {
+	repo := &memAttendanceRepo{}
+	audit := &recordingAudit{}
+	h := NewAttendanceHandler(repo, existingStudents{7: true}, knownSemesters{}, audit, noopEvents{})
+	create := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.CreateAttendance(discardLogger())(rec, httptest.NewRequest(http.MethodPost, "/api/v1/attendances", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := create(`{"student_id":7,"discipline_id":3,"visit":true,"class_date":"2024-09-02T00:00:00Z"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("first mark: status = %d, body %s", rec.Code, rec.Body)
+	}
+	rec := create(`{"student_id":7,"discipline_id":3,"visit":false,"class_date":"2024-09-02T00:00:00Z"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("same day: status = %d, want 409: %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "already recorded on this day") {
+		t.Errorf("body = %s", rec.Body)
+	}
+	// другой день и другая дисциплина — отдельные отметки
+	for _, body := range []string{
+		`{"student_id":7,"discipline_id":3,"visit":true,"class_date":"2024-09-03T00:00:00Z"}`,
+		`{"student_id":7,"discipline_id":4,"visit":true,"class_date":"2024-09-02T00:00:00Z"}`,
+	} {
+		if rec := create(body); rec.Code != http.StatusCreated {
+			t.Fatalf("%s: status = %d, body %s", body, rec.Code, rec.Body)
+		}
+	}
+	if len(repo.items) != 3 || len(audit.entries) != 3 {
+		t.Errorf("stored %d marks, %d audit entries, want 3 and 3", len(repo.items), len(audit.entries))
+	}
+}
+
+func TestListMyAttendance_OnlyOwnRows(t *testing.T) {
+	repo := &memAttendanceRepo{items: []*models.Attendance{
+		{AttendanceID: 1, StudentID: 7, DisciplineID: 3, Visit: true, ClassDate: day("2024-09-02")},
+		{AttendanceID: 2, StudentID: 8, DisciplineID: 3, Visit: false, ClassDate: day("2024-09-02")},
+		{AttendanceID: 3, StudentID: 7, DisciplineID: 4, Visit: false, ClassDate: day("2024-09-03")},
+	}}
+	h := NewAttendanceHandler(repo, existingStudents{7: true, 8: true, 9: true}, knownSemesters{}, &recordingAudit{}, noopEvents{})
+
+	tests := []struct {
+		name   string
+		userID int64
+		query  string
+		want   []int64
+	}{
+		{"свои отметки", 7, "", []int64{1, 3}},
+		// student_id из запроса не позволяет смотреть чужие отметки
+		{"чужой student_id игнорируется", 7, "?student_id=8", []int64{1, 3}},
+		{"фильтр по дисциплине", 7, "?discipline_id=4", []int64{3}},
+		{"нет отметок", 9, "", []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := authorize(t, httptest.NewRequest(http.MethodGet, "/api/v1/attendances/me"+tt.query, nil), tt.userID, "attendance:view_self")
+			rec := httptest.NewRecorder()
+			h.ListMyAttendance(discardLogger())(rec, r)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+			}
+			// пустой результат — массив, а не null
+			if len(tt.want) == 0 && strings.TrimSpace(rec.Body.String()) != "[]" {
+				t.Fatalf("body = %s, want []", rec.Body)
+			}
+			var got []models.Attendance
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d: %s", len(got), len(tt.want), rec.Body)
+			}
+			for i, id := range tt.want {
+				if got[i].AttendanceID != id || got[i].StudentID != tt.userID {
+					t.Errorf("row[%d] = id %d student %d, want id %d student %d", i, got[i].AttendanceID, got[i].StudentID, id, tt.userID)
+				}
+			}
+		})
+	}
+}
+
+func TestListAttendance_AfterID(t *testing.T) {
+	repo := &memAttendanceRepo{}
+	for id := int64(1); id <= 5; id++ {
+		repo.items = append(repo.items, &models.Attendance{AttendanceID: id, StudentID: 7, DisciplineID: 3, ClassDate: day("2024-09-02")})
+	}
+	h := NewAttendanceHandler(repo, existingStudents{7: true}, knownSemesters{}, &recordingAudit{}, noopEvents{})
+
+	tests := []struct {
+		name     string
+		query    string
+		want     []int64
+		wantNext string
+	}{
+		{"первая keyset-страница", "?after_id=0&limit=2", []int64{1, 2}, "2"},
+		{"следующая страница", "?after_id=2&limit=2", []int64{3, 4}, "4"},
+		// неполная страница — последняя, заголовка нет
+		{"последняя страница", "?after_id=4&limit=2", []int64{5}, ""},
+		// offset при after_id игнорируется
+		{"after_id с offset", "?after_id=2&limit=2&offset=2", []int64{3, 4}, "4"},
+		// без after_id обычная offset-пагинация без X-Next-After-Id
+		{"только offset", "?limit=2&offset=2", []int64{3, 4}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ListAttendance(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/attendances"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+			}
+			var got []models.Attendance
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			var ids []int64
+			for _, a := range got {
+				ids = append(ids, a.AttendanceID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.want) {
+				t.Errorf("ids = %v, want %v", ids, tt.want)
+			}
+			if next := rec.Header().Get("X-Next-After-Id"); next != tt.wantNext {
+				t.Errorf("X-Next-After-Id = %q, want %q", next, tt.wantNext)
+			}
+		})
+	}
+
+	for _, bad := range []string{"abc", "-1"} {
+		rec := httptest.NewRecorder()
+		h.ListAttendance(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/attendances?after_id="+bad, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("after_id=%s: status = %d, want 400", bad, rec.Code)
+		}
+	}
+}
+
+func TestGetStudentAttendanceSummary_StudentMissing(t *testing.T) {
+	semesters := knownSemesters{byID: map[int64]*models.Semester{
+		1: {SemesterID: 1, StartWith: day("2024-09-01"), EndsWith: day("2024-12-31")},
+	}}
+	h := NewAttendanceHandler(&memAttendanceRepo{}, existingStudents{8: true}, semesters, &recordingAudit{}, noopEvents{})
+
+	tests := []struct {
+		id   string
+		want int
+	}{
+		{"8", http.StatusOK},
+		{"404", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/students/"+tt.id+"/attendance-summary?semester_id=1", nil)
+			rec := httptest.NewRecorder()
+			h.GetStudentAttendanceSummary(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			// студент без отметок — пустой список дисциплин
+			if tt.want == http.StatusOK && !strings.Contains(rec.Body.String(), `"disciplines":[]`) {
+				t.Errorf("body = %s, want empty disciplines", rec.Body)
+			}
+		})
+	}
+}
+
+func TestListLowAttendance(t *testing.T) {
+	// студент 7 посетил 1 из 4 занятий, 8 — 3 из 4, 9 — все; в октябре у 8 одни пропуски
+	repo := &memAttendanceRepo{}
+	marks := map[int64][]bool{7: {true, false, false, false}, 8: {true, true, true, false}, 9: {true, true, true, true}}
+	for student, visits := range marks {
+		for i, visit := range visits {
+			repo.items = append(repo.items, &models.Attendance{StudentID: student, DisciplineID: 3, ClassDate: day(fmt.Sprintf("2024-09-%02d", i+2)), Visit: visit})
+		}
+	}
+	repo.items = append(repo.items,
+		&models.Attendance{StudentID: 8, DisciplineID: 3, ClassDate: day("2024-10-01"), Visit: false},
+		&models.Attendance{StudentID: 8, DisciplineID: 3, ClassDate: day("2024-10-02"), Visit: false},
+	)
+	h := NewAttendanceHandler(repo, existingStudents{}, nil, &recordingAudit{}, noopEvents{})
+
+	tests := []struct {
+		query   string
+		want    int
+		wantIDs []int64
+	}{
+		// по умолчанию порог 0.75: ровно 3 из 4 — не аномалия
+		{"", http.StatusOK, []int64{7, 8}},
+		{"?from_date=2024-09-01&to_date=2024-09-30", http.StatusOK, []int64{7}},
+		{"?from_date=2024-10-01", http.StatusOK, []int64{8}},
+		{"?threshold=1", http.StatusOK, []int64{7, 8}},
+		{"?threshold=0.25&to_date=2024-09-30", http.StatusOK, []int64{}},
+		{"?threshold=1&to_date=2024-09-30&limit=1&offset=1", http.StatusOK, []int64{8}},
+		{"?threshold=0", http.StatusBadRequest, nil},
+		{"?threshold=1.5", http.StatusBadRequest, nil},
+		{"?threshold=NaN", http.StatusBadRequest, nil},
+		{"?from_date=01.09.2024", http.StatusBadRequest, nil},
+		{"?to_date=tomorrow", http.StatusBadRequest, nil},
+		{"?from_date=2024-10-01&to_date=2024-09-01", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ListLowAttendance(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/attendances/low"+tt.query, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var items []*models.AttendanceLowRate
+			if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil || items == nil {
+				t.Fatalf("body = %s, want JSON array: %v", rec.Body, err)
+			}
+			ids := []int64{}
+			for _, s := range items {
+				ids = append(ids, s.StudentID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("students = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestCreateAttendance_ClassDate(t *testing.T) {
+	today := time.Now().UTC().Format("2006-01-02")
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		// отметка задним числом сохраняет дату занятия
+		{"backfilled", `{"student_id":7,"discipline_id":3,"visit":true,"class_date":"2024-09-02T00:00:00Z"}`, "2024-09-02T00:00:00Z"},
+		{"time of day dropped", `{"student_id":7,"discipline_id":3,"visit":true,"class_date":"2024-09-02T15:30:00Z"}`, "2024-09-02T00:00:00Z"},
+		{"omitted defaults to today", `{"student_id":7,"discipline_id":3,"visit":true}`, today + "T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memAttendanceRepo{}
+			h := NewAttendanceHandler(repo, existingStudents{7: true}, knownSemesters{}, &recordingAudit{}, noopEvents{})
+			rec := httptest.NewRecorder()
+			h.CreateAttendance(discardLogger())(rec, httptest.NewRequest(http.MethodPost, "/api/v1/attendances", strings.NewReader(tt.body)))
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("create: status = %d, body %s", rec.Code, rec.Body)
+			}
+			var created struct {
+				AttendanceID int64  `json:"attendance_id"`
+				ClassDate    string `json:"class_date"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+				t.Fatal(err)
+			}
+			if created.ClassDate != tt.want {
+				t.Errorf("created class_date = %q, want %q", created.ClassDate, tt.want)
+			}
+
+			// то же значение возвращается при чтении
+			id := fmt.Sprint(created.AttendanceID)
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/attendances/"+id, nil)
+			get := httptest.NewRecorder()
+			h.GetAttendanceByID(discardLogger())(get, withURLParams(r, "id", id))
+			if get.Code != http.StatusOK {
+				t.Fatalf("get: status = %d, body %s", get.Code, get.Body)
+			}
+			if !strings.Contains(get.Body.String(), `"class_date":"`+tt.want+`"`) {
+				t.Errorf("get body = %s, want class_date %s", get.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateAttendance_IgnoresServerFields(t *testing.T) {
+	repo := &memAttendanceRepo{}
+	h := NewAttendanceHandler(repo, existingStudents{7: true}, knownSemesters{}, &recordingAudit{}, noopEvents{})
+	body := `{"attendance_id":999,"created_at":"2000-01-01T00:00:00Z","updated_at":"2000-01-01T00:00:00Z",` +
+		`"student_id":7,"discipline_id":3,"visit":true,"class_date":"2024-09-02T00:00:00Z"}`
+	rec := httptest.NewRecorder()
+	h.CreateAttendance(discardLogger())(rec, httptest.NewRequest(http.MethodPost, "/api/v1/attendances", strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	var got models.Attendance
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.AttendanceID != 1 || got.CreatedAt.Year() == 2000 || got.UpdatedAt.Year() == 2000 {
+		t.Errorf("created = %+v", got)
+	}
+	if len(repo.items) != 1 || repo.items[0].AttendanceID != 1 || !repo.items[0].ClassDate.Equal(day("2024-09-02")) {
+		t.Errorf("stored = %+v", repo.items)
+	}
+}
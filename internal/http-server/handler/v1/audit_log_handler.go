@@ -2,54 +2,220 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"service/internal/domain/models"
 	resp "service/internal/lib/api/response"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 )
 
 type AuditLogRepository interface {
 	AddAuditLog(ctx context.Context, entry *models.AuditLog) error
-	ListAuditLogs(ctx context.Context, limit, offset int) ([]*models.AuditLog, error)
+	ListAuditLogs(ctx context.Context, actionType *string, limit, offset int) ([]*models.AuditLog, error)
+}
+
+// AuditLogStreamer построчная выгрузка аудита без буферизации
+type AuditLogStreamer interface {
+	StreamAuditLogs(ctx context.Context, actionType *string, fromDate *time.Time, fn func(*models.AuditLog) error) error
+}
+
+// GradeAuditRepository история изменений оценок студента
+type GradeAuditRepository interface {
+	ListGradeAuditByStudent(ctx context.Context, studentID int64, limit, offset int) ([]*models.AuditLog, error)
 }
 
 type AuditLogHandler struct {
-	repo AuditLogRepository
+	repo      AuditLogRepository
+	gradeRepo GradeAuditRepository
+	streamer  AuditLogStreamer
+	userRepo  UserRepository
 }
 
-func NewAuditLogHandler(repo AuditLogRepository) *AuditLogHandler {
-	return &AuditLogHandler{repo: repo}
+func NewAuditLogHandler(repo AuditLogRepository, gradeRepo GradeAuditRepository, streamer AuditLogStreamer, userRepo UserRepository) *AuditLogHandler {
+	return &AuditLogHandler{repo: repo, gradeRepo: gradeRepo, streamer: streamer, userRepo: userRepo}
 }
 
 // @Summary Получить список аудитов
 // @Tags audit-logs
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param action_type query string false "Тип действия (INSERT, UPDATE, DELETE или RENAME — переименование дисциплины)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
-// @Success 200 {array} models.AuditLog
+// @Success 200 {array} models.AuditLogWithUser
 // @Router /api/v1/audit-logs [get]
 // @Security BearerAuth
 func (h *AuditLogHandler) ListAuditLogs(log *slog.Logger) http.HandlerFunc {
 	const op = "handler.v1.auditlog.ListAuditLogs"
 	return func(w http.ResponseWriter, r *http.Request) {
 		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+		var actionType *string
+		if val := r.URL.Query().Get("action_type"); val != "" {
+			val = strings.ToUpper(val)
+			actionType = &val
 		}
-		audits, err := h.repo.ListAuditLogs(r.Context(), limit, offset)
+		audits, err := h.repo.ListAuditLogs(r.Context(), actionType, limit, offset)
 		if err != nil {
 			log.Error("failed to list audit logs", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list audit logs"))
+			renderServerError(w, r, err, "failed to list audit logs")
+			return
+		}
+
+		items, err := h.withAuthors(r.Context(), audits)
+		if err != nil {
+			log.Error("failed to get audit log users", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list audit logs")
+			return
+		}
+		render.JSON(w, r, items)
+	}
+}
+
+// @Summary Выгрузка аудита в NDJSON
+// @Description Поток записей аудита, по одной JSON-записи на строку, от старых к новым. Для SIEM:
+// @Description следующий запрос продолжает с from_date = created_at последней полученной записи.
+// @Tags audit-logs
+// @Produce application/x-ndjson
+// @Param action_type query string false "Тип действия (INSERT, UPDATE, DELETE или RENAME — переименование дисциплины)"
+// @Param from_date query string false "Записи начиная с (RFC3339 или YYYY-MM-DD)"
+// @Success 200 {object} models.AuditLog "Одна запись на строку"
+// @Failure 400 {object} resp.Response
+// @Router /api/v1/audit-logs/stream [get]
+// @Security BearerAuth
+func (h *AuditLogHandler) StreamAuditLogs(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.auditlog.StreamAuditLogs"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		var actionType *string
+		if val := r.URL.Query().Get("action_type"); val != "" {
+			val = strings.ToUpper(val)
+			actionType = &val
+		}
+		var fromDate *time.Time
+		if val := r.URL.Query().Get("from_date"); val != "" {
+			t, err := time.Parse(time.RFC3339, val)
+			if err != nil {
+				t, err = time.Parse("2006-01-02", val)
+			}
+			if err != nil {
+				log.Info("invalid from_date", slog.String("from_date", val))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid from_date"))
+				return
+			}
+			fromDate = &t
+		}
+
+		rc := http.NewResponseController(w)
+		// выгрузка может идти дольше WriteTimeout сервера
+		_ = rc.SetWriteDeadline(time.Time{})
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		enc := json.NewEncoder(w)
+		written := 0
+		err := h.streamer.StreamAuditLogs(r.Context(), actionType, fromDate, func(a *models.AuditLog) error {
+			// Encode дописывает перевод строки после каждой записи
+			if err := enc.Encode(a); err != nil {
+				return err
+			}
+			written++
+			if written%100 == 0 {
+				return rc.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			if written == 0 {
+				log.Error("failed to stream audit logs", slog.String("err", err.Error()))
+				renderServerError(w, r, err, "failed to stream audit logs")
+				return
+			}
+			// заголовки уже отправлены, клиент увидит оборванный поток
+			log.Error("audit log stream interrupted", slog.String("err", err.Error()), slog.Int("written", written))
+			return
+		}
+		_ = rc.Flush()
+	}
+}
+
+// @Summary История изменений оценок студента
+// @Tags audit-logs
+// @Accept json
+// @Produce json
+// @Param id path int true "ID студента"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.AuditLogWithUser
+// @Failure 400 {object} resp.Response
+// @Router /api/v1/students/{id}/grade-history [get]
+// @Security BearerAuth
+func (h *AuditLogHandler) ListStudentGradeHistory(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.auditlog.ListStudentGradeHistory"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		idStr := chi.URLParam(r, "id")
+		studentID, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid student id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
+			return
+		}
+		limit, offset := parsePagination(r)
+		audits, err := h.gradeRepo.ListGradeAuditByStudent(r.Context(), studentID, limit, offset)
+		if err != nil {
+			log.Error("failed to list grade history", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list grade history")
 			return
 		}
-		render.JSON(w, r, audits)
+		items, err := h.withAuthors(r.Context(), audits)
+		if err != nil {
+			log.Error("failed to get audit log users", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list grade history")
+			return
+		}
+		render.JSON(w, r, items)
+	}
+}
+
+// withAuthors дополняет записи аудита именами авторов.
+// Имена подгружаются одним запросом, а не по записи.
+func (h *AuditLogHandler) withAuthors(ctx context.Context, audits []*models.AuditLog) ([]*models.AuditLogWithUser, error) {
+	seen := make(map[int64]struct{})
+	var userIDs []int64
+	for _, a := range audits {
+		if a.UserID == nil {
+			continue
+		}
+		if _, ok := seen[int64(*a.UserID)]; !ok {
+			seen[int64(*a.UserID)] = struct{}{}
+			userIDs = append(userIDs, int64(*a.UserID))
+		}
+	}
+	users, err := h.userRepo.GetClientsByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*models.AuditLogWithUser, 0, len(audits))
+	for _, a := range audits {
+		item := &models.AuditLogWithUser{AuditLog: *a}
+		if a.UserID != nil {
+			if u, ok := users[int64(*a.UserID)]; ok {
+				item.UserFirstName = &u.FirstName
+				item.UserLastName = &u.LastName
+			}
+		}
+		items = append(items, item)
 	}
+	return items, nil
 }
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"testing"
+	"time"
+)
+
+// memAuditStream записи аудита в памяти, от старых к новым
+type memAuditStream []*models.AuditLog
+
+func (m memAuditStream) StreamAuditLogs(_ context.Context, actionType *string, fromDate *time.Time, fn func(*models.AuditLog) error) error {
+	for _, a := range m {
+		if actionType != nil && a.ActionType != *actionType {
+			continue
+		}
+		if fromDate != nil && a.CreatedAt.Before(*fromDate) {
+			continue
+		}
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestStreamAuditLogs(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2024, 9, day, 10, 0, 0, 0, time.UTC) }
+	stream := memAuditStream{
+		{AuditID: 1, CreatedAt: at(1), TableName: "student", RowID: 7, ActionType: "INSERT"},
+		{AuditID: 2, CreatedAt: at(2), TableName: "student", RowID: 7, ActionType: "UPDATE"},
+		{AuditID: 3, CreatedAt: at(3), TableName: "grade_journal", RowID: 4, ActionType: "UPDATE"},
+		{AuditID: 4, CreatedAt: at(4), TableName: "grade_journal", RowID: 4, ActionType: "DELETE"},
+	}
+	h := NewAuditLogHandler(nil, nil, stream, nil)
+
+	tests := []struct {
+		query   string
+		want    int
+		wantIDs []int64
+	}{
+		{"", http.StatusOK, []int64{1, 2, 3, 4}},
+		{"?action_type=update", http.StatusOK, []int64{2, 3}},
+		{"?from_date=2024-09-03", http.StatusOK, []int64{3, 4}},
+		{"?action_type=UPDATE&from_date=2024-09-02T10:00:00Z", http.StatusOK, []int64{2, 3}},
+		{"?action_type=RENAME", http.StatusOK, nil},
+		{"?from_date=yesterday", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.StreamAuditLogs(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit-logs/stream"+tt.query, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+				t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+			}
+			// каждая строка — отдельный JSON-объект
+			var ids []int64
+			sc := bufio.NewScanner(rec.Body)
+			for sc.Scan() {
+				var a models.AuditLog
+				if err := json.Unmarshal(sc.Bytes(), &a); err != nil {
+					t.Fatalf("line %q is not a JSON object: %v", sc.Text(), err)
+				}
+				ids = append(ids, a.AuditID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -1,26 +1,72 @@
 package v1
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log/slog"
 	"net/http"
 	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/jwt"
+	"service/internal/storage"
 	"time"
 
 	"github.com/go-chi/render"
 	"golang.org/x/crypto/bcrypt"
 )
 
+type TokenBlocklist interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+}
+
+// TokenAccessLoader роли и права пользователя для встраивания в токен
+type TokenAccessLoader interface {
+	UserAccess(ctx context.Context, userID int64) (*jwt.Access, error)
+}
+
 type AuthHandler struct {
-	userRepo  UserRepository
-	jwtSecret string
+	userRepo    UserRepository
+	blocklist   TokenBlocklist
+	access      TokenAccessLoader
+	jwtSecret   string
+	jwtTTL      time.Duration
+	defaultRole string
 }
 
-func NewAuthHandler(userRepo UserRepository, jwtSecret string) *AuthHandler {
-	return &AuthHandler{userRepo: userRepo, jwtSecret: jwtSecret}
+// NewAuthHandler defaultRole назначается новым пользователям при регистрации, пустая — без роли.
+// access nil — роли и права в токен не встраиваются
+func NewAuthHandler(
+	userRepo UserRepository,
+	blocklist TokenBlocklist,
+	access TokenAccessLoader,
+	jwtSecret string,
+	jwtTTL time.Duration,
+	defaultRole string,
+) *AuthHandler {
+	return &AuthHandler{
+		userRepo:    userRepo,
+		blocklist:   blocklist,
+		access:      access,
+		jwtSecret:   jwtSecret,
+		jwtTTL:      jwtTTL,
+		defaultRole: defaultRole,
+	}
+}
+
+// newToken выпускает токен пользователя, при заданном access — с его ролями и правами
+func (h *AuthHandler) newToken(ctx context.Context, user *models.User) (string, error) {
+	var access *jwt.Access
+	if h.access != nil {
+		var err error
+		access, err = h.access.UserAccess(ctx, int64(user.UserID))
+		if err != nil {
+			return "", err
+		}
+	}
+	return jwt.NewToken(*user, access, h.jwtTTL, h.jwtSecret)
 }
 
 // @Summary Логин пользователя
@@ -31,6 +77,7 @@ func NewAuthHandler(userRepo UserRepository, jwtSecret string) *AuthHandler {
 // @Success 200 {object} map[string]string "JWT Token"
 // @Failure 400 {object} resp.Response
 // @Failure 401 {object} resp.Response
+// @Failure 403 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Router /api/v1/login [post]
 func (h *AuthHandler) Login(log *slog.Logger) http.HandlerFunc {
@@ -41,28 +88,34 @@ func (h *AuthHandler) Login(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			log.Info("invalid login request", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		user, err := h.userRepo.GetClientByEmail(r.Context(), req.Email)
 		if err != nil || user == nil {
 			w.WriteHeader(http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error("invalid credentials"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidCredentials))
 			return
 		}
 		// bcrypt сравнение
 		if err := bcrypt.CompareHashAndPassword(user.Password, []byte(req.Password)); err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error("invalid credentials"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidCredentials))
+			return
+		}
+		// проверяем после пароля, чтобы не раскрывать статус учётки без верных данных
+		if !user.IsActive {
+			log.Info("login attempt by inactive user", slog.Int64("user_id", int64(user.UserID)))
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgUserDeactivated))
 			return
 		}
 
 		//создание токена
-		token, err := jwt.NewToken(*user, 24*time.Hour, h.jwtSecret)
+		token, err := h.newToken(r.Context(), user)
 		if err != nil {
 			log.Error("failed to sign jwt", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal error"))
+			renderServerError(w, r, err, "internal error")
 			return
 		}
 		render.JSON(w, r, map[string]string{"token": token})
@@ -70,6 +123,7 @@ func (h *AuthHandler) Login(log *slog.Logger) http.HandlerFunc {
 }
 
 // @Summary Регистрация пользователя
+// @Description Пользователю назначается роль default_role из конфига, если она задана и существует
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -87,29 +141,32 @@ func (h *AuthHandler) Register(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			log.Info("invalid register request", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 
 		if req.Email == "" || req.Password == "" {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("email and password required"))
+			render.JSON(w, r, resp.ErrorFor(r, "email and password required"))
 			return
 		}
 
-		existingUser, err := h.userRepo.GetClientByEmail(r.Context(), req.Email)
-		fmt.Printf("DEBUG GetByEmail: user=%+v, err=%v\n", existingUser, err)
-		if existingUser != nil {
+		_, err := h.userRepo.GetClientByEmail(r.Context(), req.Email)
+		if err == nil {
 			w.WriteHeader(http.StatusConflict)
-			render.JSON(w, r, resp.Error("email already exists"))
+			render.JSON(w, r, resp.ErrorFor(r, "email already exists"))
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Error("failed to check email", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "internal error")
 			return
 		}
 
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 		if err != nil {
 			log.Error("failed to hash password", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal error"))
+			renderServerError(w, r, err, "internal error")
 			return
 		}
 
@@ -120,20 +177,73 @@ func (h *AuthHandler) Register(log *slog.Logger) http.HandlerFunc {
 			LastName:   req.LastName,
 			MiddleName: req.MiddleName,
 		}
-		if err := h.userRepo.CreateClient(r.Context(), user); err != nil {
+		if err := h.createUser(r.Context(), log, user); err != nil {
+			if errors.Is(err, storage.ErrDuplicate) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "email already exists"))
+				return
+			}
 			log.Error("failed to create user", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal error"))
+			renderServerError(w, r, err, "internal error")
 			return
 		}
 
-		token, err := jwt.NewToken(*user, 24*time.Hour, h.jwtSecret)
+		token, err := h.newToken(r.Context(), user)
 		if err != nil {
 			log.Error("failed to sign jwt", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal error"))
+			renderServerError(w, r, err, "internal error")
 			return
 		}
 		render.JSON(w, r, map[string]string{"token": token})
 	}
 }
+
+// createUser создаёт пользователя и, если задана, назначает роль по умолчанию
+func (h *AuthHandler) createUser(ctx context.Context, log *slog.Logger, user *models.User) error {
+	if h.defaultRole == "" {
+		return h.userRepo.CreateClient(ctx, user)
+	}
+	assigned, err := h.userRepo.CreateClientWithRole(ctx, user, h.defaultRole)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		log.Warn("default role not found, user registered without role", slog.String("role", h.defaultRole))
+	}
+	return nil
+}
+
+// @Summary Выход пользователя
+// @Description Отзывает текущий токен до истечения его срока действия
+// @Tags auth
+// @Produce json
+// @Success 204
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/logout [post]
+// @Security BearerAuth
+func (h *AuthHandler) Logout(log *slog.Logger) http.HandlerFunc {
+	const op = "auth.Logout"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op))
+		claims := ware.GetUserClaims(r)
+
+		jti, ok := claims["jti"].(string)
+		if !ok || jti == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "token can not be revoked"))
+			return
+		}
+		expiresAt := time.Now().Add(h.jwtTTL)
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			expiresAt = exp.Time
+		}
+
+		if err := h.blocklist.Revoke(r.Context(), jti, expiresAt); err != nil {
+			log.Error("failed to revoke token", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "internal error")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLogin_TokenExpiresAfterConfiguredTTL(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret-pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newMemUserRepo(models.User{UserID: 3, Email: "ttl@example.com", Password: hash, IsActive: true})
+
+	for _, ttl := range []time.Duration{15 * time.Minute, 24 * time.Hour, 7 * 24 * time.Hour} {
+		t.Run(ttl.String(), func(t *testing.T) {
+			auth := NewAuthHandler(repo, nil, nil, testJWTSecret, ttl, "")
+			body := `{"email":"ttl@example.com","password":"secret-pass"}`
+			rec := httptest.NewRecorder()
+			before := time.Now()
+			auth.Login(discardLogger())(rec, httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(body)))
+			after := time.Now()
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+			}
+
+			var out struct {
+				Token string `json:"token"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+				t.Fatal(err)
+			}
+			token, err := jwtlib.Parse(out.Token, func(*jwtlib.Token) (interface{}, error) { return []byte(testJWTSecret), nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			exp, err := token.Claims.GetExpirationTime()
+			if err != nil || exp == nil {
+				t.Fatalf("exp claim: %v", err)
+			}
+			// exp в секундах, поэтому допускаем усечение до целой секунды
+			if lo, hi := before.Add(ttl).Truncate(time.Second), after.Add(ttl); exp.Before(lo) || exp.After(hi) {
+				t.Errorf("exp = %s, want between %s and %s", exp, lo, hi)
+			}
+		})
+	}
+}
+
+// registeringUsers memUserRepo, который создаёт пользователей и запоминает назначенные роли.
+// Роль по умолчанию назначается, только если она есть в roles.
+type registeringUsers struct {
+	*memUserRepo
+	roles    map[string]bool
+	assigned map[int64]string
+	emailErr error
+}
+
+func newRegisteringUsers(roles ...string) *registeringUsers {
+	repo := &registeringUsers{memUserRepo: newMemUserRepo(), roles: map[string]bool{}, assigned: map[int64]string{}}
+	for _, role := range roles {
+		repo.roles[role] = true
+	}
+	return repo
+}
+
+func (m *registeringUsers) GetClientByEmail(ctx context.Context, email string) (*models.User, error) {
+	if m.emailErr != nil {
+		return nil, m.emailErr
+	}
+	return m.memUserRepo.GetClientByEmail(ctx, email)
+}
+
+func (m *registeringUsers) CreateClient(_ context.Context, user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user.UserID = models.ID(len(m.users) + 1)
+	user.IsActive = true
+	m.users[int64(user.UserID)] = *user
+	return nil
+}
+
+func (m *registeringUsers) CreateClientWithRole(ctx context.Context, user *models.User, role string) (bool, error) {
+	if err := m.CreateClient(ctx, user); err != nil {
+		return false, err
+	}
+	if !m.roles[role] {
+		return false, nil
+	}
+	m.assigned[int64(user.UserID)] = role
+	return true, nil
+}
+
+func register(t *testing.T, h *AuthHandler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.Register(discardLogger())(rec, httptest.NewRequest(http.MethodPost, "/api/v1/register", strings.NewReader(body)))
+	return rec
+}
+
+func TestRegister_DefaultRole(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultRole  string
+		roles        []string
+		wantAssigned string
+	}{
+		{"role assigned", "student", []string{"student"}, "student"},
+		{"role missing", "student", nil, ""},
+		{"no default role", "", []string{"student"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRegisteringUsers(tt.roles...)
+			h := NewAuthHandler(repo, nil, nil, testJWTSecret, time.Hour, tt.defaultRole)
+			rec := register(t, h, `{"email":"new@example.com","password":"secret-pass"}`)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+			}
+			if len(repo.users) != 1 {
+				t.Fatalf("users = %v, want one registered", repo.users)
+			}
+			if got := repo.assigned[1]; got != tt.wantAssigned {
+				t.Errorf("assigned role = %q, want %q", got, tt.wantAssigned)
+			}
+		})
+	}
+}
+
+func TestRegister_EmailLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  bool
+		emailErr  error
+		want      int
+		wantUsers int
+	}{
+		{"new email", false, nil, http.StatusOK, 1},
+		{"email taken", true, nil, http.StatusConflict, 1},
+		{"lookup failed", false, errors.New("db is down"), http.StatusInternalServerError, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRegisteringUsers()
+			if tt.existing {
+				repo.users[1] = models.User{UserID: 1, Email: "new@example.com", IsActive: true}
+			}
+			repo.emailErr = tt.emailErr
+			h := NewAuthHandler(repo, nil, nil, testJWTSecret, time.Hour, "")
+			rec := register(t, h, `{"email":"new@example.com","password":"secret-pass"}`)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if len(repo.users) != tt.wantUsers {
+				t.Errorf("users = %d, want %d", len(repo.users), tt.wantUsers)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -26,12 +27,13 @@ type CurriculumRepository interface {
 }
 
 type CurriculumHandler struct {
-	repo      CurriculumRepository
-	auditRepo AuditLogRepository
+	repo         CurriculumRepository
+	semesterRepo SemesterRepository
+	auditRepo    AuditLogRepository
 }
 
-func NewCurriculumHandler(repo CurriculumRepository, auditRepo AuditLogRepository) *CurriculumHandler {
-	return &CurriculumHandler{repo: repo, auditRepo: auditRepo}
+func NewCurriculumHandler(repo CurriculumRepository, semesterRepo SemesterRepository, auditRepo AuditLogRepository) *CurriculumHandler {
+	return &CurriculumHandler{repo: repo, semesterRepo: semesterRepo, auditRepo: auditRepo}
 }
 
 // @Summary Создать учебный план
@@ -50,13 +52,12 @@ func (h *CurriculumHandler) CreateCurriculum(log *slog.Logger) http.HandlerFunc
 		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		if err := h.repo.CreateCurriculum(r.Context(), &c); err != nil {
 			log.Error("failed to create curriculum", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create curriculum"))
+			renderServerError(w, r, err, "failed to create curriculum")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -67,6 +68,7 @@ func (h *CurriculumHandler) CreateCurriculum(log *slog.Logger) http.HandlerFunc
 			NewData:    utils.PtrToJSON(c),
 			Comment:    utils.PtrToStr("Curriculum created."),
 		})
+		setLocation(w, "curriculums", c.CurriculumID)
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, c)
 	}
@@ -89,7 +91,7 @@ func (h *CurriculumHandler) GetCurriculumByID(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid curriculum id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid curriculum id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid curriculum id"))
 			return
 		}
 		c, err := h.repo.GetCurriculumByID(r.Context(), id)
@@ -97,12 +99,11 @@ func (h *CurriculumHandler) GetCurriculumByID(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("curriculum not found", slog.Int64("curriculum_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("curriculum not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "curriculum not found"))
 				return
 			}
 			log.Error("failed to get curriculum", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get curriculum"))
+			renderServerError(w, r, err, "failed to get curriculum")
 			return
 		}
 		render.JSON(w, r, c)
@@ -127,14 +128,14 @@ func (h *CurriculumHandler) UpdateCurriculum(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid curriculum id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid curriculum id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid curriculum id"))
 			return
 		}
 		var c models.Curriculum
 		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		c.CurriculumID = id
@@ -143,12 +144,11 @@ func (h *CurriculumHandler) UpdateCurriculum(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("curriculum not found for update", slog.Int64("curriculum_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("curriculum not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "curriculum not found"))
 				return
 			}
 			log.Error("failed to update curriculum", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update curriculum"))
+			renderServerError(w, r, err, "failed to update curriculum")
 			return
 		}
 
@@ -183,7 +183,7 @@ func (h *CurriculumHandler) DeleteCurriculum(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid curriculum id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid curriculum id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid curriculum id"))
 			return
 		}
 		oldData, _ := h.repo.GetCurriculumByID(r.Context(), id)
@@ -191,12 +191,11 @@ func (h *CurriculumHandler) DeleteCurriculum(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("curriculum not found for delete", slog.Int64("curriculum_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("curriculum not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "curriculum not found"))
 				return
 			}
 			log.Error("failed to delete curriculum", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete curriculum"))
+			renderServerError(w, r, err, "failed to delete curriculum")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -217,7 +216,7 @@ func (h *CurriculumHandler) DeleteCurriculum(log *slog.Logger) http.HandlerFunc
 // @Produce json
 // @Param semester_id query int false "ID семестра"
 // @Param discipline_id query int false "ID дисциплины"
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
 // @Success 200 {array} models.Curriculum
 // @Router /api/v1/curriculums [get]
@@ -242,19 +241,164 @@ func (h *CurriculumHandler) ListCurriculum(log *slog.Logger) http.HandlerFunc {
 				disciplineID = &id
 			}
 		}
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
-		}
+		limit, offset := parsePagination(r)
 
 		items, err := h.repo.ListCurriculum(r.Context(), semesterID, disciplineID, limit, offset)
 		if err != nil {
 			log.Error("failed to list curriculums", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list curriculums"))
+			renderServerError(w, r, err, "failed to list curriculums")
+			return
+		}
+		render.JSON(w, r, items)
+	}
+}
+
+// @Summary Учебный план дисциплины на семестр
+// @Description Без semester_id берётся текущий семестр
+// @Tags curriculums
+// @Produce json
+// @Param id path int true "ID дисциплины"
+// @Param semester_id query int false "ID семестра (по умолчанию текущий)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.Curriculum
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/disciplines/{id}/curriculum [get]
+// @Security BearerAuth
+func (h *CurriculumHandler) ListDisciplineCurriculum(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.curriculum_handler.ListDisciplineCurriculum"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		idStr := chi.URLParam(r, "id")
+		disciplineID, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid discipline id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid discipline id"))
+			return
+		}
+
+		var semesterID int64
+		if val := r.URL.Query().Get("semester_id"); val != "" {
+			semesterID, err = strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				log.Info("invalid semester id", slog.String("semester_id", val))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid semester_id"))
+				return
+			}
+		} else {
+			semester, err := h.semesterRepo.GetCurrentSemester(r.Context(), time.Now())
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					log.Info("no current semester")
+					w.WriteHeader(http.StatusNotFound)
+					render.JSON(w, r, resp.ErrorFor(r, "current semester not found"))
+					return
+				}
+				log.Error("failed to get current semester", slog.String("err", err.Error()))
+				renderServerError(w, r, err, "failed to get current semester")
+				return
+			}
+			semesterID = semester.SemesterID
+		}
+
+		limit, offset := parsePagination(r)
+
+		items, err := h.repo.ListCurriculum(r.Context(), &semesterID, &disciplineID, limit, offset)
+		if err != nil {
+			log.Error("failed to list curriculums", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list curriculums")
 			return
 		}
+		if items == nil {
+			items = []*models.Curriculum{}
+		}
 		render.JSON(w, r, items)
 	}
 }
+
+// @Summary Скопировать учебный план в другой семестр
+// @Tags curriculums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID исходного учебного плана"
+// @Param input body models.CurriculumCopyRequest true "Целевой семестр"
+// @Success 201 {object} models.Curriculum
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 422 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/curriculums/{id}/copy [post]
+// @Security BearerAuth
+func (h *CurriculumHandler) CopyCurriculum(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.curriculum_handler.CopyCurriculum"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid curriculum id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid curriculum id"))
+			return
+		}
+		var req models.CurriculumCopyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TargetSemesterID == 0 {
+			log.Info("invalid copy request")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "target_semester_id is required"))
+			return
+		}
+
+		src, err := h.repo.GetCurriculumByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("curriculum not found", slog.Int64("curriculum_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "curriculum not found"))
+				return
+			}
+			log.Error("failed to get curriculum", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get curriculum")
+			return
+		}
+
+		if _, err := h.semesterRepo.GetSemesterByID(r.Context(), req.TargetSemesterID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("target semester not found", slog.Int64("semester_id", req.TargetSemesterID))
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				render.JSON(w, r, resp.ErrorFor(r, "target semester not found"))
+				return
+			}
+			log.Error("failed to get semester", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get semester")
+			return
+		}
+
+		c := models.Curriculum{
+			SubjectName:        src.SubjectName,
+			SubjectDescription: src.SubjectDescription,
+			SemesterID:         &req.TargetSemesterID,
+			DisciplineID:       src.DisciplineID,
+		}
+		if err := h.repo.CreateCurriculum(r.Context(), &c); err != nil {
+			log.Error("failed to copy curriculum", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to copy curriculum")
+			return
+		}
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "curriculum",
+			RowID:      c.CurriculumID,
+			ActionType: "INSERT",
+			NewData:    utils.PtrToJSON(c),
+			Comment:    utils.PtrToStr("Curriculum copied from " + idStr + "."),
+		})
+		setLocation(w, "curriculums", c.CurriculumID)
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, c)
+	}
+}
@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memCurriculumRepo учебные планы в памяти, id выдаются по порядку
+type memCurriculumRepo struct {
+	CurriculumRepository
+	items map[int64]*models.Curriculum
+	next  int64
+}
+
+func newMemCurriculumRepo(items ...*models.Curriculum) *memCurriculumRepo {
+	m := &memCurriculumRepo{items: map[int64]*models.Curriculum{}}
+	for _, c := range items {
+		m.items[c.CurriculumID] = c
+		if c.CurriculumID > m.next {
+			m.next = c.CurriculumID
+		}
+	}
+	return m
+}
+
+func (m *memCurriculumRepo) GetCurriculumByID(_ context.Context, id int64) (*models.Curriculum, error) {
+	c, ok := m.items[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (m *memCurriculumRepo) CreateCurriculum(_ context.Context, c *models.Curriculum) error {
+	m.next++
+	c.CurriculumID = m.next
+	c.CreatedAt = time.Now().UTC()
+	c.UpdatedAt = c.CreatedAt
+	cp := *c
+	m.items[c.CurriculumID] = &cp
+	return nil
+}
+
+// knownSemesters семестры по id
+type knownSemesters struct {
+	SemesterRepository
+	byID map[int64]*models.Semester
+}
+
+func (k knownSemesters) GetSemesterByID(_ context.Context, id int64) (*models.Semester, error) {
+	s, ok := k.byID[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return s, nil
+}
+
+// GetCurrentSemester семестр, в который попадает at; из пересекающихся — начавшийся позже
+func (k knownSemesters) GetCurrentSemester(_ context.Context, at time.Time) (*models.Semester, error) {
+	var current *models.Semester
+	for _, s := range k.byID {
+		if at.Before(s.StartWith) || at.After(s.EndsWith) {
+			continue
+		}
+		if current == nil || s.StartWith.After(current.StartWith) {
+			current = s
+		}
+	}
+	if current == nil {
+		return nil, sql.ErrNoRows
+	}
+	return current, nil
+}
+
+// ListCurriculum фильтры по семестру и дисциплине, порядок — по id
+func (m *memCurriculumRepo) ListCurriculum(_ context.Context, semesterID, disciplineID *int64, limit, offset int) ([]*models.Curriculum, error) {
+	var items []*models.Curriculum
+	for _, c := range m.items {
+		switch {
+		case semesterID != nil && (c.SemesterID == nil || *c.SemesterID != *semesterID),
+			disciplineID != nil && c.DisciplineID != *disciplineID:
+			continue
+		}
+		items = append(items, c)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CurriculumID < items[j].CurriculumID })
+	if offset >= len(items) {
+		return nil, nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func copyCurriculum(t *testing.T, h *CurriculumHandler, id, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/curriculums/"+id+"/copy", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.CopyCurriculum(discardLogger())(rec, withURLParams(r, "id", id))
+	return rec
+}
+
+func TestCopyCurriculum(t *testing.T) {
+	desc := "Лекции и практика"
+	oldSemester := int64(1)
+	created := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	repo := newMemCurriculumRepo(&models.Curriculum{
+		CurriculumID: 4, CreatedAt: created, UpdatedAt: created,
+		SubjectName: "Алгебра", SubjectDescription: &desc, SemesterID: &oldSemester, DisciplineID: 9,
+	})
+	semesters := knownSemesters{byID: map[int64]*models.Semester{1: {SemesterID: 1}, 2: {SemesterID: 2}}}
+	audit := &recordingAudit{}
+	h := NewCurriculumHandler(repo, semesters, audit)
+
+	rec := copyCurriculum(t, h, "4", `{"target_semester_id":2}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var got models.Curriculum
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.CurriculumID == 4 || got.SemesterID == nil || *got.SemesterID != 2 {
+		t.Fatalf("copy = %+v, want new record in semester 2", got)
+	}
+	if got.SubjectName != "Алгебра" || got.SubjectDescription == nil || *got.SubjectDescription != desc || got.DisciplineID != 9 {
+		t.Errorf("copy lost source fields: %+v", got)
+	}
+	if !got.CreatedAt.After(created) {
+		t.Errorf("copy created_at = %s, want fresh timestamp", got.CreatedAt)
+	}
+	if loc := rec.Header().Get("Location"); !strings.HasSuffix(loc, "/curriculums/5") {
+		t.Errorf("Location = %q", loc)
+	}
+
+	src, _ := repo.GetCurriculumByID(context.Background(), 4)
+	if *src.SemesterID != 1 || !src.CreatedAt.Equal(created) {
+		t.Errorf("source changed: %+v", src)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].RowID != got.CurriculumID {
+		t.Errorf("audit = %+v", audit.entries)
+	}
+}
+
+func TestCopyCurriculum_Errors(t *testing.T) {
+	repo := newMemCurriculumRepo(&models.Curriculum{CurriculumID: 4, SubjectName: "Алгебра", DisciplineID: 9})
+	h := NewCurriculumHandler(repo, knownSemesters{byID: map[int64]*models.Semester{2: {SemesterID: 2}}}, &recordingAudit{})
+
+	tests := []struct {
+		name string
+		id   string
+		body string
+		want int
+	}{
+		{"нет целевого семестра", "4", `{"target_semester_id":99}`, http.StatusUnprocessableEntity},
+		{"не указан семестр", "4", `{}`, http.StatusBadRequest},
+		{"нет исходного плана", "7", `{"target_semester_id":2}`, http.StatusNotFound},
+		{"некорректный id", "abc", `{"target_semester_id":2}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := copyCurriculum(t, h, tt.id, tt.body); rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+	if len(repo.items) != 1 {
+		t.Errorf("failed copies created records: %d items", len(repo.items))
+	}
+}
+
+func TestListDisciplineCurriculum(t *testing.T) {
+	now := time.Now()
+	past, current := int64(1), int64(2)
+	semesters := knownSemesters{byID: map[int64]*models.Semester{
+		1: {SemesterID: 1, StartWith: now.AddDate(0, -8, 0), EndsWith: now.AddDate(0, -3, 0)},
+		2: {SemesterID: 2, StartWith: now.AddDate(0, -1, 0), EndsWith: now.AddDate(0, 4, 0)},
+	}}
+	repo := newMemCurriculumRepo(
+		&models.Curriculum{CurriculumID: 1, SubjectName: "Механика", SemesterID: &past, DisciplineID: 9},
+		&models.Curriculum{CurriculumID: 2, SubjectName: "Оптика", SemesterID: &current, DisciplineID: 9},
+		&models.Curriculum{CurriculumID: 3, SubjectName: "Электричество", SemesterID: &current, DisciplineID: 9},
+		// тот же семестр, другая дисциплина
+		&models.Curriculum{CurriculumID: 4, SubjectName: "Органика", SemesterID: &current, DisciplineID: 5},
+	)
+
+	tests := []struct {
+		name      string
+		semesters knownSemesters
+		query     string
+		want      int
+		wantIDs   []int64
+	}{
+		{"explicit semester", semesters, "?semester_id=1", http.StatusOK, []int64{1}},
+		{"defaults to current semester", semesters, "", http.StatusOK, []int64{2, 3}},
+		{"semester without curriculum", semesters, "?semester_id=7", http.StatusOK, []int64{}},
+		{"no current semester", knownSemesters{}, "", http.StatusNotFound, nil},
+		{"invalid semester", semesters, "?semester_id=abc", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCurriculumHandler(repo, tt.semesters, &recordingAudit{})
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/disciplines/9/curriculum"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.ListDisciplineCurriculum(discardLogger())(rec, withURLParams(r, "id", "9"))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var items []*models.Curriculum
+			if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+				t.Fatal(err)
+			}
+			ids := []int64{}
+			for _, c := range items {
+				ids = append(ids, c.CurriculumID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("curriculum ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"service/internal/domain/models"
+	resp "service/internal/lib/api/response"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const dashboardCacheTTL = 30 * time.Second
+
+type DashboardRepository interface {
+	GetDashboardMetrics(ctx context.Context, weekStart time.Time) (*models.DashboardMetrics, error)
+}
+
+type DashboardHandler struct {
+	repo DashboardRepository
+
+	// mu защищает только кэш и ссылку на текущее обновление, запросы к БД выполняются без блокировки
+	mu       sync.Mutex
+	cached   *models.DashboardMetrics
+	cachedAt time.Time
+	refresh  *dashboardRefresh
+}
+
+// dashboardRefresh одно обновление метрик, результат которого ждут все параллельные запросы
+type dashboardRefresh struct {
+	done    chan struct{}
+	metrics *models.DashboardMetrics
+	err     error
+}
+
+func NewDashboardHandler(repo DashboardRepository) *DashboardHandler {
+	return &DashboardHandler{repo: repo}
+}
+
+// metrics отдаёт метрики из кэша, а при устаревшем кэше запускает одно обновление на всех ожидающих
+func (h *DashboardHandler) metrics(ctx context.Context) (*models.DashboardMetrics, error) {
+	h.mu.Lock()
+	if h.cached != nil && time.Since(h.cachedAt) < dashboardCacheTTL {
+		cached := h.cached
+		h.mu.Unlock()
+		return cached, nil
+	}
+	call := h.refresh
+	if call == nil {
+		call = &dashboardRefresh{done: make(chan struct{})}
+		h.refresh = call
+		// обновление не привязано к запросу, который его начал: его результат ждут и другие
+		go h.doRefresh(context.WithoutCancel(ctx), call)
+	}
+	h.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.metrics, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (h *DashboardHandler) doRefresh(ctx context.Context, call *dashboardRefresh) {
+	call.metrics, call.err = h.repo.GetDashboardMetrics(ctx, startOfWeek(time.Now()))
+
+	h.mu.Lock()
+	if call.err == nil {
+		h.cached = call.metrics
+		h.cachedAt = time.Now()
+	}
+	h.refresh = nil
+	h.mu.Unlock()
+
+	close(call.done)
+}
+
+// @Summary Получить метрики для дашборда
+// @Tags dashboard
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.DashboardMetrics
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/dashboard [get]
+// @Security BearerAuth
+func (h *DashboardHandler) GetDashboard(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.dashboard_handler.GetDashboard"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+
+		metrics, err := h.metrics(r.Context())
+		if err != nil {
+			log.Error("failed to get dashboard metrics", slog.String("err", err.Error()))
+			renderServerError(w, r, err, resp.MsgInternal)
+			return
+		}
+		render.JSON(w, r, metrics)
+	}
+}
+
+// startOfWeek возвращает начало недели (понедельник 00:00)
+func startOfWeek(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	y, m, d := t.AddDate(0, 0, -(weekday - 1)).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
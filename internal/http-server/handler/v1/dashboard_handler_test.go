@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/domain/models"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type slowDashboardRepo struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (r *slowDashboardRepo) GetDashboardMetrics(ctx context.Context, weekStart time.Time) (*models.DashboardMetrics, error) {
+	r.calls.Add(1)
+	<-r.release
+	return &models.DashboardMetrics{}, nil
+}
+
+func TestGetDashboard_ConcurrentRequestsShareOneRefresh(t *testing.T) {
+	repo := &slowDashboardRepo{release: make(chan struct{})}
+	h := NewDashboardHandler(repo)
+	handler := h.GetDashboard(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	const n = 10
+	var wg sync.WaitGroup
+	codes := make([]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+			codes[i] = rec.Code
+		}(i)
+	}
+
+	// ждём, пока первый запрос дойдёт до БД, остальные должны ждать его результат
+	deadline := time.Now().Add(time.Second)
+	for repo.calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(repo.release)
+	wg.Wait()
+
+	if got := repo.calls.Load(); got != 1 {
+		t.Fatalf("repo called %d times, want 1", got)
+	}
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i, code)
+		}
+	}
+
+	// следующий запрос обслуживается из кэша
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+	if rec.Code != http.StatusOK || repo.calls.Load() != 1 {
+		t.Fatalf("cached request: status %d, calls %d", rec.Code, repo.calls.Load())
+	}
+}
+
+func TestGetDashboard_CanceledWaiterDoesNotBlock(t *testing.T) {
+	repo := &slowDashboardRepo{release: make(chan struct{})}
+	defer close(repo.release)
+	h := NewDashboardHandler(repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.metrics(ctx); err == nil {
+		t.Fatal("expected context error")
+	}
+}
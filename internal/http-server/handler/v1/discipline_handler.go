@@ -5,12 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"service/internal/domain/models"
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -22,18 +27,75 @@ type DisciplineRepository interface {
 	GetDisciplineByID(ctx context.Context, id int64) (*models.Discipline, error)
 	UpdateDiscipline(ctx context.Context, discipline *models.Discipline) error
 	DeleteDiscipline(ctx context.Context, id int64) error
-	ListDiscipline(ctx context.Context, limit, offset int) ([]*models.Discipline, error)
+	ListDiscipline(ctx context.Context, teacherID, studentGroupID, academicYearID *int64, updatedSince *time.Time, hasGrades *bool, limit, offset int) ([]*models.Discipline, error)
 	GetDisciplinePublicByID(ctx context.Context, id int64) (*models.DisciplinePublic, error)
-	ListDisciplinePublic(ctx context.Context, limit, offset int, teacherID, studentGroupID, academicYearID *int64) ([]*models.DisciplinePublic, error)
+	ListDisciplinePublic(ctx context.Context, limit, offset int, teacherID, studentGroupID, academicYearID *int64, updatedSince *time.Time) ([]*models.DisciplinePublic, error)
+	CountTeacherDisciplinesInGroupYear(ctx context.Context, teacherID, studentGroupID int64) (int, error)
+	TeacherExists(ctx context.Context, teacherID int64) (bool, error)
+	StudentGroupExists(ctx context.Context, studentGroupID int64) (bool, error)
+	ReassignTeacherDisciplines(ctx context.Context, fromTeacherID, toTeacherID int64) ([]int64, error)
+	SearchDisciplinePublic(ctx context.Context, q string, limit, offset int) ([]*models.DisciplinePublic, error)
+	CountCurriculumByDisciplines(ctx context.Context, disciplineIDs []int64) (map[int64]int64, error)
+	CountDisciplineStudents(ctx context.Context, disciplineID int64) (*models.DisciplineStudentCount, error)
 }
 
 type DisciplineHandler struct {
-	repo      DisciplineRepository
-	auditRepo AuditLogRepository
+	repo              DisciplineRepository
+	auditRepo         AuditLogRepository
+	checkAcademicYear bool
 }
 
-func NewDisciplineHandler(repo DisciplineRepository, auditRepo AuditLogRepository) *DisciplineHandler {
-	return &DisciplineHandler{repo: repo, auditRepo: auditRepo}
+func NewDisciplineHandler(repo DisciplineRepository, auditRepo AuditLogRepository, checkAcademicYear bool) *DisciplineHandler {
+	return &DisciplineHandler{repo: repo, auditRepo: auditRepo, checkAcademicYear: checkAcademicYear}
+}
+
+// validateReferences проверяет, что преподаватель и группа существуют.
+// Возвращает текст ошибки для 422 или пустую строку.
+func (h *DisciplineHandler) validateReferences(ctx context.Context, d *models.Discipline) (string, error) {
+	ok, err := h.repo.TeacherExists(ctx, d.TeacherID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "teacher not found", nil
+	}
+	ok, err = h.repo.StudentGroupExists(ctx, d.StudentGroupID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "student group not found", nil
+	}
+	return "", nil
+}
+
+// parseDisciplineFilters общие фильтры приватного и публичного списков дисциплин,
+// некорректные значения игнорируются
+func parseDisciplineFilters(q url.Values) (teacherID, studentGroupID, academicYearID *int64) {
+	if val := q.Get("teacher_id"); val != "" {
+		id, err := strconv.ParseInt(val, 10, 64)
+		if err == nil {
+			teacherID = &id
+		}
+	}
+	if val := q.Get("student_group_id"); val != "" {
+		id, err := strconv.ParseInt(val, 10, 64)
+		if err == nil {
+			studentGroupID = &id
+		}
+	}
+	if val := q.Get("academic_year_id"); val != "" {
+		id, err := strconv.ParseInt(val, 10, 64)
+		if err == nil {
+			academicYearID = &id
+		}
+	}
+	return teacherID, studentGroupID, academicYearID
+}
+
+type disciplineCreateResponse struct {
+	models.Discipline
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // @Summary Создать дисциплину
@@ -41,7 +103,8 @@ func NewDisciplineHandler(repo DisciplineRepository, auditRepo AuditLogRepositor
 // @Accept json
 // @Produce json
 // @Param input body models.Discipline true "Дисциплина"
-// @Success 201 {object} models.Discipline
+// @Success 201 {object} disciplineCreateResponse
+// @Failure 422 {object} resp.Response
 // @Router /api/v1/disciplines [post]
 // @Security BearerAuth
 func (h *DisciplineHandler) CreateDiscipline(log *slog.Logger) http.HandlerFunc {
@@ -56,14 +119,47 @@ func (h *DisciplineHandler) CreateDiscipline(log *slog.Logger) http.HandlerFunc
 		if err := json.NewDecoder(r.Body).Decode(&discipline); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 
+		msg, err := h.validateReferences(r.Context(), &discipline)
+		if err != nil {
+			log.Error("failed to validate discipline references", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to create discipline")
+			return
+		}
+		if msg != "" {
+			log.Info("invalid discipline references", slog.String("reason", msg))
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			render.JSON(w, r, resp.ErrorFor(r, msg))
+			return
+		}
+
+		// Неблокирующая проверка: преподаватель должен уже вести дисциплины в учебном году группы
+		var warnings []string
+		if h.checkAcademicYear {
+			count, err := h.repo.CountTeacherDisciplinesInGroupYear(r.Context(), discipline.TeacherID, discipline.StudentGroupID)
+			if err != nil {
+				log.Error("failed to check teacher academic year", slog.String("err", err.Error()))
+			} else if count == 0 {
+				log.Info("teacher has no disciplines in group academic year",
+					slog.Int64("teacher_id", discipline.TeacherID),
+					slog.Int64("student_group_id", discipline.StudentGroupID),
+				)
+				warnings = append(warnings, "teacher has no other disciplines in the group's academic year")
+			}
+		}
+
 		if err := h.repo.CreateDiscipline(r.Context(), &discipline); err != nil {
+			if errors.Is(err, storage.ErrMissingReference) {
+				log.Info("discipline references missing record", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, missingReferenceMessage(err)))
+				return
+			}
 			log.Error("failed to create discipline", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create discipline"))
+			renderServerError(w, r, err, "failed to create discipline")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -74,8 +170,9 @@ func (h *DisciplineHandler) CreateDiscipline(log *slog.Logger) http.HandlerFunc
 			NewData:    utils.PtrToJSON(discipline),
 			Comment:    utils.PtrToStr("Discipline created"),
 		})
+		setLocation(w, "disciplines", discipline.DisciplineID)
 		w.WriteHeader(http.StatusCreated)
-		render.JSON(w, r, discipline)
+		render.JSON(w, r, disciplineCreateResponse{Discipline: discipline, Warnings: warnings})
 	}
 }
 
@@ -100,7 +197,7 @@ func (h *DisciplineHandler) GetDisciplineByID(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid discipline id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid discipline id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid discipline id"))
 			return
 		}
 		discipline, err := h.repo.GetDisciplineByID(r.Context(), id)
@@ -108,18 +205,60 @@ func (h *DisciplineHandler) GetDisciplineByID(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("discipline not found", slog.Int64("discipline_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("discipline not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "discipline not found"))
 				return
 			}
 			log.Error("failed to get discipline", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get discipline"))
+			renderServerError(w, r, err, "failed to get discipline")
 			return
 		}
 		render.JSON(w, r, discipline)
 	}
 }
 
+// @Summary Число студентов дисциплины
+// @Description Количество студентов в группе, у которой ведётся дисциплина
+// @Tags disciplines
+// @Produce json
+// @Param id path int true "ID дисциплины"
+// @Success 200 {object} models.DisciplineStudentCount
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/disciplines/{id}/student-count [get]
+// @Security BearerAuth
+func (h *DisciplineHandler) GetDisciplineStudentCount(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.discipline_handler.GetDisciplineStudentCount"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid discipline id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid discipline id"))
+			return
+		}
+		count, err := h.repo.CountDisciplineStudents(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("discipline not found", slog.Int64("discipline_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "discipline not found"))
+				return
+			}
+			log.Error("failed to count discipline students", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to count discipline students")
+			return
+		}
+		render.JSON(w, r, count)
+	}
+}
+
 // @Summary Обновить дисциплину
 // @Tags disciplines
 // @Accept json
@@ -127,6 +266,7 @@ func (h *DisciplineHandler) GetDisciplineByID(log *slog.Logger) http.HandlerFunc
 // @Param id path int true "ID дисциплины"
 // @Param input body models.Discipline true "Дисциплина"
 // @Success 200 {object} models.Discipline
+// @Failure 422 {object} resp.Response
 // @Router /api/v1/disciplines/{id} [put]
 // @Security BearerAuth
 func (h *DisciplineHandler) UpdateDiscipline(log *slog.Logger) http.HandlerFunc {
@@ -142,30 +282,51 @@ func (h *DisciplineHandler) UpdateDiscipline(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid discipline id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid discipline id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid discipline id"))
 			return
 		}
 		var discipline models.Discipline
 		if err := json.NewDecoder(r.Body).Decode(&discipline); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		discipline.DisciplineID = id
+		msg, err := h.validateReferences(r.Context(), &discipline)
+		if err != nil {
+			log.Error("failed to validate discipline references", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update discipline")
+			return
+		}
+		if msg != "" {
+			log.Info("invalid discipline references", slog.String("reason", msg))
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			render.JSON(w, r, resp.ErrorFor(r, msg))
+			return
+		}
 		oldData, _ := h.repo.GetDisciplineByID(r.Context(), id)
 		if err := h.repo.UpdateDiscipline(r.Context(), &discipline); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("discipline not found for update", slog.Int64("discipline_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("discipline not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "discipline not found"))
+				return
+			}
+			if errors.Is(err, storage.ErrMissingReference) {
+				log.Info("discipline references missing record", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, missingReferenceMessage(err)))
 				return
 			}
 			log.Error("failed to update discipline", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update discipline"))
+			renderServerError(w, r, err, "failed to update discipline")
 			return
 		}
+		comment := "Discipline updated"
+		if oldData != nil && oldData.DisciplineName != discipline.DisciplineName {
+			comment = fmt.Sprintf("Discipline renamed: %q -> %q", oldData.DisciplineName, discipline.DisciplineName)
+		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
 			UserID:     utils.GetUserIDFromContext(r.Context()),
 			TableName:  "discipline",
@@ -173,7 +334,8 @@ func (h *DisciplineHandler) UpdateDiscipline(log *slog.Logger) http.HandlerFunc
 			ActionType: "UPDATE",
 			NewData:    utils.PtrToJSON(discipline),
 			OldData:    utils.PtrToJSON(oldData),
-			Comment:    utils.PtrToStr("Discipline updated"),
+			Changes:    utils.PtrToJSONDiff(oldData, discipline),
+			Comment:    utils.PtrToStr(comment),
 		})
 		w.WriteHeader(http.StatusOK)
 		render.JSON(w, r, discipline)
@@ -186,6 +348,7 @@ func (h *DisciplineHandler) UpdateDiscipline(log *slog.Logger) http.HandlerFunc
 // @Produce json
 // @Param id path int true "ID дисциплины"
 // @Success 204 {string} string "No Content"
+// @Failure 409 {object} resp.Response "На дисциплину ссылаются оценки, посещаемость или учебный план"
 // @Router /api/v1/disciplines/{id} [delete]
 // @Security BearerAuth
 func (h *DisciplineHandler) DeleteDiscipline(log *slog.Logger) http.HandlerFunc {
@@ -201,20 +364,25 @@ func (h *DisciplineHandler) DeleteDiscipline(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid discipline id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid discipline id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid discipline id"))
 			return
 		}
 		oldData, _ := h.repo.GetDisciplineByID(r.Context(), id)
 		if err := h.repo.DeleteDiscipline(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("discipline is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("discipline", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("discipline not found for delete", slog.Int64("discipline_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("discipline not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "discipline not found"))
 				return
 			}
 			log.Error("failed to delete discipline", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete discipline"))
+			renderServerError(w, r, err, "failed to delete discipline")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -235,9 +403,14 @@ func (h *DisciplineHandler) DeleteDiscipline(log *slog.Logger) http.HandlerFunc
 // @Produce json
 // @Param teacher_id query int false "ID преподавателя"
 // @Param student_group_id query int false "ID группы"
-// @Param limit query int false "Ограничение"
+// @Param academic_year_id query int false "ID учебного года"
+// @Param updated_since query string false "Изменённые начиная с (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param has_grades query bool false "Только с выставленными оценками (true) или без них (false)"
+// @Param with_curriculum_count query bool false "Вернуть models.DisciplineWithCurriculumCount с числом пунктов учебного плана"
 // @Success 200 {array} models.Discipline
+// @Failure 400 {object} resp.Response
 // @Router /api/v1/disciplines [get]
 // @Security BearerAuth
 func (h *DisciplineHandler) ListDiscipline(log *slog.Logger) http.HandlerFunc {
@@ -247,19 +420,45 @@ func (h *DisciplineHandler) ListDiscipline(log *slog.Logger) http.HandlerFunc {
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+		teacherID, studentGroupID, academicYearID := parseDisciplineFilters(r.URL.Query())
+		var hasGrades *bool
+		if v := r.URL.Query().Get("has_grades"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Info("invalid has_grades", slog.String("has_grades", v))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid has_grades"))
+				return
+			}
+			hasGrades = &b
 		}
-		disciplines, err := h.repo.ListDiscipline(r.Context(), limit, offset)
+		disciplines, err := h.repo.ListDiscipline(r.Context(), teacherID, studentGroupID, academicYearID, parseUpdatedSince(r), hasGrades, limit, offset)
 		if err != nil {
 			log.Error("failed to list disciplines", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list disciplines"))
+			renderServerError(w, r, err, "failed to list disciplines")
 			return
 		}
-		render.JSON(w, r, disciplines)
+		if ok, _ := strconv.ParseBool(r.URL.Query().Get("with_curriculum_count")); !ok {
+			render.JSON(w, r, disciplines)
+			return
+		}
+
+		ids := make([]int64, 0, len(disciplines))
+		for _, d := range disciplines {
+			ids = append(ids, d.DisciplineID)
+		}
+		counts, err := h.repo.CountCurriculumByDisciplines(r.Context(), ids)
+		if err != nil {
+			log.Error("failed to count curriculum", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list disciplines")
+			return
+		}
+		items := make([]*models.DisciplineWithCurriculumCount, 0, len(disciplines))
+		for _, d := range disciplines {
+			items = append(items, &models.DisciplineWithCurriculumCount{Discipline: *d, CurriculumCount: counts[d.DisciplineID]})
+		}
+		render.JSON(w, r, items)
 	}
 }
 
@@ -283,7 +482,7 @@ func (h *DisciplineHandler) GetDisciplinePublicByID(log *slog.Logger) http.Handl
 		if err != nil {
 			log.Info("invalid discipline id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid discipline id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid discipline id"))
 			return
 		}
 		discipline, err := h.repo.GetDisciplinePublicByID(r.Context(), id)
@@ -291,12 +490,11 @@ func (h *DisciplineHandler) GetDisciplinePublicByID(log *slog.Logger) http.Handl
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("discipline not found", slog.Int64("discipline_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("discipline not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "discipline not found"))
 				return
 			}
 			log.Error("failed to get discipline public", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get discipline public"))
+			renderServerError(w, r, err, "failed to get discipline public")
 			return
 		}
 		render.JSON(w, r, discipline)
@@ -310,7 +508,8 @@ func (h *DisciplineHandler) GetDisciplinePublicByID(log *slog.Logger) http.Handl
 // @Param teacher_id query int false "ID преподавателя"
 // @Param student_group_id query int false "ID группы студентов"
 // @Param academic_year_id query int false "ID учебного года"
-// @Param limit query int false "Ограничение"
+// @Param updated_since query string false "Изменённые начиная с (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
 // @Success 200 {array} models.DisciplinePublic
 // @Router /api/v1/disciplines/public [get]
@@ -324,46 +523,129 @@ func (h *DisciplineHandler) ListDisciplinePublic(log *slog.Logger) http.HandlerF
 		)
 		q := r.URL.Query()
 
-		limit, _ := strconv.Atoi(q.Get("limit"))
-		offset, _ := strconv.Atoi(q.Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+
+		teacherID, studentGroupID, academicYearID := parseDisciplineFilters(q)
+
+		disciplines, err := h.repo.ListDisciplinePublic(
+			r.Context(), limit, offset, teacherID, studentGroupID, academicYearID, parseUpdatedSince(r),
+		)
+		if err != nil {
+			log.Error("failed to list disciplines public", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list disciplines public")
+			return
 		}
+		render.JSON(w, r, disciplines)
+	}
+}
 
-		// фильтры, можно расширить
-		var (
-			teacherID      *int64
-			studentGroupID *int64
-			academicYearID *int64
+// @Summary Передать дисциплины другому преподавателю
+// @Tags disciplines
+// @Accept json
+// @Produce json
+// @Param input body models.DisciplineReassignRequest true "Текущий и новый преподаватель"
+// @Success 200 {object} models.DisciplineReassignResponse
+// @Failure 400 {object} resp.Response
+// @Failure 422 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/disciplines/reassign [post]
+// @Security BearerAuth
+func (h *DisciplineHandler) ReassignDisciplines(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.discipline_handler.ReassignDisciplines"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		if val := q.Get("teacher_id"); val != "" {
-			id, err := strconv.ParseInt(val, 10, 64)
-			if err == nil {
-				teacherID = &id
-			}
+		var req models.DisciplineReassignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
 		}
-		if val := q.Get("student_group_id"); val != "" {
-			id, err := strconv.ParseInt(val, 10, 64)
-			if err == nil {
-				studentGroupID = &id
-			}
+		if req.FromTeacherID == 0 || req.ToTeacherID == 0 || req.FromTeacherID == req.ToTeacherID {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "from_teacher_id and to_teacher_id must be different and non-empty"))
+			return
 		}
-		if val := q.Get("academic_year_id"); val != "" {
-			id, err := strconv.ParseInt(val, 10, 64)
-			if err == nil {
-				academicYearID = &id
-			}
+
+		ok, err := h.repo.TeacherExists(r.Context(), req.ToTeacherID)
+		if err != nil {
+			log.Error("failed to check teacher", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to reassign disciplines")
+			return
+		}
+		if !ok {
+			log.Info("target teacher not found", slog.Int64("teacher_id", req.ToTeacherID))
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
+			return
 		}
 
-		disciplines, err := h.repo.ListDisciplinePublic(
-			r.Context(), limit, offset, teacherID, studentGroupID, academicYearID,
+		ids, err := h.repo.ReassignTeacherDisciplines(r.Context(), req.FromTeacherID, req.ToTeacherID)
+		if err != nil {
+			log.Error("failed to reassign disciplines", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to reassign disciplines")
+			return
+		}
+
+		for _, id := range ids {
+			_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+				UserID:     utils.GetUserIDFromContext(r.Context()),
+				TableName:  "discipline",
+				RowID:      id,
+				ActionType: "UPDATE",
+				Changes: utils.PtrToJSON(map[string]utils.FieldChange{
+					"teacher_id": {Old: req.FromTeacherID, New: req.ToTeacherID},
+				}),
+				Comment: utils.PtrToStr("Discipline reassigned to another teacher"),
+			})
+		}
+
+		if ids == nil {
+			ids = []int64{}
+		}
+		render.JSON(w, r, models.DisciplineReassignResponse{Reassigned: len(ids), DisciplineIDs: ids})
+	}
+}
+
+// @Summary Поиск дисциплин по названию
+// @Tags disciplines
+// @Produce json
+// @Param q query string true "Часть названия"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.DisciplinePublic
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/disciplines/search [get]
+// @Security BearerAuth
+func (h *DisciplineHandler) SearchDisciplines(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.discipline_handler.SearchDisciplines"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
+		q := strings.TrimSpace(r.URL.Query().Get("q"))
+		if q == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "q is required"))
+			return
+		}
+
+		limit, offset := parsePagination(r)
+
+		items, err := h.repo.SearchDisciplinePublic(r.Context(), q, limit, offset)
 		if err != nil {
-			log.Error("failed to list disciplines public", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list disciplines public"))
+			log.Error("failed to search disciplines", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to search disciplines")
 			return
 		}
-		render.JSON(w, r, disciplines)
+		if items == nil {
+			items = []*models.DisciplinePublic{}
+		}
+		render.JSON(w, r, items)
 	}
 }
@@ -0,0 +1,566 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/storage"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDisciplineRepo дисциплины в памяти; teachers и groups — существующие ссылки,
+// yearDisciplines — число дисциплин преподавателя в учебном году группы
+type fakeDisciplineRepo struct {
+	DisciplineRepository
+	teachers        map[int64]bool
+	groups          map[int64]bool
+	yearDisciplines int
+	created         []*models.Discipline
+	// owners преподаватель каждой дисциплины
+	owners map[int64]int64
+	public []*models.DisciplinePublic
+	// list дисциплины приватного списка, groupYears — учебный год каждой группы
+	list       []*models.Discipline
+	groupYears map[int64]int64
+	// groupSizes число студентов в каждой группе
+	groupSizes map[int64]int64
+	// graded дисциплины, по которым есть оценки
+	graded map[int64]bool
+	// curriculum число пунктов учебного плана по дисциплинам
+	curriculum map[int64]int64
+	// writeErr ошибка БД при вставке и удалении
+	writeErr error
+}
+
+func newFakeDisciplineRepo() *fakeDisciplineRepo {
+	return &fakeDisciplineRepo{teachers: map[int64]bool{10: true}, groups: map[int64]bool{2: true}}
+}
+
+func (f *fakeDisciplineRepo) TeacherExists(_ context.Context, id int64) (bool, error) {
+	return f.teachers[id], nil
+}
+
+func (f *fakeDisciplineRepo) StudentGroupExists(_ context.Context, id int64) (bool, error) {
+	return f.groups[id], nil
+}
+
+func (f *fakeDisciplineRepo) CountTeacherDisciplinesInGroupYear(context.Context, int64, int64) (int, error) {
+	return f.yearDisciplines, nil
+}
+
+func (f *fakeDisciplineRepo) CreateDiscipline(_ context.Context, d *models.Discipline) error {
+	if f.writeErr != nil {
+		return f.writeErr
+	}
+	d.DisciplineID = int64(len(f.created) + 1)
+	f.created = append(f.created, d)
+	return nil
+}
+
+func (f *fakeDisciplineRepo) ReassignTeacherDisciplines(_ context.Context, fromTeacherID, toTeacherID int64) ([]int64, error) {
+	var ids []int64
+	for id, teacher := range f.owners {
+		if teacher == fromTeacherID {
+			f.owners[id] = toTeacherID
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (f *fakeDisciplineRepo) ListDiscipline(_ context.Context, teacherID, studentGroupID, academicYearID *int64, _ *time.Time, hasGrades *bool, limit, offset int) ([]*models.Discipline, error) {
+	var items []*models.Discipline
+	for _, d := range f.list {
+		switch {
+		case teacherID != nil && d.TeacherID != *teacherID,
+			studentGroupID != nil && d.StudentGroupID != *studentGroupID,
+			academicYearID != nil && f.groupYears[d.StudentGroupID] != *academicYearID,
+			hasGrades != nil && f.graded[d.DisciplineID] != *hasGrades:
+			continue
+		}
+		items = append(items, d)
+	}
+	if offset >= len(items) {
+		return nil, nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (f *fakeDisciplineRepo) CountDisciplineStudents(_ context.Context, id int64) (*models.DisciplineStudentCount, error) {
+	for _, d := range f.list {
+		if d.DisciplineID == id {
+			return &models.DisciplineStudentCount{DisciplineID: id, StudentGroupID: d.StudentGroupID, StudentCount: f.groupSizes[d.StudentGroupID]}, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// SearchDisciplinePublic ищет подстроку без учёта регистра, как LIKE в MySQL
+// CountCurriculumByDisciplines как GROUP BY в репозитории: дисциплин без плана в ответе нет
+func (f *fakeDisciplineRepo) CountCurriculumByDisciplines(_ context.Context, ids []int64) (map[int64]int64, error) {
+	counts := map[int64]int64{}
+	for _, id := range ids {
+		if n, ok := f.curriculum[id]; ok {
+			counts[id] = n
+		}
+	}
+	return counts, nil
+}
+
+func (f *fakeDisciplineRepo) SearchDisciplinePublic(_ context.Context, q string, limit, offset int) ([]*models.DisciplinePublic, error) {
+	var found []*models.DisciplinePublic
+	for _, d := range f.public {
+		if strings.Contains(strings.ToLower(d.DisciplineName), strings.ToLower(q)) {
+			found = append(found, d)
+		}
+	}
+	if offset >= len(found) {
+		return nil, nil
+	}
+	found = found[offset:]
+	if limit < len(found) {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+func createDiscipline(t *testing.T, h *DisciplineHandler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.CreateDiscipline(discardLogger())(rec, httptest.NewRequest(http.MethodPost, "/api/v1/disciplines", strings.NewReader(body)))
+	return rec
+}
+
+func TestCreateDiscipline_AcademicYearWarning(t *testing.T) {
+	const body = `{"discipline_name":"Физика","teacher_id":10,"student_group_id":2}`
+	tests := []struct {
+		name            string
+		check           bool
+		yearDisciplines int
+		wantWarning     bool
+	}{
+		{"teacher without disciplines in the year", true, 0, true},
+		{"teacher already teaches in the year", true, 2, false},
+		{"check disabled", false, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDisciplineRepo()
+			repo.yearDisciplines = tt.yearDisciplines
+			rec := createDiscipline(t, NewDisciplineHandler(repo, &recordingAudit{}, tt.check), body)
+			// предупреждение не блокирует создание
+			if rec.Code != http.StatusCreated || len(repo.created) != 1 {
+				t.Fatalf("status %d, created %d: %s", rec.Code, len(repo.created), rec.Body)
+			}
+			var got disciplineCreateResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if (len(got.Warnings) == 1) != tt.wantWarning {
+				t.Fatalf("warnings = %v, want warning %v", got.Warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestDiscipline_InvalidReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantMsg string
+	}{
+		{"неизвестный преподаватель", `{"discipline_name":"Физика","teacher_id":11,"student_group_id":2}`, "teacher not found"},
+		{"неизвестная группа", `{"discipline_name":"Физика","teacher_id":10,"student_group_id":3}`, "student group not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDisciplineRepo()
+			h := NewDisciplineHandler(repo, &recordingAudit{}, false)
+
+			create := createDiscipline(t, h, tt.body)
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/disciplines/1", strings.NewReader(tt.body))
+			update := httptest.NewRecorder()
+			h.UpdateDiscipline(discardLogger())(update, withURLParams(r, "id", "1"))
+
+			for op, rec := range map[string]*httptest.ResponseRecorder{"create": create, "update": update} {
+				if rec.Code != http.StatusUnprocessableEntity {
+					t.Fatalf("%s: status = %d, want 422: %s", op, rec.Code, rec.Body)
+				}
+				if !strings.Contains(rec.Body.String(), tt.wantMsg) {
+					t.Errorf("%s: body = %s, want %q", op, rec.Body, tt.wantMsg)
+				}
+			}
+			if len(repo.created) != 0 {
+				t.Errorf("discipline created with invalid reference: %+v", repo.created)
+			}
+		})
+	}
+}
+
+func (f *fakeDisciplineRepo) GetDisciplineByID(_ context.Context, id int64) (*models.Discipline, error) {
+	for _, d := range f.created {
+		if d.DisciplineID == id {
+			return d, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeDisciplineRepo) DeleteDiscipline(context.Context, int64) error {
+	return f.writeErr
+}
+
+// TestDiscipline_ForeignKeyConflicts ссылка пропала между проверкой и записью
+// или на дисциплину уже ссылаются: ошибки внешнего ключа из БД дают 409
+func TestDiscipline_ForeignKeyConflicts(t *testing.T) {
+	t.Run("insert", func(t *testing.T) {
+		repo := newFakeDisciplineRepo()
+		repo.writeErr = &storage.ForeignKeyError{Table: "student_group", Err: storage.ErrMissingReference}
+		audit := &recordingAudit{}
+
+		rec := createDiscipline(t, NewDisciplineHandler(repo, audit, false), `{"discipline_name":"Физика","teacher_id":10,"student_group_id":2}`)
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body)
+		}
+		if !strings.Contains(rec.Body.String(), "student group does not exist") {
+			t.Errorf("body = %s, want missing student group", rec.Body)
+		}
+		if len(audit.entries) != 0 {
+			t.Errorf("audit entries = %+v, want none", audit.entries)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		err     error
+		wantMsg string
+	}{
+		{"grades", &storage.ForeignKeyError{Table: "grade_journal", Err: storage.ErrReferenced}, "discipline has dependent grades"},
+		{"attendance", &storage.ForeignKeyError{Table: "attendance", Err: storage.ErrReferenced}, "discipline has dependent attendance records"},
+		{"unknown table", &storage.ForeignKeyError{Err: storage.ErrReferenced}, "discipline is referenced by other records"},
+	}
+	for _, tt := range tests {
+		t.Run("delete/"+tt.name, func(t *testing.T) {
+			repo := newFakeDisciplineRepo()
+			repo.writeErr = tt.err
+			audit := &recordingAudit{}
+
+			r := authorize(t, httptest.NewRequest(http.MethodDelete, "/api/v1/disciplines/1", nil), 1)
+			rec := httptest.NewRecorder()
+			NewDisciplineHandler(repo, audit, false).DeleteDiscipline(discardLogger())(rec, withURLParams(r, "id", "1"))
+			if rec.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantMsg) {
+				t.Errorf("body = %s, want %q", rec.Body, tt.wantMsg)
+			}
+			if len(audit.entries) != 0 {
+				t.Errorf("audit entries = %+v, want none", audit.entries)
+			}
+		})
+	}
+}
+
+func reassign(t *testing.T, h *DisciplineHandler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/disciplines/reassign", strings.NewReader(body)), 1)
+	rec := httptest.NewRecorder()
+	h.ReassignDisciplines(discardLogger())(rec, r)
+	return rec
+}
+
+func TestReassignDisciplines(t *testing.T) {
+	repo := newFakeDisciplineRepo()
+	repo.teachers[20] = true
+	repo.owners = map[int64]int64{1: 10, 2: 20, 3: 10, 4: 10}
+	audit := &recordingAudit{}
+	h := NewDisciplineHandler(repo, audit, false)
+
+	rec := reassign(t, h, `{"from_teacher_id":10,"to_teacher_id":20}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var got models.DisciplineReassignResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Reassigned != 3 || !reflect.DeepEqual(got.DisciplineIDs, []int64{1, 3, 4}) {
+		t.Fatalf("response = %+v", got)
+	}
+	for id, teacher := range repo.owners {
+		if teacher != 20 {
+			t.Errorf("discipline %d still belongs to teacher %d", id, teacher)
+		}
+	}
+
+	if len(audit.entries) != 3 {
+		t.Fatalf("audit entries = %d, want one per discipline", len(audit.entries))
+	}
+	for i, e := range audit.entries {
+		if e.TableName != "discipline" || e.RowID != got.DisciplineIDs[i] || e.UserID == nil || *e.UserID != 1 {
+			t.Errorf("audit[%d] = %+v", i, e)
+		}
+		if e.Changes == nil || !strings.Contains(*e.Changes, `"teacher_id":{"old":10,"new":20}`) {
+			t.Errorf("audit[%d] changes = %v", i, e.Changes)
+		}
+	}
+
+	// у преподавателя больше нет дисциплин: пустой список, а не null
+	rec = reassign(t, h, `{"from_teacher_id":10,"to_teacher_id":20}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"discipline_ids":[]`) {
+		t.Fatalf("repeat: status %d, body %s", rec.Code, rec.Body)
+	}
+}
+
+func TestReassignDisciplines_InvalidTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"целевой пользователь не преподаватель", `{"from_teacher_id":10,"to_teacher_id":99}`, http.StatusUnprocessableEntity},
+		{"тот же преподаватель", `{"from_teacher_id":10,"to_teacher_id":10}`, http.StatusBadRequest},
+		{"не указан целевой преподаватель", `{"from_teacher_id":10}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDisciplineRepo()
+			repo.owners = map[int64]int64{1: 10}
+			audit := &recordingAudit{}
+			rec := reassign(t, NewDisciplineHandler(repo, audit, false), tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if repo.owners[1] != 10 || len(audit.entries) != 0 {
+				t.Errorf("disciplines changed on rejected request: owners %v, audit %d", repo.owners, len(audit.entries))
+			}
+		})
+	}
+}
+
+func TestSearchDisciplines(t *testing.T) {
+	repo := newFakeDisciplineRepo()
+	repo.public = []*models.DisciplinePublic{
+		{DisciplineID: 1, DisciplineName: "Высшая математика"},
+		{DisciplineID: 2, DisciplineName: "Дискретная математика"},
+		{DisciplineID: 3, DisciplineName: "Физика"},
+	}
+	h := NewDisciplineHandler(repo, &recordingAudit{}, false)
+
+	tests := []struct {
+		query   string
+		want    int
+		wantIDs []int64
+	}{
+		{"q=математика", http.StatusOK, []int64{1, 2}},
+		{"q=%D0%A4%D0%B8%D0%B7", http.StatusOK, []int64{3}},
+		{"q=математика&limit=1&offset=1", http.StatusOK, []int64{2}},
+		{"q=химия", http.StatusOK, []int64{}},
+		{"q=", http.StatusBadRequest, nil},
+		{"q=%20%20", http.StatusBadRequest, nil},
+		{"", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.SearchDisciplines(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/disciplines/search?"+tt.query, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			// без совпадений — пустой массив, а не null
+			var items []*models.DisciplinePublic
+			if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil || items == nil {
+				t.Fatalf("body = %s, want JSON array: %v", rec.Body, err)
+			}
+			ids := []int64{}
+			for _, d := range items {
+				ids = append(ids, d.DisciplineID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// newListFixture группы 2 и 3 относятся к учебному году 1, группа 4 — к году 2
+func newListFixture() *fakeDisciplineRepo {
+	repo := newFakeDisciplineRepo()
+	repo.groupYears = map[int64]int64{2: 1, 3: 1, 4: 2}
+	repo.list = []*models.Discipline{
+		{DisciplineID: 1, DisciplineName: "Физика", TeacherID: 10, StudentGroupID: 2},
+		{DisciplineID: 2, DisciplineName: "Химия", TeacherID: 11, StudentGroupID: 3},
+		{DisciplineID: 3, DisciplineName: "Физика", TeacherID: 10, StudentGroupID: 4},
+	}
+	return repo
+}
+
+func listDisciplineIDs(t *testing.T, h *DisciplineHandler, query string) (int, []int64) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ListDiscipline(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/disciplines?"+query, nil))
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil
+	}
+	var items []models.Discipline
+	if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, d := range items {
+		ids = append(ids, d.DisciplineID)
+	}
+	return rec.Code, ids
+}
+
+func TestListDiscipline_AcademicYear(t *testing.T) {
+	h := NewDisciplineHandler(newListFixture(), &recordingAudit{}, false)
+	tests := []struct {
+		query   string
+		wantIDs []int64
+	}{
+		{"academic_year_id=1", []int64{1, 2}},
+		{"academic_year_id=2", []int64{3}},
+		{"academic_year_id=1&teacher_id=10", []int64{1}},
+		{"academic_year_id=3", nil},
+		// некорректное значение игнорируется, как остальные фильтры списка
+		{"academic_year_id=x", []int64{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			code, ids := listDisciplineIDs(t, h, tt.query)
+			if code != http.StatusOK {
+				t.Fatalf("status = %d", code)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestGetDisciplineStudentCount(t *testing.T) {
+	repo := newListFixture()
+	repo.groupSizes = map[int64]int64{2: 25}
+	h := NewDisciplineHandler(repo, &recordingAudit{}, false)
+
+	tests := []struct {
+		id   string
+		want int
+		body string
+	}{
+		{"1", http.StatusOK, `{"discipline_id":1,"student_group_id":2,"student_count":25}`},
+		// группа без студентов — ноль, а не 404
+		{"2", http.StatusOK, `{"discipline_id":2,"student_group_id":3,"student_count":0}`},
+		{"404", http.StatusNotFound, ""},
+		{"x", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/disciplines/"+tt.id+"/student-count", nil)
+			rec := httptest.NewRecorder()
+			h.GetDisciplineStudentCount(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.body != "" && strings.TrimSpace(rec.Body.String()) != tt.body {
+				t.Errorf("body = %s, want %s", rec.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestListDiscipline_HasGrades(t *testing.T) {
+	repo := newListFixture()
+	repo.graded = map[int64]bool{1: true, 3: true}
+	h := NewDisciplineHandler(repo, &recordingAudit{}, false)
+	tests := []struct {
+		query   string
+		want    int
+		wantIDs []int64
+	}{
+		{"has_grades=true", http.StatusOK, []int64{1, 3}},
+		{"has_grades=false", http.StatusOK, []int64{2}},
+		{"has_grades=1&teacher_id=10", http.StatusOK, []int64{1, 3}},
+		{"has_grades=false&teacher_id=10", http.StatusOK, nil},
+		{"", http.StatusOK, []int64{1, 2, 3}},
+		// в отличие от остальных фильтров, неверное значение — ошибка, а не «без фильтра»
+		{"has_grades=maybe", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			code, ids := listDisciplineIDs(t, h, tt.query)
+			if code != tt.want {
+				t.Fatalf("status = %d, want %d", code, tt.want)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestListDiscipline_WithCurriculumCount(t *testing.T) {
+	repo := newListFixture()
+	repo.curriculum = map[int64]int64{1: 3, 3: 1}
+	h := NewDisciplineHandler(repo, &recordingAudit{}, false)
+	list := func(query string) string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ListDiscipline(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/disciplines?"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200: %s", query, rec.Code, rec.Body)
+		}
+		return rec.Body.String()
+	}
+
+	var items []models.DisciplineWithCurriculumCount
+	if err := json.Unmarshal([]byte(list("with_curriculum_count=true")), &items); err != nil {
+		t.Fatal(err)
+	}
+	got := map[int64]int64{}
+	for _, d := range items {
+		got[d.DisciplineID] = d.CurriculumCount
+	}
+	// дисциплина без учебного плана получает 0, а не пропадает из списка
+	if want := map[int64]int64{1: 3, 2: 0, 3: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("counts = %v, want %v", got, want)
+	}
+	if len(items) != 3 || items[0].DisciplineName != "Физика" || items[1].TeacherID != 11 {
+		t.Errorf("items = %+v, want disciplines in list order", items)
+	}
+
+	// счёт считается по отфильтрованной странице
+	items = nil
+	if err := json.Unmarshal([]byte(list("with_curriculum_count=1&teacher_id=11")), &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].DisciplineID != 2 || items[0].CurriculumCount != 0 {
+		t.Errorf("filtered items = %+v", items)
+	}
+
+	// по умолчанию ответ прежний
+	for _, query := range []string{"", "with_curriculum_count=false"} {
+		if body := list(query); strings.Contains(body, "curriculum_count") {
+			t.Errorf("%q: body = %s, want plain disciplines", query, body)
+		}
+	}
+	if body := strings.TrimSpace(list("with_curriculum_count=true&teacher_id=99")); body != "[]" {
+		t.Errorf("empty page: body = %s, want []", body)
+	}
+}
@@ -5,11 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
 	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	"service/internal/http-server/middleware/permissions"
 	resp "service/internal/lib/api/response"
+	"service/internal/lib/notifier"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
 	"time"
 
@@ -21,55 +27,154 @@ import (
 type GradeJournalRepository interface {
 	CreateGradeJournal(ctx context.Context, g *models.GradeJournal) error
 	GetGradeJournalByID(ctx context.Context, id int64) (*models.GradeJournal, error)
+	GetGradeJournalsByIDs(ctx context.Context, ids []int64) ([]*models.GradeJournal, error)
 	UpdateGradeJournal(ctx context.Context, g *models.GradeJournal) error
+	PatchGradeJournal(ctx context.Context, id int64, patch *models.GradeJournalPatch) error
 	DeleteGradeJournal(ctx context.Context, id int64) error
-	ListGradeJournal(ctx context.Context, studentID, disciplineID *int64, fromDate, toDate *time.Time, limit, offset int) ([]*models.GradeJournal, error)
-	ListGradeJournalPublic(ctx context.Context, studentID, disciplineID *int64, fromDate, toDate *time.Time, limit, offset int) ([]*models.GradeJournalPublic, error)
+	DeleteGradeJournalBatch(ctx context.Context, ids []int64, teacherID *int64) ([]*models.GradeJournal, error)
+	ListGradeJournal(ctx context.Context, studentID, disciplineID *int64, studentIDs []int64, fromDate, toDate, updatedSince *time.Time, afterID *int64, limit, offset int) ([]*models.GradeJournal, error)
+	ListGradeJournalPublic(ctx context.Context, studentID, disciplineID *int64, studentIDs []int64, fromDate, toDate, updatedSince *time.Time, limit, offset int) ([]*models.GradeJournalPublic, error)
+	CountGradeJournal(ctx context.Context, studentID, disciplineID *int64, studentIDs []int64, fromDate, toDate, updatedSince *time.Time) (int64, error)
 	GetAverageGrade(ctx context.Context, studentID, disciplineID *int64, fromDate, toDate *time.Time) (float64, error)
+	ListDisciplinesBelowAverage(ctx context.Context, studentID int64, threshold float64) ([]*models.DisciplineAverage, error)
+}
+
+// DisciplineGetter нужен для проверки, что оценку ставит преподаватель дисциплины
+type DisciplineGetter interface {
+	GetDisciplineByID(ctx context.Context, id int64) (*models.Discipline, error)
 }
 
 type GradeJournalHandler struct {
-	repo      GradeJournalRepository
-	auditRepo AuditLogRepository
+	repo           GradeJournalRepository
+	studentRepo    StudentChecker
+	disciplineRepo DisciplineGetter
+	auditRepo      AuditLogRepository
+	userRepo       UserRepository
+	notifier       notifier.Notifier
+	events         EventDispatcher
+	maxBatchSize   int
+}
+
+func NewGradeJournalHandler(
+	repo GradeJournalRepository,
+	studentRepo StudentChecker,
+	disciplineRepo DisciplineGetter,
+	auditRepo AuditLogRepository,
+	userRepo UserRepository,
+	gradeNotifier notifier.Notifier,
+	events EventDispatcher,
+	maxBatchSize int,
+) *GradeJournalHandler {
+	return &GradeJournalHandler{
+		repo:           repo,
+		studentRepo:    studentRepo,
+		disciplineRepo: disciplineRepo,
+		auditRepo:      auditRepo,
+		userRepo:       userRepo,
+		notifier:       gradeNotifier,
+		events:         events,
+		maxBatchSize:   maxBatchSize,
+	}
+}
+
+// permGradeJournalManageAny позволяет выставлять оценки по чужим дисциплинам
+const permGradeJournalManageAny = "gradejournal:manage_any"
+
+// checkDisciplineOwner проверяет, что оценку по дисциплине ставит её преподаватель
+// или владелец права gradejournal:manage_any. При отказе ответ уже записан и возвращается false.
+func (h *GradeJournalHandler) checkDisciplineOwner(w http.ResponseWriter, r *http.Request, log *slog.Logger, disciplineID int64) bool {
+	if permissions.HasPermission(r, permGradeJournalManageAny) {
+		return true
+	}
+	d, err := h.disciplineRepo.GetDisciplineByID(r.Context(), disciplineID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Info("discipline not found", slog.Int64("discipline_id", disciplineID))
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			render.JSON(w, r, resp.ErrorFor(r, "discipline not found"))
+			return false
+		}
+		log.Error("failed to get discipline", slog.String("err", err.Error()))
+		renderServerError(w, r, err, "failed to get discipline")
+		return false
+	}
+	if userID, ok := ware.GetUserID(r); !ok || userID != d.TeacherID {
+		log.Info("user is not the discipline teacher", slog.Int64("discipline_id", disciplineID))
+		w.WriteHeader(http.StatusForbidden)
+		render.JSON(w, r, resp.ErrorFor(r, resp.MsgForbidden))
+		return false
+	}
+	return true
 }
 
-func NewGradeJournalHandler(repo GradeJournalRepository, auditRepo AuditLogRepository) *GradeJournalHandler {
-	return &GradeJournalHandler{repo: repo, auditRepo: auditRepo}
+// notifyGradePosted уведомляет студента о новой оценке. Ошибки только логируются.
+func (h *GradeJournalHandler) notifyGradePosted(ctx context.Context, log *slog.Logger, g *models.GradeJournal) {
+	if h.notifier == nil {
+		return
+	}
+	student, err := h.userRepo.GetClientByID(ctx, g.StudentID)
+	if err != nil {
+		log.Error("failed to get student for notification", slog.Int64("student_id", g.StudentID), slog.String("err", err.Error()))
+		return
+	}
+	err = h.notifier.Notify(ctx, notifier.Message{
+		To:      student.Email,
+		Subject: "Новая оценка",
+		Body:    fmt.Sprintf("Вам выставлена оценка %d (дисциплина #%d).", g.Grade, g.DisciplineID),
+	})
+	if err != nil {
+		log.Error("failed to enqueue grade notification", slog.Int64("student_id", g.StudentID), slog.String("err", err.Error()))
+	}
 }
 
 // @Summary Добавить запись в журнал оценок
+// @Description Доступно преподавателю дисциплины или пользователю с правом gradejournal:manage_any
 // @Tags gradejournals
 // @Accept json
 // @Produce json
-// @Param input body models.GradeJournal true "Запись"
+// @Param input body models.CreateGradeJournalRequest true "Запись"
 // @Success 201 {object} models.GradeJournal
+// @Failure 403 {object} resp.Response
+// @Failure 422 {object} resp.Response
 // @Router /api/v1/gradejournals [post]
 // @Security BearerAuth
 func (h *GradeJournalHandler) CreateGradeJournal(log *slog.Logger) http.HandlerFunc {
 	const op = "handler.v1.gradejournal_handler.CreateGradeJournal"
 	return func(w http.ResponseWriter, r *http.Request) {
 		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
-		var g models.GradeJournal
-		if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+		var req models.CreateGradeJournalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		g := models.GradeJournal{
+			StudentID:    req.StudentID,
+			DisciplineID: req.DisciplineID,
+			Grade:        req.Grade,
+			Comment:      req.Comment,
+		}
+		if !h.checkDisciplineOwner(w, r, log, g.DisciplineID) {
 			return
 		}
 		if err := h.repo.CreateGradeJournal(r.Context(), &g); err != nil {
 			log.Error("failed to create gradejournal", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create gradejournal"))
+			renderServerError(w, r, err, "failed to create gradejournal")
 			return
 		}
-		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+		h.addAudit(r.Context(), log, &models.AuditLog{
 			UserID:     utils.GetUserIDFromContext(r.Context()),
 			TableName:  "grade_journal",
-			RowID:      g.GradeJournalID,
-			ActionType: "CREATE",
+			RowID:      int64(g.GradeJournalID),
+			StudentID:  &g.StudentID,
+			ActionType: "INSERT",
 			NewData:    utils.PtrToJSON(g),
 			Comment:    utils.PtrToStr("Grade_Journal created"),
 		})
+		h.notifyGradePosted(r.Context(), log, &g)
+		h.events.Dispatch(r.Context(), "gradejournal.created", g)
+		setLocation(w, "gradejournals", int64(g.GradeJournalID))
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, g)
 	}
@@ -92,7 +197,7 @@ func (h *GradeJournalHandler) GetGradeJournalByID(log *slog.Logger) http.Handler
 		if err != nil {
 			log.Info("invalid gradejournal id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid gradejournal id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid gradejournal id"))
 			return
 		}
 		g, err := h.repo.GetGradeJournalByID(r.Context(), id)
@@ -100,12 +205,11 @@ func (h *GradeJournalHandler) GetGradeJournalByID(log *slog.Logger) http.Handler
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("gradejournal not found", slog.Int64("gradejournal_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("gradejournal not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "gradejournal not found"))
 				return
 			}
 			log.Error("failed to get gradejournal", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get gradejournal"))
+			renderServerError(w, r, err, "failed to get gradejournal")
 			return
 		}
 		render.JSON(w, r, g)
@@ -113,12 +217,16 @@ func (h *GradeJournalHandler) GetGradeJournalByID(log *slog.Logger) http.Handler
 }
 
 // @Summary Обновить запись в журнале
+// @Description Полная замена: непереданный comment очищается, для частичного обновления — PATCH.
+// @Description Доступно преподавателю дисциплины или пользователю с правом gradejournal:manage_any
 // @Tags gradejournals
 // @Accept json
 // @Produce json
 // @Param id path int true "ID записи"
 // @Param input body models.GradeJournal true "Запись"
 // @Success 200 {object} models.GradeJournal
+// @Failure 403 {object} resp.Response
+// @Failure 404 {object} resp.Response
 // @Router /api/v1/gradejournals/{id} [put]
 // @Security BearerAuth
 func (h *GradeJournalHandler) UpdateGradeJournal(log *slog.Logger) http.HandlerFunc {
@@ -130,50 +238,149 @@ func (h *GradeJournalHandler) UpdateGradeJournal(log *slog.Logger) http.HandlerF
 		if err != nil {
 			log.Info("invalid gradejournal id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid gradejournal id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid gradejournal id"))
 			return
 		}
 		var g models.GradeJournal
 		if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		g.GradeJournalID = models.ID(id)
+		oldData, err := h.repo.GetGradeJournalByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("gradejournal not found for update", slog.Int64("gradejournal_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "gradejournal not found"))
+				return
+			}
+			log.Error("failed to get gradejournal", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update gradejournal")
+			return
+		}
+		if !h.checkDisciplineOwner(w, r, log, oldData.DisciplineID) {
+			return
+		}
+		if g.DisciplineID != oldData.DisciplineID && !h.checkDisciplineOwner(w, r, log, g.DisciplineID) {
 			return
 		}
-		g.GradeJournalID = id
-		oldData, _ := h.repo.GetGradeJournalByID(r.Context(), id)
 		if err := h.repo.UpdateGradeJournal(r.Context(), &g); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("gradejournal not found for update", slog.Int64("gradejournal_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("gradejournal not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "gradejournal not found"))
 				return
 			}
 			log.Error("failed to update gradejournal", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update gradejournal"))
+			renderServerError(w, r, err, "failed to update gradejournal")
 			return
 		}
-		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+		h.addAudit(r.Context(), log, &models.AuditLog{
 			UserID:     utils.GetUserIDFromContext(r.Context()),
 			TableName:  "grade_journal",
 			RowID:      id,
+			StudentID:  &g.StudentID,
 			ActionType: "UPDATE",
 			NewData:    utils.PtrToJSON(g),
 			OldData:    utils.PtrToJSON(oldData),
+			Changes:    utils.PtrToJSONDiff(oldData, g),
 			Comment:    utils.PtrToStr("Grade_Journal created"),
 		})
+		h.events.Dispatch(r.Context(), "gradejournal.updated", g)
 		w.WriteHeader(http.StatusOK)
 		render.JSON(w, r, g)
 	}
 }
 
+// @Summary Частично обновить запись в журнале
+// @Description Обновляются только переданные поля, "comment": null очищает комментарий.
+// @Description Доступно преподавателю дисциплины или пользователю с правом gradejournal:manage_any
+// @Tags gradejournals
+// @Accept json
+// @Produce json
+// @Param id path int true "ID записи"
+// @Param input body models.GradeJournalPatch true "Изменяемые поля"
+// @Success 200 {object} models.GradeJournal
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/gradejournals/{id} [patch]
+// @Security BearerAuth
+func (h *GradeJournalHandler) PatchGradeJournal(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.gradejournal_handler.PatchGradeJournal"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid gradejournal id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid gradejournal id"))
+			return
+		}
+		var patch models.GradeJournalPatch
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+
+		oldData, err := h.repo.GetGradeJournalByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("gradejournal not found for patch", slog.Int64("gradejournal_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "gradejournal not found"))
+				return
+			}
+			log.Error("failed to get gradejournal", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update gradejournal")
+			return
+		}
+		if !h.checkDisciplineOwner(w, r, log, oldData.DisciplineID) {
+			return
+		}
+		if err := h.repo.PatchGradeJournal(r.Context(), id, &patch); err != nil {
+			log.Error("failed to patch gradejournal", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update gradejournal")
+			return
+		}
+		g, err := h.repo.GetGradeJournalByID(r.Context(), id)
+		if err != nil {
+			log.Error("failed to get gradejournal", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update gradejournal")
+			return
+		}
+		h.addAudit(r.Context(), log, &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "grade_journal",
+			RowID:      id,
+			StudentID:  &g.StudentID,
+			ActionType: "UPDATE",
+			NewData:    utils.PtrToJSON(g),
+			OldData:    utils.PtrToJSON(oldData),
+			Changes:    utils.PtrToJSONDiff(oldData, g),
+			Comment:    utils.PtrToStr("Grade_Journal patched"),
+		})
+		h.events.Dispatch(r.Context(), "gradejournal.updated", g)
+		render.JSON(w, r, g)
+	}
+}
+
 // @Summary Удалить запись из журнала
+// @Description Доступно преподавателю дисциплины или пользователю с правом gradejournal:manage_any
 // @Tags gradejournals
 // @Accept json
 // @Produce json
 // @Param id path int true "ID записи"
 // @Success 204 {string} string "No Content"
+// @Failure 403 {object} resp.Response
+// @Failure 404 {object} resp.Response
 // @Router /api/v1/gradejournals/{id} [delete]
 // @Security BearerAuth
 func (h *GradeJournalHandler) DeleteGradeJournal(log *slog.Logger) http.HandlerFunc {
@@ -185,51 +392,158 @@ func (h *GradeJournalHandler) DeleteGradeJournal(log *slog.Logger) http.HandlerF
 		if err != nil {
 			log.Info("invalid gradejournal id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid gradejournal id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid gradejournal id"))
+			return
+		}
+		oldData, err := h.repo.GetGradeJournalByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("gradejournal not found for delete", slog.Int64("gradejournal_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "gradejournal not found"))
+				return
+			}
+			log.Error("failed to get gradejournal", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to delete gradejournal")
+			return
+		}
+		if !h.checkDisciplineOwner(w, r, log, oldData.DisciplineID) {
 			return
 		}
-		oldData, _ := h.repo.GetGradeJournalByID(r.Context(), id)
 		if err := h.repo.DeleteGradeJournal(r.Context(), id); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("gradejournal not found for delete", slog.Int64("gradejournal_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("gradejournal not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "gradejournal not found"))
 				return
 			}
 			log.Error("failed to delete gradejournal", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete gradejournal"))
+			renderServerError(w, r, err, "failed to delete gradejournal")
 			return
 		}
-		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+		h.addAudit(r.Context(), log, &models.AuditLog{
 			UserID:     utils.GetUserIDFromContext(r.Context()),
 			TableName:  "grade_journal",
 			RowID:      id,
+			StudentID:  &oldData.StudentID,
 			ActionType: "DELETE",
 			OldData:    utils.PtrToJSON(oldData),
 			Comment:    utils.PtrToStr("Grade_Journal deleted"),
 		})
+		h.events.Dispatch(r.Context(), "gradejournal.deleted", map[string]int64{"grade_journal_id": id})
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
 
-// @Summary Получить список оценок с фильтрацией
+// @Summary Удалить записи журнала пакетом
+// @Description Удаляет записи одной транзакцией и пишет в аудит одну сводную запись. Отсутствующие id пропускаются.
+// @Description Без права gradejournal:manage_any все записи должны относиться к дисциплинам текущего преподавателя.
 // @Tags gradejournals
 // @Accept json
 // @Produce json
+// @Param input body models.GradeJournalBulkDeleteRequest true "ID записей (не более max_batch_size, по умолчанию 1000)"
+// @Success 200 {object} models.GradeJournalBulkDeleteResponse
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 413 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/gradejournals/bulk-delete [post]
+// @Security BearerAuth
+func (h *GradeJournalHandler) BulkDeleteGradeJournals(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.gradejournal_handler.BulkDeleteGradeJournals"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		var req models.GradeJournalBulkDeleteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		if len(req.IDs) == 0 {
+			log.Info("empty ids")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "ids must not be empty"))
+			return
+		}
+		if !checkBatchSize(w, r, log, len(req.IDs), h.maxBatchSize) {
+			return
+		}
+		// без gradejournal:manage_any удалять можно только оценки своих дисциплин;
+		// владелец проверяется в транзакции удаления
+		var teacherID *int64
+		if !permissions.HasPermission(r, permGradeJournalManageAny) {
+			userID, ok := ware.GetUserID(r)
+			if !ok {
+				log.Info("no user id in token")
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, resp.ErrorFor(r, resp.MsgForbidden))
+				return
+			}
+			teacherID = &userID
+		}
+
+		deleted, err := h.repo.DeleteGradeJournalBatch(r.Context(), req.IDs, teacherID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotOwner) {
+				log.Info("user is not the teacher of all disciplines in the batch")
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, resp.ErrorFor(r, resp.MsgForbidden))
+				return
+			}
+			log.Error("failed to delete gradejournals", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to delete gradejournals")
+			return
+		}
+		ids := make([]int64, len(deleted))
+		for i, g := range deleted {
+			ids[i] = int64(g.GradeJournalID)
+		}
+		if len(deleted) > 0 {
+			// сводная запись относится к нескольким студентам, поэтому student_id не заполняется
+			h.addAudit(r.Context(), log, &models.AuditLog{
+				UserID:     utils.GetUserIDFromContext(r.Context()),
+				TableName:  "grade_journal",
+				ActionType: "DELETE",
+				OldData:    utils.PtrToJSON(deleted),
+				Comment:    utils.PtrToStr(fmt.Sprintf("Grade_Journal bulk deleted: %d records", len(deleted))),
+			})
+			h.events.Dispatch(r.Context(), "gradejournal.bulk_deleted", map[string][]int64{"grade_journal_ids": ids})
+		}
+		render.JSON(w, r, models.GradeJournalBulkDeleteResponse{Deleted: len(ids), IDs: ids})
+	}
+}
+
+// @Summary Получить список оценок с фильтрацией
+// @Tags gradejournals
+// @Accept json
+// @Produce json,text/csv
+// @Param ids query string false "ID записей через запятую (1,2,3), остальные фильтры игнорируются; порядок сохраняется, отсутствующие пропускаются"
 // @Param student_id query int false "ID студента"
+// @Param student_ids query string false "ID студентов через запятую (1,2,3)"
 // @Param discipline_id query int false "ID дисциплины"
 // @Param from_date query string false "С даты (YYYY-MM-DD)"
 // @Param to_date query string false "По дату (YYYY-MM-DD)"
-// @Param limit query int false "Ограничение"
-// @Param offset query int false "Смещение"
+// @Param updated_since query string false "Изменённые начиная с (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение (игнорируется при after_id)"
+// @Param after_id query int false "Keyset-пагинация: записи с id больше after_id, для выгрузок и глубоких страниц"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.GradeJournal
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
+// @Header 200 {integer} X-Next-After-Id "after_id следующей страницы в keyset-режиме"
+// @Failure 400 {object} resp.Response
+// @Failure 413 {object} resp.Response
 // @Router /api/v1/gradejournals [get]
 // @Security BearerAuth
 func (h *GradeJournalHandler) ListGradeJournal(log *slog.Logger) http.HandlerFunc {
 	const op = "handler.v1.gradejournal_handler.ListGradeJournal"
 	return func(w http.ResponseWriter, r *http.Request) {
 		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		if val := r.URL.Query().Get("ids"); val != "" {
+			h.listGradeJournalsByIDs(w, r, log, val)
+			return
+		}
 		var studentID, disciplineID *int64
 		var fromDate, toDate *time.Time
 
@@ -240,6 +554,17 @@ func (h *GradeJournalHandler) ListGradeJournal(log *slog.Logger) http.HandlerFun
 				studentID = &id
 			}
 		}
+		var studentIDs []int64
+		if val := r.URL.Query().Get("student_ids"); val != "" {
+			ids, err := parseIDList(val)
+			if err != nil {
+				log.Info("invalid student_ids", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid student_ids"))
+				return
+			}
+			studentIDs = ids
+		}
 		disciplineIDStr := r.URL.Query().Get("discipline_id")
 		if disciplineIDStr != "" {
 			id, err := strconv.ParseInt(disciplineIDStr, 10, 64)
@@ -261,34 +586,92 @@ func (h *GradeJournalHandler) ListGradeJournal(log *slog.Logger) http.HandlerFun
 				toDate = &d
 			}
 		}
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+		afterID, ok := parseAfterID(r)
+		if !ok {
+			log.Info("invalid after_id", slog.String("after_id", r.URL.Query().Get("after_id")))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid after_id"))
+			return
 		}
 
-		items, err := h.repo.ListGradeJournal(r.Context(), studentID, disciplineID, fromDate, toDate, limit, offset)
+		if err := setTotalCount(w, r, func() (int64, error) {
+			return h.repo.CountGradeJournal(r.Context(), studentID, disciplineID, studentIDs, fromDate, toDate, parseUpdatedSince(r))
+		}); err != nil {
+			log.Error("failed to count gradejournals", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list gradejournals")
+			return
+		}
+		items, err := h.repo.ListGradeJournal(r.Context(), studentID, disciplineID, studentIDs, fromDate, toDate, parseUpdatedSince(r), afterID, limit, offset)
 		if err != nil {
 			log.Error("failed to list gradejournals", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list gradejournals"))
+			renderServerError(w, r, err, "failed to list gradejournals")
+			return
+		}
+		if afterID != nil && len(items) > 0 {
+			setNextAfterID(w, len(items), limit, int64(items[len(items)-1].GradeJournalID))
+		}
+		if wantsCSV(r) {
+			rows := make([][]string, 0, len(items))
+			for _, g := range items {
+				rows = append(rows, []string{
+					strconv.FormatInt(int64(g.GradeJournalID), 10),
+					csvTime(g.CreatedAt),
+					csvTime(g.UpdatedAt),
+					strconv.FormatInt(g.StudentID, 10),
+					strconv.FormatInt(g.DisciplineID, 10),
+					strconv.Itoa(int(g.Grade)),
+					csvString(g.Comment),
+				})
+			}
+			header := []string{"grade_journal_id", "created_at", "updated_at", "student_id", "discipline_id", "grade", "comment"}
+			if err := renderCSV(w, header, rows); err != nil {
+				log.Error("failed to write csv", slog.String("err", err.Error()))
+			}
 			return
 		}
 		render.JSON(w, r, items)
 	}
 }
 
+func (h *GradeJournalHandler) listGradeJournalsByIDs(w http.ResponseWriter, r *http.Request, log *slog.Logger, val string) {
+	ids, err := parseIDList(val)
+	if err != nil {
+		log.Info("invalid ids", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ErrorFor(r, "invalid ids"))
+		return
+	}
+	if !checkBatchSize(w, r, log, len(ids), h.maxBatchSize) {
+		return
+	}
+	items, err := h.repo.GetGradeJournalsByIDs(r.Context(), ids)
+	if err != nil {
+		log.Error("failed to get gradejournals by ids", slog.String("err", err.Error()))
+		renderServerError(w, r, err, "failed to list gradejournals")
+		return
+	}
+	if items == nil {
+		items = []*models.GradeJournal{}
+	}
+	render.JSON(w, r, items)
+}
+
 // @Summary Получить список публичных оценок
 // @Tags gradejournals
 // @Accept json
-// @Produce json
+// @Produce json,text/csv
 // @Param student_id query int false "ID студента"
+// @Param student_ids query string false "ID студентов через запятую (1,2,3)"
 // @Param discipline_id query int false "ID дисциплины"
 // @Param from_date query string false "С даты (YYYY-MM-DD)"
 // @Param to_date query string false "По дату (YYYY-MM-DD)"
-// @Param limit query int false "Ограничение"
+// @Param updated_since query string false "Изменённые начиная с (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.GradeJournalPublic
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/gradejournals/public [get]
 // @Security BearerAuth
 func (h *GradeJournalHandler) ListGradeJournalPublic(log *slog.Logger) http.HandlerFunc {
@@ -305,6 +688,17 @@ func (h *GradeJournalHandler) ListGradeJournalPublic(log *slog.Logger) http.Hand
 				studentID = &id
 			}
 		}
+		var studentIDs []int64
+		if val := r.URL.Query().Get("student_ids"); val != "" {
+			ids, err := parseIDList(val)
+			if err != nil {
+				log.Info("invalid student_ids", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid student_ids"))
+				return
+			}
+			studentIDs = ids
+		}
 		disciplineIDStr := r.URL.Query().Get("discipline_id")
 		if disciplineIDStr != "" {
 			id, err := strconv.ParseInt(disciplineIDStr, 10, 64)
@@ -326,17 +720,41 @@ func (h *GradeJournalHandler) ListGradeJournalPublic(log *slog.Logger) http.Hand
 				toDate = &d
 			}
 		}
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
-		}
+		limit, offset := parsePagination(r)
 
-		items, err := h.repo.ListGradeJournalPublic(r.Context(), studentID, disciplineID, fromDate, toDate, limit, offset)
+		if err := setTotalCount(w, r, func() (int64, error) {
+			return h.repo.CountGradeJournal(r.Context(), studentID, disciplineID, studentIDs, fromDate, toDate, parseUpdatedSince(r))
+		}); err != nil {
+			log.Error("failed to count gradejournals", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list gradejournals")
+			return
+		}
+		items, err := h.repo.ListGradeJournalPublic(r.Context(), studentID, disciplineID, studentIDs, fromDate, toDate, parseUpdatedSince(r), limit, offset)
 		if err != nil {
 			log.Error("failed to list gradejournals public", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list gradejournals public"))
+			renderServerError(w, r, err, "failed to list gradejournals public")
+			return
+		}
+		if wantsCSV(r) {
+			rows := make([][]string, 0, len(items))
+			for _, g := range items {
+				rows = append(rows, []string{
+					strconv.FormatInt(int64(g.GradeJournalID), 10),
+					csvTime(g.CreatedAt),
+					csvTime(g.UpdatedAt),
+					strconv.FormatInt(g.StudentID, 10),
+					g.FirstName,
+					g.LastName,
+					strconv.FormatInt(g.DisciplineID, 10),
+					g.DisciplineName,
+					strconv.Itoa(int(g.Grade)),
+					csvString(g.Comment),
+				})
+			}
+			header := []string{"grade_journal_id", "created_at", "updated_at", "student_id", "first_name", "last_name", "discipline_id", "discipline_name", "grade", "comment"}
+			if err := renderCSV(w, header, rows); err != nil {
+				log.Error("failed to write csv", slog.String("err", err.Error()))
+			}
 			return
 		}
 		render.JSON(w, r, items)
@@ -393,10 +811,138 @@ func (h *GradeJournalHandler) GetAverageGrade(log *slog.Logger) http.HandlerFunc
 		avg, err := h.repo.GetAverageGrade(r.Context(), studentID, disciplineID, fromDate, toDate)
 		if err != nil {
 			log.Error("failed to get average grade", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get average grade"))
+			renderServerError(w, r, err, "failed to get average grade")
 			return
 		}
 		render.JSON(w, r, map[string]float64{"average_grade": avg})
 	}
 }
+
+// @Summary Оценки студента
+// @Tags gradejournals
+// @Produce json
+// @Param id path int true "ID студента"
+// @Param discipline_id query int false "ID дисциплины"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.GradeJournal
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/students/{id}/grades [get]
+// @Security BearerAuth
+func (h *GradeJournalHandler) ListStudentGrades(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.gradejournal_handler.ListStudentGrades"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		studentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
+			return
+		}
+
+		exists, err := h.studentRepo.StudentExists(r.Context(), studentID)
+		if err != nil {
+			log.Error("failed to check student", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list grades")
+			return
+		}
+		if !exists {
+			log.Info("student not found", slog.Int64("student_id", studentID))
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.ErrorFor(r, "student not found"))
+			return
+		}
+
+		var disciplineID *int64
+		if val := r.URL.Query().Get("discipline_id"); val != "" {
+			id, err := strconv.ParseInt(val, 10, 64)
+			if err == nil {
+				disciplineID = &id
+			}
+		}
+		limit, offset := parsePagination(r)
+
+		items, err := h.repo.ListGradeJournal(r.Context(), &studentID, disciplineID, nil, nil, nil, nil, nil, limit, offset)
+		if err != nil {
+			log.Error("failed to list grades", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list grades")
+			return
+		}
+		if items == nil {
+			items = []*models.GradeJournal{}
+		}
+		render.JSON(w, r, items)
+	}
+}
+
+// defaultAtRiskThreshold порог среднего балла по умолчанию для /students/{id}/at-risk
+const defaultAtRiskThreshold = 3.0
+
+// @Summary Дисциплины, где студент в зоне риска
+// @Description Дисциплины, в которых средний балл студента строго ниже порога
+// @Tags gradejournals
+// @Produce json
+// @Param id path int true "ID студента"
+// @Param threshold query number false "Порог среднего балла (по умолчанию 3)"
+// @Success 200 {array} models.DisciplineAverage
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/students/{id}/at-risk [get]
+// @Security BearerAuth
+func (h *GradeJournalHandler) ListStudentAtRisk(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.gradejournal_handler.ListStudentAtRisk"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		studentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
+			return
+		}
+		threshold := defaultAtRiskThreshold
+		if val := r.URL.Query().Get("threshold"); val != "" {
+			threshold, err = strconv.ParseFloat(val, 64)
+			if err != nil || math.IsNaN(threshold) || math.IsInf(threshold, 0) || threshold <= 0 {
+				log.Info("invalid threshold", slog.String("threshold", val))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid threshold"))
+				return
+			}
+		}
+
+		exists, err := h.studentRepo.StudentExists(r.Context(), studentID)
+		if err != nil {
+			log.Error("failed to check student", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list at-risk disciplines")
+			return
+		}
+		if !exists {
+			log.Info("student not found", slog.Int64("student_id", studentID))
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.ErrorFor(r, "student not found"))
+			return
+		}
+
+		items, err := h.repo.ListDisciplinesBelowAverage(r.Context(), studentID, threshold)
+		if err != nil {
+			log.Error("failed to list at-risk disciplines", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list at-risk disciplines")
+			return
+		}
+		if items == nil {
+			items = []*models.DisciplineAverage{}
+		}
+		render.JSON(w, r, items)
+	}
+}
+
+// addAudit пишет запись аудита; ошибка не прерывает запрос, но логируется,
+// иначе отклонённая БД запись пропала бы из истории оценок незаметно
+func (h *GradeJournalHandler) addAudit(ctx context.Context, log *slog.Logger, entry *models.AuditLog) {
+	if err := h.auditRepo.AddAuditLog(ctx, entry); err != nil {
+		log.Error("failed to write audit log", slog.String("err", err.Error()))
+	}
+}
@@ -0,0 +1,710 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/lib/notifier"
+	"service/internal/storage"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memGradeRepo журнал оценок в памяти
+type memGradeRepo struct {
+	GradeJournalRepository
+	grades  map[int64]*models.GradeJournal
+	deleted []int64
+	// teachers владельцы дисциплин на момент удаления пакета
+	teachers disciplineTeachers
+}
+
+func newMemGradeRepo(grades ...*models.GradeJournal) *memGradeRepo {
+	repo := &memGradeRepo{grades: map[int64]*models.GradeJournal{}}
+	for _, g := range grades {
+		repo.grades[int64(g.GradeJournalID)] = g
+	}
+	return repo
+}
+
+func (m *memGradeRepo) GetGradeJournalByID(_ context.Context, id int64) (*models.GradeJournal, error) {
+	g, ok := m.grades[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return g, nil
+}
+
+// CreateGradeJournal выдаёт следующий id и серверные временные метки, как репозиторий
+func (m *memGradeRepo) CreateGradeJournal(_ context.Context, g *models.GradeJournal) error {
+	g.GradeJournalID = models.ID(len(m.grades) + 1)
+	g.CreatedAt = time.Now().UTC()
+	g.UpdatedAt = g.CreatedAt
+	cp := *g
+	m.grades[int64(g.GradeJournalID)] = &cp
+	return nil
+}
+
+func (m *memGradeRepo) GetGradeJournalsByIDs(_ context.Context, ids []int64) ([]*models.GradeJournal, error) {
+	var result []*models.GradeJournal
+	for _, id := range ids {
+		if g, ok := m.grades[id]; ok {
+			result = append(result, g)
+		}
+	}
+	return result, nil
+}
+
+func (m *memGradeRepo) DeleteGradeJournal(_ context.Context, id int64) error {
+	if _, ok := m.grades[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.grades, id)
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *memGradeRepo) DeleteGradeJournalBatch(ctx context.Context, ids []int64, teacherID *int64) ([]*models.GradeJournal, error) {
+	grades, _ := m.GetGradeJournalsByIDs(ctx, ids)
+	for _, g := range grades {
+		if teacherID != nil && m.teachers[g.DisciplineID] != *teacherID {
+			return nil, storage.ErrNotOwner
+		}
+	}
+	for _, g := range grades {
+		_ = m.DeleteGradeJournal(ctx, int64(g.GradeJournalID))
+	}
+	return grades, nil
+}
+
+// filtered оценки под фильтром по возрастанию id
+func (m *memGradeRepo) filtered(studentID, disciplineID *int64, studentIDs []int64) []*models.GradeJournal {
+	var result []*models.GradeJournal
+	for _, g := range m.grades {
+		if studentID != nil && g.StudentID != *studentID || disciplineID != nil && g.DisciplineID != *disciplineID {
+			continue
+		}
+		if len(studentIDs) > 0 && !slices.Contains(studentIDs, g.StudentID) {
+			continue
+		}
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].GradeJournalID < result[j].GradeJournalID })
+	return result
+}
+
+func (m *memGradeRepo) ListGradeJournal(_ context.Context, studentID, disciplineID *int64, studentIDs []int64, _, _, _ *time.Time, afterID *int64, limit, offset int) ([]*models.GradeJournal, error) {
+	items := m.filtered(studentID, disciplineID, studentIDs)
+	if afterID != nil {
+		offset = sort.Search(len(items), func(i int) bool { return int64(items[i].GradeJournalID) > *afterID })
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (m *memGradeRepo) CountGradeJournal(_ context.Context, studentID, disciplineID *int64, studentIDs []int64, _, _, _ *time.Time) (int64, error) {
+	return int64(len(m.filtered(studentID, disciplineID, studentIDs))), nil
+}
+
+// ListDisciplinesBelowAverage средний балл по дисциплинам студента строго ниже threshold
+func (m *memGradeRepo) ListDisciplinesBelowAverage(_ context.Context, studentID int64, threshold float64) ([]*models.DisciplineAverage, error) {
+	byDiscipline := map[int64]*models.DisciplineAverage{}
+	sums := map[int64]float64{}
+	for _, g := range m.grades {
+		if g.StudentID != studentID {
+			continue
+		}
+		a, ok := byDiscipline[g.DisciplineID]
+		if !ok {
+			a = &models.DisciplineAverage{DisciplineID: g.DisciplineID}
+			byDiscipline[g.DisciplineID] = a
+		}
+		a.GradesCount++
+		sums[g.DisciplineID] += float64(g.Grade)
+	}
+	var items []*models.DisciplineAverage
+	for id, a := range byDiscipline {
+		a.AverageGrade = sums[id] / float64(a.GradesCount)
+		if a.AverageGrade < threshold {
+			items = append(items, a)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].DisciplineID < items[j].DisciplineID })
+	return items, nil
+}
+
+// disciplineTeachers дисциплины с их преподавателями
+type disciplineTeachers map[int64]int64
+
+func (d disciplineTeachers) GetDisciplineByID(_ context.Context, id int64) (*models.Discipline, error) {
+	teacherID, ok := d[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &models.Discipline{DisciplineID: id, TeacherID: teacherID}, nil
+}
+
+// дисциплина 3 принадлежит преподавателю 10, дисциплина 4 — преподавателю 20
+func newOwnershipFixture() (*memGradeRepo, *GradeJournalHandler, *recordingAudit) {
+	repo := newMemGradeRepo(
+		&models.GradeJournal{GradeJournalID: 1, StudentID: 7, Grade: 5, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 2, StudentID: 8, Grade: 4, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 3, StudentID: 7, Grade: 3, DisciplineID: 4},
+	)
+	repo.teachers = disciplineTeachers{3: 10, 4: 20}
+	audit := &recordingAudit{}
+	h := NewGradeJournalHandler(repo, nil, repo.teachers, audit, nil, nil, noopEvents{}, 100)
+	return repo, h, audit
+}
+
+func TestDeleteGradeJournal_Ownership(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		perms  []string
+		want   int
+	}{
+		{"discipline teacher", 10, nil, http.StatusNoContent},
+		{"other teacher", 20, nil, http.StatusForbidden},
+		{"manage_any", 99, []string{permGradeJournalManageAny}, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, h, audit := newOwnershipFixture()
+			r := authorize(t, httptest.NewRequest(http.MethodDelete, "/api/v1/gradejournals/1", nil), tt.userID, tt.perms...)
+			rec := httptest.NewRecorder()
+			h.DeleteGradeJournal(discardLogger())(rec, withURLParams(r, "id", "1"))
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			deleted := len(repo.deleted) == 1
+			if deleted != (tt.want == http.StatusNoContent) {
+				t.Fatalf("deleted = %v with status %d", deleted, rec.Code)
+			}
+			if deleted && (len(audit.entries) != 1 || *audit.entries[0].StudentID != 7) {
+				t.Fatalf("audit entries %+v", audit.entries)
+			}
+		})
+	}
+}
+
+func TestDeleteGradeJournal_NotFound(t *testing.T) {
+	_, h, _ := newOwnershipFixture()
+	r := authorize(t, httptest.NewRequest(http.MethodDelete, "/api/v1/gradejournals/404", nil), 10)
+	rec := httptest.NewRecorder()
+	h.DeleteGradeJournal(discardLogger())(rec, withURLParams(r, "id", "404"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status %d, want 404", rec.Code)
+	}
+}
+
+func TestBulkDeleteGradeJournals_Ownership(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		perms  []string
+		ids    string
+		want   int
+	}{
+		{"own disciplines only", 10, nil, `[1,2]`, http.StatusOK},
+		{"mixed with other teacher's grade", 10, nil, `[1,3]`, http.StatusForbidden},
+		{"other teacher", 20, nil, `[1]`, http.StatusForbidden},
+		{"manage_any", 99, []string{permGradeJournalManageAny}, `[1,2,3]`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, h, _ := newOwnershipFixture()
+			body := strings.NewReader(`{"ids":` + tt.ids + `}`)
+			r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/gradejournals/bulk-delete", body), tt.userID, tt.perms...)
+			rec := httptest.NewRecorder()
+			h.BulkDeleteGradeJournals(discardLogger())(rec, r)
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusForbidden && len(repo.deleted) != 0 {
+				t.Fatalf("grades deleted despite 403: %v", repo.deleted)
+			}
+		})
+	}
+}
+
+// владелец сверяется с дисциплинами на момент удаления, а не с ранее прочитанными
+func TestBulkDeleteGradeJournals_OwnerCheckedOnDelete(t *testing.T) {
+	repo, _, audit := newOwnershipFixture()
+	// дисциплину 4 передали преподавателю 10 уже после того, как обработчик мог её прочитать
+	stale := disciplineTeachers{3: 10, 4: 10}
+	h := NewGradeJournalHandler(repo, nil, stale, audit, nil, nil, noopEvents{}, 100)
+	r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/gradejournals/bulk-delete", strings.NewReader(`{"ids":[1,3]}`)), 10)
+	rec := httptest.NewRecorder()
+	h.BulkDeleteGradeJournals(discardLogger())(rec, r)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403: %s", rec.Code, rec.Body)
+	}
+	if len(repo.deleted) != 0 || len(audit.entries) != 0 {
+		t.Errorf("deleted = %v, audit = %d; want nothing", repo.deleted, len(audit.entries))
+	}
+}
+
+func TestBulkDeleteGradeJournals(t *testing.T) {
+	repo, h, audit := newOwnershipFixture()
+	// 404 нет в журнале и просто пропускается
+	r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/gradejournals/bulk-delete", strings.NewReader(`{"ids":[3,404,1]}`)), 99, permGradeJournalManageAny)
+	rec := httptest.NewRecorder()
+	h.BulkDeleteGradeJournals(discardLogger())(rec, r)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got models.GradeJournalBulkDeleteResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (models.GradeJournalBulkDeleteResponse{Deleted: 2, IDs: []int64{3, 1}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("response = %+v, want %+v", got, want)
+	}
+	if _, ok := repo.grades[2]; !ok || len(repo.grades) != 1 {
+		t.Errorf("remaining grades = %v, want only 2", repo.grades)
+	}
+
+	// одна сводная запись аудита на весь пакет со снимками удалённых оценок
+	if len(audit.entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(audit.entries))
+	}
+	entry := audit.entries[0]
+	if entry.ActionType != "DELETE" || entry.TableName != "grade_journal" || entry.RowID != 0 || entry.StudentID != nil {
+		t.Errorf("audit entry = %+v, want summary without row and student", entry)
+	}
+	if entry.UserID == nil || *entry.UserID != 99 {
+		t.Errorf("audit user = %v, want 99", entry.UserID)
+	}
+	if entry.Comment == nil || *entry.Comment != "Grade_Journal bulk deleted: 2 records" {
+		t.Errorf("audit comment = %v", entry.Comment)
+	}
+	var snapshot []models.GradeJournal
+	if entry.OldData == nil || json.Unmarshal([]byte(*entry.OldData), &snapshot) != nil || len(snapshot) != 2 ||
+		snapshot[0].GradeJournalID != 3 || snapshot[1].StudentID != 7 {
+		t.Errorf("audit old_data = %v, want both deleted grades", entry.OldData)
+	}
+}
+
+func TestBulkDeleteGradeJournals_NothingDeleted(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"unknown ids", `{"ids":[404,405]}`, http.StatusOK},
+		{"empty ids", `{"ids":[]}`, http.StatusBadRequest},
+		{"missing ids", `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, h, audit := newOwnershipFixture()
+			r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/gradejournals/bulk-delete", strings.NewReader(tt.body)), 99, permGradeJournalManageAny)
+			rec := httptest.NewRecorder()
+			h.BulkDeleteGradeJournals(discardLogger())(rec, r)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusOK && strings.TrimSpace(rec.Body.String()) != `{"deleted":0,"ids":[]}` {
+				t.Errorf("body = %s, want zero deleted", rec.Body)
+			}
+			// без удалённых записей сводка в аудит не пишется
+			if len(repo.grades) != 3 || len(audit.entries) != 0 {
+				t.Errorf("grades %d, audit entries %d, want 3 and none", len(repo.grades), len(audit.entries))
+			}
+		})
+	}
+}
+
+func TestBulkDeleteGradeJournals_InputLimits(t *testing.T) {
+	// лимит в 2 id: третий уже лишний
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"at max_batch_size", `{"ids":[1,2]}`, http.StatusOK},
+		{"above max_batch_size", `{"ids":[1,2,3]}`, http.StatusRequestEntityTooLarge},
+		{"nested arrays", `{"ids":[[1],[2]]}`, http.StatusBadRequest},
+		// глубже предела вложенности encoding/json (10000) — отказ декодера, а не паника
+		{"too deep", `{"ids":` + strings.Repeat("[", 10001) + strings.Repeat("]", 10001) + `}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, _, audit := newOwnershipFixture()
+			h := NewGradeJournalHandler(repo, nil, disciplineTeachers{3: 10, 4: 20}, audit, nil, nil, noopEvents{}, 2)
+			r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/gradejournals/bulk-delete", strings.NewReader(tt.body)), 99, permGradeJournalManageAny)
+			rec := httptest.NewRecorder()
+			h.BulkDeleteGradeJournals(discardLogger())(rec, r)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusOK {
+				return
+			}
+			// отказ до удаления
+			if len(repo.deleted) != 0 || len(repo.grades) != 3 || len(audit.entries) != 0 {
+				t.Errorf("deleted %v, audit entries %d on rejected request", repo.deleted, len(audit.entries))
+			}
+		})
+	}
+}
+
+func TestCreateGradeJournal_IgnoresServerFields(t *testing.T) {
+	repo, h, _ := newOwnershipFixture()
+	body := `{"grade_journal_id":"999","created_at":"2000-01-01T00:00:00Z","updated_at":"2000-01-01T00:00:00Z",` +
+		`"student_id":7,"discipline_id":3,"grade":4}`
+	r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/gradejournals", strings.NewReader(body)), 10)
+	rec := httptest.NewRecorder()
+	h.CreateGradeJournal(discardLogger())(rec, r)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	var got models.GradeJournal
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	// id и временные метки выставляет сервер, переданные клиентом отбрасываются
+	if got.GradeJournalID != 4 || got.CreatedAt.Year() == 2000 || got.UpdatedAt.Year() == 2000 {
+		t.Errorf("created = %+v", got)
+	}
+	if _, ok := repo.grades[999]; ok {
+		t.Error("grade stored under client-supplied id")
+	}
+	if got.StudentID != 7 || got.DisciplineID != 3 || got.Grade != 4 {
+		t.Errorf("writable fields = %+v", got)
+	}
+}
+
+// recordingNotifier запоминает отправленные уведомления
+type recordingNotifier struct {
+	messages []notifier.Message
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, msg notifier.Message) error {
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func TestNotifyGradePosted_SendsToStudentEmail(t *testing.T) {
+	users := newMemUserRepo(
+		models.User{UserID: 7, Email: "student@example.com"},
+		models.User{UserID: 8, Email: "other@example.com"},
+	)
+	n := &recordingNotifier{}
+	h := NewGradeJournalHandler(newMemGradeRepo(), nil, nil, nil, users, n, noopEvents{}, 100)
+
+	h.notifyGradePosted(context.Background(), discardLogger(), &models.GradeJournal{StudentID: 7, Grade: 5, DisciplineID: 3})
+
+	if len(n.messages) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(n.messages))
+	}
+	if got := n.messages[0]; got.To != "student@example.com" || !strings.Contains(got.Body, "5") {
+		t.Fatalf("message = %+v", got)
+	}
+}
+
+func TestNotifyGradePosted_UnknownStudentSendsNothing(t *testing.T) {
+	n := &recordingNotifier{}
+	h := NewGradeJournalHandler(newMemGradeRepo(), nil, nil, nil, newMemUserRepo(), n, noopEvents{}, 100)
+	h.notifyGradePosted(context.Background(), discardLogger(), &models.GradeJournal{StudentID: 404})
+	if len(n.messages) != 0 {
+		t.Fatalf("sent %v for unknown student", n.messages)
+	}
+}
+
+func TestListGradeJournal_ContentNegotiation(t *testing.T) {
+	comment := "Контрольная, вариант 2"
+	created := time.Date(2024, 9, 2, 10, 0, 0, 0, time.UTC)
+	repo := newMemGradeRepo(&models.GradeJournal{
+		GradeJournalID: 1, CreatedAt: created, UpdatedAt: created,
+		StudentID: 7, Grade: 5, Comment: &comment, DisciplineID: 3,
+	})
+	h := NewGradeJournalHandler(repo, nil, nil, &recordingAudit{}, nil, nil, noopEvents{}, 100)
+
+	tests := []struct {
+		accept  string
+		wantCSV bool
+	}{
+		{"text/csv", true},
+		{"text/csv; charset=utf-8", true},
+		{"application/json", false},
+		{"", false},
+		{"application/xml", false},
+		{"application/json, text/csv", false},
+		{"text/html, text/csv;q=0.9", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/gradejournals", nil)
+			r.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			h.ListGradeJournal(discardLogger())(rec, r)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+			}
+
+			if !tt.wantCSV {
+				if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+					t.Fatalf("Content-Type = %q, want JSON", ct)
+				}
+				var items []models.GradeJournal
+				if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil || len(items) != 1 || items[0].Grade != 5 {
+					t.Fatalf("json body = %s (%v)", rec.Body, err)
+				}
+				return
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+				t.Fatalf("Content-Type = %q, want text/csv", ct)
+			}
+			records, err := csv.NewReader(rec.Body).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := [][]string{
+				{"grade_journal_id", "created_at", "updated_at", "student_id", "discipline_id", "grade", "comment"},
+				{"1", "2024-09-02T10:00:00Z", "2024-09-02T10:00:00Z", "7", "3", "5", comment},
+			}
+			if !reflect.DeepEqual(records, want) {
+				t.Fatalf("csv = %q, want %q", records, want)
+			}
+		})
+	}
+}
+
+func TestListGradeJournal_StudentIDs(t *testing.T) {
+	repo := newMemGradeRepo(
+		&models.GradeJournal{GradeJournalID: 1, StudentID: 7, Grade: 5, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 2, StudentID: 8, Grade: 4, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 3, StudentID: 9, Grade: 3, DisciplineID: 4},
+		&models.GradeJournal{GradeJournalID: 4, StudentID: 7, Grade: 2, DisciplineID: 4},
+	)
+	h := NewGradeJournalHandler(repo, nil, nil, &recordingAudit{}, nil, nil, noopEvents{}, 100)
+
+	tests := []struct {
+		query   string
+		want    int
+		wantIDs []models.ID
+	}{
+		{"student_ids=7,9", http.StatusOK, []models.ID{1, 3, 4}},
+		{"student_ids=8", http.StatusOK, []models.ID{2}},
+		// фильтры складываются
+		{"student_ids=7,8&discipline_id=4", http.StatusOK, []models.ID{4}},
+		{"student_ids=7,x", http.StatusBadRequest, nil},
+		{"student_ids=7%3B8", http.StatusBadRequest, nil},
+		{"student_ids=-", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ListGradeJournal(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/gradejournals?"+tt.query, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var items []models.GradeJournal
+			if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+				t.Fatal(err)
+			}
+			var ids []models.ID
+			for _, g := range items {
+				ids = append(ids, g.GradeJournalID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("grades = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestListGradeJournal_IDsBatchLimit(t *testing.T) {
+	repo := newMemGradeRepo(
+		&models.GradeJournal{GradeJournalID: 1, StudentID: 7, Grade: 5, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 2, StudentID: 8, Grade: 4, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 3, StudentID: 9, Grade: 3, DisciplineID: 4},
+	)
+	h := NewGradeJournalHandler(repo, nil, nil, &recordingAudit{}, nil, nil, noopEvents{}, 3)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"ids=3,1,2", http.StatusOK},
+		{"ids=1,2,3,4", http.StatusRequestEntityTooLarge},
+		{"ids=1,x", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ListGradeJournal(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/gradejournals?"+tt.query, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestListStudentGrades_StudentMissing(t *testing.T) {
+	repo := newMemGradeRepo(&models.GradeJournal{GradeJournalID: 1, StudentID: 7, Grade: 5, DisciplineID: 3})
+	h := NewGradeJournalHandler(repo, existingStudents{7: true, 8: true}, nil, &recordingAudit{}, nil, nil, noopEvents{}, 100)
+
+	tests := []struct {
+		id       string
+		want     int
+		wantBody string
+	}{
+		{"7", http.StatusOK, ""},
+		// студент есть, оценок нет — пустой массив
+		{"8", http.StatusOK, "[]"},
+		{"404", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/students/"+tt.id+"/grades", nil)
+			rec := httptest.NewRecorder()
+			h.ListStudentGrades(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("body = %s, want %s", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestListStudentAtRisk_StudentMissing(t *testing.T) {
+	h := NewGradeJournalHandler(newMemGradeRepo(), existingStudents{8: true}, nil, &recordingAudit{}, nil, nil, noopEvents{}, 100)
+
+	tests := []struct {
+		id       string
+		want     int
+		wantBody string
+	}{
+		{"8", http.StatusOK, "[]"},
+		{"404", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/students/"+tt.id+"/at-risk", nil)
+			rec := httptest.NewRecorder()
+			h.ListStudentAtRisk(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("body = %s, want %s", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestListStudentAtRisk_Threshold(t *testing.T) {
+	// средние студента 7: дисциплина 3 — 3.0, дисциплина 4 — 4.5, дисциплина 5 — 2.0
+	repo := newMemGradeRepo(
+		&models.GradeJournal{GradeJournalID: 1, StudentID: 7, Grade: 4, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 2, StudentID: 7, Grade: 2, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 3, StudentID: 7, Grade: 5, DisciplineID: 4},
+		&models.GradeJournal{GradeJournalID: 4, StudentID: 7, Grade: 4, DisciplineID: 4},
+		&models.GradeJournal{GradeJournalID: 5, StudentID: 7, Grade: 2, DisciplineID: 5},
+	)
+	h := NewGradeJournalHandler(repo, existingStudents{7: true}, nil, &recordingAudit{}, nil, nil, noopEvents{}, 100)
+
+	tests := []struct {
+		query   string
+		want    int
+		wantIDs []int64
+	}{
+		// по умолчанию порог 3: средний ровно 3.0 не считается отставанием
+		{"", http.StatusOK, []int64{5}},
+		{"?threshold=3", http.StatusOK, []int64{5}},
+		{"?threshold=3.01", http.StatusOK, []int64{3, 5}},
+		{"?threshold=4.5", http.StatusOK, []int64{3, 5}},
+		{"?threshold=5", http.StatusOK, []int64{3, 4, 5}},
+		{"?threshold=1", http.StatusOK, []int64{}},
+		{"?threshold=0", http.StatusBadRequest, nil},
+		{"?threshold=-1", http.StatusBadRequest, nil},
+		{"?threshold=NaN", http.StatusBadRequest, nil},
+		{"?threshold=Inf", http.StatusBadRequest, nil},
+		{"?threshold=three", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/students/7/at-risk"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.ListStudentAtRisk(discardLogger())(rec, withURLParams(r, "id", "7"))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var items []*models.DisciplineAverage
+			if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+				t.Fatal(err)
+			}
+			ids := []int64{}
+			for _, a := range items {
+				ids = append(ids, a.DisciplineID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("disciplines = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestListGradeJournal_TotalCount(t *testing.T) {
+	repo := newMemGradeRepo(
+		&models.GradeJournal{GradeJournalID: 1, StudentID: 7, Grade: 5, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 2, StudentID: 7, Grade: 4, DisciplineID: 3},
+		&models.GradeJournal{GradeJournalID: 3, StudentID: 7, Grade: 3, DisciplineID: 4},
+		&models.GradeJournal{GradeJournalID: 4, StudentID: 8, Grade: 2, DisciplineID: 4},
+	)
+	h := NewGradeJournalHandler(repo, nil, nil, &recordingAudit{}, nil, nil, noopEvents{}, 100)
+
+	tests := []struct {
+		query     string
+		wantTotal string
+		wantLen   int
+	}{
+		// общее число под фильтром, а не размер страницы
+		{"student_id=7&limit=2&with_total=true", "3", 2},
+		{"with_total=true", "4", 4},
+		{"student_id=7&limit=2", "", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ListGradeJournal(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/gradejournals?"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("X-Total-Count"); got != tt.wantTotal {
+				t.Errorf("X-Total-Count = %q, want %q", got, tt.wantTotal)
+			}
+			// тело остаётся массивом
+			var items []models.GradeJournal
+			if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil || len(items) != tt.wantLen {
+				t.Errorf("items = %d, want %d: %v", len(items), tt.wantLen, err)
+			}
+		})
+	}
+}
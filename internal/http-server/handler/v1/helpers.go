@@ -0,0 +1,267 @@
+package v1
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"mime"
+	"net/http"
+	"service/internal/http-server/middleware/permissions"
+	resp "service/internal/lib/api/response"
+	"service/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// parseUpdatedSince разбирает параметр updated_since для инкрементальной синхронизации.
+// Принимает RFC3339 или дату YYYY-MM-DD, при ошибке фильтр не применяется.
+func parseUpdatedSince(r *http.Request) *time.Time {
+	val := r.URL.Query().Get("updated_since")
+	if val == "" {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, val); err == nil {
+		return &t
+	}
+	if t, err := time.Parse("2006-01-02", val); err == nil {
+		return &t
+	}
+	return nil
+}
+
+// parseIDList разбирает список id через запятую, например "1,2,3".
+// Пустые элементы пропускаются, нечисловой id — ошибка.
+func parseIDList(val string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(val, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+const (
+	// defaultLimit размер страницы, если limit не задан или некорректен
+	defaultLimit = 20
+	// maxLimit верхняя граница limit, большие значения урезаются
+	maxLimit = 500
+	// unboundedLimit значение limit, при котором выборка идёт без ограничения
+	unboundedLimit = -1
+	// unboundedPermission право на выборку без ограничения (выгрузки)
+	unboundedPermission = "list:unbounded"
+)
+
+// parsePagination разбирает limit и offset списков:
+//   - limit не задан, не число, 0 или отрицательный — defaultLimit;
+//   - limit=-1 при праве list:unbounded — без ограничения, без права — defaultLimit;
+//   - limit больше maxLimit — maxLimit.
+//
+// Отрицательный или некорректный offset считается нулём.
+func parsePagination(r *http.Request) (limit, offset int) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	switch {
+	case err != nil || limit == 0:
+		limit = defaultLimit
+	case limit == unboundedLimit && permissions.HasPermission(r, unboundedPermission):
+		// MySQL не поддерживает LIMIT без значения, максимальное число равносильно его отсутствию
+		limit = math.MaxInt
+	case limit < 0:
+		limit = defaultLimit
+	case limit > maxLimit:
+		limit = maxLimit
+	}
+	offset, err = strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// parseAfterID разбирает after_id для keyset-пагинации. Keyset стоит использовать для
+// выгрузок и глубоких страниц: MySQL не пропускает OFFSET строк, а ищет по индексу первичного
+// ключа. Offset удобнее для перехода на произвольную страницу в интерфейсе.
+// Возвращает false, если значение задано, но некорректно.
+func parseAfterID(r *http.Request) (*int64, bool) {
+	val := r.URL.Query().Get("after_id")
+	if val == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || id < 0 {
+		return nil, false
+	}
+	return &id, true
+}
+
+// parseDateParam разбирает необязательную дату YYYY-MM-DD из query.
+// Возвращает false, если значение задано, но некорректно.
+func parseDateParam(r *http.Request, name string) (*time.Time, bool) {
+	val := r.URL.Query().Get(name)
+	if val == "" {
+		return nil, true
+	}
+	d, err := time.Parse("2006-01-02", val)
+	if err != nil {
+		return nil, false
+	}
+	return &d, true
+}
+
+// setNextAfterID выставляет X-Next-After-Id, если страница заполнена целиком
+// и за ней могут быть ещё записи
+func setNextAfterID(w http.ResponseWriter, count, limit int, lastID int64) {
+	if count > 0 && count == limit {
+		w.Header().Set("X-Next-After-Id", strconv.FormatInt(lastID, 10))
+	}
+}
+
+// retryAfterSeconds через сколько клиенту стоит повторить запрос при недоступной БД
+const retryAfterSeconds = "5"
+
+// renderServerError отвечает 503 с Retry-After, если БД недоступна, иначе 500
+func renderServerError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if storage.IsConnError(err) {
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnavailable))
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	render.JSON(w, r, resp.ErrorFor(r, msg))
+}
+
+// checkBatchSize отвечает 413, если в пакетном запросе больше max элементов; max <= 0 — без ограничения.
+// При отказе ответ уже записан и возвращается false.
+func checkBatchSize(w http.ResponseWriter, r *http.Request, log *slog.Logger, n, max int) bool {
+	if max <= 0 || n <= max {
+		return true
+	}
+	log.Info("batch too large", slog.Int("count", n), slog.Int("max", max))
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	render.JSON(w, r, resp.ErrorFor(r, fmt.Sprintf("batch too large: at most %d items allowed", max)))
+	return false
+}
+
+// fkDependentNouns названия ссылающихся таблиц для сообщений 409 при удалении
+var fkDependentNouns = map[string]string{
+	"grade_journal":    "grades",
+	"attendance":       "attendance records",
+	"curriculum":       "curriculum entries",
+	"discipline":       "disciplines",
+	"student":          "students",
+	"teacher":          "teachers",
+	"student_group":    "student groups",
+	"semester":         "semesters",
+	"user_roles":       "role assignments",
+	"role_permissions": "permission assignments",
+}
+
+// fkParentNouns названия родительских таблиц для сообщений 409 о ссылке на несуществующую запись
+var fkParentNouns = map[string]string{
+	"user":          "user",
+	"student":       "student",
+	"teacher":       "teacher",
+	"student_group": "student group",
+	"academic_year": "academic year",
+	"semester":      "semester",
+	"discipline":    "discipline",
+	"roles":         "role",
+	"permissions":   "permission",
+}
+
+// referencedMessage текст 409 при удалении записи, на которую ссылаются другие:
+// "discipline has dependent grades". Если ссылающаяся таблица неизвестна —
+// "<entity> is referenced by other records".
+func referencedMessage(entity string, err error) string {
+	var fkErr *storage.ForeignKeyError
+	if errors.As(err, &fkErr) {
+		if noun, ok := fkDependentNouns[fkErr.Table]; ok {
+			return entity + " has dependent " + noun
+		}
+	}
+	return entity + " is referenced by other records"
+}
+
+// missingReferenceMessage текст 409, когда запись ссылается на строку, удалённую
+// между проверкой и сохранением: "student group does not exist"
+func missingReferenceMessage(err error) string {
+	var fkErr *storage.ForeignKeyError
+	if errors.As(err, &fkErr) {
+		if noun, ok := fkParentNouns[fkErr.Table]; ok {
+			return noun + " does not exist"
+		}
+	}
+	return "referenced record does not exist"
+}
+
+// setLocation выставляет заголовок Location на созданный ресурс, вызывать до WriteHeader
+func setLocation(w http.ResponseWriter, resource string, id int64) {
+	w.Header().Set("Location", "/api/v1/"+resource+"/"+strconv.FormatInt(id, 10))
+}
+
+// wantsCSV выбирает формат ответа по заголовку Accept.
+// Побеждает первый из text/csv и application/json, по умолчанию JSON.
+func wantsCSV(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return true
+		case "application/json":
+			return false
+		}
+	}
+	return false
+}
+
+func renderCSV(w http.ResponseWriter, header []string, rows [][]string) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func csvTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// setTotalCount при ?with_total=true пишет в X-Total-Count общее число строк под фильтром.
+// Вызывается до записи тела ответа, тело списка остаётся массивом.
+func setTotalCount(w http.ResponseWriter, r *http.Request, count func() (int64, error)) error {
+	if ok, _ := strconv.ParseBool(r.URL.Query().Get("with_total")); !ok {
+		return nil
+	}
+	total, err := count()
+	if err != nil {
+		return err
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	return nil
+}
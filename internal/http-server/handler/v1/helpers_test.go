@@ -0,0 +1,317 @@
+package v1
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	"service/internal/http-server/middleware/permissions"
+	"service/internal/lib/jwt"
+	"service/internal/lib/phone"
+	"service/internal/storage"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "test-secret"
+
+// withPermissions прогоняет запрос через JWTAuth и Preload с правами из токена
+// и возвращает запрос в том виде, в каком его видит обработчик
+func withPermissions(t *testing.T, target string, perms ...string) *http.Request {
+	t.Helper()
+	return authorize(t, httptest.NewRequest(http.MethodGet, target, nil), 1, perms...)
+}
+
+// authorize то же, что withPermissions, для готового запроса от имени userID
+func authorize(t *testing.T, r *http.Request, userID int64, perms ...string) *http.Request {
+	t.Helper()
+	if perms == nil {
+		// пустой список, а не null: иначе Preload пошёл бы за правами в БД
+		perms = []string{}
+	}
+	token, err := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, jwtlib.MapClaims{
+		"id":                 userID,
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		jwt.ClaimPermissions: perms,
+	}).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	rbac := permissions.NewRBACMiddleware(nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), true)
+	var got *http.Request
+	h := ware.JWTAuth(testJWTSecret, nil)(rbac.Preload()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	})))
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if got == nil {
+		t.Fatal("request did not pass auth middleware")
+	}
+	return got
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantLimit  int
+		wantOffset int
+	}{
+		{"", defaultLimit, 0},
+		{"?limit=abc&offset=xyz", defaultLimit, 0},
+		{"?limit=0", defaultLimit, 0},
+		{"?limit=-5&offset=-3", defaultLimit, 0},
+		{"?limit=50&offset=100", 50, 100},
+		{"?limit=100000", maxLimit, 0},
+		// без права list:unbounded -1 не снимает ограничение
+		{"?limit=-1", defaultLimit, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/students"+tt.query, nil)
+			limit, offset := parsePagination(r)
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Fatalf("got limit=%d offset=%d, want %d %d", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestParsePagination_Unbounded(t *testing.T) {
+	r := withPermissions(t, "/api/v1/students?limit=-1", unboundedPermission)
+	if limit, _ := parsePagination(r); limit != math.MaxInt {
+		t.Fatalf("limit = %d, want unbounded", limit)
+	}
+
+	r = withPermissions(t, "/api/v1/students?limit=-1", "student:read")
+	if limit, _ := parsePagination(r); limit != defaultLimit {
+		t.Fatalf("limit = %d without %s, want %d", limit, unboundedPermission, defaultLimit)
+	}
+}
+
+func TestForeignKeyMessages(t *testing.T) {
+	referenced := &storage.ForeignKeyError{Table: "grade_journal", Err: storage.ErrReferenced}
+	if got := referencedMessage("discipline", fmt.Errorf("wrap: %w", referenced)); got != "discipline has dependent grades" {
+		t.Fatalf("referencedMessage = %q", got)
+	}
+	unknown := &storage.ForeignKeyError{Table: "something_new", Err: storage.ErrReferenced}
+	if got := referencedMessage("discipline", unknown); got != "discipline is referenced by other records" {
+		t.Fatalf("referencedMessage for unknown table = %q", got)
+	}
+
+	missing := &storage.ForeignKeyError{Table: "student_group", Err: storage.ErrMissingReference}
+	if got := missingReferenceMessage(missing); got != "student group does not exist" {
+		t.Fatalf("missingReferenceMessage = %q", got)
+	}
+	if got := missingReferenceMessage(errors.New("other")); got != "referenced record does not exist" {
+		t.Fatalf("missingReferenceMessage fallback = %q", got)
+	}
+}
+
+// discardLogger логгер для тестов обработчиков
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// withURLParams добавляет в запрос параметры маршрута chi
+func withURLParams(r *http.Request, kv ...string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(kv); i += 2 {
+		rctx.URLParams.Add(kv[i], kv[i+1])
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+// recordingAudit запоминает записи аудита вместо записи в БД
+type recordingAudit struct {
+	AuditLogRepository
+	mu      sync.Mutex
+	entries []*models.AuditLog
+}
+
+func (a *recordingAudit) AddAuditLog(_ context.Context, entry *models.AuditLog) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+// noopEvents EventDispatcher, который ничего не отправляет
+type noopEvents struct{}
+
+func (noopEvents) Dispatch(context.Context, string, interface{}) {}
+
+func TestParseUpdatedSince(t *testing.T) {
+	at := func(t time.Time) *time.Time { return &t }
+	tests := []struct {
+		query string
+		want  *time.Time
+	}{
+		{"", nil},
+		{"updated_since=2024-10-01T12:30:00Z", at(time.Date(2024, 10, 1, 12, 30, 0, 0, time.UTC))},
+		{"updated_since=2024-10-01T15:30:00%2B03:00", at(time.Date(2024, 10, 1, 12, 30, 0, 0, time.UTC))},
+		{"updated_since=2024-10-01", at(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC))},
+		// некорректное значение не фильтрует, а не отдаёт пустой список
+		{"updated_since=01.10.2024", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := parseUpdatedSince(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil))
+			if (got == nil) != (tt.want == nil) || got != nil && !got.Equal(*tt.want) {
+				t.Errorf("parseUpdatedSince = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreate_LocationHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		serve func(w http.ResponseWriter)
+		want  string
+	}{
+		{"discipline", func(w http.ResponseWriter) {
+			h := NewDisciplineHandler(newFakeDisciplineRepo(), &recordingAudit{}, false)
+			body := `{"discipline_name":"Физика","teacher_id":10,"student_group_id":2}`
+			h.CreateDiscipline(discardLogger())(w, httptest.NewRequest(http.MethodPost, "/api/v1/disciplines", strings.NewReader(body)))
+		}, "/api/v1/disciplines/1"},
+		{"curriculum", func(w http.ResponseWriter) {
+			h := NewCurriculumHandler(newMemCurriculumRepo(&models.Curriculum{CurriculumID: 6}), knownSemesters{}, &recordingAudit{})
+			body := `{"subject_name":"Алгебра","discipline_id":9}`
+			h.CreateCurriculum(discardLogger())(w, httptest.NewRequest(http.MethodPost, "/api/v1/curriculums", strings.NewReader(body)))
+		}, "/api/v1/curriculums/7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.serve(rec)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIDList(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    []int64
+		wantErr bool
+	}{
+		{"7", []int64{7}, false},
+		{"1,2,3", []int64{1, 2, 3}, false},
+		{" 4 , 5 ,", []int64{4, 5}, false},
+		{"9223372036854775807", []int64{math.MaxInt64}, false},
+		{"1,abc,3", nil, true},
+		{"1;2", nil, true},
+		{"1.5", nil, true},
+		{"9223372036854775808", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseIDList(tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("parseIDList(%q) = %v, want %v", tt.val, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderServerError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{"bad connection", fmt.Errorf("get student: %w", driver.ErrBadConn), http.StatusServiceUnavailable, retryAfterSeconds},
+		{"query error", errors.New("unknown column"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ошибка приходит из репозитория через обычный обработчик
+			repo := failingStudents{err: tt.err}
+			h := NewStudentHandler(repo, nil, nil, nil, nil, StudentYearPolicy{}, phone.Normalizer{}, 100)
+			rec := httptest.NewRecorder()
+			r := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/students/7", nil), "id", "7")
+			h.GetStudentByID(discardLogger())(rec, r)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if !strings.Contains(rec.Body.String(), `"error"`) {
+				t.Errorf("body = %s, want JSON error", rec.Body)
+			}
+		})
+	}
+}
+
+// failingStudents репозиторий студентов, который всегда возвращает err
+type failingStudents struct {
+	StudentRepository
+	err error
+}
+
+func (f failingStudents) GetStudentByID(context.Context, int64) (*models.Student, error) {
+	return nil, f.err
+}
+
+func TestSetTotalCount(t *testing.T) {
+	countErr := errors.New("count failed")
+	tests := []struct {
+		query     string
+		count     int64
+		err       error
+		wantTotal string
+		wantErr   error
+		wantCall  bool
+	}{
+		{"?with_total=true", 42, nil, "42", nil, true},
+		{"?with_total=1", 0, nil, "0", nil, true},
+		{"?with_total=false", 42, nil, "", nil, false},
+		{"", 42, nil, "", nil, false},
+		{"?with_total=yes", 42, nil, "", nil, false},
+		{"?with_total=true", 0, countErr, "", countErr, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			called := false
+			rec := httptest.NewRecorder()
+			err := setTotalCount(rec, httptest.NewRequest(http.MethodGet, "/api/v1/gradejournals"+tt.query, nil), func() (int64, error) {
+				called = true
+				return tt.count, tt.err
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			// без with_total подсчёт не выполняется
+			if called != tt.wantCall {
+				t.Errorf("count called = %v, want %v", called, tt.wantCall)
+			}
+			if got := rec.Header().Get("X-Total-Count"); got != tt.wantTotal {
+				t.Errorf("X-Total-Count = %q, want %q", got, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	resp "service/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type MeHandler struct {
+	userRepo     UserRepository
+	userRoleRepo UserRoleRepository
+}
+
+func NewMeHandler(userRepo UserRepository, userRoleRepo UserRoleRepository) *MeHandler {
+	return &MeHandler{userRepo: userRepo, userRoleRepo: userRoleRepo}
+}
+
+// @Summary Получить профиль текущего пользователя
+// @Tags me
+// @Produce json
+// @Success 200 {object} models.Me
+// @Failure 401 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/me [get]
+// @Security BearerAuth
+func (h *MeHandler) GetMe(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.me_handler.GetMe"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := ware.GetUserID(r)
+		if !ok {
+			log.Info("user id not found in claims")
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnauthorized))
+			return
+		}
+
+		user, err := h.userRepo.GetClientByID(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("user not found", slog.Int64("user_id", userID))
+				w.WriteHeader(http.StatusUnauthorized)
+				render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnauthorized))
+				return
+			}
+			log.Error("failed to get user", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get user")
+			return
+		}
+
+		roles, err := h.userRoleRepo.GetRolesByUserID(r.Context(), userID)
+		if err != nil {
+			log.Error("failed to get user roles", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get user roles")
+			return
+		}
+		if roles == nil {
+			roles = []*models.UserRole{}
+		}
+
+		render.JSON(w, r, models.Me{
+			UserID:     user.UserID,
+			CreatedAt:  user.CreatedAt,
+			UpdatedAt:  user.UpdatedAt,
+			FirstName:  user.FirstName,
+			LastName:   user.LastName,
+			MiddleName: user.MiddleName,
+			Email:      user.Email,
+			Roles:      roles,
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+)
+
+// userRoles роли пользователей в памяти
+type userRoles struct {
+	UserRoleRepository
+	roles map[int64][]*models.UserRole
+}
+
+func (u userRoles) GetRolesByUserID(_ context.Context, userID int64) ([]*models.UserRole, error) {
+	return u.roles[userID], nil
+}
+
+func getMe(t *testing.T, h *MeHandler, userID int64) *httptest.ResponseRecorder {
+	t.Helper()
+	r := authorize(t, httptest.NewRequest(http.MethodGet, "/api/v1/me", nil), userID)
+	rec := httptest.NewRecorder()
+	h.GetMe(discardLogger())(rec, r)
+	return rec
+}
+
+func TestGetMe(t *testing.T) {
+	users := newMemUserRepo(
+		models.User{UserID: 1, FirstName: "Анна", LastName: "Иванова", Email: "anna@example.com", Password: []byte("hash")},
+		models.User{UserID: 2, FirstName: "Пётр", LastName: "Петров", Email: "petr@example.com", Password: []byte("hash")},
+	)
+	roles := userRoles{roles: map[int64][]*models.UserRole{
+		1: {{UserID: 1, RoleID: 3}, {UserID: 1, RoleID: 5}},
+	}}
+	h := NewMeHandler(users, roles)
+
+	rec := getMe(t, h, 1)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), "password") {
+		t.Errorf("response leaks password: %s", rec.Body)
+	}
+	var me models.Me
+	if err := json.Unmarshal(rec.Body.Bytes(), &me); err != nil {
+		t.Fatal(err)
+	}
+	if me.UserID != 1 || me.Email != "anna@example.com" || me.FirstName != "Анна" {
+		t.Errorf("profile = %+v", me)
+	}
+	if len(me.Roles) != 2 || me.Roles[0].RoleID != 3 || me.Roles[1].RoleID != 5 {
+		t.Errorf("roles = %+v, want [3 5]", me.Roles)
+	}
+
+	// пользователь без ролей получает пустой массив, а не null
+	rec = getMe(t, h, 2)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), `"roles":[]`) {
+		t.Errorf("body = %s, want empty roles array", rec.Body)
+	}
+}
+
+func TestGetMe_MissingUser(t *testing.T) {
+	h := NewMeHandler(newMemUserRepo(), userRoles{})
+
+	// токен пережил удаление пользователя
+	if rec := getMe(t, h, 42); rec.Code != http.StatusUnauthorized {
+		t.Errorf("deleted user: status = %d, want 401", rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	h.GetMe(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/me", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("no claims: status = %d, want 401", rec.Code)
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"regexp"
 	"service/internal/domain/models"
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -24,6 +27,35 @@ type PermissionRepository interface {
 	UpdatePermission(ctx context.Context, perm *models.Permission) error
 	DeletePermission(ctx context.Context, id int64) error
 	ListPermission(ctx context.Context, limit, offset int) ([]*models.Permission, error)
+	RenamePermissions(ctx context.Context, from, to string, prefix bool) ([]*models.PermissionRenamed, error)
+	CountPermission(ctx context.Context) (int64, error)
+	ListPermissionNames(ctx context.Context) ([]string, error)
+}
+
+// permissionNameRe формат права "ресурс:действие", например gradejournal:create
+var permissionNameRe = regexp.MustCompile(`^[a-z][a-z0-9_-]*:[a-z][a-z0-9_-]*$`)
+
+// normalizePermissionName приводит имя к нижнему регистру (RBAC сравнивает без учёта регистра)
+// и проверяет формат
+func normalizePermissionName(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return name, permissionNameRe.MatchString(name)
+}
+
+// groupPermissionsByResource раскладывает имена "ресурс:действие" по ресурсам.
+// Имя без ":" попадает в каталог как ресурс без действий.
+func groupPermissionsByResource(names []string) map[string][]string {
+	catalog := make(map[string][]string)
+	for _, name := range names {
+		resource, action, ok := strings.Cut(name, ":")
+		if _, exists := catalog[resource]; !exists {
+			catalog[resource] = []string{}
+		}
+		if ok {
+			catalog[resource] = append(catalog[resource], action)
+		}
+	}
+	return catalog
 }
 
 type PermissionHandler struct {
@@ -53,13 +85,19 @@ func (h *PermissionHandler) CreatePermission(log *slog.Logger) http.HandlerFunc
 		if err := json.NewDecoder(r.Body).Decode(&perm); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		var ok bool
+		if perm.PermissionName, ok = normalizePermissionName(perm.PermissionName); !ok {
+			log.Info("invalid permission name", slog.String("permission_name", perm.PermissionName))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "permission_name must match resource:action"))
 			return
 		}
 		if err := h.repo.CreatePermission(r.Context(), &perm); err != nil {
 			log.Error("failed to create permission", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create permission"))
+			renderServerError(w, r, err, "failed to create permission")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -70,6 +108,7 @@ func (h *PermissionHandler) CreatePermission(log *slog.Logger) http.HandlerFunc
 			NewData:    utils.PtrToJSON(perm),
 			Comment:    utils.PtrToStr("Permission created"),
 		})
+		setLocation(w, "permissions", perm.PermissionID)
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, perm)
 	}
@@ -95,7 +134,7 @@ func (h *PermissionHandler) GetPermissionByID(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid permission id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid permission id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid permission id"))
 			return
 		}
 		perm, err := h.repo.GetPermissionByID(r.Context(), id)
@@ -103,12 +142,11 @@ func (h *PermissionHandler) GetPermissionByID(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("permission not found", slog.Int64("id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("permission not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "permission not found"))
 				return
 			}
 			log.Error("failed to get permission", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get permission"))
+			renderServerError(w, r, err, "failed to get permission")
 			return
 		}
 		render.JSON(w, r, perm)
@@ -136,14 +174,21 @@ func (h *PermissionHandler) UpdatePermission(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid permission id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		var perm models.Permission
 		if err := json.NewDecoder(r.Body).Decode(&perm); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		var ok bool
+		if perm.PermissionName, ok = normalizePermissionName(perm.PermissionName); !ok {
+			log.Info("invalid permission name", slog.String("permission_name", perm.PermissionName))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "permission_name must match resource:action"))
 			return
 		}
 		perm.PermissionID = id
@@ -152,12 +197,11 @@ func (h *PermissionHandler) UpdatePermission(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("permission not found for update", slog.Int64("id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("permission not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "permission not found"))
 				return
 			}
 			log.Error("failed to update permission", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update permission"))
+			renderServerError(w, r, err, "failed to update permission")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -194,20 +238,25 @@ func (h *PermissionHandler) DeletePermission(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid permission id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid permission id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid permission id"))
 			return
 		}
 		oldData, _ := h.repo.GetPermissionByID(r.Context(), id)
 		if err := h.repo.DeletePermission(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("permission is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("permission", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("permission not found for delete", slog.Int64("id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("permission not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "permission not found"))
 				return
 			}
 			log.Error("failed to delete permission", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete permission"))
+			renderServerError(w, r, err, "failed to delete permission")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -226,28 +275,145 @@ func (h *PermissionHandler) DeletePermission(log *slog.Logger) http.HandlerFunc
 // @Tags permissions
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.Permission
 // @Failure 500 {object} resp.Response
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/permissions [get]
 // @Security BearerAuth
 func (h *PermissionHandler) ListPermissions(log *slog.Logger) http.HandlerFunc {
 	const op = "handler.v1.permission.ListPermissions"
 	return func(w http.ResponseWriter, r *http.Request) {
 		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+		if err := setTotalCount(w, r, func() (int64, error) { return h.repo.CountPermission(r.Context()) }); err != nil {
+			log.Error("failed to count permissions", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list permissions")
+			return
 		}
 		perms, err := h.repo.ListPermission(r.Context(), limit, offset)
 		if err != nil {
 			log.Error("failed to list permissions", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list permissions"))
+			renderServerError(w, r, err, "failed to list permissions")
 			return
 		}
 		render.JSON(w, r, perms)
 	}
 }
+
+// parseRenamePattern проверяет пару from/to: обе — точные имена или обе — "resource:*".
+// Для шаблона возвращает префиксы "resource:".
+func parseRenamePattern(from, to string) (string, string, bool, bool) {
+	from = strings.ToLower(strings.TrimSpace(from))
+	to = strings.ToLower(strings.TrimSpace(to))
+	fromPrefix, toPrefix := strings.HasSuffix(from, ":*"), strings.HasSuffix(to, ":*")
+	if fromPrefix != toPrefix {
+		return "", "", false, false
+	}
+	if fromPrefix {
+		from, to = strings.TrimSuffix(from, "*"), strings.TrimSuffix(to, "*")
+		// проверяем формат ресурса на условном действии
+		_, okFrom := normalizePermissionName(from + "x")
+		_, okTo := normalizePermissionName(to + "x")
+		return from, to, true, okFrom && okTo
+	}
+	_, okFrom := normalizePermissionName(from)
+	_, okTo := normalizePermissionName(to)
+	return from, to, false, okFrom && okTo
+}
+
+// @Summary Каталог прав по ресурсам
+// @Description Имена прав, сгруппированные по ресурсу: {"gradejournal": ["create", "view", ...]}
+// @Tags permissions
+// @Produce json
+// @Success 200 {object} map[string][]string
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/permissions/catalog [get]
+// @Security BearerAuth
+func (h *PermissionHandler) GetPermissionCatalog(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.permission.GetPermissionCatalog"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		names, err := h.repo.ListPermissionNames(r.Context())
+		if err != nil {
+			log.Error("failed to list permission names", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get permission catalog")
+			return
+		}
+		render.JSON(w, r, groupPermissionsByResource(names))
+	}
+}
+
+// @Summary Переименовать права
+// @Description Имя меняется на месте, поэтому назначения ролям сохраняются. "resource:*" переименовывает все права ресурса
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param input body models.PermissionRenameRequest true "Старое и новое имя"
+// @Success 200 {object} models.PermissionRenameResponse
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/permissions/rename [post]
+// @Security BearerAuth
+func (h *PermissionHandler) RenamePermissions(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.permission.RenamePermissions"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		var req models.PermissionRenameRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		from, to, prefix, ok := parseRenamePattern(req.From, req.To)
+		if !ok {
+			log.Info("invalid rename pattern", slog.String("from", req.From), slog.String("to", req.To))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "from and to must both be resource:action or both resource:*"))
+			return
+		}
+		if from == to {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "from and to are the same"))
+			return
+		}
+
+		renamed, err := h.repo.RenamePermissions(r.Context(), from, to, prefix)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("no permissions to rename", slog.String("from", req.From))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "permission not found"))
+				return
+			}
+			if errors.Is(err, storage.ErrDuplicate) {
+				log.Info("target permission name already exists", slog.String("to", req.To))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "target permission name already exists"))
+				return
+			}
+			log.Error("failed to rename permissions", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to rename permissions")
+			return
+		}
+
+		for _, item := range renamed {
+			_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+				UserID:     utils.GetUserIDFromContext(r.Context()),
+				TableName:  "permission",
+				RowID:      item.PermissionID,
+				ActionType: "UPDATE",
+				OldData:    utils.PtrToJSON(map[string]string{"permission_name": item.From}),
+				NewData:    utils.PtrToJSON(map[string]string{"permission_name": item.To}),
+				Comment:    utils.PtrToStr("Permission renamed"),
+			})
+		}
+
+		render.JSON(w, r, models.PermissionRenameResponse{Renamed: len(renamed), Permissions: renamed})
+	}
+}
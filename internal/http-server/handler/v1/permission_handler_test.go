@@ -0,0 +1,246 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/storage"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// memPermissionRepo права в памяти
+type memPermissionRepo struct {
+	PermissionRepository
+	perms map[int64]*models.Permission
+}
+
+func newMemPermissionRepo(names ...string) *memPermissionRepo {
+	repo := &memPermissionRepo{perms: map[int64]*models.Permission{}}
+	for i, name := range names {
+		id := int64(i + 1)
+		repo.perms[id] = &models.Permission{PermissionID: id, PermissionName: name}
+	}
+	return repo
+}
+
+func (m *memPermissionRepo) CreatePermission(_ context.Context, p *models.Permission) error {
+	p.PermissionID = int64(len(m.perms) + 1)
+	cp := *p
+	m.perms[p.PermissionID] = &cp
+	return nil
+}
+
+func (m *memPermissionRepo) GetPermissionByID(_ context.Context, id int64) (*models.Permission, error) {
+	p, ok := m.perms[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func (m *memPermissionRepo) UpdatePermission(_ context.Context, p *models.Permission) error {
+	if _, ok := m.perms[p.PermissionID]; !ok {
+		return sql.ErrNoRows
+	}
+	cp := *p
+	m.perms[p.PermissionID] = &cp
+	return nil
+}
+
+// ListPermissionNames имена по алфавиту, как ORDER BY в репозитории
+func (m *memPermissionRepo) ListPermissionNames(context.Context) ([]string, error) {
+	names := make([]string, 0, len(m.perms))
+	for _, p := range m.perms {
+		names = append(names, p.PermissionName)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RenamePermissions переименовывает всё или ничего, как транзакция в репозитории
+func (m *memPermissionRepo) RenamePermissions(_ context.Context, from, to string, prefix bool) ([]*models.PermissionRenamed, error) {
+	var renamed []*models.PermissionRenamed
+	for id, p := range m.perms {
+		if p.PermissionName == from || prefix && strings.HasPrefix(p.PermissionName, from) {
+			renamed = append(renamed, &models.PermissionRenamed{PermissionID: id, From: p.PermissionName, To: to + strings.TrimPrefix(p.PermissionName, from)})
+		}
+	}
+	if len(renamed) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	sort.Slice(renamed, func(i, j int) bool { return renamed[i].PermissionID < renamed[j].PermissionID })
+	for _, item := range renamed {
+		for id, p := range m.perms {
+			if p.PermissionName == item.To && id != item.PermissionID {
+				return nil, storage.ErrDuplicate
+			}
+		}
+	}
+	for _, item := range renamed {
+		m.perms[item.PermissionID].PermissionName = item.To
+	}
+	return renamed, nil
+}
+
+func renamePermissions(t *testing.T, h *PermissionHandler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/permissions/rename", strings.NewReader(body)), 1, "permission:update")
+	rec := httptest.NewRecorder()
+	h.RenamePermissions(discardLogger())(rec, r)
+	return rec
+}
+
+func TestRenamePermissions(t *testing.T) {
+	repo := newMemPermissionRepo("studentgroup:create", "studentgroup:list", "student:list")
+	audit := &recordingAudit{}
+	h := NewPermissionHandler(repo, audit)
+
+	rec := renamePermissions(t, h, `{"from":"StudentGroup:*","to":"student_group:*"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got models.PermissionRenameResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := models.PermissionRenameResponse{Renamed: 2, Permissions: []*models.PermissionRenamed{
+		{PermissionID: 1, From: "studentgroup:create", To: "student_group:create"},
+		{PermissionID: 2, From: "studentgroup:list", To: "student_group:list"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("response = %+v, want %+v", got, want)
+	}
+	// id сохраняются, поэтому назначения ролям не теряются
+	if repo.perms[1].PermissionName != "student_group:create" || repo.perms[3].PermissionName != "student:list" {
+		t.Errorf("permissions = %+v", repo.perms)
+	}
+	if len(audit.entries) != 2 || audit.entries[0].RowID != 1 || audit.entries[1].RowID != 2 {
+		t.Errorf("audit entries = %+v, want one per renamed permission", audit.entries)
+	}
+}
+
+func TestRenamePermissions_Rejected(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"target taken", `{"from":"studentgroup:*","to":"student:*"}`, http.StatusConflict},
+		{"exact target taken", `{"from":"studentgroup:list","to":"student:list"}`, http.StatusConflict},
+		{"nothing to rename", `{"from":"report:*","to":"reports:*"}`, http.StatusNotFound},
+		{"prefix to exact", `{"from":"studentgroup:*","to":"student_group:list"}`, http.StatusBadRequest},
+		{"same name", `{"from":"student:list","to":"Student:List"}`, http.StatusBadRequest},
+		{"malformed", `{"from":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// student:list занимает одно из новых имён при переносе studentgroup:* в student:*
+			repo := newMemPermissionRepo("studentgroup:create", "studentgroup:list", "student:list")
+			audit := &recordingAudit{}
+			rec := renamePermissions(t, NewPermissionHandler(repo, audit), tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if repo.perms[1].PermissionName != "studentgroup:create" || repo.perms[2].PermissionName != "studentgroup:list" {
+				t.Errorf("permissions changed despite %d: %+v", rec.Code, repo.perms)
+			}
+			if len(audit.entries) != 0 {
+				t.Errorf("audit entries = %+v, want none", audit.entries)
+			}
+		})
+	}
+}
+
+func TestPermissionName_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     int
+		wantName string
+	}{
+		{"lowercase", "gradejournal:create", http.StatusOK, "gradejournal:create"},
+		{"uppercase is lowered", "GradeJournal:Create", http.StatusOK, "gradejournal:create"},
+		{"spaces trimmed", "  student:view_self ", http.StatusOK, "student:view_self"},
+		{"dash and digits", "report-v2:export", http.StatusOK, "report-v2:export"},
+		{"no action", "gradejournal", http.StatusBadRequest, ""},
+		{"empty action", "gradejournal:", http.StatusBadRequest, ""},
+		{"two colons", "a:b:c", http.StatusBadRequest, ""},
+		{"space inside", "grade journal:create", http.StatusBadRequest, ""},
+		{"starts with digit", "1grade:create", http.StatusBadRequest, ""},
+		{"empty", "", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(models.Permission{PermissionName: tt.input})
+
+			repo := newMemPermissionRepo("student:list")
+			h := NewPermissionHandler(repo, &recordingAudit{})
+
+			create := httptest.NewRecorder()
+			h.CreatePermission(discardLogger())(create, httptest.NewRequest(http.MethodPost, "/api/v1/permissions", strings.NewReader(string(body))))
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/permissions/1", strings.NewReader(string(body)))
+			update := httptest.NewRecorder()
+			h.UpdatePermission(discardLogger())(update, withURLParams(r, "id", "1"))
+
+			wantCreate := tt.want
+			if wantCreate == http.StatusOK {
+				wantCreate = http.StatusCreated
+			}
+			if create.Code != wantCreate {
+				t.Fatalf("create: status = %d, want %d: %s", create.Code, wantCreate, create.Body)
+			}
+			if update.Code != tt.want {
+				t.Fatalf("update: status = %d, want %d: %s", update.Code, tt.want, update.Body)
+			}
+			if tt.want != http.StatusOK {
+				if len(repo.perms) != 1 || repo.perms[1].PermissionName != "student:list" {
+					t.Errorf("permissions changed on invalid name: %+v", repo.perms)
+				}
+				return
+			}
+			// сохраняется нормализованное имя
+			if got := repo.perms[1].PermissionName; got != tt.wantName {
+				t.Errorf("updated name = %q, want %q", got, tt.wantName)
+			}
+			if got := repo.perms[2].PermissionName; got != tt.wantName {
+				t.Errorf("created name = %q, want %q", got, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestGetPermissionCatalog(t *testing.T) {
+	tests := []struct {
+		name  string
+		perms []string
+		want  string
+	}{
+		{
+			name:  "grouped by resource",
+			perms: []string{"student:list", "gradejournal:view", "student:create", "gradejournal:create"},
+			want:  `{"gradejournal":["create","view"],"student":["create","list"]}`,
+		},
+		// имя без действия (из старых сидов) даёт ресурс с пустым списком
+		{"legacy name without action", []string{"reports", "student:list"}, `{"reports":[],"student":["list"]}`},
+		{"empty", nil, `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewPermissionHandler(newMemPermissionRepo(tt.perms...), &recordingAudit{})
+			rec := httptest.NewRecorder()
+			h.GetPermissionCatalog(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/permissions/catalog", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"service/internal/domain/models"
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
@@ -24,6 +25,7 @@ type RoleRepository interface {
 	UpdateRole(ctx context.Context, role *models.Role) error
 	DeleteRole(ctx context.Context, id int64) error
 	ListRole(ctx context.Context) ([]*models.Role, error)
+	ListRoleWithCounts(ctx context.Context) ([]*models.RoleWithCount, error)
 }
 
 type RoleHandler struct {
@@ -53,14 +55,13 @@ func (h *RoleHandler) CreateRole(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&role); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		id, err := h.repo.CreateRole(r.Context(), &role)
 		if err != nil {
 			log.Error("failed to create role", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create role"))
+			renderServerError(w, r, err, "failed to create role")
 			return
 		}
 		role.RoleID = id
@@ -72,6 +73,7 @@ func (h *RoleHandler) CreateRole(log *slog.Logger) http.HandlerFunc {
 			NewData:    utils.PtrToJSON(role),
 			Comment:    utils.PtrToStr("Role created"),
 		})
+		setLocation(w, "roles", role.RoleID)
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, role)
 	}
@@ -97,7 +99,7 @@ func (h *RoleHandler) GetRoleByID(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid role id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid role id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid role id"))
 			return
 		}
 		role, err := h.repo.GetRoleByID(r.Context(), id)
@@ -105,12 +107,11 @@ func (h *RoleHandler) GetRoleByID(log *slog.Logger) http.HandlerFunc {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("role not found", slog.Int64("id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("role not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "role not found"))
 				return
 			}
 			log.Error("failed to get role", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get role"))
+			renderServerError(w, r, err, "failed to get role")
 			return
 		}
 		render.JSON(w, r, role)
@@ -138,14 +139,14 @@ func (h *RoleHandler) UpdateRole(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid role id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		var role models.Role
 		if err := json.NewDecoder(r.Body).Decode(&role); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		role.RoleID = id
@@ -154,12 +155,11 @@ func (h *RoleHandler) UpdateRole(log *slog.Logger) http.HandlerFunc {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("role not found for update", slog.Int64("id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("role not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "role not found"))
 				return
 			}
 			log.Error("failed to update role", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update role"))
+			renderServerError(w, r, err, "failed to update role")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -196,20 +196,25 @@ func (h *RoleHandler) DeleteRole(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid role id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid role id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid role id"))
 			return
 		}
 		oldData, _ := h.repo.GetRoleByID(r.Context(), id)
 		if err := h.repo.DeleteRole(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("role is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("role", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("role not found for delete", slog.Int64("id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("role not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "role not found"))
 				return
 			}
 			log.Error("failed to delete role", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete role"))
+			renderServerError(w, r, err, "failed to delete role")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -239,10 +244,33 @@ func (h *RoleHandler) ListRoles(log *slog.Logger) http.HandlerFunc {
 		roles, err := h.repo.ListRole(r.Context())
 		if err != nil {
 			log.Error("failed to list roles", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list roles"))
+			renderServerError(w, r, err, "failed to list roles")
+			return
+		}
+		render.JSON(w, r, roles)
+	}
+}
+
+// @Summary Получить список ролей с числом пользователей
+// @Tags roles
+// @Produce json
+// @Success 200 {array} models.RoleWithCount
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/roles/with-counts [get]
+// @Security BearerAuth
+func (h *RoleHandler) ListRolesWithCounts(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.role.ListRolesWithCounts"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		roles, err := h.repo.ListRoleWithCounts(r.Context())
+		if err != nil {
+			log.Error("failed to list roles with counts", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list roles")
 			return
 		}
+		if roles == nil {
+			roles = []*models.RoleWithCount{}
+		}
 		render.JSON(w, r, roles)
 	}
 }
@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"strings"
+	"testing"
+)
+
+// countedRoles роли с числом пользователей
+type countedRoles struct {
+	RoleRepository
+	roles []*models.RoleWithCount
+}
+
+func (c countedRoles) ListRoleWithCounts(context.Context) ([]*models.RoleWithCount, error) {
+	return c.roles, nil
+}
+
+func TestListRolesWithCounts(t *testing.T) {
+	repo := countedRoles{roles: []*models.RoleWithCount{
+		{Role: models.Role{RoleID: 1, RoleName: "admin"}, UserCount: 2},
+		{Role: models.Role{RoleID: 2, RoleName: "auditor"}, UserCount: 0},
+	}}
+	rec := httptest.NewRecorder()
+	NewRoleHandler(repo, nil).ListRolesWithCounts(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/roles/with-counts", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	var counts []float64
+	for _, r := range got {
+		counts = append(counts, r["user_count"].(float64))
+	}
+	// нулевое число пользователей отдаётся явно
+	if !reflect.DeepEqual(counts, []float64{2, 0}) || got[0]["role_name"] != "admin" {
+		t.Errorf("roles = %v", got)
+	}
+
+	// без ролей — пустой массив, а не null
+	rec = httptest.NewRecorder()
+	NewRoleHandler(countedRoles{}, nil).ListRolesWithCounts(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/roles/with-counts", nil))
+	if strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("empty body = %s, want []", rec.Body)
+	}
+}
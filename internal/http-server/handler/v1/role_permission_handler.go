@@ -53,13 +53,12 @@ func (h *RolePermissionHandler) AssignPermission(log *slog.Logger) http.HandlerF
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		if err := h.repo.AssignPermission(r.Context(), input.RoleID, input.PermissionID); err != nil {
 			log.Error("failed to assign permission", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to assign permission"))
+			renderServerError(w, r, err, "failed to assign permission")
 			return
 		}
 		w.WriteHeader(http.StatusOK)
@@ -85,13 +84,12 @@ func (h *RolePermissionHandler) RemovePermission(log *slog.Logger) http.HandlerF
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		if err := h.repo.RemovePermission(r.Context(), input.RoleID, input.PermissionID); err != nil {
 			log.Error("failed to remove permission", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to remove permission"))
+			renderServerError(w, r, err, "failed to remove permission")
 			return
 		}
 		w.WriteHeader(http.StatusOK)
@@ -123,7 +121,7 @@ func (h RolePermissionHandler) GetPermissionsByRoleID(log *slog.Logger) http.Han
 		if err != nil {
 			log.Info("invalid role id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid role id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid role id"))
 			return
 		}
 		permissions, err := h.repo.GetPermissionsByRoleID(r.Context(), role_id)
@@ -131,12 +129,11 @@ func (h RolePermissionHandler) GetPermissionsByRoleID(log *slog.Logger) http.Han
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("permissions for role id not found", slog.Any("permissions", permissions))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("permissions for role id not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "permissions for role id not found"))
 				return
 			}
 			log.Error("failed to get permissions for role", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get permissions for role"))
+			renderServerError(w, r, err, "failed to get permissions for role")
 			return
 		}
 
@@ -10,6 +10,7 @@ import (
 	"service/internal/domain/models"
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
 	"time"
 
@@ -24,6 +25,8 @@ type SemesterRepository interface {
 	UpdateSemester(ctx context.Context, s *models.Semester) error
 	DeleteSemester(ctx context.Context, id int64) error
 	ListSemester(ctx context.Context, academicYearID *int64, fromDate, toDate *time.Time, limit, offset int) ([]*models.Semester, error)
+	CountSemester(ctx context.Context, academicYearID *int64, fromDate, toDate *time.Time) (int64, error)
+	GetCurrentSemester(ctx context.Context, at time.Time) (*models.Semester, error)
 }
 
 type SemesterHandler struct {
@@ -51,13 +54,12 @@ func (h *SemesterHandler) CreateSemester(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		if err := h.repo.CreateSemester(r.Context(), &s); err != nil {
 			log.Error("failed to create semester", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create semester"))
+			renderServerError(w, r, err, "failed to create semester")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -68,6 +70,7 @@ func (h *SemesterHandler) CreateSemester(log *slog.Logger) http.HandlerFunc {
 			NewData:    utils.PtrToJSON(s),
 			Comment:    utils.PtrToStr("Semestr created"),
 		})
+		setLocation(w, "semesters", s.SemesterID)
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, s)
 	}
@@ -90,7 +93,7 @@ func (h *SemesterHandler) GetSemesterByID(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid semester id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid semester id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid semester id"))
 			return
 		}
 		semester, err := h.repo.GetSemesterByID(r.Context(), id)
@@ -98,12 +101,11 @@ func (h *SemesterHandler) GetSemesterByID(log *slog.Logger) http.HandlerFunc {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("semester not found", slog.Int64("semester_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("semester not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "semester not found"))
 				return
 			}
 			log.Error("failed to get semester", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get semester"))
+			renderServerError(w, r, err, "failed to get semester")
 			return
 		}
 		render.JSON(w, r, semester)
@@ -128,14 +130,14 @@ func (h *SemesterHandler) UpdateSemester(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid semester id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid semester id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid semester id"))
 			return
 		}
 		var s models.Semester
 		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		s.SemesterID = id
@@ -144,12 +146,11 @@ func (h *SemesterHandler) UpdateSemester(log *slog.Logger) http.HandlerFunc {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("semester not found for update", slog.Int64("semester_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("semester not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "semester not found"))
 				return
 			}
 			log.Error("failed to update semester", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update semester"))
+			renderServerError(w, r, err, "failed to update semester")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -183,20 +184,25 @@ func (h *SemesterHandler) DeleteSemester(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid semester id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid semester id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid semester id"))
 			return
 		}
 		oldData, _ := h.repo.GetSemesterByID(r.Context(), id)
 		if err := h.repo.DeleteSemester(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("semester is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("semester", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("semester not found for delete", slog.Int64("semester_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("semester not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "semester not found"))
 				return
 			}
 			log.Error("failed to delete semester", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete semester"))
+			renderServerError(w, r, err, "failed to delete semester")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -218,9 +224,11 @@ func (h *SemesterHandler) DeleteSemester(log *slog.Logger) http.HandlerFunc {
 // @Param academic_year_id query int false "ID учебного года"
 // @Param from_date query string false "С даты (YYYY-MM-DD)"
 // @Param to_date query string false "По дату (YYYY-MM-DD)"
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.Semester
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/semesters [get]
 // @Security BearerAuth
 func (h *SemesterHandler) ListSemester(log *slog.Logger) http.HandlerFunc {
@@ -252,17 +260,17 @@ func (h *SemesterHandler) ListSemester(log *slog.Logger) http.HandlerFunc {
 			}
 		}
 
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
-		}
+		limit, offset := parsePagination(r)
 
+		if err := setTotalCount(w, r, func() (int64, error) { return h.repo.CountSemester(r.Context(), academicYearID, fromDate, toDate) }); err != nil {
+			log.Error("failed to count semesters", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list semesters")
+			return
+		}
 		semesters, err := h.repo.ListSemester(r.Context(), academicYearID, fromDate, toDate, limit, offset)
 		if err != nil {
 			log.Error("failed to list semesters", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list semesters"))
+			renderServerError(w, r, err, "failed to list semesters")
 			return
 		}
 		render.JSON(w, r, semesters)
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	resp "service/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type StatsRepository interface {
+	GetEntityCounts(ctx context.Context) (map[string]int64, error)
+}
+
+type StatsHandler struct {
+	repo StatsRepository
+}
+
+func NewStatsHandler(repo StatsRepository) *StatsHandler {
+	return &StatsHandler{repo: repo}
+}
+
+// @Summary Получить количество записей по сущностям
+// @Tags stats
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/stats/overview [get]
+// @Security BearerAuth
+func (h *StatsHandler) GetOverview(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.stats_handler.GetOverview"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		counts, err := h.repo.GetEntityCounts(r.Context())
+		if err != nil {
+			log.Error("failed to get entity counts", slog.String("err", err.Error()))
+			renderServerError(w, r, err, resp.MsgInternal)
+			return
+		}
+		render.JSON(w, r, counts)
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	"service/internal/http-server/middleware/permissions"
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -24,16 +29,60 @@ type StudentGroupRepository interface {
 	UpdateStudentGroup(ctx context.Context, group *models.StudentGroup) error
 	DeleteStudentGroup(ctx context.Context, id int64) error
 	ListStudentGroups(ctx context.Context, limit, offset int) ([]*models.StudentGroup, error)
+	CountStudentGroups(ctx context.Context) (int64, error)
 	ListStudentGroupPublic(ctx context.Context, limit, offset int) ([]*models.StudentGroupPublic, error)
+	MergeStudentGroups(ctx context.Context, fromID, toID int64, deleteSource bool) ([]int64, error)
+}
+
+type GroupRosterRepository interface {
+	ListStudentsByGroup(ctx context.Context, studentGroupID int64) ([]*models.StudentWithUser, error)
+	ListGroupRanking(ctx context.Context, studentGroupID int64, from, to *time.Time) ([]*models.GroupRankingEntry, error)
 }
 
 type StudentGroupHandler struct {
-	repo      StudentGroupRepository
-	auditRepo AuditLogRepository
+	repo        StudentGroupRepository
+	studentRepo GroupRosterRepository
+	auditRepo   AuditLogRepository
+}
+
+func NewStudentGroupHandler(repo StudentGroupRepository, studentRepo GroupRosterRepository, auditRepo AuditLogRepository) *StudentGroupHandler {
+	return &StudentGroupHandler{repo: repo, studentRepo: studentRepo, auditRepo: auditRepo}
+}
+
+// permStudentGroupManageAny позволяет изменять и удалять группы, где пользователь не куратор
+const permStudentGroupManageAny = "studentgroup:manage_any"
+
+// canManageGroup изменять группу может её куратор или владелец права studentgroup:manage_any
+func canManageGroup(r *http.Request, group *models.StudentGroup) bool {
+	if permissions.HasPermission(r, permStudentGroupManageAny) {
+		return true
+	}
+	userID, ok := ware.GetUserID(r)
+	return ok && userID == group.CuratorID
 }
 
-func NewStudentGroupHandler(repo StudentGroupRepository, auditRepo AuditLogRepository) *StudentGroupHandler {
-	return &StudentGroupHandler{repo: repo, auditRepo: auditRepo}
+// loadManagedGroup загружает группу и проверяет право на её изменение.
+// При отказе ответ уже записан и возвращается false.
+func (h *StudentGroupHandler) loadManagedGroup(w http.ResponseWriter, r *http.Request, log *slog.Logger, id int64) (*models.StudentGroup, bool) {
+	group, err := h.repo.GetStudentGroupByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Info("group not found", slog.Int64("student_group_id", id))
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.ErrorFor(r, "group not found"))
+			return nil, false
+		}
+		log.Error("failed to get group", slog.String("err", err.Error()))
+		renderServerError(w, r, err, "failed to get group")
+		return nil, false
+	}
+	if !canManageGroup(r, group) {
+		log.Info("user is not the group curator", slog.Int64("student_group_id", id))
+		w.WriteHeader(http.StatusForbidden)
+		render.JSON(w, r, resp.ErrorFor(r, resp.MsgForbidden))
+		return nil, false
+	}
+	return group, true
 }
 
 // @Summary Создать группу студентов
@@ -58,14 +107,13 @@ func (h *StudentGroupHandler) CreateStudentGroup(log *slog.Logger) http.HandlerF
 		if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 
 		if err := h.repo.CreateStudentGroup(r.Context(), &group); err != nil {
 			log.Error("failed to create student group", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create student group"))
+			renderServerError(w, r, err, "failed to create student group")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -76,6 +124,7 @@ func (h *StudentGroupHandler) CreateStudentGroup(log *slog.Logger) http.HandlerF
 			NewData:    utils.PtrToJSON(group),
 			Comment:    utils.PtrToStr("Student group created"),
 		})
+		setLocation(w, "student-groups", group.StudentGroupID)
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, group)
 	}
@@ -102,7 +151,7 @@ func (h *StudentGroupHandler) GetStudentGroupByID(log *slog.Logger) http.Handler
 		if err != nil {
 			log.Info("invalid student group id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid group id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid group id"))
 			return
 		}
 		group, err := h.repo.GetStudentGroupByID(r.Context(), id)
@@ -110,12 +159,11 @@ func (h *StudentGroupHandler) GetStudentGroupByID(log *slog.Logger) http.Handler
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("student group not found", slog.Int64("student_group_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("group not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "group not found"))
 				return
 			}
 			log.Error("failed to get student group", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get group"))
+			renderServerError(w, r, err, "failed to get group")
 			return
 		}
 		render.JSON(w, r, group)
@@ -143,7 +191,7 @@ func (h *StudentGroupHandler) GetStudentGroupPublicByID(log *slog.Logger) http.H
 		if err != nil {
 			log.Info("invalid group id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid group id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid group id"))
 			return
 		}
 		group, err := h.repo.GetStudentGroupPublicByID(r.Context(), id)
@@ -151,12 +199,11 @@ func (h *StudentGroupHandler) GetStudentGroupPublicByID(log *slog.Logger) http.H
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("student group not found", slog.Int64("student_group_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("group not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "group not found"))
 				return
 			}
 			log.Error("failed to get group public", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get group"))
+			renderServerError(w, r, err, "failed to get group")
 			return
 		}
 		render.JSON(w, r, group)
@@ -164,12 +211,14 @@ func (h *StudentGroupHandler) GetStudentGroupPublicByID(log *slog.Logger) http.H
 }
 
 // @Summary Обновить группу студентов
+// @Description Доступно куратору группы или пользователю с правом studentgroup:manage_any
 // @Tags student-groups
 // @Accept json
 // @Produce json
 // @Param id path int true "ID группы"
 // @Param input body models.StudentGroup true "Группа"
 // @Success 200 {object} models.StudentGroup
+// @Failure 403 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Router /api/v1/student-groups/{id} [put]
 // @Security BearerAuth
@@ -185,28 +234,30 @@ func (h *StudentGroupHandler) UpdateStudentGroup(log *slog.Logger) http.HandlerF
 		if err != nil {
 			log.Info("invalid group id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid group id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid group id"))
 			return
 		}
 		var group models.StudentGroup
 		if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		group.StudentGroupID = id
-		oldData, _ := h.repo.GetStudentGroupByID(r.Context(), id)
+		oldData, ok := h.loadManagedGroup(w, r, log, id)
+		if !ok {
+			return
+		}
 		if err := h.repo.UpdateStudentGroup(r.Context(), &group); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("group not found for update", slog.Int64("student_group_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("group not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "group not found"))
 				return
 			}
 			log.Error("failed to update group", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update group"))
+			renderServerError(w, r, err, "failed to update group")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -224,11 +275,13 @@ func (h *StudentGroupHandler) UpdateStudentGroup(log *slog.Logger) http.HandlerF
 }
 
 // @Summary Удалить группу студентов
+// @Description Доступно куратору группы или пользователю с правом studentgroup:manage_any
 // @Tags student-groups
 // @Accept json
 // @Produce json
 // @Param id path int true "ID группы"
 // @Success 204 {string} string "No Content"
+// @Failure 403 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Router /api/v1/student-groups/{id} [delete]
 // @Security BearerAuth
@@ -244,20 +297,28 @@ func (h *StudentGroupHandler) DeleteStudentGroup(log *slog.Logger) http.HandlerF
 		if err != nil {
 			log.Info("invalid group id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid group id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid group id"))
+			return
+		}
+		oldData, ok := h.loadManagedGroup(w, r, log, id)
+		if !ok {
 			return
 		}
-		oldData, _ := h.repo.GetStudentGroupByID(r.Context(), id)
 		if err := h.repo.DeleteStudentGroup(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("student group is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("student group", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("group not found for delete", slog.Int64("student_group_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("group not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "group not found"))
 				return
 			}
 			log.Error("failed to delete group", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete group"))
+			renderServerError(w, r, err, "failed to delete group")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -278,9 +339,11 @@ func (h *StudentGroupHandler) DeleteStudentGroup(log *slog.Logger) http.HandlerF
 // @Produce json
 // @Param curator_id query int false "ID куратора"
 // @Param academic_year_id query int false "ID учебного года"
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.StudentGroup
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/student-groups [get]
 // @Security BearerAuth
 func (h *StudentGroupHandler) ListStudentGroups(log *slog.Logger) http.HandlerFunc {
@@ -290,16 +353,16 @@ func (h *StudentGroupHandler) ListStudentGroups(log *slog.Logger) http.HandlerFu
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+		if err := setTotalCount(w, r, func() (int64, error) { return h.repo.CountStudentGroups(r.Context()) }); err != nil {
+			log.Error("failed to count student groups", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list student groups")
+			return
 		}
 		groups, err := h.repo.ListStudentGroups(r.Context(), limit, offset)
 		if err != nil {
 			log.Error("failed to list groups", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list groups"))
+			renderServerError(w, r, err, "failed to list groups")
 			return
 		}
 		render.JSON(w, r, groups)
@@ -310,9 +373,11 @@ func (h *StudentGroupHandler) ListStudentGroups(log *slog.Logger) http.HandlerFu
 // @Tags student-groups
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.StudentGroupPublic
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/student-groups/public [get]
 // @Security BearerAuth
 func (h *StudentGroupHandler) ListStudentGroupPublic(log *slog.Logger) http.HandlerFunc {
@@ -322,18 +387,237 @@ func (h *StudentGroupHandler) ListStudentGroupPublic(log *slog.Logger) http.Hand
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
+		limit, offset := parsePagination(r)
+		if err := setTotalCount(w, r, func() (int64, error) { return h.repo.CountStudentGroups(r.Context()) }); err != nil {
+			log.Error("failed to count student groups", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list student groups")
+			return
 		}
 		groups, err := h.repo.ListStudentGroupPublic(r.Context(), limit, offset)
 		if err != nil {
 			log.Error("failed to list groups public", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list groups public"))
+			renderServerError(w, r, err, "failed to list groups public")
 			return
 		}
 		render.JSON(w, r, groups)
 	}
 }
+
+// @Summary Выгрузить список студентов группы в CSV
+// @Tags student-groups
+// @Produce text/csv
+// @Param id path int true "ID группы"
+// @Success 200 {string} string "CSV"
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/student-groups/{id}/students.csv [get]
+// @Security BearerAuth
+func (h *StudentGroupHandler) ExportGroupStudentsCSV(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.studentgroup_handler.ExportGroupStudentsCSV"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid id"))
+			return
+		}
+
+		if _, err := h.repo.GetStudentGroupByID(r.Context(), id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "student group not found"))
+				return
+			}
+			log.Error("failed to get student group", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get student group")
+			return
+		}
+
+		students, err := h.studentRepo.ListStudentsByGroup(r.Context(), id)
+		if err != nil {
+			log.Error("failed to list group students", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list group students")
+			return
+		}
+
+		header := []string{"user_id", "last_name", "first_name", "middle_name", "birthday", "phone"}
+		rows := make([][]string, 0, len(students))
+		for _, s := range students {
+			rows = append(rows, []string{
+				strconv.FormatInt(int64(s.UserID), 10), s.LastName, s.FirstName, csvString(s.MiddleName),
+				s.Birthday.Format("2006-01-02"), s.Phone,
+			})
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="group-%d-students.csv"`, id))
+		if err := renderCSV(w, header, rows); err != nil {
+			log.Error("failed to write csv", slog.String("err", err.Error()))
+		}
+	}
+}
+
+// @Summary Рейтинг студентов группы
+// @Description Студенты группы по убыванию среднего балла за период. При равном среднем место общее (1, 1, 3), студенты без оценок идут в конце с rank = null
+// @Tags student-groups
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param from_date query string false "С даты (YYYY-MM-DD)"
+// @Param to_date query string false "По дату включительно (YYYY-MM-DD)"
+// @Success 200 {array} models.GroupRankingEntry
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/student-groups/{id}/ranking [get]
+// @Security BearerAuth
+func (h *StudentGroupHandler) GetGroupRanking(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.studentgroup_handler.GetGroupRanking"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid id"))
+			return
+		}
+		from, ok := parseDateParam(r, "from_date")
+		if !ok {
+			log.Info("invalid from_date", slog.String("from_date", r.URL.Query().Get("from_date")))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid from_date"))
+			return
+		}
+		to, ok := parseDateParam(r, "to_date")
+		if !ok {
+			log.Info("invalid to_date", slog.String("to_date", r.URL.Query().Get("to_date")))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid to_date"))
+			return
+		}
+		if from != nil && to != nil && from.After(*to) {
+			log.Info("from_date is after to_date")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "from_date must not be after to_date"))
+			return
+		}
+
+		if _, err := h.repo.GetStudentGroupByID(r.Context(), id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "student group not found"))
+				return
+			}
+			log.Error("failed to get student group", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get student group")
+			return
+		}
+
+		items, err := h.studentRepo.ListGroupRanking(r.Context(), id, from, to)
+		if err != nil {
+			log.Error("failed to get group ranking", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get group ranking")
+			return
+		}
+		if items == nil {
+			items = []*models.GroupRankingEntry{}
+		}
+		render.JSON(w, r, items)
+	}
+}
+
+// @Summary Объединить группы
+// @Description Переводит всех студентов из from_group_id в to_group_id одной транзакцией, при delete_source удаляет исходную группу. Нужно быть куратором обеих групп или иметь studentgroup:manage_any
+// @Tags student-groups
+// @Accept json
+// @Produce json
+// @Param input body models.StudentGroupMergeRequest true "Исходная и целевая группа"
+// @Success 200 {object} models.StudentGroupMergeResponse
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/student-groups/merge [post]
+// @Security BearerAuth
+func (h *StudentGroupHandler) MergeStudentGroups(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.studentgroup_handler.MergeStudentGroups"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		var req models.StudentGroupMergeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		if req.FromGroupID == 0 || req.ToGroupID == 0 || req.FromGroupID == req.ToGroupID {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "from_group_id and to_group_id must be different and non-empty"))
+			return
+		}
+		if req.DeleteSource && !permissions.HasPermission(r, "studentgroup:delete") {
+			log.Info("delete_source requires studentgroup:delete")
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgForbidden))
+			return
+		}
+
+		// перенос опустошает исходную группу и меняет состав целевой,
+		// поэтому нужно право на изменение обеих
+		for _, id := range []int64{req.FromGroupID, req.ToGroupID} {
+			if _, ok := h.loadManagedGroup(w, r, log, id); !ok {
+				return
+			}
+		}
+
+		ids, err := h.repo.MergeStudentGroups(r.Context(), req.FromGroupID, req.ToGroupID, req.DeleteSource)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				// группу удалили между проверкой и переносом
+				log.Info("group not found during merge")
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "group not found"))
+				return
+			}
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("source group is referenced", slog.Int64("group_id", req.FromGroupID))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("student group", err)))
+				return
+			}
+			log.Error("failed to merge groups", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to merge groups")
+			return
+		}
+
+		for _, id := range ids {
+			_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+				UserID:     utils.GetUserIDFromContext(r.Context()),
+				TableName:  "student",
+				RowID:      id,
+				ActionType: "UPDATE",
+				Changes: utils.PtrToJSON(map[string]utils.FieldChange{
+					"student_group_id": {Old: req.FromGroupID, New: req.ToGroupID},
+				}),
+				Comment: utils.PtrToStr("Student moved by group merge"),
+			})
+		}
+		if req.DeleteSource {
+			_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+				UserID:     utils.GetUserIDFromContext(r.Context()),
+				TableName:  "student_group",
+				RowID:      req.FromGroupID,
+				ActionType: "DELETE",
+				Comment:    utils.PtrToStr(fmt.Sprintf("Student group merged into %d", req.ToGroupID)),
+			})
+		}
+
+		if ids == nil {
+			ids = []int64{}
+		}
+		render.JSON(w, r, models.StudentGroupMergeResponse{Moved: len(ids), StudentIDs: ids, SourceDeleted: req.DeleteSource})
+	}
+}
@@ -0,0 +1,310 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memGroupRepo группы и состав групп в памяти
+type memGroupRepo struct {
+	StudentGroupRepository
+	groups  map[int64]*models.StudentGroup
+	members map[int64]int64 // студент -> группа
+	updated []int64
+}
+
+func newMemGroupRepo(groups ...*models.StudentGroup) *memGroupRepo {
+	repo := &memGroupRepo{groups: map[int64]*models.StudentGroup{}, members: map[int64]int64{}}
+	for _, g := range groups {
+		repo.groups[g.StudentGroupID] = g
+	}
+	return repo
+}
+
+func (m *memGroupRepo) GetStudentGroupByID(_ context.Context, id int64) (*models.StudentGroup, error) {
+	g, ok := m.groups[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return g, nil
+}
+
+func (m *memGroupRepo) UpdateStudentGroup(_ context.Context, g *models.StudentGroup) error {
+	if _, ok := m.groups[g.StudentGroupID]; !ok {
+		return sql.ErrNoRows
+	}
+	m.groups[g.StudentGroupID] = g
+	m.updated = append(m.updated, g.StudentGroupID)
+	return nil
+}
+
+func (m *memGroupRepo) MergeStudentGroups(_ context.Context, fromID, toID int64, deleteSource bool) ([]int64, error) {
+	if _, ok := m.groups[fromID]; !ok {
+		return nil, sql.ErrNoRows
+	}
+	if _, ok := m.groups[toID]; !ok {
+		return nil, sql.ErrNoRows
+	}
+	var ids []int64
+	for student, group := range m.members {
+		if group == fromID {
+			m.members[student] = toID
+			ids = append(ids, student)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if deleteSource {
+		delete(m.groups, fromID)
+	}
+	return ids, nil
+}
+
+// groupRoster студенты групп для списков и выгрузок
+type groupRoster map[int64][]*models.StudentWithUser
+
+func (g groupRoster) ListStudentsByGroup(_ context.Context, groupID int64) ([]*models.StudentWithUser, error) {
+	return g[groupID], nil
+}
+
+func (g groupRoster) ListGroupRanking(context.Context, int64, *time.Time, *time.Time) ([]*models.GroupRankingEntry, error) {
+	return nil, nil
+}
+
+// rankedRoster рейтинг групп в том порядке, в каком его отдаёт репозиторий
+type rankedRoster struct {
+	groupRoster
+	ranking  map[int64][]*models.GroupRankingEntry
+	from, to *time.Time
+}
+
+func (r *rankedRoster) ListGroupRanking(_ context.Context, groupID int64, from, to *time.Time) ([]*models.GroupRankingEntry, error) {
+	r.from, r.to = from, to
+	return r.ranking[groupID], nil
+}
+
+// группы 1 и 2 курирует преподаватель 10, группу 3 — преподаватель 20
+func newGroupFixture() (*memGroupRepo, *StudentGroupHandler, *recordingAudit) {
+	repo := newMemGroupRepo(
+		&models.StudentGroup{StudentGroupID: 1, StudentGroupName: "ИВТ-21", CuratorID: 10, AcademicYearID: 1},
+		&models.StudentGroup{StudentGroupID: 2, StudentGroupName: "ИВТ-22", CuratorID: 10, AcademicYearID: 1},
+		&models.StudentGroup{StudentGroupID: 3, StudentGroupName: "ПИ-21", CuratorID: 20, AcademicYearID: 1},
+	)
+	repo.members[7] = 1
+	repo.members[8] = 1
+	repo.members[9] = 2
+	audit := &recordingAudit{}
+	return repo, NewStudentGroupHandler(repo, nil, audit), audit
+}
+
+func mergeGroups(t *testing.T, h *StudentGroupHandler, userID int64, body string, perms ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/student-groups/merge", strings.NewReader(body)), userID, perms...)
+	rec := httptest.NewRecorder()
+	h.MergeStudentGroups(discardLogger())(rec, r)
+	return rec
+}
+
+func TestMergeStudentGroups(t *testing.T) {
+	repo, h, audit := newGroupFixture()
+
+	rec := mergeGroups(t, h, 10, `{"from_group_id":1,"to_group_id":2,"delete_source":true}`, "studentgroup:delete")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got models.StudentGroupMergeResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := models.StudentGroupMergeResponse{Moved: 2, StudentIDs: []int64{7, 8}, SourceDeleted: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("response = %+v, want %+v", got, want)
+	}
+	if repo.members[7] != 2 || repo.members[8] != 2 || repo.members[9] != 2 {
+		t.Errorf("members = %v, want all in group 2", repo.members)
+	}
+	if _, ok := repo.groups[1]; ok {
+		t.Error("source group not deleted")
+	}
+	// по записи на каждого переведённого студента и одна на удаление группы
+	if len(audit.entries) != 3 || audit.entries[2].TableName != "student_group" || audit.entries[2].ActionType != "DELETE" {
+		t.Errorf("audit entries = %+v", audit.entries)
+	}
+}
+
+func TestMergeStudentGroups_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		body   string
+		perms  []string
+		want   int
+	}{
+		{"missing source", 10, `{"from_group_id":404,"to_group_id":2}`, nil, http.StatusNotFound},
+		{"missing target", 10, `{"from_group_id":1,"to_group_id":404}`, nil, http.StatusNotFound},
+		{"into itself", 10, `{"from_group_id":1,"to_group_id":1}`, nil, http.StatusBadRequest},
+		{"no source", 10, `{"to_group_id":2}`, nil, http.StatusBadRequest},
+		{"not curator of target", 10, `{"from_group_id":1,"to_group_id":3}`, nil, http.StatusForbidden},
+		{"delete_source without permission", 10, `{"from_group_id":1,"to_group_id":2,"delete_source":true}`, nil, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, h, audit := newGroupFixture()
+			rec := mergeGroups(t, h, tt.userID, tt.body, tt.perms...)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if repo.members[7] != 1 || repo.members[8] != 1 || len(repo.groups) != 3 {
+				t.Errorf("groups changed despite %d: members %v", rec.Code, repo.members)
+			}
+			if len(audit.entries) != 0 {
+				t.Errorf("audit entries = %+v, want none", audit.entries)
+			}
+		})
+	}
+}
+
+func TestUpdateStudentGroup_Curator(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int64
+		perms  []string
+		want   int
+	}{
+		{"curator", 10, nil, http.StatusOK},
+		{"other teacher", 20, nil, http.StatusForbidden},
+		{"manage_any", 99, []string{permStudentGroupManageAny}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, h, audit := newGroupFixture()
+			body := strings.NewReader(`{"student_group_name":"ИВТ-21а","curator_id":10,"academic_year_id":1}`)
+			r := authorize(t, httptest.NewRequest(http.MethodPut, "/api/v1/student-groups/1", body), tt.userID, tt.perms...)
+			rec := httptest.NewRecorder()
+			h.UpdateStudentGroup(discardLogger())(rec, withURLParams(r, "id", "1"))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			updated := len(repo.updated) == 1
+			if updated != (tt.want == http.StatusOK) {
+				t.Fatalf("updated = %v with status %d", updated, rec.Code)
+			}
+			if updated && (repo.groups[1].StudentGroupName != "ИВТ-21а" || len(audit.entries) != 1) {
+				t.Errorf("group = %+v, audit entries = %d", repo.groups[1], len(audit.entries))
+			}
+		})
+	}
+}
+
+func TestExportGroupStudentsCSV(t *testing.T) {
+	middle := "Петрович"
+	roster := groupRoster{1: {
+		{Student: models.Student{UserID: 7, Phone: "+79001234567", Birthday: time.Date(2003, 5, 1, 0, 0, 0, 0, time.UTC), StudentGroupID: 1},
+			FirstName: "Иван", LastName: "Иванов", MiddleName: &middle},
+		{Student: models.Student{UserID: 8, Phone: "+79007654321", Birthday: time.Date(2004, 1, 15, 0, 0, 0, 0, time.UTC), StudentGroupID: 1},
+			FirstName: "Анна", LastName: "Смирнова, мл."},
+	}}
+	repo, _, audit := newGroupFixture()
+	h := NewStudentGroupHandler(repo, roster, audit)
+
+	tests := []struct {
+		id       string
+		want     int
+		wantRows [][]string
+	}{
+		{"1", http.StatusOK, [][]string{
+			{"user_id", "last_name", "first_name", "middle_name", "birthday", "phone"},
+			{"7", "Иванов", "Иван", "Петрович", "2003-05-01", "+79001234567"},
+			{"8", "Смирнова, мл.", "Анна", "", "2004-01-15", "+79007654321"},
+		}},
+		// группа без студентов — только заголовок
+		{"2", http.StatusOK, [][]string{{"user_id", "last_name", "first_name", "middle_name", "birthday", "phone"}}},
+		{"404", http.StatusNotFound, nil},
+		{"x", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/student-groups/"+tt.id+"/students.csv", nil)
+			rec := httptest.NewRecorder()
+			h.ExportGroupStudentsCSV(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+				t.Errorf("Content-Type = %q, want text/csv", ct)
+			}
+			if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename="group-`+tt.id+`-students.csv"` {
+				t.Errorf("Content-Disposition = %q", cd)
+			}
+			rows, err := csv.NewReader(rec.Body).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("csv = %q, want %q", rows, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestGetGroupRanking(t *testing.T) {
+	rank := func(n int64) *int64 { return &n }
+	avg := func(v float64) *float64 { return &v }
+	roster := &rankedRoster{ranking: map[int64][]*models.GroupRankingEntry{1: {
+		{Rank: rank(1), StudentID: 8, LastName: "Антонов", AverageGrade: avg(4.5), GradesCount: 2},
+		{Rank: rank(1), StudentID: 7, LastName: "Борисов", AverageGrade: avg(4.5), GradesCount: 4},
+		{Rank: rank(3), StudentID: 9, LastName: "Васильев", AverageGrade: avg(3), GradesCount: 1},
+		{StudentID: 10, LastName: "Григорьев"},
+	}}}
+	repo, _, audit := newGroupFixture()
+	h := NewStudentGroupHandler(repo, roster, audit)
+
+	tests := []struct {
+		id, query string
+		want      int
+		body      string
+	}{
+		// общее место при равном среднем, следующее пропускается; без оценок — в конце с null
+		{"1", "", http.StatusOK, `[{"rank":1,"student_id":8,"first_name":"","last_name":"Антонов","average_grade":4.5,"grades_count":2},` +
+			`{"rank":1,"student_id":7,"first_name":"","last_name":"Борисов","average_grade":4.5,"grades_count":4},` +
+			`{"rank":3,"student_id":9,"first_name":"","last_name":"Васильев","average_grade":3,"grades_count":1},` +
+			`{"rank":null,"student_id":10,"first_name":"","last_name":"Григорьев","average_grade":null,"grades_count":0}]`},
+		{"2", "", http.StatusOK, `[]`},
+		{"404", "", http.StatusNotFound, ""},
+		{"x", "", http.StatusBadRequest, ""},
+		{"1", "?from_date=2024-10-01&to_date=2024-09-01", http.StatusBadRequest, ""},
+		{"1", "?from_date=first", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id+tt.query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/student-groups/"+tt.id+"/ranking"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			h.GetGroupRanking(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.body != "" && strings.TrimSpace(rec.Body.String()) != tt.body {
+				t.Errorf("body = %s\nwant   %s", rec.Body, tt.body)
+			}
+		})
+	}
+
+	// период передаётся в репозиторий как есть, граница to_date включается там
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/student-groups/1/ranking?from_date=2024-09-01&to_date=2024-09-30", nil)
+	h.GetGroupRanking(discardLogger())(httptest.NewRecorder(), withURLParams(r, "id", "1"))
+	if roster.from == nil || !roster.from.Equal(day("2024-09-01")) || roster.to == nil || !roster.to.Equal(day("2024-09-30")) {
+		t.Errorf("period = %v..%v", roster.from, roster.to)
+	}
+}
@@ -3,37 +3,145 @@ package v1
 import (
 	"context"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	"service/internal/http-server/middleware/permissions"
 	resp "service/internal/lib/api/response"
+	"service/internal/lib/phone"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type StudentRepository interface {
 	CreateStudent(ctx context.Context, student *models.Student) error
+	CreateUserWithStudent(ctx context.Context, user *models.User, student *models.Student) error
 	GetStudentByID(ctx context.Context, userID int64) (*models.Student, error)
+	GetStudentWithUserByID(ctx context.Context, userID int64) (*models.StudentWithUser, error)
+	PatchStudent(ctx context.Context, userID int64, patch *models.StudentPatch) error
 	GetStudentPublicByID(ctx context.Context, userID int64) (*models.StudentPublic, error)
 	UpdateStudent(ctx context.Context, student *models.Student) error
 	DeleteStudent(ctx context.Context, userID int64) error
-	ListStudent(ctx context.Context, limit, offset int) ([]*models.Student, error)
-	ListStudentPublic(ctx context.Context, limit, offset int) ([]*models.StudentPublic, error)
+	ListStudentWithFilters(ctx context.Context, studentGroupID *int64, fromDate, toDate, updatedSince *time.Time, limit, offset int) ([]*models.Student, error)
+	ListStudentPublicWithFilters(ctx context.Context, studentGroupID *int64, fromDate, toDate, updatedSince *time.Time, limit, offset int) ([]*models.StudentPublic, error)
 }
 
+// StudentChecker нужен вложенным ресурсам студента, чтобы отличать
+// "студента нет" (404) от "у студента нет записей" (200 и пустой список)
+type StudentChecker interface {
+	StudentExists(ctx context.Context, userID int64) (bool, error)
+}
+
+// StudentGroupGetter и CurrentAcademicYearGetter нужны для проверки учебного года группы студента
+type StudentGroupGetter interface {
+	GetStudentGroupByID(ctx context.Context, id int64) (*models.StudentGroup, error)
+}
+
+type CurrentAcademicYearGetter interface {
+	GetCurrentAcademicYear(ctx context.Context, at time.Time) (*models.AcademicYear, error)
+}
+
+// StudentYearPolicy настройки проверки учебного года группы при создании и изменении студента
+type StudentYearPolicy struct {
+	Enabled bool
+	// CurrentAcademicYearID фиксированный текущий год, 0 — по дате из academic_year
+	CurrentAcademicYearID int64
+}
+
+// permStudentAnyAcademicYear позволяет записать студента в группу любого учебного года
+const permStudentAnyAcademicYear = "student:any_academic_year"
+
+// maxImportSize ограничение на размер загружаемого CSV
+const maxImportSize = 5 << 20
+
+// обязательные колонки CSV импорта, middle_name и password — необязательные
+var studentImportColumns = []string{"first_name", "last_name", "email", "phone", "birthday", "student_group_id"}
+
 type StudentHandler struct {
-	repo      StudentRepository
-	auditRepo AuditLogRepository
+	repo           StudentRepository
+	disciplineRepo DisciplineRepository
+	groupRepo      StudentGroupGetter
+	yearRepo       CurrentAcademicYearGetter
+	auditRepo      AuditLogRepository
+	yearPolicy     StudentYearPolicy
+	phones         phone.Normalizer
+	maxBatchSize   int
+}
+
+func NewStudentHandler(
+	repo StudentRepository,
+	disciplineRepo DisciplineRepository,
+	groupRepo StudentGroupGetter,
+	yearRepo CurrentAcademicYearGetter,
+	auditRepo AuditLogRepository,
+	yearPolicy StudentYearPolicy,
+	phones phone.Normalizer,
+	maxBatchSize int,
+) *StudentHandler {
+	return &StudentHandler{
+		repo:           repo,
+		disciplineRepo: disciplineRepo,
+		groupRepo:      groupRepo,
+		yearRepo:       yearRepo,
+		auditRepo:      auditRepo,
+		yearPolicy:     yearPolicy,
+		phones:         phones,
+		maxBatchSize:   maxBatchSize,
+	}
 }
 
-func NewStudentHandler(repo StudentRepository, auditRepo AuditLogRepository) *StudentHandler {
-	return &StudentHandler{repo: repo, auditRepo: auditRepo}
+// validateGroupYear проверяет, что группа относится к ожидаемому учебному году.
+// Ожидаемый год берётся из ?academic_year_id, затем из конфига, затем по текущей дате;
+// если год определить нельзя, проверка пропускается.
+// Возвращает текст ошибки для 422 или пустую строку.
+func (h *StudentHandler) validateGroupYear(r *http.Request, groupID int64) (string, error) {
+	if !h.yearPolicy.Enabled || groupID == 0 || permissions.HasPermission(r, permStudentAnyAcademicYear) {
+		return "", nil
+	}
+
+	expected := h.yearPolicy.CurrentAcademicYearID
+	if val := r.URL.Query().Get("academic_year_id"); val != "" {
+		id, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return "invalid academic_year_id", nil
+		}
+		expected = id
+	}
+	if expected == 0 {
+		year, err := h.yearRepo.GetCurrentAcademicYear(r.Context(), time.Now())
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		expected = year.AcademicYearID
+	}
+
+	group, err := h.groupRepo.GetStudentGroupByID(r.Context(), groupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "student group not found", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if group.AcademicYearID != expected {
+		return "student group belongs to a different academic year", nil
+	}
+	return "", nil
 }
 
 // @Summary Создать студента
@@ -41,8 +149,10 @@ func NewStudentHandler(repo StudentRepository, auditRepo AuditLogRepository) *St
 // @Accept json
 // @Produce json
 // @Param input body models.Student true "Студент"
+// @Param academic_year_id query int false "Ожидаемый учебный год группы (по умолчанию текущий)"
 // @Success 201 {object} models.Student
 // @Failure 400 {object} resp.Response
+// @Failure 422 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Router /api/v1/students [post]
 // @Security BearerAuth
@@ -57,23 +167,49 @@ func (h *StudentHandler) CreateStudent(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&student); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		normalized, err := h.phones.Normalize(student.Phone)
+		if err != nil {
+			log.Info("invalid phone number")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid phone number"))
+			return
+		}
+		student.Phone = normalized
+		msg, err := h.validateGroupYear(r, student.StudentGroupID)
+		if err != nil {
+			log.Error("failed to validate student group academic year", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to create student")
+			return
+		}
+		if msg != "" {
+			log.Info("student group academic year mismatch", slog.String("reason", msg))
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			render.JSON(w, r, resp.ErrorFor(r, msg))
 			return
 		}
 		if err := h.repo.CreateStudent(r.Context(), &student); err != nil {
+			if errors.Is(err, storage.ErrMissingReference) {
+				log.Info("student references missing record", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, missingReferenceMessage(err)))
+				return
+			}
 			log.Error("failed to create student", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create student"))
+			renderServerError(w, r, err, "failed to create student")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
 			UserID:     utils.GetUserIDFromContext(r.Context()),
 			TableName:  "student",
-			RowID:      student.UserID,
+			RowID:      int64(student.UserID),
 			ActionType: "CREATE",
 			NewData:    utils.PtrToJSON(student),
 			Comment:    utils.PtrToStr("Student created"),
 		})
+		setLocation(w, "students", int64(student.UserID))
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, student)
 	}
@@ -84,7 +220,9 @@ func (h *StudentHandler) CreateStudent(log *slog.Logger) http.HandlerFunc {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID студента"
+// @Param expand query string false "Дополнительные поля (user)"
 // @Success 200 {object} models.Student
+// @Success 200 {object} models.StudentWithUser
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -102,20 +240,25 @@ func (h *StudentHandler) GetStudentByID(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid student id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid student id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
 			return
 		}
-		student, err := h.repo.GetStudentByID(r.Context(), id)
+		// по умолчанию ответ без данных пользователя, join только по ?expand=user
+		var student interface{}
+		if r.URL.Query().Get("expand") == "user" {
+			student, err = h.repo.GetStudentWithUserByID(r.Context(), id)
+		} else {
+			student, err = h.repo.GetStudentByID(r.Context(), id)
+		}
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("student not found", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("student not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "student not found"))
 				return
 			}
 			log.Error("failed to get student", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get student"))
+			renderServerError(w, r, err, "failed to get student")
 			return
 		}
 		render.JSON(w, r, student)
@@ -143,7 +286,7 @@ func (h *StudentHandler) GetStudentPublicByID(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid student id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid student id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
 			return
 		}
 		student, err := h.repo.GetStudentPublicByID(r.Context(), id)
@@ -151,12 +294,11 @@ func (h *StudentHandler) GetStudentPublicByID(log *slog.Logger) http.HandlerFunc
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("student not found", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("student not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "student not found"))
 				return
 			}
 			log.Error("failed to get student public", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get student public"))
+			renderServerError(w, r, err, "failed to get student public")
 			return
 		}
 		render.JSON(w, r, student)
@@ -169,9 +311,11 @@ func (h *StudentHandler) GetStudentPublicByID(log *slog.Logger) http.HandlerFunc
 // @Produce json
 // @Param id path int true "ID студента"
 // @Param input body models.Student true "Студент"
+// @Param academic_year_id query int false "Ожидаемый учебный год группы (по умолчанию текущий)"
 // @Success 200 {object} models.Student
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 422 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Router /api/v1/students/{id} [put]
 // @Security BearerAuth
@@ -187,28 +331,56 @@ func (h *StudentHandler) UpdateStudent(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid student id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid student id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
 			return
 		}
 		var student models.Student
 		if err := json.NewDecoder(r.Body).Decode(&student); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		normalized, err := h.phones.Normalize(student.Phone)
+		if err != nil {
+			log.Info("invalid phone number")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid phone number"))
 			return
 		}
-		student.UserID = id
+		student.Phone = normalized
+		student.UserID = models.ID(id)
 		oldData, _ := h.repo.GetStudentByID(r.Context(), id)
+		// группу проверяем только при переводе, иначе старые студенты не смогли бы править телефон
+		if oldData == nil || oldData.StudentGroupID != student.StudentGroupID {
+			msg, err := h.validateGroupYear(r, student.StudentGroupID)
+			if err != nil {
+				log.Error("failed to validate student group academic year", slog.String("err", err.Error()))
+				renderServerError(w, r, err, "failed to update student")
+				return
+			}
+			if msg != "" {
+				log.Info("student group academic year mismatch", slog.String("reason", msg))
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				render.JSON(w, r, resp.ErrorFor(r, msg))
+				return
+			}
+		}
 		if err := h.repo.UpdateStudent(r.Context(), &student); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("student not found for update", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("student not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "student not found"))
+				return
+			}
+			if errors.Is(err, storage.ErrMissingReference) {
+				log.Info("student references missing record", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, missingReferenceMessage(err)))
 				return
 			}
 			log.Error("failed to update student", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update student"))
+			renderServerError(w, r, err, "failed to update student")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -218,6 +390,7 @@ func (h *StudentHandler) UpdateStudent(log *slog.Logger) http.HandlerFunc {
 			ActionType: "UPDATE",
 			NewData:    utils.PtrToJSON(student),
 			OldData:    utils.PtrToJSON(oldData),
+			Changes:    utils.PtrToJSONDiff(oldData, student),
 			Comment:    utils.PtrToStr("Student updated"),
 		})
 		w.WriteHeader(http.StatusOK)
@@ -225,6 +398,111 @@ func (h *StudentHandler) UpdateStudent(log *slog.Logger) http.HandlerFunc {
 	}
 }
 
+// @Summary Частично обновить данные студента
+// @Description Обновляются только переданные поля
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param id path int true "ID студента"
+// @Param input body models.StudentPatch true "Изменяемые поля"
+// @Param academic_year_id query int false "Ожидаемый учебный год группы (по умолчанию текущий)"
+// @Success 200 {object} models.Student
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 422 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/students/{id} [patch]
+// @Security BearerAuth
+func (h *StudentHandler) PatchStudent(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.student_handler.PatchStudent"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid student id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
+			return
+		}
+		var patch models.StudentPatch
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		if patch.Phone != nil {
+			normalized, err := h.phones.Normalize(*patch.Phone)
+			if err != nil {
+				log.Info("invalid phone number")
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid phone number"))
+				return
+			}
+			patch.Phone = &normalized
+		}
+
+		oldData, err := h.repo.GetStudentByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("student not found for patch", slog.Int64("user_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "student not found"))
+				return
+			}
+			log.Error("failed to get student", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update student")
+			return
+		}
+		if patch.StudentGroupID != nil && *patch.StudentGroupID != oldData.StudentGroupID {
+			msg, err := h.validateGroupYear(r, *patch.StudentGroupID)
+			if err != nil {
+				log.Error("failed to validate student group academic year", slog.String("err", err.Error()))
+				renderServerError(w, r, err, "failed to update student")
+				return
+			}
+			if msg != "" {
+				log.Info("student group academic year mismatch", slog.String("reason", msg))
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				render.JSON(w, r, resp.ErrorFor(r, msg))
+				return
+			}
+		}
+		if err := h.repo.PatchStudent(r.Context(), id, &patch); err != nil {
+			if errors.Is(err, storage.ErrMissingReference) {
+				log.Info("student references missing record", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, missingReferenceMessage(err)))
+				return
+			}
+			log.Error("failed to patch student", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update student")
+			return
+		}
+		student, err := h.repo.GetStudentByID(r.Context(), id)
+		if err != nil {
+			log.Error("failed to get student", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update student")
+			return
+		}
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "student",
+			RowID:      id,
+			ActionType: "UPDATE",
+			NewData:    utils.PtrToJSON(student),
+			OldData:    utils.PtrToJSON(oldData),
+			Changes:    utils.PtrToJSONDiff(oldData, student),
+			Comment:    utils.PtrToStr("Student patched"),
+		})
+		render.JSON(w, r, student)
+	}
+}
+
 // @Summary Удалить студента
 // @Tags students
 // @Accept json
@@ -233,6 +511,7 @@ func (h *StudentHandler) UpdateStudent(log *slog.Logger) http.HandlerFunc {
 // @Success 204 {string} string "No Content"
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response "У студента есть оценки или посещаемость"
 // @Failure 500 {object} resp.Response
 // @Router /api/v1/students/{id} [delete]
 // @Security BearerAuth
@@ -248,20 +527,25 @@ func (h *StudentHandler) DeleteStudent(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid student id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid student id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid student id"))
 			return
 		}
 		oldData, _ := h.repo.GetStudentByID(r.Context(), id)
 		if err := h.repo.DeleteStudent(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("student is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("student", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("student not found for delete", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("student not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "student not found"))
 				return
 			}
 			log.Error("failed to delete student", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete student"))
+			renderServerError(w, r, err, "failed to delete student")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -280,7 +564,11 @@ func (h *StudentHandler) DeleteStudent(log *slog.Logger) http.HandlerFunc {
 // @Tags students
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param student_group_id query int false "ID группы"
+// @Param from_date query string false "С даты (YYYY-MM-DD)"
+// @Param to_date query string false "По дату (YYYY-MM-DD)"
+// @Param updated_since query string false "Изменённые начиная с (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
 // @Success 200 {array} models.Student
 // @Failure 500 {object} resp.Response
@@ -293,16 +581,12 @@ func (h *StudentHandler) ListStudent(log *slog.Logger) http.HandlerFunc {
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
-		}
-		students, err := h.repo.ListStudent(r.Context(), limit, offset)
+		limit, offset := parsePagination(r)
+		studentGroupID, fromDate, toDate := parseStudentFilters(r)
+		students, err := h.repo.ListStudentWithFilters(r.Context(), studentGroupID, fromDate, toDate, parseUpdatedSince(r), limit, offset)
 		if err != nil {
 			log.Error("failed to list students", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list students"))
+			renderServerError(w, r, err, "failed to list students")
 			return
 		}
 		render.JSON(w, r, students)
@@ -313,7 +597,11 @@ func (h *StudentHandler) ListStudent(log *slog.Logger) http.HandlerFunc {
 // @Tags students
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param student_group_id query int false "ID группы"
+// @Param from_date query string false "С даты (YYYY-MM-DD)"
+// @Param to_date query string false "По дату (YYYY-MM-DD)"
+// @Param updated_since query string false "Изменённые начиная с (RFC3339 или YYYY-MM-DD)"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
 // @Success 200 {array} models.StudentPublic
 // @Router /api/v1/students/public [get]
@@ -325,18 +613,281 @@ func (h *StudentHandler) ListStudentPublic(log *slog.Logger) http.HandlerFunc {
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		if limit == 0 {
-			limit = 20
-		}
-		students, err := h.repo.ListStudentPublic(r.Context(), limit, offset)
+		limit, offset := parsePagination(r)
+		studentGroupID, fromDate, toDate := parseStudentFilters(r)
+		students, err := h.repo.ListStudentPublicWithFilters(r.Context(), studentGroupID, fromDate, toDate, parseUpdatedSince(r), limit, offset)
 		if err != nil {
 			log.Error("failed to list students public", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list students public"))
+			renderServerError(w, r, err, "failed to list students public")
 			return
 		}
 		render.JSON(w, r, students)
 	}
 }
+
+func parseStudentFilters(r *http.Request) (studentGroupID *int64, fromDate, toDate *time.Time) {
+	studentGroupIDStr := r.URL.Query().Get("student_group_id")
+	if studentGroupIDStr != "" {
+		id, err := strconv.ParseInt(studentGroupIDStr, 10, 64)
+		if err == nil {
+			studentGroupID = &id
+		}
+	}
+	fromDateStr := r.URL.Query().Get("from_date")
+	if fromDateStr != "" {
+		d, err := time.Parse("2006-01-02", fromDateStr)
+		if err == nil {
+			fromDate = &d
+		}
+	}
+	toDateStr := r.URL.Query().Get("to_date")
+	if toDateStr != "" {
+		d, err := time.Parse("2006-01-02", toDateStr)
+		if err == nil {
+			toDate = &d
+		}
+	}
+	return studentGroupID, fromDate, toDate
+}
+
+// @Summary Дисциплины группы текущего студента
+// @Tags students
+// @Produce json
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.DisciplinePublic
+// @Failure 401 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/students/me/disciplines [get]
+// @Security BearerAuth
+func (h *StudentHandler) ListMyDisciplines(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.student_handler.ListMyDisciplines"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := ware.GetUserID(r)
+		if !ok {
+			log.Info("user id not found in claims")
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnauthorized))
+			return
+		}
+
+		student, err := h.repo.GetStudentByID(r.Context(), userID)
+		if err != nil {
+			// пользователь не студент или не зачислен в группу — дисциплин нет
+			if errors.Is(err, sql.ErrNoRows) {
+				render.JSON(w, r, []*models.DisciplinePublic{})
+				return
+			}
+			log.Error("failed to get student", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get student")
+			return
+		}
+		if student.StudentGroupID == 0 {
+			render.JSON(w, r, []*models.DisciplinePublic{})
+			return
+		}
+
+		limit, offset := parsePagination(r)
+
+		items, err := h.disciplineRepo.ListDisciplinePublic(r.Context(), limit, offset, nil, &student.StudentGroupID, nil, nil)
+		if err != nil {
+			log.Error("failed to list disciplines", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list disciplines")
+			return
+		}
+		if items == nil {
+			items = []*models.DisciplinePublic{}
+		}
+		render.JSON(w, r, items)
+	}
+}
+
+// @Summary Импорт студентов из CSV
+// @Description Колонки: first_name, last_name, middle_name (необяз.), email, password (необяз.), phone, birthday (YYYY-MM-DD), student_group_id.
+// @Description Каждая строка создаётся в отдельной транзакции, существующие email пропускаются.
+// @Description Строк данных не больше max_batch_size, иначе 413 и ничего не создаётся.
+// @Tags students
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV файл"
+// @Success 200 {object} models.StudentImportReport
+// @Failure 400 {object} resp.Response
+// @Failure 413 {object} resp.Response
+// @Router /api/v1/students/import [post]
+// @Security BearerAuth
+func (h *StudentHandler) ImportStudents(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.student_handler.ImportStudents"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			log.Info("failed to read uploaded file", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "file is required"))
+			return
+		}
+		defer file.Close()
+
+		cr := csv.NewReader(file)
+		cr.TrimLeadingSpace = true
+		header, err := cr.Read()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "failed to read csv header"))
+			return
+		}
+		cols := make(map[string]int, len(header))
+		for i, name := range header {
+			cols[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		var missing []string
+		for _, name := range studentImportColumns {
+			if _, ok := cols[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "missing csv columns: "+strings.Join(missing, ", ")))
+			return
+		}
+
+		// сначала читаем весь файл, чтобы отказать по размеру пакета до создания студентов
+		type csvRecord struct {
+			fields []string
+			err    error
+		}
+		var records []csvRecord
+		for {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			records = append(records, csvRecord{fields: record, err: err})
+			if !checkBatchSize(w, r, log, len(records), h.maxBatchSize) {
+				return
+			}
+			// после ошибки разбора кавычек читать дальше нельзя
+			var parseErr *csv.ParseError
+			if err != nil && errors.As(err, &parseErr) && !errors.Is(err, csv.ErrFieldCount) {
+				break
+			}
+		}
+
+		report := models.StudentImportReport{Rows: []*models.StudentImportRow{}}
+		for i, rec := range records {
+			row := &models.StudentImportRow{Line: i + 2}
+			report.Rows = append(report.Rows, row)
+			if rec.err != nil {
+				row.Status, row.Error = models.StudentImportError, rec.err.Error()
+				report.Failed++
+				continue
+			}
+			h.importStudentRow(r.Context(), log, cols, rec.fields, row)
+			switch row.Status {
+			case models.StudentImportCreated:
+				report.Created++
+			case models.StudentImportSkipped:
+				report.Skipped++
+			default:
+				report.Failed++
+			}
+		}
+
+		render.JSON(w, r, report)
+	}
+}
+
+func (h *StudentHandler) importStudentRow(ctx context.Context, log *slog.Logger, cols map[string]int, record []string, row *models.StudentImportRow) {
+	get := func(name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	fail := func(msg string) {
+		row.Status, row.Error = models.StudentImportError, msg
+	}
+
+	user := models.User{
+		FirstName: get("first_name"),
+		LastName:  get("last_name"),
+		Email:     strings.ToLower(get("email")),
+	}
+	row.Email = user.Email
+	if middle := get("middle_name"); middle != "" {
+		user.MiddleName = &middle
+	}
+	if user.FirstName == "" || user.LastName == "" || user.Email == "" {
+		fail("first_name, last_name and email are required")
+		return
+	}
+	birthday, err := time.Parse("2006-01-02", get("birthday"))
+	if err != nil {
+		fail("invalid birthday, expected YYYY-MM-DD")
+		return
+	}
+	groupID, err := strconv.ParseInt(get("student_group_id"), 10, 64)
+	if err != nil {
+		fail("invalid student_group_id")
+		return
+	}
+	student := models.Student{Phone: get("phone"), Birthday: birthday, StudentGroupID: groupID}
+	if student.Phone == "" {
+		fail("phone is required")
+		return
+	}
+	if student.Phone, err = h.phones.Normalize(student.Phone); err != nil {
+		fail("invalid phone number")
+		return
+	}
+
+	password := get("password")
+	if password == "" {
+		if password, err = generatePassword(); err != nil {
+			log.Error("failed to generate password", slog.String("err", err.Error()))
+			fail("internal error")
+			return
+		}
+		row.Password = password
+	}
+	user.Password, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to hash password", slog.String("err", err.Error()))
+		row.Password = ""
+		fail("internal error")
+		return
+	}
+
+	if err := h.repo.CreateUserWithStudent(ctx, &user, &student); err != nil {
+		row.Password = ""
+		if errors.Is(err, storage.ErrDuplicate) {
+			row.Status, row.Error = models.StudentImportSkipped, "email already exists"
+			return
+		}
+		log.Error("failed to import student", slog.Int("line", row.Line), slog.String("err", err.Error()))
+		fail("failed to create student")
+		return
+	}
+
+	row.Status, row.UserID = models.StudentImportCreated, user.UserID
+	_ = h.auditRepo.AddAuditLog(ctx, &models.AuditLog{
+		UserID:     utils.GetUserIDFromContext(ctx),
+		TableName:  "student",
+		RowID:      int64(student.UserID),
+		ActionType: "INSERT",
+		NewData:    utils.PtrToJSON(student),
+		Comment:    utils.PtrToStr("Student imported from CSV"),
+	})
+}
@@ -0,0 +1,493 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/lib/phone"
+	"service/internal/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memStudentRepo студенты в памяти по user_id
+type memStudentRepo struct {
+	StudentRepository
+	students map[int64]*models.Student
+	// users данные пользователей для ?expand=user
+	users map[int64]models.User
+	// filters аргументы последнего вызова ListStudentWithFilters
+	filters studentFilters
+}
+
+type studentFilters struct {
+	group    *int64
+	from, to *time.Time
+}
+
+func newMemStudentRepo(students ...*models.Student) *memStudentRepo {
+	m := &memStudentRepo{students: map[int64]*models.Student{}}
+	for _, s := range students {
+		m.students[int64(s.UserID)] = s
+	}
+	return m
+}
+
+func (m *memStudentRepo) GetStudentByID(_ context.Context, userID int64) (*models.Student, error) {
+	s, ok := m.students[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (m *memStudentRepo) GetStudentWithUserByID(ctx context.Context, userID int64) (*models.StudentWithUser, error) {
+	s, err := m.GetStudentByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	u := m.users[userID]
+	return &models.StudentWithUser{Student: *s, FirstName: u.FirstName, LastName: u.LastName, MiddleName: u.MiddleName, Email: u.Email}, nil
+}
+
+// CreateUserWithStudent повторяет уникальный индекс по email пользователя
+func (m *memStudentRepo) CreateUserWithStudent(_ context.Context, user *models.User, student *models.Student) error {
+	for _, u := range m.users {
+		if strings.EqualFold(u.Email, user.Email) {
+			return storage.ErrDuplicate
+		}
+	}
+	if m.users == nil {
+		m.users = map[int64]models.User{}
+	}
+	user.UserID = models.ID(100 + len(m.users))
+	student.UserID = user.UserID
+	m.users[int64(user.UserID)] = *user
+	cp := *student
+	m.students[int64(student.UserID)] = &cp
+	return nil
+}
+
+func (m *memStudentRepo) CreateStudent(_ context.Context, student *models.Student) error {
+	cp := *student
+	m.students[int64(student.UserID)] = &cp
+	return nil
+}
+
+func (m *memStudentRepo) UpdateStudent(_ context.Context, student *models.Student) error {
+	if _, ok := m.students[int64(student.UserID)]; !ok {
+		return sql.ErrNoRows
+	}
+	cp := *student
+	m.students[int64(student.UserID)] = &cp
+	return nil
+}
+
+func (m *memStudentRepo) PatchStudent(_ context.Context, userID int64, patch *models.StudentPatch) error {
+	s, ok := m.students[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if patch.Phone != nil {
+		s.Phone = *patch.Phone
+	}
+	if patch.Birthday != nil {
+		s.Birthday = *patch.Birthday
+	}
+	if patch.StudentGroupID != nil {
+		s.StudentGroupID = *patch.StudentGroupID
+	}
+	return nil
+}
+
+func (m *memStudentRepo) ListStudentWithFilters(_ context.Context, group *int64, from, to, _ *time.Time, _, _ int) ([]*models.Student, error) {
+	m.filters = studentFilters{group: group, from: from, to: to}
+	return nil, nil
+}
+
+// groupDisciplines отдаёт дисциплины по группе и запоминает запрошенную группу
+type groupDisciplines struct {
+	DisciplineRepository
+	byGroup map[int64][]*models.DisciplinePublic
+	asked   []int64
+}
+
+func (g *groupDisciplines) ListDisciplinePublic(_ context.Context, _, _ int, _, studentGroupID, _ *int64, _ *time.Time) ([]*models.DisciplinePublic, error) {
+	if studentGroupID != nil {
+		g.asked = append(g.asked, *studentGroupID)
+		return g.byGroup[*studentGroupID], nil
+	}
+	return nil, nil
+}
+
+func TestListMyDisciplines(t *testing.T) {
+	students := newMemStudentRepo(
+		&models.Student{UserID: 1, StudentGroupID: 2},
+		&models.Student{UserID: 3},
+		&models.Student{UserID: 4, StudentGroupID: 5},
+	)
+	disciplines := &groupDisciplines{byGroup: map[int64][]*models.DisciplinePublic{
+		2: {{DisciplineID: 7, DisciplineName: "Математика"}, {DisciplineID: 8, DisciplineName: "Физика"}},
+		9: {{DisciplineID: 11, DisciplineName: "Химия"}},
+	}}
+	h := NewStudentHandler(students, disciplines, nil, nil, nil, StudentYearPolicy{}, phone.Normalizer{}, 100)
+
+	tests := []struct {
+		name   string
+		userID int64
+		want   []int64
+	}{
+		{"дисциплины группы студента", 1, []int64{7, 8}},
+		{"студент без группы", 3, []int64{}},
+		{"у группы нет дисциплин", 4, []int64{}},
+		{"пользователь не студент", 99, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := authorize(t, httptest.NewRequest(http.MethodGet, "/api/v1/students/me/disciplines", nil), tt.userID)
+			w := httptest.NewRecorder()
+			h.ListMyDisciplines(discardLogger())(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body)
+			}
+			// пустой результат — массив, а не null
+			if body := strings.TrimSpace(w.Body.String()); len(tt.want) == 0 && body != "[]" {
+				t.Fatalf("body = %s, want []", body)
+			}
+			var got []models.DisciplinePublic
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d disciplines, want %d", len(got), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if got[i].DisciplineID != id {
+					t.Errorf("discipline[%d] = %d, want %d", i, got[i].DisciplineID, id)
+				}
+			}
+		})
+	}
+	if len(disciplines.asked) != 2 || disciplines.asked[0] != 2 || disciplines.asked[1] != 5 {
+		t.Errorf("disciplines requested for groups %v, want [2 5]", disciplines.asked)
+	}
+}
+
+func TestListMyDisciplines_Unauthorized(t *testing.T) {
+	h := NewStudentHandler(newMemStudentRepo(), &groupDisciplines{}, nil, nil, nil, StudentYearPolicy{}, phone.Normalizer{}, 100)
+	w := httptest.NewRecorder()
+	h.ListMyDisciplines(discardLogger())(w, httptest.NewRequest(http.MethodGet, "/api/v1/students/me/disciplines", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+}
+
+func TestListStudent_CombinedFilters(t *testing.T) {
+	day := func(s string) *time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return &d
+	}
+	group := int64(4)
+
+	tests := []struct {
+		query string
+		want  studentFilters
+	}{
+		{"", studentFilters{}},
+		{"student_group_id=4&from_date=2024-09-01&to_date=2024-09-30", studentFilters{group: &group, from: day("2024-09-01"), to: day("2024-09-30")}},
+		{"student_group_id=4&to_date=2024-09-30", studentFilters{group: &group, to: day("2024-09-30")}},
+		// некорректные значения игнорируются, остальные фильтры применяются
+		{"student_group_id=abc&from_date=01.09.2024&to_date=2024-09-30", studentFilters{to: day("2024-09-30")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			repo := newMemStudentRepo()
+			h := NewStudentHandler(repo, nil, nil, nil, nil, StudentYearPolicy{}, phone.Normalizer{}, 100)
+			rec := httptest.NewRecorder()
+			h.ListStudent(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/students?"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+			}
+
+			got := repo.filters
+			if (got.group == nil) != (tt.want.group == nil) || got.group != nil && *got.group != *tt.want.group {
+				t.Errorf("student_group_id = %v, want %v", got.group, tt.want.group)
+			}
+			for _, d := range []struct {
+				name      string
+				got, want *time.Time
+			}{{"from_date", got.from, tt.want.from}, {"to_date", got.to, tt.want.to}} {
+				if (d.got == nil) != (d.want == nil) || d.got != nil && !d.got.Equal(*d.want) {
+					t.Errorf("%s = %v, want %v", d.name, d.got, d.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetStudentByID_Expand(t *testing.T) {
+	repo := newMemStudentRepo(&models.Student{UserID: 7, Phone: "+79001234567", StudentGroupID: 2})
+	repo.users = map[int64]models.User{7: {UserID: 7, FirstName: "Анна", LastName: "Иванова", Email: "anna@example.com"}}
+	h := NewStudentHandler(repo, nil, nil, nil, nil, StudentYearPolicy{}, phone.Normalizer{}, 100)
+
+	get := func(query string) map[string]interface{} {
+		t.Helper()
+		r := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/students/7"+query, nil), "id", "7")
+		rec := httptest.NewRecorder()
+		h.GetStudentByID(discardLogger())(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body %s", query, rec.Code, rec.Body)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	lean := get("")
+	expanded := get("?expand=user")
+
+	userFields := []string{"first_name", "last_name", "email"}
+	for _, f := range userFields {
+		if _, ok := lean[f]; ok {
+			t.Errorf("default response contains %q", f)
+		}
+	}
+	if expanded["first_name"] != "Анна" || expanded["last_name"] != "Иванова" || expanded["email"] != "anna@example.com" {
+		t.Errorf("expanded response = %v", expanded)
+	}
+	// поля студента в обоих ответах совпадают
+	for k, v := range lean {
+		if !reflect.DeepEqual(expanded[k], v) {
+			t.Errorf("%s: expanded %v, lean %v", k, expanded[k], v)
+		}
+	}
+	if len(expanded) != len(lean)+len(userFields) {
+		t.Errorf("expanded has %d fields, want %d", len(expanded), len(lean)+len(userFields))
+	}
+	// неизвестное значение expand не меняет ответ
+	if other := get("?expand=teacher"); !reflect.DeepEqual(other, lean) {
+		t.Errorf("?expand=teacher = %v, want lean response", other)
+	}
+}
+
+func TestPatchStudent_OmittedFieldsUntouched(t *testing.T) {
+	birthday := time.Date(2005, 3, 14, 0, 0, 0, 0, time.UTC)
+	repo := newMemStudentRepo(&models.Student{UserID: 7, Phone: "+79001234567", Birthday: birthday, StudentGroupID: 2})
+	h := NewStudentHandler(repo, nil, nil, nil, &recordingAudit{}, StudentYearPolicy{}, phone.Normalizer{}, 100)
+
+	r := httptest.NewRequest(http.MethodPatch, "/api/v1/students/7", strings.NewReader(`{"phone":"8 (900) 765-43-21"}`))
+	rec := httptest.NewRecorder()
+	h.PatchStudent(discardLogger())(rec, withURLParams(r, "id", "7"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+
+	got, _ := repo.GetStudentByID(context.Background(), 7)
+	if got.Phone != "+79007654321" {
+		t.Errorf("phone = %q, want normalized patched value", got.Phone)
+	}
+	if !got.Birthday.Equal(birthday) || got.StudentGroupID != 2 {
+		t.Errorf("omitted fields changed: birthday %s, group %d", got.Birthday, got.StudentGroupID)
+	}
+}
+
+// importCSV отправляет csv как файл multipart-формы в ImportStudents
+func importCSV(t *testing.T, h *StudentHandler, csv string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "students.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(csv))
+	mw.Close()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/students/import", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	h.ImportStudents(discardLogger())(rec, r)
+	return rec
+}
+
+func TestImportStudents(t *testing.T) {
+	repo := newMemStudentRepo()
+	repo.users = map[int64]models.User{1: {UserID: 1, Email: "taken@example.com"}}
+	audit := &recordingAudit{}
+	h := NewStudentHandler(repo, nil, nil, nil, audit, StudentYearPolicy{}, phone.Normalizer{}, 100)
+
+	rec := importCSV(t, h, "first_name,last_name,email,password,phone,birthday,student_group_id\n"+
+		"Анна,Иванова,Anna@Example.com,secret,8 900 123-45-67,2005-03-14,2\n"+
+		"Пётр,Петров,TAKEN@example.com,secret,+79001112233,2005-01-01,2\n"+
+		"Иван,Сидоров,ivan@example.com,secret,+79004445566,14.03.2005,2\n")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var report models.StudentImportReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 1 || report.Skipped != 1 || report.Failed != 1 || len(report.Rows) != 3 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	created, skipped, failed := report.Rows[0], report.Rows[1], report.Rows[2]
+	if created.Line != 2 || created.Status != models.StudentImportCreated || created.Email != "anna@example.com" || created.UserID == 0 {
+		t.Errorf("valid row = %+v", created)
+	}
+	if created.Password != "" {
+		t.Error("password returned although it was given in the file")
+	}
+	stored, err := repo.GetStudentByID(context.Background(), int64(created.UserID))
+	if err != nil || stored.Phone != "+79001234567" || stored.StudentGroupID != 2 {
+		t.Errorf("stored student = %+v, err %v", stored, err)
+	}
+	if skipped.Line != 3 || skipped.Status != models.StudentImportSkipped || skipped.Error != "email already exists" {
+		t.Errorf("duplicate email row = %+v", skipped)
+	}
+	if failed.Line != 4 || failed.Status != models.StudentImportError || !strings.Contains(failed.Error, "birthday") {
+		t.Errorf("bad row = %+v", failed)
+	}
+	if len(audit.entries) != 1 {
+		t.Errorf("audit entries = %d, want 1 for the created student", len(audit.entries))
+	}
+}
+
+func TestImportStudents_Rejected(t *testing.T) {
+	const header = "first_name,last_name,email,phone,birthday,student_group_id\n"
+	row := "Анна,Иванова,anna@example.com,+79001234567,2005-03-14,2\n"
+
+	tests := []struct {
+		name string
+		csv  string
+		want int
+		msg  string
+	}{
+		{"нет колонки", "first_name,last_name,email,phone,birthday\n" + row, http.StatusBadRequest, "missing csv columns: student_group_id"},
+		{"больше max_batch_size", header + strings.Repeat(row, 3), http.StatusRequestEntityTooLarge, "at most 2 items"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemStudentRepo()
+			h := NewStudentHandler(repo, nil, nil, nil, &recordingAudit{}, StudentYearPolicy{}, phone.Normalizer{}, 2)
+			rec := importCSV(t, h, tt.csv)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.msg) {
+				t.Errorf("body = %s, want %q", rec.Body, tt.msg)
+			}
+			// отказ до создания студентов
+			if len(repo.students) != 0 {
+				t.Errorf("created %d students on rejected import", len(repo.students))
+			}
+		})
+	}
+
+	// ровно max_batch_size строк принимается
+	h := NewStudentHandler(newMemStudentRepo(), nil, nil, nil, &recordingAudit{}, StudentYearPolicy{}, phone.Normalizer{}, 2)
+	if rec := importCSV(t, h, header+row+strings.Replace(row, "anna@", "anna2@", 1)); rec.Code != http.StatusOK {
+		t.Fatalf("import of max_batch_size rows: status = %d, body %s", rec.Code, rec.Body)
+	}
+}
+
+func TestStudent_GroupAcademicYear(t *testing.T) {
+	now := time.Now()
+	// текущий год 1 идёт сейчас, год 2 — следующий; группа 2 в году 1, группа 4 в году 2
+	years := &memYearRepo{years: []*models.AcademicYear{
+		{AcademicYearID: 1, StartWith: now.AddDate(0, -1, 0), EndsWith: now.AddDate(0, 10, 0)},
+		{AcademicYearID: 2, StartWith: now.AddDate(0, 11, 0), EndsWith: now.AddDate(1, 10, 0)},
+	}}
+	groups := newMemGroupRepo(
+		&models.StudentGroup{StudentGroupID: 2, AcademicYearID: 1},
+		&models.StudentGroup{StudentGroupID: 4, AcademicYearID: 2},
+	)
+
+	tests := []struct {
+		name    string
+		policy  StudentYearPolicy
+		query   string
+		group   int64
+		perms   []string
+		want    int
+		wantMsg string
+	}{
+		{name: "group of current year", policy: StudentYearPolicy{Enabled: true}, group: 2, want: http.StatusOK},
+		{name: "group of another year", policy: StudentYearPolicy{Enabled: true}, group: 4, want: http.StatusUnprocessableEntity, wantMsg: "student group belongs to a different academic year"},
+		{name: "year passed in query", policy: StudentYearPolicy{Enabled: true}, query: "?academic_year_id=2", group: 4, want: http.StatusOK},
+		{name: "year from config", policy: StudentYearPolicy{Enabled: true, CurrentAcademicYearID: 2}, group: 2, want: http.StatusUnprocessableEntity, wantMsg: "student group belongs to a different academic year"},
+		{name: "override permission", policy: StudentYearPolicy{Enabled: true}, group: 4, perms: []string{permStudentAnyAcademicYear}, want: http.StatusOK},
+		{name: "unknown group", policy: StudentYearPolicy{Enabled: true}, group: 9, want: http.StatusUnprocessableEntity, wantMsg: "student group not found"},
+		{name: "invalid year in query", policy: StudentYearPolicy{Enabled: true}, query: "?academic_year_id=abc", group: 2, want: http.StatusUnprocessableEntity, wantMsg: "invalid academic_year_id"},
+		{name: "policy disabled", group: 4, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemStudentRepo(&models.Student{UserID: 7, Phone: "+79001234567", StudentGroupID: 3})
+			h := NewStudentHandler(repo, nil, groups, years, &recordingAudit{}, tt.policy, phone.Normalizer{}, 100)
+			body := fmt.Sprintf(`{"user_id":"8","phone":"+79001234567","student_group_id":%d}`, tt.group)
+
+			create := httptest.NewRecorder()
+			r := authorize(t, httptest.NewRequest(http.MethodPost, "/api/v1/students"+tt.query, strings.NewReader(body)), 1, tt.perms...)
+			h.CreateStudent(discardLogger())(create, r)
+
+			// перевод существующего студента проверяется так же
+			update := httptest.NewRecorder()
+			r = authorize(t, httptest.NewRequest(http.MethodPut, "/api/v1/students/7"+tt.query, strings.NewReader(body)), 1, tt.perms...)
+			h.UpdateStudent(discardLogger())(update, withURLParams(r, "id", "7"))
+
+			wantCreate := tt.want
+			if wantCreate == http.StatusOK {
+				wantCreate = http.StatusCreated
+			}
+			if create.Code != wantCreate {
+				t.Fatalf("create: status = %d, want %d: %s", create.Code, wantCreate, create.Body)
+			}
+			if update.Code != tt.want {
+				t.Fatalf("update: status = %d, want %d: %s", update.Code, tt.want, update.Body)
+			}
+			if tt.want == http.StatusOK {
+				return
+			}
+			for op, rec := range map[string]*httptest.ResponseRecorder{"create": create, "update": update} {
+				if !strings.Contains(rec.Body.String(), tt.wantMsg) {
+					t.Errorf("%s: body = %s, want %q", op, rec.Body, tt.wantMsg)
+				}
+			}
+			if _, ok := repo.students[8]; ok {
+				t.Errorf("student created despite %d", tt.want)
+			}
+			if repo.students[7].StudentGroupID != 3 {
+				t.Errorf("student moved despite %d: group %d", tt.want, repo.students[7].StudentGroupID)
+			}
+		})
+	}
+}
+
+func TestUpdateStudent_SameGroupSkipsYearCheck(t *testing.T) {
+	// студент остался в группе прошлого года: правка телефона не требует перевода
+	repo := newMemStudentRepo(&models.Student{UserID: 7, Phone: "+79001234567", StudentGroupID: 4})
+	groups := newMemGroupRepo(&models.StudentGroup{StudentGroupID: 4, AcademicYearID: 1})
+	h := NewStudentHandler(repo, nil, groups, &memYearRepo{}, &recordingAudit{}, StudentYearPolicy{Enabled: true, CurrentAcademicYearID: 2}, phone.Normalizer{}, 100)
+
+	r := authorize(t, httptest.NewRequest(http.MethodPut, "/api/v1/students/7", strings.NewReader(`{"phone":"+79007654321","student_group_id":4}`)), 1)
+	rec := httptest.NewRecorder()
+	h.UpdateStudent(discardLogger())(rec, withURLParams(r, "id", "7"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if repo.students[7].Phone != "+79007654321" {
+		t.Errorf("phone = %q, want updated", repo.students[7].Phone)
+	}
+}
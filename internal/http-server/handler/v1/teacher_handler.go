@@ -9,8 +9,11 @@ import (
 	"net/http"
 	"service/internal/domain/models"
 	ware "service/internal/http-server/middleware"
+	"service/internal/http-server/middleware/permissions"
 	resp "service/internal/lib/api/response"
+	"service/internal/lib/phone"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
@@ -22,21 +25,35 @@ type TeacherRepository interface {
 	CreateTeacher(ctx context.Context, teacher *models.Teacher) error
 	GetTeacherByID(ctx context.Context, userID int64) (*models.Teacher, error)
 	GetTeacherPublicByID(ctx context.Context, userID int64) (*models.TeacherPublic, error)
+	GetTeacherWorkload(ctx context.Context, teacherID int64) (*models.TeacherWorkload, error)
 	UpdateTeacher(ctx context.Context, teacher *models.Teacher) error
+	PatchTeacher(ctx context.Context, userID int64, patch *models.TeacherPatch) error
 	DeleteTeacher(ctx context.Context, userID int64) error
 	ListTeacher(ctx context.Context, limit, offset int) ([]*models.Teacher, error)
+	CountTeacher(ctx context.Context) (int64, error)
 	ListTeacherPublic(ctx context.Context, limit, offset int) ([]*models.TeacherPublic, error)
+	GetTeacherPublicWithPhoneByID(ctx context.Context, userID int64) (*models.TeacherPublicWithPhone, error)
+	ListTeacherPublicWithPhone(ctx context.Context, limit, offset int) ([]*models.TeacherPublicWithPhone, error)
+}
+
+type TeacherStudentsRepository interface {
+	ListStudentsByTeacher(ctx context.Context, teacherID int64) ([]*models.StudentWithUser, error)
 }
 
 type TeacherHandler struct {
-	repo      TeacherRepository
-	auditRepo AuditLogRepository
+	repo        TeacherRepository
+	studentRepo TeacherStudentsRepository
+	auditRepo   AuditLogRepository
+	phones      phone.Normalizer
 }
 
-func NewTeacherHandler(repo TeacherRepository, auditRepo AuditLogRepository) *TeacherHandler {
-	return &TeacherHandler{repo: repo, auditRepo: auditRepo}
+func NewTeacherHandler(repo TeacherRepository, studentRepo TeacherStudentsRepository, auditRepo AuditLogRepository, phones phone.Normalizer) *TeacherHandler {
+	return &TeacherHandler{repo: repo, studentRepo: studentRepo, auditRepo: auditRepo, phones: phones}
 }
 
+// permTeacherView открывает телефон в публичном профиле преподавателя
+const permTeacherView = "teacher:view"
+
 // @Summary Создать преподавателя
 // @Tags teachers
 // @Accept json
@@ -56,23 +73,31 @@ func (h *TeacherHandler) CreateTeacher(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&teacher); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		normalized, err := h.phones.Normalize(teacher.Phone)
+		if err != nil {
+			log.Info("invalid phone number")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid phone number"))
 			return
 		}
+		teacher.Phone = normalized
 		if err := h.repo.CreateTeacher(r.Context(), &teacher); err != nil {
 			log.Error("failed to create teacher", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create teacher"))
+			renderServerError(w, r, err, "failed to create teacher")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
 			UserID:     utils.GetUserIDFromContext(r.Context()),
 			TableName:  "teacher",
-			RowID:      teacher.UserID,
+			RowID:      int64(teacher.UserID),
 			ActionType: "CREATE",
 			NewData:    utils.PtrToJSON(teacher),
 			Comment:    utils.PtrToStr("Teacher created"),
 		})
+		setLocation(w, "teacher", int64(teacher.UserID))
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, teacher)
 	}
@@ -98,7 +123,7 @@ func (h *TeacherHandler) GetTeacherByID(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid teacher id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid teacher id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid teacher id"))
 			return
 		}
 		teacher, err := h.repo.GetTeacherByID(r.Context(), id)
@@ -106,12 +131,11 @@ func (h *TeacherHandler) GetTeacherByID(log *slog.Logger) http.HandlerFunc {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("teacher not found", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("teacher not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
 				return
 			}
 			log.Error("failed to get teacher", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get teacher"))
+			renderServerError(w, r, err, "failed to get teacher")
 			return
 		}
 		render.JSON(w, r, teacher)
@@ -122,8 +146,10 @@ func (h *TeacherHandler) GetTeacherByID(log *slog.Logger) http.HandlerFunc {
 // @Tags teachers
 // @Accept json
 // @Produce json
+// @Description Пользователи с правом teacher:view дополнительно получают телефон
 // @Param id path int true "ID преподавателя"
 // @Success 200 {object} models.TeacherPublic
+// @Success 200 {object} models.TeacherPublicWithPhone
 // @Router /api/v1/teacher/public/{id} [get]
 // @Security BearerAuth
 func (h *TeacherHandler) GetTeacherPublicByID(log *slog.Logger) http.HandlerFunc {
@@ -138,20 +164,25 @@ func (h *TeacherHandler) GetTeacherPublicByID(log *slog.Logger) http.HandlerFunc
 		if err != nil {
 			log.Info("invalid teacher id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid teacher id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid teacher id"))
 			return
 		}
-		teacher, err := h.repo.GetTeacherPublicByID(r.Context(), id)
+		privileged := permissions.HasPermission(r, permTeacherView)
+		var teacher interface{}
+		if privileged {
+			teacher, err = h.repo.GetTeacherPublicWithPhoneByID(r.Context(), id)
+		} else {
+			teacher, err = h.repo.GetTeacherPublicByID(r.Context(), id)
+		}
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("teacher not found", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("teacher not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
 				return
 			}
 			log.Error("failed to get teacher", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get teacher"))
+			renderServerError(w, r, err, "failed to get teacher")
 			return
 		}
 		render.JSON(w, r, teacher)
@@ -172,26 +203,163 @@ func (h *TeacherHandler) GetMyTeacherProfile(log *slog.Logger) http.HandlerFunc
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		claims := ware.GetUserClaims(r)
-		teacherId := claims["id"].(int64)
+		teacherId, ok := ware.GetUserID(r)
+		if !ok {
+			log.Info("user id not found in claims")
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnauthorized))
+			return
+		}
 		teacher, err := h.repo.GetTeacherByID(r.Context(), teacherId)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("teacher not found", slog.Int64("user_id", teacherId))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("teacher not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
 				return
 			}
 			log.Error("failed to get teacher", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get teacher"))
+			renderServerError(w, r, err, "failed to get teacher")
 			return
 		}
 		render.JSON(w, r, teacher)
 	}
 }
 
+// @Summary Студенты текущего преподавателя
+// @Description Студенты всех групп, где преподаватель ведёт дисциплины, без повторов
+// @Tags teachers
+// @Produce json
+// @Success 200 {array} models.StudentWithUser
+// @Failure 401 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/teacher/me/students [get]
+// @Security BearerAuth
+func (h *TeacherHandler) ListMyStudents(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.teacher_handler.ListMyStudents"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := ware.GetUserID(r)
+		if !ok {
+			log.Info("user id not found in claims")
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnauthorized))
+			return
+		}
+
+		if _, err := h.repo.GetTeacherByID(r.Context(), userID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("teacher not found", slog.Int64("user_id", userID))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
+				return
+			}
+			log.Error("failed to get teacher", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get teacher")
+			return
+		}
+
+		students, err := h.studentRepo.ListStudentsByTeacher(r.Context(), userID)
+		if err != nil {
+			log.Error("failed to list teacher students", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list students")
+			return
+		}
+		if students == nil {
+			students = []*models.StudentWithUser{}
+		}
+		render.JSON(w, r, students)
+	}
+}
+
+// @Summary Нагрузка преподавателя
+// @Description Число дисциплин, различных групп и студентов в них
+// @Tags teachers
+// @Produce json
+// @Param id path int true "ID преподавателя"
+// @Success 200 {object} models.TeacherWorkload
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/teacher/{id}/workload [get]
+// @Security BearerAuth
+func (h *TeacherHandler) GetTeacherWorkload(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.teacher_handler.GetTeacherWorkload"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid teacher id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid teacher id"))
+			return
+		}
+		h.renderWorkload(w, r, log, id)
+	}
+}
+
+// @Summary Нагрузка текущего преподавателя
+// @Tags teachers
+// @Produce json
+// @Success 200 {object} models.TeacherWorkload
+// @Failure 401 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/teacher/me/workload [get]
+// @Security BearerAuth
+func (h *TeacherHandler) GetMyTeacherWorkload(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.teacher_handler.GetMyTeacherWorkload"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := ware.GetUserID(r)
+		if !ok {
+			log.Info("user id not found in claims")
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnauthorized))
+			return
+		}
+		h.renderWorkload(w, r, log, userID)
+	}
+}
+
+func (h *TeacherHandler) renderWorkload(w http.ResponseWriter, r *http.Request, log *slog.Logger, teacherID int64) {
+	if _, err := h.repo.GetTeacherByID(r.Context(), teacherID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Info("teacher not found", slog.Int64("teacher_id", teacherID))
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
+			return
+		}
+		log.Error("failed to get teacher", slog.String("err", err.Error()))
+		renderServerError(w, r, err, "failed to get teacher")
+		return
+	}
+
+	workload, err := h.repo.GetTeacherWorkload(r.Context(), teacherID)
+	if err != nil {
+		log.Error("failed to get teacher workload", slog.String("err", err.Error()))
+		renderServerError(w, r, err, "failed to get teacher workload")
+		return
+	}
+	render.JSON(w, r, workload)
+}
+
 // @Summary Обновить преподавателя по ID
+// @Description Полная замена: непереданные working_experience и education очищаются, для частичного обновления — PATCH
 // @Tags teachers
 // @Accept json
 // @Produce json
@@ -212,28 +380,35 @@ func (h *TeacherHandler) UpdateTeacher(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid teacher id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		var teacher models.Teacher
 		if err := json.NewDecoder(r.Body).Decode(&teacher); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		normalized, err := h.phones.Normalize(teacher.Phone)
+		if err != nil {
+			log.Info("invalid phone number")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid phone number"))
 			return
 		}
-		teacher.UserID = teacherId
+		teacher.Phone = normalized
+		teacher.UserID = models.ID(teacherId)
 		oldData, _ := h.repo.GetTeacherByID(r.Context(), teacherId)
 		if err := h.repo.UpdateTeacher(r.Context(), &teacher); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("user not found for update", slog.Int64("user_id", teacherId))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("teacher not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
 				return
 			}
 			log.Info("failed to update user", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update user"))
+			renderServerError(w, r, err, "failed to update user")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -250,6 +425,89 @@ func (h *TeacherHandler) UpdateTeacher(log *slog.Logger) http.HandlerFunc {
 	}
 }
 
+// @Summary Частично обновить преподавателя по ID
+// @Description Обновляются только переданные поля, null в working_experience или education очищает значение
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param id path int true "ID преподавателя"
+// @Param input body models.TeacherPatch true "Изменяемые поля"
+// @Success 200 {object} models.Teacher
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/teacher/{id} [patch]
+// @Security BearerAuth
+func (h *TeacherHandler) PatchTeacher(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.teacher_handler.PatchTeacher"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		idStr := chi.URLParam(r, "id")
+		teacherID, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid teacher id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid teacher id"))
+			return
+		}
+		var patch models.TeacherPatch
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		if patch.Phone != nil {
+			normalized, err := h.phones.Normalize(*patch.Phone)
+			if err != nil {
+				log.Info("invalid phone number")
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid phone number"))
+				return
+			}
+			patch.Phone = &normalized
+		}
+
+		oldData, err := h.repo.GetTeacherByID(r.Context(), teacherID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("teacher not found for patch", slog.Int64("user_id", teacherID))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
+				return
+			}
+			log.Error("failed to get teacher", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update teacher")
+			return
+		}
+		if err := h.repo.PatchTeacher(r.Context(), teacherID, &patch); err != nil {
+			log.Error("failed to patch teacher", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update teacher")
+			return
+		}
+		teacher, err := h.repo.GetTeacherByID(r.Context(), teacherID)
+		if err != nil {
+			log.Error("failed to get teacher", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update teacher")
+			return
+		}
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "teacher",
+			RowID:      teacherID,
+			ActionType: "UPDATE",
+			NewData:    utils.PtrToJSON(teacher),
+			OldData:    utils.PtrToJSON(oldData),
+			Changes:    utils.PtrToJSONDiff(oldData, teacher),
+			Comment:    utils.PtrToStr("Teacher patched"),
+		})
+		render.JSON(w, r, teacher)
+	}
+}
+
 // @Summary Обновить свой профиль преподавателя
 // @Tags teachers
 // @Accept json
@@ -265,27 +523,39 @@ func (h *TeacherHandler) UpdateMyTeacherProfile(log *slog.Logger) http.HandlerFu
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		claims := ware.GetUserClaims(r)
-		teacherId := claims["id"].(int64)
+		teacherId, ok := ware.GetUserID(r)
+		if !ok {
+			log.Info("user id not found in claims")
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgUnauthorized))
+			return
+		}
 		var teacher models.Teacher
 		if err := json.NewDecoder(r.Body).Decode(&teacher); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		normalized, err := h.phones.Normalize(teacher.Phone)
+		if err != nil {
+			log.Info("invalid phone number")
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid phone number"))
 			return
 		}
-		teacher.UserID = teacherId
+		teacher.Phone = normalized
+		teacher.UserID = models.ID(teacherId)
 		oldData, _ := h.repo.GetTeacherByID(r.Context(), teacherId)
 		if err := h.repo.UpdateTeacher(r.Context(), &teacher); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("user not found for update", slog.Int64("user_id", teacherId))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("teacher not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
 				return
 			}
 			log.Info("failed to update user", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update user"))
+			renderServerError(w, r, err, "failed to update user")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -322,20 +592,25 @@ func (h *TeacherHandler) DeleteTeacher(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid teacher id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid teacher id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid teacher id"))
 			return
 		}
 		oldData, _ := h.repo.GetTeacherByID(r.Context(), id)
 		if err := h.repo.DeleteTeacher(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("teacher is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("teacher", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("teacher not found for delete", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("teacher not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "teacher not found"))
 				return
 			}
 			log.Error("failed to delete teacher", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete teacher"))
+			renderServerError(w, r, err, "failed to delete teacher")
 			return
 		}
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
@@ -354,9 +629,11 @@ func (h *TeacherHandler) DeleteTeacher(log *slog.Logger) http.HandlerFunc {
 // @Tags teachers
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.Teacher
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/teacher [get]
 // @Security BearerAuth
 func (h *TeacherHandler) ListTeacher(log *slog.Logger) http.HandlerFunc {
@@ -366,14 +643,17 @@ func (h *TeacherHandler) ListTeacher(log *slog.Logger) http.HandlerFunc {
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, offset := parsePagination(r)
 
+		if err := setTotalCount(w, r, func() (int64, error) { return h.repo.CountTeacher(r.Context()) }); err != nil {
+			log.Error("failed to count teachers", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list teachers")
+			return
+		}
 		teachers, err := h.repo.ListTeacher(r.Context(), limit, offset)
 		if err != nil {
 			log.Error("failed to list teachers", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list teachers"))
+			renderServerError(w, r, err, "failed to list teachers")
 			return
 		}
 		render.JSON(w, r, teachers)
@@ -384,9 +664,13 @@ func (h *TeacherHandler) ListTeacher(log *slog.Logger) http.HandlerFunc {
 // @Tags teachers
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
+// @Description Пользователи с правом teacher:view дополнительно получают телефон
 // @Success 200 {array} models.TeacherPublic
+// @Success 200 {array} models.TeacherPublicWithPhone
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/teacher/public [get]
 // @Security BearerAuth
 func (h *TeacherHandler) ListTeacherPublic(log *slog.Logger) http.HandlerFunc {
@@ -396,14 +680,27 @@ func (h *TeacherHandler) ListTeacherPublic(log *slog.Logger) http.HandlerFunc {
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, offset := parsePagination(r)
 
-		teachers, err := h.repo.ListTeacherPublic(r.Context(), limit, offset)
+		if err := setTotalCount(w, r, func() (int64, error) { return h.repo.CountTeacher(r.Context()) }); err != nil {
+			log.Error("failed to count teachers", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list public teachers")
+			return
+		}
+
+		privileged := permissions.HasPermission(r, permTeacherView)
+		var (
+			teachers interface{}
+			err      error
+		)
+		if privileged {
+			teachers, err = h.repo.ListTeacherPublicWithPhone(r.Context(), limit, offset)
+		} else {
+			teachers, err = h.repo.ListTeacherPublic(r.Context(), limit, offset)
+		}
 		if err != nil {
 			log.Error("failed to list public teachers", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list public teachers"))
+			renderServerError(w, r, err, "failed to list public teachers")
 			return
 		}
 		render.JSON(w, r, teachers)
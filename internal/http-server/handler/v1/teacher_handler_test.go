@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/lib/phone"
+	"strings"
+	"testing"
+)
+
+// publicTeacherRepo отдаёт один и тот же профиль с телефоном и без
+type publicTeacherRepo struct {
+	TeacherRepository
+}
+
+func (publicTeacherRepo) GetTeacherPublicByID(_ context.Context, id int64) (*models.TeacherPublic, error) {
+	return &models.TeacherPublic{UserID: models.ID(id), FirstName: "Анна"}, nil
+}
+
+func (publicTeacherRepo) GetTeacherPublicWithPhoneByID(_ context.Context, id int64) (*models.TeacherPublicWithPhone, error) {
+	return &models.TeacherPublicWithPhone{
+		TeacherPublic: models.TeacherPublic{UserID: models.ID(id), FirstName: "Анна"},
+		Phone:         "+79991234567",
+	}, nil
+}
+
+func (publicTeacherRepo) CountTeacher(context.Context) (int64, error) { return 1, nil }
+
+func (r publicTeacherRepo) ListTeacherPublic(ctx context.Context, _, _ int) ([]*models.TeacherPublic, error) {
+	t, err := r.GetTeacherPublicByID(ctx, 5)
+	return []*models.TeacherPublic{t}, err
+}
+
+func (r publicTeacherRepo) ListTeacherPublicWithPhone(ctx context.Context, _, _ int) ([]*models.TeacherPublicWithPhone, error) {
+	t, err := r.GetTeacherPublicWithPhoneByID(ctx, 5)
+	return []*models.TeacherPublicWithPhone{t}, err
+}
+
+func TestGetTeacherPublicByID_PhoneDependsOnPermission(t *testing.T) {
+	h := NewTeacherHandler(publicTeacherRepo{}, nil, nil, phone.Normalizer{})
+	tests := []struct {
+		name      string
+		perms     []string
+		wantPhone bool
+	}{
+		{"with teacher:view", []string{"teacher:view_public", permTeacherView}, true},
+		{"without teacher:view", []string{"teacher:view_public"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := withPermissions(t, "/api/v1/teachers/public/5", tt.perms...)
+			rec := httptest.NewRecorder()
+			h.GetTeacherPublicByID(discardLogger())(rec, withURLParams(r, "id", "5"))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body)
+			}
+			if hasPhone := strings.Contains(rec.Body.String(), `"phone"`); hasPhone != tt.wantPhone {
+				t.Fatalf("phone in response = %v, want %v: %s", hasPhone, tt.wantPhone, rec.Body)
+			}
+		})
+	}
+}
+
+func TestListTeacherPublic_Serialization(t *testing.T) {
+	h := NewTeacherHandler(publicTeacherRepo{}, nil, nil, phone.Normalizer{})
+	tests := []struct {
+		name  string
+		perms []string
+		want  string
+	}{
+		{
+			name:  "привилегированный вызов",
+			perms: []string{"teacher:view_public", permTeacherView},
+			// телефон на одном уровне с остальными полями, а не во вложенном объекте
+			want: `[{"user_id":"5","first_name":"Анна","last_name":"","education":"","phone":"+79991234567"}]`,
+		},
+		{
+			name:  "публичный вызов",
+			perms: []string{"teacher:view_public"},
+			want:  `[{"user_id":"5","first_name":"Анна","last_name":"","education":""}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ListTeacherPublic(discardLogger())(rec, withPermissions(t, "/api/v1/teachers/public", tt.perms...))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+				t.Errorf("body = %s\nwant   %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// knownTeachers преподаватели по id
+type knownTeachers struct {
+	TeacherRepository
+	ids map[int64]bool
+}
+
+func (k knownTeachers) GetTeacherByID(_ context.Context, id int64) (*models.Teacher, error) {
+	if !k.ids[id] {
+		return nil, sql.ErrNoRows
+	}
+	return &models.Teacher{UserID: models.ID(id)}, nil
+}
+
+// teacherStudents студенты групп каждого преподавателя
+type teacherStudents map[int64][]*models.StudentWithUser
+
+func (t teacherStudents) ListStudentsByTeacher(_ context.Context, teacherID int64) ([]*models.StudentWithUser, error) {
+	return t[teacherID], nil
+}
+
+func TestListMyStudents(t *testing.T) {
+	students := teacherStudents{10: {
+		{Student: models.Student{UserID: 7, StudentGroupID: 2}, FirstName: "Иван", LastName: "Иванов"},
+		{Student: models.Student{UserID: 8, StudentGroupID: 3}, FirstName: "Анна", LastName: "Смирнова"},
+	}}
+	h := NewTeacherHandler(knownTeachers{ids: map[int64]bool{10: true, 11: true}}, students, nil, phone.Normalizer{})
+
+	tests := []struct {
+		name    string
+		userID  int64
+		want    int
+		wantIDs []models.ID
+	}{
+		{"teacher with students", 10, http.StatusOK, []models.ID{7, 8}},
+		{"teacher without students", 11, http.StatusOK, []models.ID{}},
+		{"not a teacher", 99, http.StatusNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := authorize(t, httptest.NewRequest(http.MethodGet, "/api/v1/teacher/me/students", nil), tt.userID, "teacher:view_self")
+			rec := httptest.NewRecorder()
+			h.ListMyStudents(discardLogger())(rec, r)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var items []models.StudentWithUser
+			if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil || items == nil {
+				t.Fatalf("body = %s, want JSON array: %v", rec.Body, err)
+			}
+			ids := []models.ID{}
+			for _, s := range items {
+				ids = append(ids, s.UserID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("students = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+
+	// без пользователя в контексте — 401
+	rec := httptest.NewRecorder()
+	h.ListMyStudents(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/teacher/me/students", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous: status = %d, want 401", rec.Code)
+	}
+}
+
+// GetTeacherWorkload у преподавателя 10 две дисциплины в одной группе, у 11 — ничего
+func (k knownTeachers) GetTeacherWorkload(_ context.Context, id int64) (*models.TeacherWorkload, error) {
+	if id == 10 {
+		return &models.TeacherWorkload{TeacherID: id, Disciplines: 2, Groups: 1, Students: 25}, nil
+	}
+	return &models.TeacherWorkload{TeacherID: id}, nil
+}
+
+func TestGetTeacherWorkload(t *testing.T) {
+	h := NewTeacherHandler(knownTeachers{ids: map[int64]bool{10: true, 11: true}}, nil, nil, phone.Normalizer{})
+
+	tests := []struct {
+		name   string
+		userID int64
+		id     string
+		want   int
+		body   string
+	}{
+		{"by id", 1, "10", http.StatusOK, `{"teacher_id":10,"disciplines":2,"groups":1,"students":25}`},
+		{"without disciplines", 1, "11", http.StatusOK, `{"teacher_id":11,"disciplines":0,"groups":0,"students":0}`},
+		{"unknown teacher", 1, "99", http.StatusNotFound, ""},
+		{"invalid id", 1, "x", http.StatusBadRequest, ""},
+		{"me", 10, "", http.StatusOK, `{"teacher_id":10,"disciplines":2,"groups":1,"students":25}`},
+		{"me not a teacher", 99, "", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if tt.id == "" {
+				r := authorize(t, httptest.NewRequest(http.MethodGet, "/api/v1/teacher/me/workload", nil), tt.userID, "teacher:view_self")
+				h.GetMyTeacherWorkload(discardLogger())(rec, r)
+			} else {
+				r := authorize(t, httptest.NewRequest(http.MethodGet, "/api/v1/teacher/"+tt.id+"/workload", nil), tt.userID, "teacher:view")
+				h.GetTeacherWorkload(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			}
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.body != "" && strings.TrimSpace(rec.Body.String()) != tt.body {
+				t.Errorf("body = %s, want %s", rec.Body, tt.body)
+			}
+		})
+	}
+
+	// без пользователя в контексте — 401
+	rec := httptest.NewRecorder()
+	h.GetMyTeacherWorkload(discardLogger())(rec, httptest.NewRequest(http.MethodGet, "/api/v1/teacher/me/workload", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous: status = %d, want 401", rec.Code)
+	}
+}
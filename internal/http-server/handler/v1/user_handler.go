@@ -2,6 +2,8 @@ package v1
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -10,6 +12,7 @@ import (
 
 	resp "service/internal/lib/api/response"
 	"service/internal/lib/utils"
+	"service/internal/storage"
 	"strconv"
 
 	"database/sql"
@@ -17,24 +20,33 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type UserRepository interface {
 	CreateClient(ctx context.Context, user *models.User) error
+	CreateClientWithRole(ctx context.Context, user *models.User, roleName string) (bool, error)
 	GetClientByID(ctx context.Context, id int64) (*models.User, error)
 	GetClientByEmail(ctx context.Context, email string) (*models.User, error)
 	UpdateClient(ctx context.Context, user *models.User) error
 	DeleteClient(ctx context.Context, id int64) error
-	ListClient(ctx context.Context, limit, offset int) ([]*models.User, error)
+	ListClient(ctx context.Context, active *bool, limit, offset int) ([]*models.User, error)
+	CountClient(ctx context.Context, active *bool) (int64, error)
+	GetClientsByIDs(ctx context.Context, ids []int64) (map[int64]*models.User, error)
+	UpdateClientPassword(ctx context.Context, id int64, password []byte) error
+	PatchClient(ctx context.Context, id int64, patch *models.UserPatch, passwordHash []byte) error
+	SetClientActive(ctx context.Context, id int64, active bool) error
+	GetUserProfile(ctx context.Context, id int64) (*models.UserProfile, error)
 }
 
 type UserHandler struct {
 	repo      UserRepository
+	roleRepo  UserRoleRepository
 	auditRepo AuditLogRepository
 }
 
-func NewUserHandler(repo UserRepository, auditRepo AuditLogRepository) *UserHandler {
-	return &UserHandler{repo: repo, auditRepo: auditRepo}
+func NewUserHandler(repo UserRepository, roleRepo UserRoleRepository, auditRepo AuditLogRepository) *UserHandler {
+	return &UserHandler{repo: repo, roleRepo: roleRepo, auditRepo: auditRepo}
 }
 
 // @Summary Создать пользователя
@@ -58,25 +70,31 @@ func (h *UserHandler) CreateUser(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		if err := h.repo.CreateClient(r.Context(), &user); err != nil {
+			if errors.Is(err, storage.ErrDuplicate) {
+				log.Info("email already taken")
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "email already exists"))
+				return
+			}
 			log.Error("failed to create user", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to create user"))
+			renderServerError(w, r, err, "failed to create user")
 			return
 		}
 
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
 			UserID:     utils.GetUserIDFromContext(r.Context()),
 			TableName:  "user",
-			RowID:      user.UserID,
+			RowID:      int64(user.UserID),
 			ActionType: "INSERT",
 			NewData:    utils.PtrToJSON(user),
 			Comment:    utils.PtrToStr("User created"),
 		})
 
+		setLocation(w, "users", int64(user.UserID))
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, user)
 	}
@@ -105,7 +123,7 @@ func (h *UserHandler) GetUserByID(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid user id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid user id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid user id"))
 			return
 		}
 		user, err := h.repo.GetClientByID(r.Context(), id)
@@ -113,27 +131,80 @@ func (h *UserHandler) GetUserByID(log *slog.Logger) http.HandlerFunc {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("user not found", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("user not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "user not found"))
 				return
 			}
 			log.Error("failed to get user", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get user"))
+			renderServerError(w, r, err, "failed to get user")
 			return
 		}
 		render.JSON(w, r, user)
 	}
 }
 
+// @Summary Полный профиль пользователя
+// @Description Данные пользователя с блоком student или teacher и ролями. Если пользователь не студент и не преподаватель, type = user
+// @Tags users
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} models.UserProfile
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/users/{id}/profile [get]
+// @Security BearerAuth
+func (h *UserHandler) GetUserProfile(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.user.GetUserProfile"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid user id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid user id"))
+			return
+		}
+		profile, err := h.repo.GetUserProfile(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("user not found", slog.Int64("user_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "user not found"))
+				return
+			}
+			log.Error("failed to get user profile", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get user profile")
+			return
+		}
+
+		roles, err := h.roleRepo.GetRolesByUserID(r.Context(), id)
+		if err != nil {
+			log.Error("failed to get user roles", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get user roles")
+			return
+		}
+		if roles == nil {
+			roles = []*models.UserRole{}
+		}
+		profile.Roles = roles
+		render.JSON(w, r, profile)
+	}
+}
+
 // @Summary Обновить пользователя
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path int true "ID пользователя"
-// @Param input body models.User true "Пользователь"
+// @Param input body models.UserUpdate true "Пользователь"
 // @Success 200 {object} models.User
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Router /api/v1/users/{id} [put]
 // @Security BearerAuth
@@ -149,35 +220,69 @@ func (h *UserHandler) UpdateUser(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid user id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
-		var user models.User
+		var req models.UserUpdate
 		oldUser, _ := h.repo.GetClientByID(r.Context(), id)
-		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
-		user.UserID = id
+		user := models.User{
+			UserID:     models.ID(id),
+			FirstName:  req.FirstName,
+			LastName:   req.LastName,
+			MiddleName: req.MiddleName,
+			Email:      req.Email,
+		}
+		// пустой пароль репозиторий не меняет, иначе сохраняем только хеш
+		if req.Password != "" {
+			user.Password, err = bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+			if err != nil {
+				log.Error("failed to hash password", slog.String("err", err.Error()))
+				renderServerError(w, r, err, "failed to update user")
+				return
+			}
+		}
+
+		// email уникален: разрешаем только свой текущий адрес
+		if existing, err := h.repo.GetClientByEmail(r.Context(), user.Email); err == nil && int64(existing.UserID) != id {
+			log.Info("email already taken", slog.Int64("user_id", id))
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.ErrorFor(r, "email already exists"))
+			return
+		}
+
 		if err := h.repo.UpdateClient(r.Context(), &user); err != nil {
+			if errors.Is(err, storage.ErrDuplicate) {
+				log.Info("email already taken", slog.Int64("user_id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "email already exists"))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("user not found for update", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("user not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "user not found"))
 				return
 			}
 			log.Info("failed to update user", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to update user"))
+			renderServerError(w, r, err, "failed to update user")
 			return
 		}
+		// хеш пароля не попадает ни в ответ, ни в аудит
+		user.Password = nil
+		if oldUser != nil {
+			oldUser.Password = nil
+		}
 
 		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
 			UserID:     utils.GetUserIDFromContext(r.Context()),
 			TableName:  "user",
-			RowID:      user.UserID,
+			RowID:      int64(user.UserID),
 			ActionType: "UPDATE",
 			OldData:    utils.PtrToJSON(oldUser),
 			NewData:    utils.PtrToJSON(user),
@@ -189,6 +294,112 @@ func (h *UserHandler) UpdateUser(log *slog.Logger) http.HandlerFunc {
 	}
 }
 
+// @Summary Частично обновить пользователя
+// @Description Обновляются только переданные поля, "middle_name": null очищает отчество
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param input body models.UserPatch true "Изменяемые поля"
+// @Success 200 {object} models.User
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/users/{id} [patch]
+// @Security BearerAuth
+func (h *UserHandler) PatchUser(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.user.PatchUser"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid user id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid user id"))
+			return
+		}
+		var patch models.UserPatch
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+
+		oldUser, err := h.repo.GetClientByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("user not found for patch", slog.Int64("user_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "user not found"))
+				return
+			}
+			log.Error("failed to get user", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update user")
+			return
+		}
+
+		if patch.Email != nil {
+			if existing, err := h.repo.GetClientByEmail(r.Context(), *patch.Email); err == nil && int64(existing.UserID) != id {
+				log.Info("email already taken", slog.Int64("user_id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "email already exists"))
+				return
+			}
+		}
+
+		var passwordHash []byte
+		if patch.Password != nil {
+			passwordHash, err = bcrypt.GenerateFromPassword([]byte(*patch.Password), bcrypt.DefaultCost)
+			if err != nil {
+				log.Error("failed to hash password", slog.String("err", err.Error()))
+				renderServerError(w, r, err, "failed to update user")
+				return
+			}
+		}
+
+		if err := h.repo.PatchClient(r.Context(), id, &patch, passwordHash); err != nil {
+			if errors.Is(err, storage.ErrDuplicate) {
+				log.Info("email already taken", slog.Int64("user_id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, "email already exists"))
+				return
+			}
+			log.Error("failed to patch user", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update user")
+			return
+		}
+
+		user, err := h.repo.GetClientByID(r.Context(), id)
+		if err != nil {
+			log.Error("failed to get user", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update user")
+			return
+		}
+		// хеши паролей не пишем ни в аудит, ни в ответ
+		oldUser.Password = nil
+		user.Password = nil
+
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "user",
+			RowID:      id,
+			ActionType: "UPDATE",
+			OldData:    utils.PtrToJSON(oldUser),
+			NewData:    utils.PtrToJSON(user),
+			Changes:    utils.PtrToJSONDiff(oldUser, user),
+			Comment:    utils.PtrToStr("User patched"),
+		})
+
+		render.JSON(w, r, user)
+	}
+}
+
 // @Summary Удалить пользователя
 // @Tags users
 // @Accept json
@@ -212,20 +423,25 @@ func (h *UserHandler) DeleteUser(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid user id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid user id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid user id"))
 			return
 		}
 		oldUser, _ := h.repo.GetClientByID(r.Context(), id)
 		if err := h.repo.DeleteClient(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrReferenced) {
+				log.Info("user is referenced by other records", slog.Int64("id", id))
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, resp.ErrorFor(r, referencedMessage("user", err)))
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("user not found for delete", slog.Int64("user_id", id))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("user not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "user not found"))
 				return
 			}
 			log.Error("failed to delete user", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to delete user"))
+			renderServerError(w, r, err, "failed to delete user")
 			return
 		}
 
@@ -246,10 +462,14 @@ func (h *UserHandler) DeleteUser(log *slog.Logger) http.HandlerFunc {
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param limit query int false "Ограничение"
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
 // @Param offset query int false "Смещение"
+// @Param is_active query bool false "Только активные (true) или деактивированные (false)"
+// @Param with_total query bool false "Вернуть общее число записей в X-Total-Count"
 // @Success 200 {array} models.User
+// @Failure 400 {object} resp.Response
 // @Failure 500 {object} resp.Response
+// @Header 200 {integer} X-Total-Count "Общее число записей под фильтром"
 // @Router /api/v1/users [get]
 // @Security BearerAuth
 func (h *UserHandler) ListUsers(log *slog.Logger) http.HandlerFunc {
@@ -259,20 +479,199 @@ func (h *UserHandler) ListUsers(log *slog.Logger) http.HandlerFunc {
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
-		limitStr := r.URL.Query().Get("limit")
-		offsetStr := r.URL.Query().Get("offset")
-		limit, _ := strconv.Atoi(limitStr)
-		offset, _ := strconv.Atoi(offsetStr)
-		if limit == 0 {
-			limit = 20
-		}
-		users, err := h.repo.ListClient(r.Context(), limit, offset)
+		limit, offset := parsePagination(r)
+		var active *bool
+		if v := r.URL.Query().Get("is_active"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Info("invalid is_active", slog.String("is_active", v))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, "invalid is_active"))
+				return
+			}
+			active = &b
+		}
+		if err := setTotalCount(w, r, func() (int64, error) { return h.repo.CountClient(r.Context(), active) }); err != nil {
+			log.Error("failed to count users", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list users")
+			return
+		}
+		users, err := h.repo.ListClient(r.Context(), active, limit, offset)
 		if err != nil {
 			log.Error("failed to list users", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to list users"))
+			renderServerError(w, r, err, "failed to list users")
 			return
 		}
 		render.JSON(w, r, users)
 	}
 }
+
+// @Summary Сбросить пароль пользователя
+// @Description Если пароль не передан, генерируется случайный и возвращается один раз в ответе
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param input body models.PasswordResetRequest false "Новый пароль"
+// @Success 200 {object} models.PasswordResetResponse
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/users/{id}/password-reset [post]
+// @Security BearerAuth
+func (h *UserHandler) ResetPassword(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.user.ResetPassword"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid user id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid user id"))
+			return
+		}
+		var req models.PasswordResetRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				log.Info("failed to decode request body", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+				return
+			}
+		}
+
+		var result models.PasswordResetResponse
+		result.UserID = models.ID(id)
+		password := req.Password
+		if password == "" {
+			password, err = generatePassword()
+			if err != nil {
+				log.Error("failed to generate password", slog.String("err", err.Error()))
+				renderServerError(w, r, err, "failed to reset password")
+				return
+			}
+			result.Password = password
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			log.Error("failed to hash password", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to reset password")
+			return
+		}
+		if err := h.repo.UpdateClientPassword(r.Context(), id, hash); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("user not found for password reset", slog.Int64("user_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "user not found"))
+				return
+			}
+			log.Error("failed to reset password", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to reset password")
+			return
+		}
+
+		// сам пароль и хеш в аудит не пишем
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "user",
+			RowID:      id,
+			ActionType: "UPDATE",
+			Comment:    utils.PtrToStr("Password reset by admin"),
+		})
+
+		render.JSON(w, r, result)
+	}
+}
+
+// @Summary Деактивировать пользователя
+// @Description Пользователь остаётся в БД (оценки и аудит сохраняются), но не может войти, а выданные ему токены перестают приниматься
+// @Tags users
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} models.User
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/users/{id}/deactivate [post]
+// @Security BearerAuth
+func (h *UserHandler) DeactivateUser(log *slog.Logger) http.HandlerFunc {
+	return h.setUserActive(log, "handler.v1.user.DeactivateUser", false)
+}
+
+// @Summary Активировать пользователя
+// @Tags users
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} models.User
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/users/{id}/activate [post]
+// @Security BearerAuth
+func (h *UserHandler) ActivateUser(log *slog.Logger) http.HandlerFunc {
+	return h.setUserActive(log, "handler.v1.user.ActivateUser", true)
+}
+
+func (h *UserHandler) setUserActive(log *slog.Logger, op string, active bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid user id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid user id"))
+			return
+		}
+		if err := h.repo.SetClientActive(r.Context(), id, active); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("user not found", slog.Int64("user_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "user not found"))
+				return
+			}
+			log.Error("failed to change user status", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to change user status")
+			return
+		}
+
+		user, err := h.repo.GetClientByID(r.Context(), id)
+		if err != nil {
+			log.Error("failed to get user", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get user")
+			return
+		}
+		user.Password = nil
+
+		comment := "User deactivated"
+		if active {
+			comment = "User activated"
+		}
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "user",
+			RowID:      id,
+			ActionType: "UPDATE",
+			NewData:    utils.PtrToJSON(user),
+			Comment:    utils.PtrToStr(comment),
+		})
+
+		render.JSON(w, r, user)
+	}
+}
+
+func generatePassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,551 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	resp "service/internal/lib/api/response"
+	"service/internal/storage"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memUserRepo хранит пользователей в памяти и повторяет контракт репозитория:
+// пустой пароль в UpdateClient не меняет хеш, отсутствующий id — sql.ErrNoRows
+type memUserRepo struct {
+	UserRepository
+	mu    sync.Mutex
+	users map[int64]models.User
+}
+
+func newMemUserRepo(users ...models.User) *memUserRepo {
+	repo := &memUserRepo{users: map[int64]models.User{}}
+	for _, u := range users {
+		repo.users[int64(u.UserID)] = u
+	}
+	return repo
+}
+
+func (m *memUserRepo) GetClientByID(_ context.Context, id int64) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &u, nil
+}
+
+func (m *memUserRepo) GetClientByEmail(_ context.Context, email string) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range m.users {
+		if strings.EqualFold(u.Email, email) {
+			return &u, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memUserRepo) UpdateClient(_ context.Context, user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old, ok := m.users[int64(user.UserID)]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	// уникальный индекс по email
+	for id, u := range m.users {
+		if id != int64(user.UserID) && strings.EqualFold(u.Email, user.Email) {
+			return storage.ErrDuplicate
+		}
+	}
+	updated := *user
+	updated.IsActive = old.IsActive
+	if len(updated.Password) == 0 {
+		updated.Password = old.Password
+	}
+	m.users[int64(user.UserID)] = updated
+	return nil
+}
+
+func (m *memUserRepo) PatchClient(_ context.Context, id int64, patch *models.UserPatch, passwordHash []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if patch.FirstName != nil {
+		u.FirstName = *patch.FirstName
+	}
+	if patch.LastName != nil {
+		u.LastName = *patch.LastName
+	}
+	if patch.MiddleName.Set {
+		u.MiddleName = patch.MiddleName.Value
+	}
+	if patch.Email != nil {
+		u.Email = *patch.Email
+	}
+	if passwordHash != nil {
+		u.Password = passwordHash
+	}
+	m.users[id] = u
+	return nil
+}
+
+func (m *memUserRepo) UpdateClientPassword(_ context.Context, id int64, hash []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	u.Password = hash
+	m.users[id] = u
+	return nil
+}
+
+func (m *memUserRepo) SetClientActive(_ context.Context, id int64, active bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	u.IsActive = active
+	m.users[id] = u
+	return nil
+}
+
+func (m *memUserRepo) IsClientActive(_ context.Context, id int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[id]
+	if !ok {
+		return false, sql.ErrNoRows
+	}
+	return u.IsActive, nil
+}
+
+func putUser(t *testing.T, h *UserHandler, id, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPut, "/api/v1/users/"+id, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.UpdateUser(discardLogger())(rec, withURLParams(r, "id", id))
+	return rec
+}
+
+func login(t *testing.T, repo UserRepository, email, password string) int {
+	t.Helper()
+	auth := NewAuthHandler(repo, nil, nil, testJWTSecret, time.Hour, "")
+	rec := httptest.NewRecorder()
+	body := `{"email":"` + email + `","password":"` + password + `"}`
+	auth.Login(discardLogger())(rec, httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(body)))
+	return rec.Code
+}
+
+func TestUpdateUser_WithoutPasswordKeepsLogin(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newMemUserRepo(models.User{UserID: 5, Email: "ivanov@example.com", Password: hash, IsActive: true})
+	audit := &recordingAudit{}
+	h := NewUserHandler(repo, nil, audit)
+
+	rec := putUser(t, h, "5", `{"first_name":"Иван","last_name":"Иванов","email":"ivanov@example.com"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if code := login(t, repo, "ivanov@example.com", "old-pass"); code != http.StatusOK {
+		t.Fatalf("login after update without password: status %d", code)
+	}
+
+	rec = putUser(t, h, "5", `{"first_name":"Иван","last_name":"Иванов","email":"ivanov@example.com","password":"new-pass"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if code := login(t, repo, "ivanov@example.com", "new-pass"); code != http.StatusOK {
+		t.Fatalf("login with new password: status %d", code)
+	}
+	if code := login(t, repo, "ivanov@example.com", "old-pass"); code != http.StatusUnauthorized {
+		t.Fatalf("login with old password: status %d, want 401", code)
+	}
+
+	// хеш не возвращается клиенту и не пишется в аудит
+	if strings.Contains(rec.Body.String(), "new-pass") || strings.Contains(rec.Body.String(), `"password":"`) {
+		t.Fatalf("response leaks password: %s", rec.Body)
+	}
+	for _, e := range audit.entries {
+		for _, data := range []*string{e.OldData, e.NewData} {
+			if data != nil && strings.Contains(*data, `"password":"`) {
+				t.Fatalf("audit leaks password: %s", *data)
+			}
+		}
+	}
+}
+
+func TestUpdateUser_MissingUser(t *testing.T) {
+	h := NewUserHandler(newMemUserRepo(), nil, &recordingAudit{})
+	rec := putUser(t, h, "404", `{"first_name":"Иван","last_name":"Иванов","email":"nobody@example.com"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status %d, want 404", rec.Code)
+	}
+}
+
+func TestLogin_InactiveUserRejectedUntilReactivated(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newMemUserRepo(models.User{UserID: 5, Email: "ivanov@example.com", Password: hash, IsActive: true})
+	h := NewUserHandler(repo, nil, &recordingAudit{})
+	setActive := func(handler http.HandlerFunc) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler(rec, withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/5", nil), "id", "5"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", rec.Code, rec.Body)
+		}
+	}
+
+	setActive(h.DeactivateUser(discardLogger()))
+	if code := login(t, repo, "ivanov@example.com", "pass"); code != http.StatusForbidden {
+		t.Fatalf("inactive user login: status %d, want 403", code)
+	}
+	// статус учётки не раскрывается без верного пароля
+	if code := login(t, repo, "ivanov@example.com", "wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("inactive user with wrong password: status %d, want 401", code)
+	}
+
+	setActive(h.ActivateUser(discardLogger()))
+	if code := login(t, repo, "ivanov@example.com", "pass"); code != http.StatusOK {
+		t.Fatalf("reactivated user login: status %d, want 200", code)
+	}
+}
+
+func TestDeactivateUser_RejectsIssuedToken(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newMemUserRepo(models.User{UserID: 5, Email: "ivanov@example.com", Password: hash, IsActive: true})
+
+	rec := httptest.NewRecorder()
+	body := `{"email":"ivanov@example.com","password":"pass"}`
+	NewAuthHandler(repo, nil, nil, testJWTSecret, time.Hour, "").Login(discardLogger())(rec, httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(body)))
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil || out.Token == "" {
+		t.Fatalf("login: status %d, body %s", rec.Code, rec.Body)
+	}
+
+	// цепочка защищённых маршрутов: токен, затем статус пользователя
+	protected := ware.JWTAuth(testJWTSecret, nil)(ware.ActiveUser(repo)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+	request := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+		r.Header.Set("Authorization", "Bearer "+out.Token)
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, r)
+		return rec
+	}
+	if rec := request(); rec.Code != http.StatusOK {
+		t.Fatalf("before deactivation: status %d", rec.Code)
+	}
+
+	h := NewUserHandler(repo, nil, &recordingAudit{})
+	rec = httptest.NewRecorder()
+	h.DeactivateUser(discardLogger())(rec, withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/5/deactivate", nil), "id", "5"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("deactivate: status %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = request()
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("token issued before deactivation: status %d, want 403", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), resp.MsgUserDeactivated) {
+		t.Errorf("body = %s, want %q", rec.Body, resp.MsgUserDeactivated)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ActivateUser(discardLogger())(rec, withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/5/activate", nil), "id", "5"))
+	if rec := request(); rec.Code != http.StatusOK {
+		t.Fatalf("after reactivation: status %d, want 200", rec.Code)
+	}
+}
+
+func resetPassword(t *testing.T, h *UserHandler, id, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/users/"+id+"/password-reset", strings.NewReader(body))
+	r = authorize(t, r, 1, "user:reset_password")
+	rec := httptest.NewRecorder()
+	h.ResetPassword(discardLogger())(rec, withURLParams(r, "id", id))
+	return rec
+}
+
+func TestResetPassword_UpdatesHashAndWritesAudit(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("forgotten"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newMemUserRepo(models.User{UserID: 5, Email: "locked@example.com", Password: hash, IsActive: true})
+	audit := &recordingAudit{}
+	h := NewUserHandler(repo, nil, audit)
+
+	rec := resetPassword(t, h, "5", `{"password":"chosen-by-admin"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var res models.PasswordResetResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	// заданный администратором пароль обратно не возвращается
+	if res.UserID != 5 || res.Password != "" {
+		t.Fatalf("response = %+v", res)
+	}
+	stored, _ := repo.GetClientByID(context.Background(), 5)
+	if bcrypt.CompareHashAndPassword(stored.Password, []byte("chosen-by-admin")) != nil {
+		t.Fatal("stored hash does not match the new password")
+	}
+	if code := login(t, repo, "locked@example.com", "forgotten"); code != http.StatusUnauthorized {
+		t.Errorf("login with old password: status %d, want 401", code)
+	}
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(audit.entries))
+	}
+	entry := audit.entries[0]
+	if entry.TableName != "user" || entry.RowID != 5 || entry.ActionType != "UPDATE" {
+		t.Errorf("audit entry = %+v", entry)
+	}
+	if entry.UserID == nil || *entry.UserID != 1 {
+		t.Errorf("audit user = %v, want admin 1", entry.UserID)
+	}
+	if entry.OldData != nil || entry.NewData != nil {
+		t.Error("audit entry must not carry the password hash")
+	}
+}
+
+func TestResetPassword_GeneratedPasswordReturnedOnce(t *testing.T) {
+	repo := newMemUserRepo(models.User{UserID: 5, Email: "locked@example.com", Password: []byte("x"), IsActive: true})
+	h := NewUserHandler(repo, nil, &recordingAudit{})
+
+	rec := resetPassword(t, h, "5", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var res models.PasswordResetResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Password == "" {
+		t.Fatal("generated password not returned")
+	}
+	if code := login(t, repo, "locked@example.com", res.Password); code != http.StatusOK {
+		t.Fatalf("login with generated password: status %d", code)
+	}
+}
+
+func TestResetPassword_MissingUser(t *testing.T) {
+	audit := &recordingAudit{}
+	h := NewUserHandler(newMemUserRepo(), nil, audit)
+	if rec := resetPassword(t, h, "42", `{"password":"whatever"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("status %d, want 404", rec.Code)
+	}
+	if len(audit.entries) != 0 {
+		t.Fatalf("audit written for missing user: %+v", audit.entries)
+	}
+}
+
+// staleEmailLookup не находит email при проверке, как при гонке двух обновлений
+type staleEmailLookup struct {
+	*memUserRepo
+}
+
+func (staleEmailLookup) GetClientByEmail(context.Context, string) (*models.User, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestUpdateUser_EmailUnique(t *testing.T) {
+	newRepo := func() *memUserRepo {
+		return newMemUserRepo(
+			models.User{UserID: 5, FirstName: "Иван", Email: "ivanov@example.com", IsActive: true},
+			models.User{UserID: 6, FirstName: "Пётр", Email: "petrov@example.com", IsActive: true},
+		)
+	}
+	tests := []struct {
+		name      string
+		repo      func(*memUserRepo) UserRepository
+		email     string
+		want      int
+		wantEmail string
+	}{
+		{"свой email без изменений", nil, "ivanov@example.com", http.StatusOK, "ivanov@example.com"},
+		{"новый свободный email", nil, "ivan@example.com", http.StatusOK, "ivan@example.com"},
+		{"email другого пользователя", nil, "petrov@example.com", http.StatusConflict, "ivanov@example.com"},
+		{"email другого пользователя в другом регистре", nil, "Petrov@Example.com", http.StatusConflict, "ivanov@example.com"},
+		{
+			"дубликат от уникального индекса",
+			func(m *memUserRepo) UserRepository { return staleEmailLookup{m} },
+			"petrov@example.com", http.StatusConflict, "ivanov@example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := newRepo()
+			var repo UserRepository = mem
+			if tt.repo != nil {
+				repo = tt.repo(mem)
+			}
+			audit := &recordingAudit{}
+			h := NewUserHandler(repo, nil, audit)
+
+			rec := putUser(t, h, "5", `{"first_name":"Иван","last_name":"Иванов","email":"`+tt.email+`"}`)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if got, _ := mem.GetClientByID(context.Background(), 5); got.Email != tt.wantEmail {
+				t.Errorf("stored email = %q, want %q", got.Email, tt.wantEmail)
+			}
+			if tt.want == http.StatusConflict && len(audit.entries) != 0 {
+				t.Errorf("audit written on conflict: %+v", audit.entries)
+			}
+		})
+	}
+}
+
+func patchUser(t *testing.T, h *UserHandler, id, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPatch, "/api/v1/users/"+id, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.PatchUser(discardLogger())(rec, withURLParams(r, "id", id))
+	return rec
+}
+
+func TestPatchUser_OmittedFieldsUntouched(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	middle := "Петрович"
+	original := models.User{
+		UserID: 5, FirstName: "Иван", LastName: "Иванов", MiddleName: &middle,
+		Email: "ivanov@example.com", Password: hash, IsActive: true,
+	}
+	repo := newMemUserRepo(original)
+	h := NewUserHandler(repo, nil, &recordingAudit{})
+
+	rec := patchUser(t, h, "5", `{"first_name":"Иоанн"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), `"password":"`) {
+		t.Errorf("response leaks password hash: %s", rec.Body)
+	}
+
+	got, _ := repo.GetClientByID(context.Background(), 5)
+	if got.FirstName != "Иоанн" {
+		t.Errorf("first_name = %q, want patched value", got.FirstName)
+	}
+	if got.LastName != original.LastName || got.Email != original.Email || got.MiddleName == nil || *got.MiddleName != middle {
+		t.Errorf("omitted fields changed: %+v", got)
+	}
+	if string(got.Password) != string(hash) {
+		t.Error("password hash changed without password in patch")
+	}
+	if code := login(t, repo, "ivanov@example.com", "old-pass"); code != http.StatusOK {
+		t.Errorf("login after patch: status %d", code)
+	}
+
+	// пустой объект ничего не меняет
+	if rec := patchUser(t, h, "5", `{}`); rec.Code != http.StatusOK {
+		t.Fatalf("empty patch: status %d: %s", rec.Code, rec.Body)
+	}
+	if again, _ := repo.GetClientByID(context.Background(), 5); !reflect.DeepEqual(again, got) {
+		t.Errorf("empty patch changed user: %+v", again)
+	}
+}
+
+// profileRepo профили пользователей по id
+type profileRepo struct {
+	UserRepository
+	profiles map[int64]*models.UserProfile
+}
+
+func (p profileRepo) GetUserProfile(_ context.Context, id int64) (*models.UserProfile, error) {
+	profile, ok := p.profiles[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *profile
+	return &cp, nil
+}
+
+func TestGetUserProfile(t *testing.T) {
+	repo := profileRepo{profiles: map[int64]*models.UserProfile{
+		7: {UserID: 7, FirstName: "Анна", Type: models.UserTypeStudent,
+			Student: &models.StudentProfile{Phone: "+79001234567", StudentGroupID: 2, StudentGroupName: "ИВТ-21"}},
+		10: {UserID: 10, FirstName: "Олег", Type: models.UserTypeTeacher,
+			Teacher: &models.TeacherProfile{Phone: "+79007654321"}},
+	}}
+	roles := userRoles{roles: map[int64][]*models.UserRole{7: {{RoleID: 4, UserID: 7}}}}
+	h := NewUserHandler(repo, roles, &recordingAudit{})
+
+	tests := []struct {
+		name     string
+		id       string
+		want     int
+		wantType string
+		roles    int
+	}{
+		{"student", "7", http.StatusOK, models.UserTypeStudent, 1},
+		// без ролей — пустой массив, а не null
+		{"teacher", "10", http.StatusOK, models.UserTypeTeacher, 0},
+		{"not found", "404", http.StatusNotFound, "", 0},
+		{"invalid id", "x", http.StatusBadRequest, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+tt.id+"/profile", nil)
+			rec := httptest.NewRecorder()
+			h.GetUserProfile(discardLogger())(rec, withURLParams(r, "id", tt.id))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+				t.Fatal(err)
+			}
+			var got models.UserProfile
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Type != tt.wantType || got.Roles == nil || len(got.Roles) != tt.roles {
+				t.Errorf("profile = %s", rec.Body)
+			}
+			// в ответе только блок своего типа
+			_, hasStudent := raw["student"]
+			_, hasTeacher := raw["teacher"]
+			if hasStudent != (tt.wantType == models.UserTypeStudent) || hasTeacher != (tt.wantType == models.UserTypeTeacher) {
+				t.Errorf("blocks student=%v teacher=%v for type %s", hasStudent, hasTeacher, tt.wantType)
+			}
+		})
+	}
+}
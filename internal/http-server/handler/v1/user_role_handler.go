@@ -55,13 +55,12 @@ func (h *UserRoleHandler) AssignRole(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		if err := h.repo.AssignRole(r.Context(), input.UserID, input.RoleID); err != nil {
 			log.Error("failed to assign role", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to assign role"))
+			renderServerError(w, r, err, "failed to assign role")
 			return
 		}
 
@@ -98,13 +97,12 @@ func (h *UserRoleHandler) RemoveRole(log *slog.Logger) http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 			log.Info("failed to decode request body", slog.String("err", err.Error()))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid request"))
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
 			return
 		}
 		if err := h.repo.RemoveRole(r.Context(), input.UserID, input.RoleID); err != nil {
 			log.Error("failed to remove role", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to remove role"))
+			renderServerError(w, r, err, "failed to remove role")
 			return
 		}
 
@@ -146,7 +144,7 @@ func (h *UserRoleHandler) GetRolesByUserID(log *slog.Logger) http.HandlerFunc {
 		if err != nil {
 			log.Info("invalid user id", slog.String("id", idStr))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid user id"))
+			render.JSON(w, r, resp.ErrorFor(r, "invalid user id"))
 			return
 		}
 		users_role, err := h.repo.GetRolesByUserID(r.Context(), id)
@@ -154,12 +152,11 @@ func (h *UserRoleHandler) GetRolesByUserID(log *slog.Logger) http.HandlerFunc {
 			if errors.Is(err, sql.ErrNoRows) {
 				log.Info("user roles not found", slog.Any("users_role", users_role))
 				w.WriteHeader(http.StatusNotFound)
-				render.JSON(w, r, resp.Error("user roles not found"))
+				render.JSON(w, r, resp.ErrorFor(r, "user roles not found"))
 				return
 			}
 			log.Error("failed to get user roles", slog.String("err", err.Error()))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get user roles"))
+			renderServerError(w, r, err, "failed to get user roles")
 			return
 		}
 
@@ -0,0 +1,286 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"service/internal/domain/models"
+	resp "service/internal/lib/api/response"
+	"service/internal/lib/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, wh *models.Webhook) error
+	GetWebhookByID(ctx context.Context, id int64) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, wh *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListWebhook(ctx context.Context, limit, offset int) ([]*models.Webhook, error)
+}
+
+// EventDispatcher рассылает доменные события (например, во вебхуки)
+type EventDispatcher interface {
+	Dispatch(ctx context.Context, event string, data interface{})
+}
+
+type WebhookHandler struct {
+	repo      WebhookRepository
+	auditRepo AuditLogRepository
+}
+
+func NewWebhookHandler(repo WebhookRepository, auditRepo AuditLogRepository) *WebhookHandler {
+	return &WebhookHandler{repo: repo, auditRepo: auditRepo}
+}
+
+// validateWebhook проверяет общие для создания и обновления поля: url — абсолютный http(s) адрес,
+// event_types не пустой. Возвращает текст ошибки для 400 или пустую строку.
+func validateWebhook(wh *models.Webhook) string {
+	if wh.URL == "" || len(wh.EventTypes) == 0 {
+		return "url and event_types required"
+	}
+	u, err := url.ParseRequestURI(wh.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "url must be an absolute http or https URL"
+	}
+	return ""
+}
+
+// @Summary Создать вебхук
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param input body models.Webhook true "Вебхук"
+// @Success 201 {object} models.Webhook
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /api/v1/webhooks [post]
+// @Security BearerAuth
+func (h *WebhookHandler) CreateWebhook(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.webhook_handler.CreateWebhook"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		var wh models.Webhook
+		if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		if wh.Secret == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "url, event_types and secret required"))
+			return
+		}
+		if msg := validateWebhook(&wh); msg != "" {
+			log.Info("invalid webhook", slog.String("reason", msg))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, msg))
+			return
+		}
+		if err := h.repo.CreateWebhook(r.Context(), &wh); err != nil {
+			log.Error("failed to create webhook", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to create webhook")
+			return
+		}
+		wh.Secret = ""
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "webhook",
+			RowID:      wh.WebhookID,
+			ActionType: "INSERT",
+			NewData:    utils.PtrToJSON(wh),
+			Comment:    utils.PtrToStr("Webhook created"),
+		})
+		setLocation(w, "webhooks", wh.WebhookID)
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, wh)
+	}
+}
+
+// @Summary Получить вебхук по ID
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID вебхука"
+// @Success 200 {object} models.Webhook
+// @Failure 404 {object} resp.Response
+// @Router /api/v1/webhooks/{id} [get]
+// @Security BearerAuth
+func (h *WebhookHandler) GetWebhookByID(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.webhook_handler.GetWebhookByID"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid webhook id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid webhook id"))
+			return
+		}
+		wh, err := h.repo.GetWebhookByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("webhook not found", slog.Int64("webhook_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "webhook not found"))
+				return
+			}
+			log.Error("failed to get webhook", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to get webhook")
+			return
+		}
+		wh.Secret = ""
+		render.JSON(w, r, wh)
+	}
+}
+
+// @Summary Обновить вебхук
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID вебхука"
+// @Param input body models.Webhook true "Вебхук"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Router /api/v1/webhooks/{id} [put]
+// @Security BearerAuth
+func (h *WebhookHandler) UpdateWebhook(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.webhook_handler.UpdateWebhook"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid webhook id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid webhook id"))
+			return
+		}
+		var wh models.Webhook
+		if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
+			log.Info("failed to decode request body", slog.String("err", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, resp.MsgInvalidRequest))
+			return
+		}
+		if msg := validateWebhook(&wh); msg != "" {
+			log.Info("invalid webhook", slog.String("reason", msg))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, msg))
+			return
+		}
+		wh.WebhookID = id
+		oldData, err := h.repo.GetWebhookByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				log.Info("webhook not found for update", slog.Int64("webhook_id", id))
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, resp.ErrorFor(r, "webhook not found"))
+				return
+			}
+			log.Error("failed to get webhook", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update webhook")
+			return
+		}
+		// секрет можно не передавать, тогда остаётся прежний
+		if wh.Secret == "" {
+			wh.Secret = oldData.Secret
+		}
+		if err := h.repo.UpdateWebhook(r.Context(), &wh); err != nil {
+			log.Error("failed to update webhook", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to update webhook")
+			return
+		}
+		oldData.Secret = ""
+		wh.Secret = ""
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "webhook",
+			RowID:      id,
+			ActionType: "UPDATE",
+			NewData:    utils.PtrToJSON(wh),
+			OldData:    utils.PtrToJSON(oldData),
+			Comment:    utils.PtrToStr("Webhook updated"),
+		})
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, wh)
+	}
+}
+
+// @Summary Удалить вебхук
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID вебхука"
+// @Success 204 {string} string "No Content"
+// @Router /api/v1/webhooks/{id} [delete]
+// @Security BearerAuth
+func (h *WebhookHandler) DeleteWebhook(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.webhook_handler.DeleteWebhook"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			log.Info("invalid webhook id", slog.String("id", idStr))
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.ErrorFor(r, "invalid webhook id"))
+			return
+		}
+		oldData, _ := h.repo.GetWebhookByID(r.Context(), id)
+		if err := h.repo.DeleteWebhook(r.Context(), id); err != nil {
+			log.Error("failed to delete webhook", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to delete webhook")
+			return
+		}
+		if oldData != nil {
+			oldData.Secret = ""
+		}
+		_ = h.auditRepo.AddAuditLog(r.Context(), &models.AuditLog{
+			UserID:     utils.GetUserIDFromContext(r.Context()),
+			TableName:  "webhook",
+			RowID:      id,
+			ActionType: "DELETE",
+			OldData:    utils.PtrToJSON(oldData),
+			Comment:    utils.PtrToStr("Webhook deleted"),
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// @Summary Получить список вебхуков
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param limit query int false "Ограничение (по умолчанию 20, максимум 500; -1 — без ограничения при праве list:unbounded)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.Webhook
+// @Router /api/v1/webhooks [get]
+// @Security BearerAuth
+func (h *WebhookHandler) ListWebhook(log *slog.Logger) http.HandlerFunc {
+	const op = "handler.v1.webhook_handler.ListWebhook"
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(slog.String("op", op), slog.String("request_id", middleware.GetReqID(r.Context())))
+		limit, offset := parsePagination(r)
+		hooks, err := h.repo.ListWebhook(r.Context(), limit, offset)
+		if err != nil {
+			log.Error("failed to list webhooks", slog.String("err", err.Error()))
+			renderServerError(w, r, err, "failed to list webhooks")
+			return
+		}
+		for _, wh := range hooks {
+			wh.Secret = ""
+		}
+		render.JSON(w, r, hooks)
+	}
+}
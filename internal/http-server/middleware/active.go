@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"service/internal/lib/api/response"
+
+	"github.com/go-chi/render"
+)
+
+// ActiveChecker сообщает, активен ли пользователь, sql.ErrNoRows — пользователя нет
+type ActiveChecker interface {
+	IsClientActive(ctx context.Context, id int64) (bool, error)
+}
+
+// ActiveUser отклоняет запросы деактивированных пользователей. Выданные до деактивации
+// токены остаются валидными до истечения, поэтому статус проверяется на каждом запросе.
+// Ставится после JWTAuth; запросы без user id пропускаются — отказ остаётся за AuthRequired.
+func ActiveUser(users ActiveChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserID(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			active, err := users.IsClientActive(r.Context(), userID)
+			if err != nil {
+				// удалённый пользователь: токен больше ни к кому не относится
+				if errors.Is(err, sql.ErrNoRows) {
+					unauthorized(w, r, response.MsgInvalidToken)
+					return
+				}
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, response.ErrorFor(r, response.MsgInternal))
+				return
+			}
+			if !active {
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, response.ErrorFor(r, response.MsgUserDeactivated))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/domain/models"
+	libjwt "service/internal/lib/jwt"
+	"testing"
+	"time"
+)
+
+// activeUsers статусы пользователей по id, отсутствующий id — sql.ErrNoRows
+type activeUsers map[int64]bool
+
+func (u activeUsers) IsClientActive(_ context.Context, id int64) (bool, error) {
+	if id == 500 {
+		return false, errors.New("db is down")
+	}
+	active, ok := u[id]
+	if !ok {
+		return false, sql.ErrNoRows
+	}
+	return active, nil
+}
+
+func TestActiveUser(t *testing.T) {
+	const secret = "test-secret"
+	users := activeUsers{5: true, 6: false}
+	h := JWTAuth(secret, nil)(ActiveUser(users)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+
+	tests := []struct {
+		name   string
+		userID models.ID
+		want   int
+	}{
+		{"active", 5, http.StatusOK},
+		{"deactivated", 6, http.StatusForbidden},
+		{"deleted", 7, http.StatusUnauthorized},
+		{"db error", 500, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := libjwt.NewToken(models.User{UserID: tt.userID}, nil, time.Hour, secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
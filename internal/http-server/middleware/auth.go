@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"service/internal/lib/api/response"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,15 +14,13 @@ func AuthRequired() func(http.Handler) http.Handler {
 
 			claims, ok := r.Context().Value(userCtxKey).(jwt.MapClaims)
 			if !ok || claims == nil {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				unauthorized(w, r, response.MsgUnauthorized)
 				return
 			}
 
-			if exp, ok := claims["exp"].(float64); ok {
-				if int64(exp) < time.Now().Unix() {
-					http.Error(w, "token expired", http.StatusUnauthorized)
-					return
-				}
+			if exp, err := claims.GetExpirationTime(); err == nil && exp != nil && exp.Before(time.Now()) {
+				unauthorized(w, r, response.MsgTokenExpired)
+				return
 			}
 			next.ServeHTTP(w, r)
 		})
@@ -0,0 +1,68 @@
+package clientip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNew_ResolvesClientIP(t *testing.T) {
+	trusted, err := ParseTrusted([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		realIP     string
+		want       string
+	}{
+		{"untrusted peer ignores headers", "203.0.113.9:1234", "198.51.100.1", "198.51.100.2", "203.0.113.9"},
+		{"trusted peer uses forwarded for", "10.1.2.3:1234", "198.51.100.1", "", "198.51.100.1"},
+		{"skips trusted hops from the right", "10.1.2.3:1234", "198.51.100.1, 10.0.0.5, 192.0.2.1", "", "198.51.100.1"},
+		{"spoofed leftmost entry is ignored", "10.1.2.3:1234", "1.1.1.1, 198.51.100.1", "", "198.51.100.1"},
+		{"all hops trusted returns leftmost", "10.1.2.3:1234", "10.0.0.7, 10.0.0.5", "", "10.0.0.7"},
+		{"garbage hop stops the walk", "10.1.2.3:1234", "198.51.100.1, not-an-ip", "", "10.1.2.3"},
+		{"trusted peer falls back to real ip", "192.0.2.1:1234", "", "198.51.100.3", "198.51.100.3"},
+		{"trusted peer without headers", "192.0.2.1:1234", "", "", "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := New(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = FromRequest(r)
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				r.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), r)
+			if got != tt.want {
+				t.Fatalf("client ip = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrusted_RejectsInvalid(t *testing.T) {
+	if _, err := ParseTrusted([]string{"10.0.0.0/33"}); err == nil {
+		t.Fatal("expected error for invalid CIDR")
+	}
+	if _, err := ParseTrusted([]string{"proxy.local"}); err == nil {
+		t.Fatal("expected error for hostname")
+	}
+}
+
+func TestFromRequest_WithoutMiddleware(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "203.0.113.9:1234"
+	if got := FromRequest(r); got != "203.0.113.9" {
+		t.Fatalf("FromRequest = %q", got)
+	}
+}
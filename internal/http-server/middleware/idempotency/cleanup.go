@@ -0,0 +1,44 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Expirer interface {
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// RunCleanup периодически удаляет истёкшие ключи: сами по себе они перезаписываются
+// только при повторе того же ключа, и таблица росла бы без ограничений.
+// Блокирует до отмены ctx, при interval <= 0 сразу возвращается.
+func RunCleanup(ctx context.Context, repo Expirer, interval time.Duration, log *slog.Logger) {
+	if interval <= 0 {
+		return
+	}
+	log = log.With(slog.String("component", "idempotency/cleanup"))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cleanup(ctx, repo, log)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func cleanup(ctx context.Context, repo Expirer, log *slog.Logger) {
+	now := time.Now().UTC()
+	n, err := repo.DeleteExpired(ctx, now)
+	if err != nil {
+		log.Error("failed to delete expired idempotency keys", slog.String("err", err.Error()))
+		return
+	}
+	log.Info("expired idempotency keys deleted", slog.Int64("rows", n))
+}
@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingExpirer struct {
+	mu    sync.Mutex
+	calls []time.Time
+}
+
+func (e *recordingExpirer) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls = append(e.calls, now)
+	return 2, nil
+}
+
+func (e *recordingExpirer) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.calls)
+}
+
+func TestRunCleanup_DeletesPeriodically(t *testing.T) {
+	e := &recordingExpirer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	start := time.Now().UTC()
+	go func() {
+		RunCleanup(ctx, e, 5*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		close(done)
+	}()
+
+	deadline := time.After(time.Second)
+	for e.count() < 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("cleaned up %d times, want at least 2", e.count())
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+
+	// первая очистка сразу при старте, граница — текущее время
+	if first := e.calls[0]; first.Before(start) || first.After(time.Now().UTC()) {
+		t.Errorf("cutoff = %s, want now", first)
+	}
+}
+
+func TestRunCleanup_Disabled(t *testing.T) {
+	e := &recordingExpirer{}
+	// выключенная очистка возвращается сразу, не дожидаясь отмены ctx
+	RunCleanup(context.Background(), e, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if e.count() != 0 {
+		t.Fatalf("cleaned up %d times with cleanup disabled", e.count())
+	}
+}
@@ -0,0 +1,176 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	"service/internal/lib/api/response"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	keyTTL = 24 * time.Hour
+	// maxBodySize ограничение на тело запроса, которое читается целиком для отпечатка
+	maxBodySize = 10 << 20
+
+	MsgKeyMismatch   = "idempotency key was used for a different request"
+	MsgKeyInProgress = "request with this idempotency key is still in progress"
+)
+
+type Repository interface {
+	GetIdempotencyKey(ctx context.Context, userID int64, key string) (*models.IdempotencyKey, error)
+	ReserveIdempotencyKey(ctx context.Context, k *models.IdempotencyKey) (bool, error)
+	CompleteIdempotencyKey(ctx context.Context, k *models.IdempotencyKey) error
+	ReleaseIdempotencyKey(ctx context.Context, userID int64, key string) error
+}
+
+// New повторно отдаёт сохранённый ответ для POST-запросов с тем же Idempotency-Key.
+// Ключи хранятся отдельно для каждого пользователя и привязаны к методу, пути и телу запроса:
+// тот же ключ с другим запросом — 422, пока первый запрос выполняется — 409.
+// Сохраняются только ответы 2xx, после ошибки ключ освобождается для повтора.
+//
+// Подключается на уровне маршрута после проверки прав, чтобы повтор не обходил RBAC.
+// Эндпоинты, ответ которых содержит учётные данные (сброс пароля), подключать нельзя:
+// ответ хранится в БД открытым текстом.
+func New(repo Repository, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(slog.String("component", "middleware/idempotency"))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userID, ok := ware.GetUserID(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			if err != nil {
+				log.Info("failed to read request body", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				render.JSON(w, r, response.ErrorFor(r, response.MsgInvalidRequest))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			reserved := &models.IdempotencyKey{
+				UserID:      userID,
+				Key:         key,
+				Fingerprint: fingerprint(r, body),
+				ExpiresAt:   time.Now().UTC().Add(keyTTL),
+			}
+			ok, err = repo.ReserveIdempotencyKey(r.Context(), reserved)
+			if err != nil {
+				// без хранилища ключей запрос выполняется как обычный
+				log.Error("failed to reserve idempotency key", slog.String("err", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !ok {
+				replay(w, r, log, repo, reserved)
+				return
+			}
+
+			// резерв снимается и при панике обработчика, и при отмене контекста запроса
+			ctx := context.WithoutCancel(r.Context())
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				if err := repo.ReleaseIdempotencyKey(ctx, userID, key); err != nil {
+					log.Error("failed to release idempotency key", slog.String("err", err.Error()))
+				}
+			}()
+
+			var buf bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&buf)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status < 200 || status >= 300 {
+				return
+			}
+			reserved.StatusCode = status
+			reserved.ContentType = ww.Header().Get("Content-Type")
+			reserved.Location = ww.Header().Get("Location")
+			reserved.ResponseBody = buf.Bytes()
+			if err := repo.CompleteIdempotencyKey(ctx, reserved); err != nil {
+				log.Error("failed to save idempotency key", slog.String("err", err.Error()))
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// replay отдаёт сохранённый ответ для занятого ключа
+func replay(w http.ResponseWriter, r *http.Request, log *slog.Logger, repo Repository, req *models.IdempotencyKey) {
+	saved, err := repo.GetIdempotencyKey(r.Context(), req.UserID, req.Key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// ключ освободили между резервом и чтением — клиент может повторить
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, response.ErrorFor(r, MsgKeyInProgress))
+			return
+		}
+		log.Error("failed to get idempotency key", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, response.ErrorFor(r, response.MsgInternal))
+		return
+	}
+	if saved.Fingerprint != req.Fingerprint {
+		log.Info("idempotency key reused for a different request")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		render.JSON(w, r, response.ErrorFor(r, MsgKeyMismatch))
+		return
+	}
+	if saved.Pending() {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, response.ErrorFor(r, MsgKeyInProgress))
+		return
+	}
+
+	if saved.ContentType != "" {
+		w.Header().Set("Content-Type", saved.ContentType)
+	}
+	if saved.Location != "" {
+		w.Header().Set("Location", saved.Location)
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(saved.StatusCode)
+	_, _ = w.Write(saved.ResponseBody)
+}
+
+// fingerprint sha256 от метода, пути и хеша тела запроса
+func fingerprint(r *http.Request, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(bodyHash[:])
+	return hex.EncodeToString(h.Sum(nil))
+}
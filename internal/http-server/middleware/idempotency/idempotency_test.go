@@ -0,0 +1,211 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+// memRepo хранилище ключей в памяти с той же семантикой резерва, что и в MySQL
+type memRepo struct {
+	mu   sync.Mutex
+	keys map[string]models.IdempotencyKey
+}
+
+func newMemRepo() *memRepo { return &memRepo{keys: map[string]models.IdempotencyKey{}} }
+
+func (m *memRepo) GetIdempotencyKey(_ context.Context, userID int64, key string) (*models.IdempotencyKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k, ok := m.keys[key]
+	if !ok || k.UserID != userID {
+		return nil, sql.ErrNoRows
+	}
+	return &k, nil
+}
+
+func (m *memRepo) ReserveIdempotencyKey(_ context.Context, k *models.IdempotencyKey) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.keys[k.Key]; ok && old.ExpiresAt.After(time.Now()) {
+		return false, nil
+	}
+	m.keys[k.Key] = *k
+	return true, nil
+}
+
+func (m *memRepo) CompleteIdempotencyKey(_ context.Context, k *models.IdempotencyKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys[k.Key] = *k
+	return nil
+}
+
+func (m *memRepo) ReleaseIdempotencyKey(_ context.Context, _ int64, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if k, ok := m.keys[key]; ok && k.Pending() {
+		delete(m.keys, key)
+	}
+	return nil
+}
+
+func newTestServer(t *testing.T, repo Repository, h http.HandlerFunc) http.Handler {
+	t.Helper()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return ware.JWTAuth(testSecret, nil)(New(repo, log)(h))
+}
+
+func newRequest(t *testing.T, key, body string) *http.Request {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":  42,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/students", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+token)
+	if key != "" {
+		r.Header.Set(HeaderKey, key)
+	}
+	return r
+}
+
+func createdHandler(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/v1/students/7")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"call":%d,"echo":%s}`, n, body)
+	}
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, newMemRepo(), createdHandler(&calls))
+
+	first := httptest.NewRecorder()
+	srv.ServeHTTP(first, newRequest(t, "k1", `{"name":"a"}`))
+	second := httptest.NewRecorder()
+	srv.ServeHTTP(second, newRequest(t, "k1", `{"name":"a"}`))
+
+	if calls.Load() != 1 {
+		t.Fatalf("handler called %d times, want 1", calls.Load())
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %q, want %d %q", second.Code, second.Body, first.Code, first.Body)
+	}
+	if got := second.Header().Get(HeaderReplayed); got != "true" {
+		t.Fatalf("%s = %q", HeaderReplayed, got)
+	}
+	if got := second.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+	if got := second.Header().Get("Location"); got != "/api/v1/students/7" {
+		t.Fatalf("Location = %q", got)
+	}
+}
+
+func TestIdempotency_DifferentBodyIsRejected(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, newMemRepo(), createdHandler(&calls))
+
+	srv.ServeHTTP(httptest.NewRecorder(), newRequest(t, "k1", `{"name":"a"}`))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, newRequest(t, "k1", `{"name":"b"}`))
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, want 422", rec.Code)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler called %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotency_PendingKeyConflicts(t *testing.T) {
+	repo := newMemRepo()
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	srv := newTestServer(t, repo, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		srv.ServeHTTP(httptest.NewRecorder(), newRequest(t, "k1", `{}`))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, newRequest(t, "k1", `{}`))
+	close(release)
+	<-done
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status %d, want 409", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("Retry-After is not set")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler called %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotency_ErrorResponseIsNotCached(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, newMemRepo(), func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	first := httptest.NewRecorder()
+	srv.ServeHTTP(first, newRequest(t, "k1", `{}`))
+	second := httptest.NewRecorder()
+	srv.ServeHTTP(second, newRequest(t, "k1", `{}`))
+
+	if first.Code != http.StatusBadRequest || second.Code != http.StatusCreated {
+		t.Fatalf("statuses %d, %d; want 400, 201", first.Code, second.Code)
+	}
+	if second.Header().Get(HeaderReplayed) != "" {
+		t.Fatal("error response was replayed")
+	}
+}
+
+func TestIdempotency_WithoutKeyPassesThrough(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, newMemRepo(), createdHandler(&calls))
+
+	srv.ServeHTTP(httptest.NewRecorder(), newRequest(t, "", `{}`))
+	srv.ServeHTTP(httptest.NewRecorder(), newRequest(t, "", `{}`))
+
+	if calls.Load() != 2 {
+		t.Fatalf("handler called %d times, want 2", calls.Load())
+	}
+}
@@ -2,12 +2,16 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"service/internal/lib/api/response"
 	"strings"
 	"time"
 
+	"github.com/go-chi/render"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -15,14 +19,19 @@ type contextKey string
 
 const userCtxKey = contextKey("user")
 
-func JWTAuth(secret string) func(http.Handler) http.Handler {
+// RevocationChecker проверяет, отозван ли токен по его jti
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+func JWTAuth(secret string, revoked RevocationChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			const bearerPrefix = "Bearer "
 
 			authHeader := r.Header.Get("Authorization")
 			if !strings.HasPrefix(authHeader, bearerPrefix) {
-				http.Error(w, "Missing or invalid Authorization header", http.StatusUnauthorized)
+				unauthorized(w, r, response.MsgMissingToken)
 				return
 			}
 
@@ -32,33 +41,44 @@ func JWTAuth(secret string) func(http.Handler) http.Handler {
 					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 				}
 				return []byte(secret), nil
-			})
+			}, jwt.WithJSONNumber())
 
 			if err != nil {
 				// Используем ошибки v5
 				if errors.Is(err, jwt.ErrTokenExpired) {
-					http.Error(w, "Token is expired", http.StatusUnauthorized)
+					unauthorized(w, r, response.MsgTokenExpired)
 					return
 				}
-				http.Error(w, "Invalid token: "+err.Error(), http.StatusUnauthorized)
+				unauthorized(w, r, response.MsgInvalidToken)
 				return
 			}
 
 			if !token.Valid {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				unauthorized(w, r, response.MsgInvalidToken)
 				return
 			}
 
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
-				http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+				unauthorized(w, r, response.MsgInvalidToken)
 				return
 			}
 
 			// Дополнительная ручная проверка exp (избыточна, но для надёжности)
-			if exp, ok := claims["exp"].(float64); ok {
-				if int64(exp) < time.Now().Unix() {
-					http.Error(w, "Token is expired", http.StatusUnauthorized)
+			if exp, err := claims.GetExpirationTime(); err == nil && exp != nil && exp.Before(time.Now()) {
+				unauthorized(w, r, response.MsgTokenExpired)
+				return
+			}
+
+			if jti, ok := claims["jti"].(string); ok && revoked != nil {
+				isRevoked, err := revoked.IsRevoked(r.Context(), jti)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					render.JSON(w, r, response.ErrorFor(r, response.MsgInternal))
+					return
+				}
+				if isRevoked {
+					unauthorized(w, r, response.MsgTokenRevoked)
 					return
 				}
 			}
@@ -69,7 +89,44 @@ func JWTAuth(secret string) func(http.Handler) http.Handler {
 	}
 }
 
+// unauthorized ответ 401 в общем JSON-формате API. WWW-Authenticate по RFC 6750:
+// без токена — просто "Bearer", с негодным токеном — с error="invalid_token"
+func unauthorized(w http.ResponseWriter, r *http.Request, msgID string) {
+	challenge := `Bearer error="invalid_token"`
+	if msgID == response.MsgMissingToken || msgID == response.MsgUnauthorized {
+		challenge = "Bearer"
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.WriteHeader(http.StatusUnauthorized)
+	render.JSON(w, r, response.ErrorFor(r, msgID))
+}
+
 func GetUserClaims(r *http.Request) jwt.MapClaims {
 	claims, _ := r.Context().Value(userCtxKey).(jwt.MapClaims)
 	return claims
 }
+
+// GetUserID достаёт id пользователя из claims токена.
+// Claims разбираются как json.Number, чтобы id больше 2^53 не теряли точность.
+func GetUserID(r *http.Request) (int64, bool) {
+	return UserIDFromContext(r.Context())
+}
+
+// UserIDFromContext то же, что GetUserID, для кода, у которого есть только контекст запроса.
+// id принимается только числом: строковый или дробный id считается отсутствующим.
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	claims, _ := ctx.Value(userCtxKey).(jwt.MapClaims)
+	switch v := claims["id"].(type) {
+	case json.Number:
+		id, err := v.Int64()
+		return id, err == nil
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
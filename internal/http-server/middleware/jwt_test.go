@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"service/internal/domain/models"
+	"service/internal/lib/api/response"
+	libjwt "service/internal/lib/jwt"
+	"service/internal/lib/jwt/blocklist"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWTAuth_UserIDAbove2To53(t *testing.T) {
+	const secret = "test-secret"
+	const id int64 = 9007199254740993
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":  id,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got int64
+	var ok bool
+	h := JWTAuth(secret, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok = GetUserID(r)
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusOK || !ok {
+		t.Fatalf("status %d, ok %v", rec.Code, ok)
+	}
+	if got != id {
+		t.Fatalf("user id = %d, want %d", got, id)
+	}
+}
+
+func TestUserIDFromContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     interface{}
+		want   int64
+		wantOK bool
+	}{
+		{"json number", json.Number("42"), 42, true},
+		{"whole float", float64(42), 42, true},
+		{"int64", int64(42), 42, true},
+		{"fractional json number", json.Number("1.9"), 0, false},
+		// дробный id не округляется до чужого
+		{"fractional float", 1.9, 0, false},
+		{"float above int64", 1e19, 0, false},
+		{"string", "42", 0, false},
+		{"missing", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), userCtxKey, jwt.MapClaims{"id": tt.id})
+			got, ok := UserIDFromContext(ctx)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("UserIDFromContext() = %d, %v; want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestJWTAuth_RejectsMissingToken(t *testing.T) {
+	h := JWTAuth("secret", nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != "Bearer" {
+		t.Fatalf("status %d, challenge %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+}
+
+func TestJWTAuth_RejectsTokenAfterLogout(t *testing.T) {
+	const secret = "test-secret"
+	token, err := libjwt.NewToken(models.User{UserID: 5}, nil, time.Hour, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked := blocklist.NewMemory()
+	h := JWTAuth(secret, revoked)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	request := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	if rec := request(); rec.Code != http.StatusOK {
+		t.Fatalf("before logout: status %d", rec.Code)
+	}
+
+	// logout отзывает jti токена до его истечения
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jti, _ := parsed.Claims.(jwt.MapClaims)["jti"].(string)
+	if err := revoked.Revoke(context.Background(), jti, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := request()
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("after logout: status %d, want 401", rec.Code)
+	}
+	var body response.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error != response.Translate(response.Language(""), response.MsgTokenRevoked) {
+		t.Fatalf("error = %q", body.Error)
+	}
+}
+
+func TestJWTAuth_UnauthorizedJSON(t *testing.T) {
+	const secret = "test-secret"
+	sign := func(key string, exp time.Time) string {
+		t.Helper()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"id": 5, "exp": exp.Unix()}).SignedString([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"id": 5, "exp": time.Now().Add(time.Hour).Unix()}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const invalidChallenge = `Bearer error="invalid_token"`
+	tests := []struct {
+		name          string
+		header        string
+		wantMsg       string
+		wantChallenge string
+	}{
+		{"missing header", "", response.MsgMissingToken, "Bearer"},
+		{"other scheme", "Basic dXNlcjpwYXNz", response.MsgMissingToken, "Bearer"},
+		{"malformed token", "Bearer not.a.jwt", response.MsgInvalidToken, invalidChallenge},
+		{"wrong secret", "Bearer " + sign("other-secret", time.Now().Add(time.Hour)), response.MsgInvalidToken, invalidChallenge},
+		{"alg none", "Bearer " + unsigned, response.MsgInvalidToken, invalidChallenge},
+		{"expired", "Bearer " + sign(secret, time.Now().Add(-time.Minute)), response.MsgTokenExpired, invalidChallenge},
+	}
+	h := JWTAuth(secret, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called")
+	}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, lang := range []string{"", "ru"} {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				if tt.header != "" {
+					r.Header.Set("Authorization", tt.header)
+				}
+				r.Header.Set("Accept-Language", lang)
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, r)
+
+				if rec.Code != http.StatusUnauthorized {
+					t.Fatalf("lang %q: status = %d, want 401", lang, rec.Code)
+				}
+				if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+					t.Errorf("lang %q: WWW-Authenticate = %q, want %q", lang, got, tt.wantChallenge)
+				}
+				if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+					t.Errorf("lang %q: Content-Type = %q, want JSON", lang, ct)
+				}
+				// тело — ровно {"status":"Error","error":...}, как у остальных ошибок API
+				var body map[string]string
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("lang %q: body %q is not JSON: %v", lang, rec.Body, err)
+				}
+				want := map[string]string{"status": response.StatusError, "error": response.Translate(response.Language(lang), tt.wantMsg)}
+				if !reflect.DeepEqual(body, want) {
+					t.Errorf("lang %q: body = %v, want %v", lang, body, want)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"log/slog"
 	"net/http"
+	"service/internal/http-server/middleware/clientip"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -21,6 +22,7 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("client_ip", clientip.FromRequest(r)),
 				slog.String("user_agent", r.UserAgent()),
 				slog.String("request_id", middleware.GetReqID(r.Context())),
 			)
@@ -1,84 +1,179 @@
 package permissions
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"service/internal/domain/repository"
 	"service/internal/http-server/middleware"
 	"service/internal/lib/api/response"
+	"service/internal/lib/jwt"
 	"strings"
 
 	"github.com/go-chi/render"
 )
 
+type ctxKey struct{}
+
+// permsCtxKey ключ контекста для набора прав, загруженного Preload
+var permsCtxKey = ctxKey{}
+
 type RBACMiddleware struct {
 	userRoleRepo   *repository.UserRoleRepository
 	rolePermRepo   *repository.RolePermissionRepository
 	permissionRepo *repository.PermissionRepository
 	logger         *slog.Logger
+	// trustToken права берутся из claims токена, если они там есть, без запроса в БД
+	trustToken bool
 }
 
+// NewRBACMiddleware trustToken включает доверие правам из токена: изменения ролей
+// видны только в новых токенах, зато проверка прав не ходит в БД на каждом запросе
 func NewRBACMiddleware(
 	userRoleRepo *repository.UserRoleRepository,
 	rolePermRepo *repository.RolePermissionRepository,
 	permissionRepo *repository.PermissionRepository,
 	logger *slog.Logger,
+	trustToken bool,
 ) *RBACMiddleware {
 	return &RBACMiddleware{
 		userRoleRepo:   userRoleRepo,
 		rolePermRepo:   rolePermRepo,
 		permissionRepo: permissionRepo,
 		logger:         logger,
+		trustToken:     trustToken,
 	}
 }
 
 func (m *RBACMiddleware) RequirePermission(permissionName string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			claims := middleware.GetUserClaims(r)
-			idClaim, ok := claims["id"]
-			if !ok {
-				m.logger.Info("user id not found in claims")
+			// без корректного id в токене нельзя определить роли, иначе права искались бы для user_id = 0
+			userID, ok := middleware.GetUserID(r)
+			if !ok || userID <= 0 {
+				m.logger.Info("user id missing or malformed in claims")
 				w.WriteHeader(http.StatusUnauthorized)
-				render.JSON(w, r, response.Error("unauthorized"))
+				render.JSON(w, r, response.ErrorFor(r, response.MsgUnauthorized))
 				return
 			}
 
-			var userID int64
-			switch v := idClaim.(type) {
-			case int64:
-				userID = v
-			case float64:
-				userID = int64(v)
-			}
-
-			roles, err := m.userRoleRepo.GetRolesByUserID(r.Context(), userID)
+			permsSet, err := m.permissionsFor(r, userID)
 			if err != nil {
-				m.logger.Error("failed to get user roles", slog.String("err", err.Error()))
+				m.logger.Error("failed to get user permissions", slog.String("err", err.Error()))
 				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, response.Error("internal error"))
+				render.JSON(w, r, response.ErrorFor(r, response.MsgInternal))
 				return
 			}
-			permsSet := make(map[string]struct{})
-			for _, role := range roles {
-				perms, err := m.rolePermRepo.GetPermissionsByRoleID(r.Context(), role.RoleID)
-				if err != nil {
-					m.logger.Error("failed to get role permissions", slog.String("err", err.Error()))
-					w.WriteHeader(http.StatusInternalServerError)
-					render.JSON(w, r, response.Error("internal error"))
-					return
-				}
-				for _, perm := range perms {
-					permsSet[strings.ToLower(perm.PermissionName)] = struct{}{}
-				}
-			}
 			if _, ok := permsSet[strings.ToLower(permissionName)]; !ok {
 				m.logger.Info("permission denied", slog.String("permission", permissionName))
 				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, response.Error("permission denied"))
+				render.JSON(w, r, response.ErrorFor(r, response.MsgForbidden))
 				return
 			}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// Preload один раз загружает права пользователя из токена и кладёт их в контекст
+// запроса. Запросы без user id пропускаются как есть — отказ остаётся за RequirePermission.
+func (m *RBACMiddleware) Preload() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := middleware.GetUserID(r)
+			if !ok || userID <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			permsSet, err := m.loadPermissions(r, userID)
+			if err != nil {
+				m.logger.Error("failed to preload user permissions", slog.String("err", err.Error()))
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, response.ErrorFor(r, response.MsgInternal))
+				return
+			}
+			ctx := context.WithValue(r.Context(), permsCtxKey, permsSet)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HasPermission проверяет право по набору, загруженному Preload, без отказа в доступе:
+// для хендлеров, где от прав зависит только состав ответа.
+// Без Preload (или без пользователя в токене) всегда false.
+func HasPermission(r *http.Request, permissionName string) bool {
+	permsSet, ok := r.Context().Value(permsCtxKey).(map[string]struct{})
+	if !ok {
+		return false
+	}
+	_, ok = permsSet[strings.ToLower(permissionName)]
+	return ok
+}
+
+// permissionsFor берёт права из контекста, если их уже загрузил Preload, иначе идёт в БД
+func (m *RBACMiddleware) permissionsFor(r *http.Request, userID int64) (map[string]struct{}, error) {
+	if permsSet, ok := r.Context().Value(permsCtxKey).(map[string]struct{}); ok {
+		return permsSet, nil
+	}
+	return m.loadPermissions(r, userID)
+}
+
+// loadPermissions права из токена при trustToken, иначе (и для токенов без прав) из БД
+func (m *RBACMiddleware) loadPermissions(r *http.Request, userID int64) (map[string]struct{}, error) {
+	if m.trustToken {
+		if permsSet, ok := tokenPermissions(r); ok {
+			return permsSet, nil
+		}
+	}
+	access, err := m.UserAccess(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	permsSet := make(map[string]struct{}, len(access.Permissions))
+	for _, name := range access.Permissions {
+		permsSet[name] = struct{}{}
+	}
+	return permsSet, nil
+}
+
+// tokenPermissions набор прав из claims токена, false — в токене прав нет
+func tokenPermissions(r *http.Request) (map[string]struct{}, bool) {
+	raw, ok := middleware.GetUserClaims(r)[jwt.ClaimPermissions].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	permsSet := make(map[string]struct{}, len(raw))
+	for _, v := range raw {
+		if name, ok := v.(string); ok {
+			permsSet[strings.ToLower(name)] = struct{}{}
+		}
+	}
+	return permsSet, true
+}
+
+// UserAccess роли и права пользователя из БД для встраивания в токен.
+// Имена прав приводятся к нижнему регистру.
+func (m *RBACMiddleware) UserAccess(ctx context.Context, userID int64) (*jwt.Access, error) {
+	roles, err := m.userRoleRepo.GetRolesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	access := &jwt.Access{RoleIDs: make([]int64, 0, len(roles)), Permissions: []string{}}
+	seen := make(map[string]struct{})
+	for _, role := range roles {
+		access.RoleIDs = append(access.RoleIDs, role.RoleID)
+		perms, err := m.rolePermRepo.GetPermissionsByRoleID(ctx, role.RoleID)
+		if err != nil {
+			return nil, err
+		}
+		for _, perm := range perms {
+			name := strings.ToLower(perm.PermissionName)
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			access.Permissions = append(access.Permissions, name)
+		}
+	}
+	return access, nil
+}
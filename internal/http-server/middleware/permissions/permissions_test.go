@@ -0,0 +1,128 @@
+package permissions
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/domain/models"
+	"service/internal/http-server/middleware"
+	"service/internal/lib/jwt"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func newTestRBAC() *RBACMiddleware {
+	// репозитории не нужны: права берутся из токена
+	return NewRBACMiddleware(nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), true)
+}
+
+func signedRequest(t *testing.T, claims jwtlib.MapClaims) *http.Request {
+	t.Helper()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token, err := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/students", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func TestRequirePermission_UserIDClaim(t *testing.T) {
+	tests := []struct {
+		name string
+		id   interface{}
+		want int
+	}{
+		{"numeric id", 7, http.StatusOK},
+		{"string id", "7", http.StatusUnauthorized},
+		{"fractional id", 7.5, http.StatusUnauthorized},
+		{"non-numeric id", "abc", http.StatusUnauthorized},
+		{"missing id", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwtlib.MapClaims{jwt.ClaimPermissions: []string{"student:read"}}
+			if tt.id != nil {
+				claims["id"] = tt.id
+			}
+			rbac := newTestRBAC()
+			h := middleware.JWTAuth(testSecret, nil)(rbac.Preload()(rbac.RequirePermission("student:read")(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			)))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, signedRequest(t, claims))
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestRequirePermission_Forbidden(t *testing.T) {
+	rbac := newTestRBAC()
+	h := middleware.JWTAuth(testSecret, nil)(rbac.Preload()(rbac.RequirePermission("student:delete")(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signedRequest(t, jwtlib.MapClaims{"id": 7, jwt.ClaimPermissions: []string{"student:read"}}))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status %d, want 403", rec.Code)
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	rbac := newTestRBAC()
+	var got map[string]bool
+	h := middleware.JWTAuth(testSecret, nil)(rbac.Preload()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = map[string]bool{
+			"teacher:view":   HasPermission(r, "teacher:view"),
+			"TEACHER:VIEW":   HasPermission(r, "TEACHER:VIEW"),
+			"teacher:delete": HasPermission(r, "teacher:delete"),
+		}
+	})))
+	h.ServeHTTP(httptest.NewRecorder(), signedRequest(t, jwtlib.MapClaims{"id": 7, jwt.ClaimPermissions: []string{"Teacher:View"}}))
+
+	want := map[string]bool{"teacher:view": true, "TEACHER:VIEW": true, "teacher:delete": false}
+	for name, ok := range want {
+		if got[name] != ok {
+			t.Fatalf("HasPermission(%q) = %v, want %v", name, got[name], ok)
+		}
+	}
+}
+
+func TestHasPermission_WithoutPreload(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if HasPermission(r, "teacher:view") {
+		t.Fatal("HasPermission without Preload must be false")
+	}
+}
+
+func TestPreload_UsesTokenPermissionsWithoutDB(t *testing.T) {
+	access := &jwt.Access{RoleIDs: []int64{2}, Permissions: []string{"grade:create"}}
+	token, err := jwt.NewToken(models.User{UserID: 7}, access, time.Hour, testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	// репозитории nil: обращение к БД завершилось бы паникой
+	rbac := newTestRBAC()
+	var allowed, denied bool
+	h := middleware.JWTAuth(testSecret, nil)(rbac.Preload()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed = HasPermission(r, "grade:create")
+		denied = HasPermission(r, "grade:delete")
+	})))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusOK || !allowed || denied {
+		t.Fatalf("status %d, grade:create %v, grade:delete %v", rec.Code, allowed, denied)
+	}
+}
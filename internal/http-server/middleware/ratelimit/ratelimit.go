@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"service/internal/http-server/middleware/clientip"
+	"service/internal/lib/api/response"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// idleTTL через сколько неиспользуемый bucket удаляется из памяти
+const idleTTL = 10 * time.Minute
+
+// skipPaths служебные эндпоинты, которые не ограничиваются
+var skipPaths = map[string]struct{}{
+	"/health":  {},
+	"/healthz": {},
+	"/livez":   {},
+	"/readyz":  {},
+}
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// Limiter token bucket на каждый IP. Потокобезопасен.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	rate    float64
+	burst   float64
+	lastGC  time.Time
+	nowFunc func() time.Time
+}
+
+func NewLimiter(rps float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		rate:    rps,
+		burst:   float64(burst),
+		nowFunc: time.Now,
+	}
+}
+
+// Allow списывает токен для ключа. Если токенов нет, возвращает время до появления следующего.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.nowFunc()
+	l.gc(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*l.rate)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+func (l *Limiter) gc(now time.Time) {
+	if now.Sub(l.lastGC) < idleTTL {
+		return
+	}
+	l.lastGC = now
+	for k, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleTTL {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// New ограничивает число запросов с одного IP. rps <= 0 отключает ограничение.
+func New(rps float64, burst int, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if rps <= 0 {
+			return next
+		}
+		log := log.With(slog.String("component", "middleware/ratelimit"))
+		limiter := NewLimiter(rps, burst)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := skipPaths[r.URL.Path]; ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip := clientip.FromRequest(r)
+			allowed, wait := limiter.Allow(ip)
+			if !allowed {
+				log.Info("rate limit exceeded", slog.String("ip", ip))
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				w.WriteHeader(http.StatusTooManyRequests)
+				render.JSON(w, r, response.ErrorFor(r, response.MsgTooManyRequests))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
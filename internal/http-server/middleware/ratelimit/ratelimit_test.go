@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimiter_RefillsOverTime(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(2, 2)
+	l.nowFunc = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("ip"); !ok {
+			t.Fatalf("request %d within burst was limited", i+1)
+		}
+	}
+	ok, wait := l.Allow("ip")
+	if ok {
+		t.Fatal("request over burst was allowed")
+	}
+	if wait != 500*time.Millisecond {
+		t.Fatalf("wait = %v, want 500ms at 2 rps", wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("ip"); !ok {
+		t.Fatal("token was not refilled")
+	}
+	if ok, _ := l.Allow("other"); !ok {
+		t.Fatal("buckets are not separate per key")
+	}
+}
+
+func TestNew_Returns429WithRetryAfter(t *testing.T) {
+	h := New(0.5, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	do := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = "203.0.113.7:5555"
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	if rec := do("/api/v1/students"); rec.Code != http.StatusNoContent {
+		t.Fatalf("first request: %d", rec.Code)
+	}
+	rec := do("/api/v1/students")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: %d, want 429", rec.Code)
+	}
+	// при 0.5 rps следующий токен появится через 2 секунды
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("Retry-After = %q, want 2", got)
+	}
+	if rec := do("/healthz"); rec.Code != http.StatusNoContent {
+		t.Fatalf("health check was limited: %d", rec.Code)
+	}
+}
+
+func TestNew_DisabledWithZeroRPS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := New(0, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))(next)
+	for i := 0; i < 5; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: %d", i+1, rec.Code)
+		}
+	}
+}
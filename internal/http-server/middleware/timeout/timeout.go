@@ -0,0 +1,118 @@
+package timeout
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"service/internal/lib/api/response"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// streamPaths потоковые выгрузки: буферизация ответа и общий таймаут для них не подходят
+var streamPaths = map[string]struct{}{
+	"/api/v1/audit-logs/stream": {},
+}
+
+// New ограничивает время работы обработчика: по истечении d контекст запроса
+// отменяется, клиент получает 503, а дальнейший вывод обработчика отбрасывается.
+// Ответ буферизуется до завершения обработчика, как в http.TimeoutHandler.
+// d <= 0 отключает ограничение.
+func New(d time.Duration, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		log := log.With(slog.String("component", "middleware/timeout"))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := streamPaths[r.URL.Path]; ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &writer{header: make(http.Header)}
+			done := make(chan struct{})
+			panicChan := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicChan <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicChan:
+				// паника уходит в Recoverer выше по цепочке
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				dst := w.Header()
+				for k, vv := range tw.header {
+					dst[k] = vv
+				}
+				if tw.code == 0 {
+					tw.code = http.StatusOK
+				}
+				w.WriteHeader(tw.code)
+				_, _ = w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				log.Warn("handler timeout exceeded",
+					slog.String("path", r.URL.Path),
+					slog.Duration("timeout", d),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+				)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				render.JSON(w, r, response.ErrorFor(r, response.MsgTimeout))
+			}
+		})
+	}
+}
+
+// writer буфер ответа обработчика. После таймаута запись возвращает http.ErrHandlerTimeout.
+type writer struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (tw *writer) Header() http.Header {
+	return tw.header
+}
+
+func (tw *writer) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *writer) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
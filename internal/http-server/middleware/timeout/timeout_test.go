@@ -0,0 +1,86 @@
+package timeout
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestNew_PassesThroughFastHandler(t *testing.T) {
+	h := New(time.Second, discard)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/students", nil))
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != "ok" || rec.Header().Get("X-Test") != "1" {
+		t.Fatalf("got %d %q %v", rec.Code, rec.Body, rec.Header())
+	}
+}
+
+func TestNew_SlowHandlerGets503(t *testing.T) {
+	writeErr := make(chan error, 1)
+	h := New(20*time.Millisecond, discard)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		// даём middleware отдать 503, после этого запись должна отклоняться
+		time.Sleep(10 * time.Millisecond)
+		_, err := w.Write([]byte("late"))
+		writeErr <- err
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/students", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "late") {
+		t.Fatalf("late output reached the client: %q", rec.Body)
+	}
+	select {
+	case err := <-writeErr:
+		if !errors.Is(err, http.ErrHandlerTimeout) {
+			t.Fatalf("late write error = %v, want ErrHandlerTimeout", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not canceled")
+	}
+}
+
+func TestNew_StreamPathIsNotLimited(t *testing.T) {
+	h := New(10*time.Millisecond, discard)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		if r.Context().Err() != nil {
+			t.Error("stream request context was canceled")
+		}
+		_, _ = w.Write([]byte("stream"))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit-logs/stream", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "stream" {
+		t.Fatalf("got %d %q", rec.Code, rec.Body)
+	}
+}
+
+func TestNew_RepanicsHandlerPanic(t *testing.T) {
+	h := New(time.Second, discard)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("recovered %v, want boom", p)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
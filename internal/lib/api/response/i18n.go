@@ -0,0 +1,179 @@
+package response
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	LangEn = "en"
+	LangRu = "ru"
+
+	// DefaultLang язык ответов без Accept-Language: английский, как и до локализации
+	DefaultLang = LangEn
+)
+
+// Идентификаторы общих сообщений. Идентификатор совпадает с английским текстом,
+// поэтому клиенты без Accept-Language получают прежние ответы.
+const (
+	MsgInvalidRequest     = "invalid request"
+	MsgUnauthorized       = "unauthorized"
+	MsgForbidden          = "permission denied"
+	MsgInvalidCredentials = "invalid credentials"
+	MsgUnavailable        = "service temporarily unavailable"
+	MsgInternal           = "internal error"
+	MsgTooManyRequests    = "too many requests"
+	MsgTimeout            = "request timed out"
+	MsgMethodNotAllowed   = "method not allowed"
+
+	MsgMissingToken = "missing or invalid authorization header"
+	MsgInvalidToken = "invalid token"
+	MsgTokenExpired = "token is expired"
+	MsgTokenRevoked = "token is revoked"
+
+	MsgUserDeactivated = "user is deactivated"
+)
+
+// catalog переводы по идентификатору сообщения. Для английского перевод не нужен,
+// отсутствующие сообщения отдаются как есть.
+var catalog = map[string]map[string]string{
+	LangRu: {
+		MsgInvalidRequest:     "некорректный запрос",
+		MsgUnauthorized:       "требуется авторизация",
+		MsgForbidden:          "доступ запрещён",
+		MsgInvalidCredentials: "неверный email или пароль",
+		MsgUnavailable:        "сервис временно недоступен",
+		MsgInternal:           "внутренняя ошибка сервера",
+		MsgTooManyRequests:    "слишком много запросов, повторите позже",
+		MsgTimeout:            "превышено время обработки запроса",
+		MsgMethodNotAllowed:   "метод не поддерживается",
+
+		MsgMissingToken: "отсутствует или некорректен заголовок Authorization",
+		MsgInvalidToken: "недействительный токен",
+		MsgTokenExpired: "срок действия токена истёк",
+		MsgTokenRevoked: "токен отозван",
+
+		MsgUserDeactivated: "пользователь деактивирован",
+
+		"email and password required":                            "требуются email и пароль",
+		"email already exists":                                   "email уже используется",
+		"academic year already exists":                           "учебный год с таким названием уже существует",
+		"invalid phone number":                                   "некорректный номер телефона",
+		"idempotency key was used for a different request":       "ключ идемпотентности уже использован для другого запроса",
+		"request with this idempotency key is still in progress": "запрос с этим ключом идемпотентности ещё выполняется",
+		"invalid ids":                                            "некорректный список id",
+		"invalid id":                                             "некорректный id",
+		"file is required":                                       "требуется файл",
+
+		"invalid user id":          "некорректный id пользователя",
+		"invalid student id":       "некорректный id студента",
+		"invalid teacher id":       "некорректный id преподавателя",
+		"invalid group id":         "некорректный id группы",
+		"invalid discipline id":    "некорректный id дисциплины",
+		"invalid semester id":      "некорректный id семестра",
+		"invalid academic year id": "некорректный id учебного года",
+		"invalid curriculum id":    "некорректный id учебного плана",
+		"invalid gradejournal id":  "некорректный id оценки",
+		"invalid attendance id":    "некорректный id посещаемости",
+		"invalid role id":          "некорректный id роли",
+		"invalid permission id":    "некорректный id разрешения",
+		"invalid webhook id":       "некорректный id вебхука",
+		"invalid student_ids":      "некорректный список student_ids",
+		"invalid semester_id":      "некорректный semester_id",
+
+		"user not found":                    "пользователь не найден",
+		"user roles not found":              "роли пользователя не найдены",
+		"student not found":                 "студент не найден",
+		"teacher not found":                 "преподаватель не найден",
+		"group not found":                   "группа не найдена",
+		"student group not found":           "группа не найдена",
+		"discipline not found":              "дисциплина не найдена",
+		"semester not found":                "семестр не найден",
+		"current semester not found":        "текущий семестр не найден",
+		"target semester not found":         "целевой семестр не найден",
+		"academic year not found":           "учебный год не найден",
+		"current academic year not found":   "текущий учебный год не найден",
+		"curriculum not found":              "учебный план не найден",
+		"gradejournal not found":            "оценка не найдена",
+		"attendance not found":              "запись посещаемости не найдена",
+		"role not found":                    "роль не найдена",
+		"permission not found":              "разрешение не найдено",
+		"permissions for role id not found": "разрешения роли не найдены",
+		"webhook not found":                 "вебхук не найден",
+
+		"user is referenced by other records":          "на пользователя ссылаются другие записи",
+		"student is referenced by other records":       "на студента ссылаются другие записи",
+		"teacher is referenced by other records":       "на преподавателя ссылаются другие записи",
+		"student group is referenced by other records": "на группу ссылаются другие записи",
+		"discipline is referenced by other records":    "на дисциплину ссылаются другие записи",
+		"semester is referenced by other records":      "на семестр ссылаются другие записи",
+		"academic year is referenced by other records": "на учебный год ссылаются другие записи",
+		"role is referenced by other records":          "на роль ссылаются другие записи",
+		"permission is referenced by other records":    "на разрешение ссылаются другие записи",
+
+		"discipline has dependent grades":             "у дисциплины есть оценки",
+		"discipline has dependent attendance records": "у дисциплины есть записи посещаемости",
+		"discipline has dependent curriculum entries": "дисциплина используется в учебном плане",
+		"student has dependent grades":                "у студента есть оценки",
+		"student has dependent attendance records":    "у студента есть записи посещаемости",
+		"student group has dependent students":        "в группе есть студенты",
+		"student group has dependent disciplines":     "у группы есть дисциплины",
+		"teacher has dependent disciplines":           "у преподавателя есть дисциплины",
+		"semester has dependent curriculum entries":   "семестр используется в учебном плане",
+		"academic year has dependent student groups":  "в учебном году есть группы",
+		"academic year has dependent semesters":       "в учебном году есть семестры",
+		"user has dependent students":                 "пользователь является студентом",
+		"user has dependent teachers":                 "пользователь является преподавателем",
+		"user has dependent student groups":           "пользователь является куратором группы",
+		"student group does not exist":                "группа не существует",
+		"teacher does not exist":                      "преподаватель не существует",
+		"user does not exist":                         "пользователь не существует",
+		"referenced record does not exist":            "связанная запись не существует",
+	},
+}
+
+// ErrorFor ответ с ошибкой на языке из Accept-Language запроса
+func ErrorFor(r *http.Request, msgID string) Response {
+	return Error(Translate(Language(r.Header.Get("Accept-Language")), msgID))
+}
+
+// Translate перевод сообщения, при отсутствии перевода возвращается сам идентификатор
+func Translate(lang, msgID string) string {
+	if msg, ok := catalog[lang][msgID]; ok {
+		return msg
+	}
+	return msgID
+}
+
+// Language выбирает поддерживаемый язык из заголовка Accept-Language с учётом q-весов,
+// например "ru-RU,ru;q=0.9,en;q=0.8" → ru. Если подходящего нет — DefaultLang.
+func Language(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if (base == LangRu || base == LangEn) && q > 0 {
+			candidates = append(candidates, candidate{lang: base, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLang
+	}
+	// стабильная сортировка сохраняет порядок заголовка при равных весах
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
@@ -0,0 +1,65 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", LangEn},
+		{"ru", LangRu},
+		{"ru-RU", LangRu},
+		{"EN-us", LangEn},
+		{"ru-RU,ru;q=0.9,en;q=0.8", LangRu},
+		{"en;q=0.5, ru;q=0.8", LangRu},
+		{"ru;q=0.3, en;q=0.7", LangEn},
+		// при равных весах побеждает первый в заголовке
+		{"en, ru", LangEn},
+		// q=0 — язык явно не принимается
+		{"ru;q=0", LangEn},
+		// неподдерживаемые языки пропускаются
+		{"de-DE,de;q=0.9,ru;q=0.5", LangRu},
+		{"fr, de;q=0.9", LangEn},
+		{"ru;q=abc", LangEn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := Language(tt.header); got != tt.want {
+				t.Errorf("Language(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		msgID  string
+		want   string
+	}{
+		{"ru", "ru", MsgForbidden, "доступ запрещён"},
+		{"q-weighted", "en;q=0.4, ru-RU;q=0.9", MsgTokenExpired, "срок действия токена истёк"},
+		{"unknown language falls back to English", "de", MsgForbidden, MsgForbidden},
+		{"no header", "", MsgUserDeactivated, "user is deactivated"},
+		// сообщения без перевода отдаются как есть
+		{"missing from catalog", "ru", "something went wrong", "something went wrong"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("Accept-Language", tt.header)
+			}
+			got := ErrorFor(r, tt.msgID)
+			if got.Status != StatusError || got.Error != tt.want {
+				t.Errorf("ErrorFor = %+v, want error %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Purger interface {
+	PurgeAuditLogsOlderThan(ctx context.Context, t time.Time) (int64, error)
+}
+
+// RunRetention периодически удаляет записи аудита старше maxAge.
+// Блокирует до отмены ctx, при maxAge <= 0 сразу возвращается.
+func RunRetention(ctx context.Context, repo Purger, maxAge, interval time.Duration, log *slog.Logger) {
+	if maxAge <= 0 || interval <= 0 {
+		return
+	}
+	log = log.With(slog.String("component", "audit/retention"))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purge(ctx, repo, maxAge, log)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purge(ctx context.Context, repo Purger, maxAge time.Duration, log *slog.Logger) {
+	before := time.Now().UTC().Add(-maxAge)
+	n, err := repo.PurgeAuditLogsOlderThan(ctx, before)
+	if err != nil {
+		log.Error("failed to purge audit logs", slog.String("err", err.Error()))
+		return
+	}
+	log.Info("audit logs purged", slog.Int64("rows", n), slog.Time("before", before))
+}
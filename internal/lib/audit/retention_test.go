@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingPurger struct {
+	mu    sync.Mutex
+	calls []time.Time
+}
+
+func (p *recordingPurger) PurgeAuditLogsOlderThan(_ context.Context, t time.Time) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, t)
+	return 3, nil
+}
+
+func (p *recordingPurger) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.calls)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestRunRetention_PurgesOlderThanMaxAge(t *testing.T) {
+	p := &recordingPurger{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	const maxAge = 90 * 24 * time.Hour
+
+	start := time.Now().UTC()
+	go func() {
+		RunRetention(ctx, p, maxAge, 5*time.Millisecond, discardLogger())
+		close(done)
+	}()
+
+	deadline := time.After(time.Second)
+	for p.count() < 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("purged %d times, want at least 2", p.count())
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+
+	// первая очистка сразу при старте, граница — now - maxAge
+	first := p.calls[0]
+	if lo, hi := start.Add(-maxAge), time.Now().UTC().Add(-maxAge); first.Before(lo) || first.After(hi) {
+		t.Errorf("cutoff = %s, want between %s and %s", first, lo, hi)
+	}
+}
+
+func TestRunRetention_Disabled(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		maxAge, interval time.Duration
+	}{
+		{"max age 0", 0, time.Hour},
+		{"interval 0", time.Hour, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &recordingPurger{}
+			// выключенная очистка возвращается сразу, не дожидаясь отмены ctx
+			RunRetention(context.Background(), p, tt.maxAge, tt.interval, discardLogger())
+			if p.count() != 0 {
+				t.Fatalf("purged %d times with retention disabled", p.count())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Package blocklist хранит отозванные токены (jti) до истечения их срока действия.
+package blocklist
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Store interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// Memory хранит отозванные jti в памяти процесса.
+// После перезапуска сервиса список очищается.
+type Memory struct {
+	mu    sync.Mutex
+	items map[string]time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{items: make(map[string]time.Time)}
+}
+
+func (m *Memory) Revoke(_ context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cleanup(time.Now())
+	m.items[jti] = expiresAt
+	return nil
+}
+
+func (m *Memory) IsRevoked(_ context.Context, jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt, ok := m.items[jti]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(expiresAt) {
+		delete(m.items, jti)
+		return false, nil
+	}
+	return true, nil
+}
+
+// cleanup удаляет записи по истёкшим токенам, вызывается под блокировкой
+func (m *Memory) cleanup(now time.Time) {
+	for jti, expiresAt := range m.items {
+		if now.After(expiresAt) {
+			delete(m.items, jti)
+		}
+	}
+}
@@ -1,21 +1,54 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"service/internal/domain/models"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func NewToken(user models.User, duration time.Duration, jwtSecret string) (string, error) {
+// Имена claims с ролями и правами пользователя
+const (
+	ClaimRoles       = "roles"
+	ClaimPermissions = "perms"
+)
+
+// Access роли и права пользователя на момент выдачи токена. Они не обновляются
+// до истечения токена: снятая роль продолжает действовать до конца jwt-ttl.
+type Access struct {
+	RoleIDs     []int64
+	Permissions []string
+}
+
+// NewToken при access != nil встраивает в токен роли и права пользователя
+func NewToken(user models.User, access *Access, duration time.Duration, jwtSecret string) (string, error) {
+	jti, err := newJTI()
+	if err != nil {
+		return "", err
+	}
 	token := jwt.New(jwt.SigningMethodHS256)
 	claims := token.Claims.(jwt.MapClaims)
-	claims["id"] = user.UserID
+	claims["id"] = int64(user.UserID)
 	claims["email"] = user.Email
 	claims["exp"] = time.Now().Add(duration).Unix()
+	claims["jti"] = jti
+	if access != nil {
+		claims[ClaimRoles] = access.RoleIDs
+		claims[ClaimPermissions] = access.Permissions
+	}
 	tokenString, err := token.SignedString([]byte(jwtSecret))
 	if err != nil {
 		return "", err
 	}
 	return tokenString, nil
 }
+
+func newJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
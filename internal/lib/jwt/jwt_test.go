@@ -0,0 +1,49 @@
+package jwt
+
+import (
+	"reflect"
+	"service/internal/domain/models"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func parseClaims(t *testing.T, token, secret string) jwt.MapClaims {
+	t.Helper()
+	parsed, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return []byte(secret), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Claims.(jwt.MapClaims)
+}
+
+func TestNewToken_EmbedsRolesAndPermissions(t *testing.T) {
+	access := &Access{RoleIDs: []int64{1, 3}, Permissions: []string{"student:read", "grade:create"}}
+	token, err := NewToken(models.User{UserID: 5, Email: "a@example.com"}, access, time.Hour, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := parseClaims(t, token, "secret")
+
+	if got := claims[ClaimRoles]; !reflect.DeepEqual(got, []interface{}{float64(1), float64(3)}) {
+		t.Fatalf("roles = %v", got)
+	}
+	if got := claims[ClaimPermissions]; !reflect.DeepEqual(got, []interface{}{"student:read", "grade:create"}) {
+		t.Fatalf("perms = %v", got)
+	}
+}
+
+func TestNewToken_WithoutAccess(t *testing.T) {
+	token, err := NewToken(models.User{UserID: 5}, nil, time.Hour, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := parseClaims(t, token, "secret")
+	if _, ok := claims[ClaimRoles]; ok {
+		t.Fatal("roles claim must be absent without access")
+	}
+	if _, ok := claims[ClaimPermissions]; ok {
+		t.Fatal("perms claim must be absent without access")
+	}
+}
@@ -0,0 +1,103 @@
+package rotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Writer пишет в файл и переименовывает его в <path>.1, <path>.2, ...
+// при превышении MaxSize байт. Хранится не больше MaxBackups старых файлов.
+type Writer struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// New открывает (или создаёт) файл лога. maxSize <= 0 отключает ротацию.
+func New(path string, maxSize int64, maxBackups int) (*Writer, error) {
+	const op = "lib.logger.rotate.New"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	w := &Writer{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return w, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		// при неудачной ротации запись не теряется, а продолжается в текущий файл
+		if rerr := w.rotate(); rerr != nil {
+			n, err := w.file.Write(p)
+			w.size += int64(n)
+			if err != nil {
+				return n, err
+			}
+			return n, rerr
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+// rotate сдвигает старые файлы и открывает новый. Текущий файл закрывается
+// только после открытия нового, поэтому при ошибке запись продолжается в него.
+func (w *Writer) rotate() error {
+	if w.maxBackups <= 0 {
+		// файл открыт с O_APPEND, после обрезки запись пойдёт с начала
+		if err := w.file.Truncate(0); err != nil {
+			return err
+		}
+		w.size = 0
+		return nil
+	}
+	// самый старый файл перезаписывается сдвигом
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", w.path, i)
+		if err := os.Rename(src, fmt.Sprintf("%s.%d", w.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	old := w.file
+	if err := w.open(); err != nil {
+		return err
+	}
+	return old.Close()
+}
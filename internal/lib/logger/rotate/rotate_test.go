@@ -0,0 +1,84 @@
+package rotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriter_RotatesIntoBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := New(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for _, line := range []string{"first-line\n", "second-line\n", "third-line\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertContent(t, path, "third-line\n")
+	assertContent(t, path+".1", "second-line\n")
+	assertContent(t, path+".2", "first-line\n")
+}
+
+func TestWriter_NoBackupsTruncates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := New(path, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	_, _ = w.Write([]byte("first-line\n"))
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, path, "second\n")
+}
+
+func TestWriter_FailedRotationKeepsWriting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	// непустой каталог на месте app.log.1 не даёт переименовать текущий файл
+	if err := os.MkdirAll(filepath.Join(path+".1", "busy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	_, _ = w.Write([]byte("first-line\n"))
+	n, err := w.Write([]byte("second\n"))
+	if err == nil {
+		t.Fatal("expected rotation error")
+	}
+	if n != len("second\n") {
+		t.Fatalf("written %d bytes, want the line to be written anyway", n)
+	}
+	// после снятия помехи ротация проходит и файл по-прежнему открыт
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatalf("write after recovered rotation: %v", err)
+	}
+	assertContent(t, path, "third\n")
+	assertContent(t, path+".1", "first-line\nsecond\n")
+}
+
+func assertContent(t *testing.T, path, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Fatalf("%s = %q, want %q", filepath.Base(path), got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+var ErrQueueFull = errors.New("notification queue is full")
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Async ставит уведомления в буферизованную очередь и отправляет их в фоне,
+// чтобы ошибки и задержки доставки не влияли на HTTP-запрос.
+type Async struct {
+	next  Notifier
+	queue chan Message
+	log   *slog.Logger
+}
+
+func NewAsync(next Notifier, bufferSize int, log *slog.Logger) *Async {
+	a := &Async{
+		next:  next,
+		queue: make(chan Message, bufferSize),
+		log:   log.With(slog.String("component", "notifier")),
+	}
+	go a.run()
+	return a
+}
+
+func (a *Async) Notify(_ context.Context, msg Message) error {
+	select {
+	case a.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (a *Async) run() {
+	for msg := range a.queue {
+		if err := a.next.Notify(context.Background(), msg); err != nil {
+			a.log.Error("failed to send notification",
+				slog.String("to", msg.To),
+				slog.String("err", err.Error()),
+			)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"service/internal/config"
+	"service/internal/lib/notifier"
+	"strings"
+)
+
+type Notifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func New(cfg config.SMTP) *Notifier {
+	n := &Notifier{
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		from: cfg.From,
+	}
+	if cfg.Username != "" {
+		n.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return n
+}
+
+func (n *Notifier) Notify(_ context.Context, msg notifier.Message) error {
+	const op = "notifier.smtp.Notify"
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{msg.To}, n.buildMessage(msg)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// buildMessage собирает письмо; тема кодируется по RFC 2047, чтобы кириллица не ломала заголовок
+func (n *Notifier) buildMessage(msg notifier.Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(msg.Body)
+	return []byte(b.String())
+}
@@ -0,0 +1,48 @@
+package smtp
+
+import (
+	"mime"
+	"net/mail"
+	"service/internal/lib/notifier"
+	"strings"
+	"testing"
+)
+
+func TestBuildMessage_EncodesSubject(t *testing.T) {
+	n := &Notifier{from: "noreply@example.com"}
+	raw := n.buildMessage(notifier.Message{
+		To:      "student@example.com",
+		Subject: "Новая оценка",
+		Body:    "Оценка: 5",
+	})
+
+	m, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	rawSubject := m.Header.Get("Subject")
+	if !strings.HasPrefix(rawSubject, "=?utf-8?q?") {
+		t.Fatalf("subject is not Q-encoded: %q", rawSubject)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(rawSubject)
+	if err != nil || subject != "Новая оценка" {
+		t.Fatalf("decoded subject %q, err %v", subject, err)
+	}
+	if got := m.Header.Get("MIME-Version"); got != "1.0" {
+		t.Fatalf("MIME-Version = %q", got)
+	}
+	if got := m.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+}
+
+func TestBuildMessage_SubjectCannotInjectHeaders(t *testing.T) {
+	n := &Notifier{from: "noreply@example.com"}
+	raw := string(n.buildMessage(notifier.Message{
+		To:      "student@example.com",
+		Subject: "hi\r\nBcc: attacker@example.com",
+	}))
+	if strings.Contains(raw, "\r\nBcc:") {
+		t.Fatalf("subject injected a header:\n%s", raw)
+	}
+}
@@ -0,0 +1,39 @@
+package phone
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid phone number")
+
+// e164Re итоговый формат: "+", код страны без ведущего нуля, всего до 15 цифр
+var e164Re = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// separators символы оформления, которые отбрасываются перед проверкой
+var separators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "", "\t", "")
+
+// Normalizer приводит номера к E.164.
+// Strict = true принимает только международный формат с "+" (разделители допустимы),
+// иначе дополнительно понимает "00..." и российские 8XXXXXXXXXX / 7XXXXXXXXXX.
+type Normalizer struct {
+	Strict bool
+}
+
+// Normalize возвращает номер в виде +XXXXXXXXXXX или ErrInvalid
+func (n Normalizer) Normalize(raw string) (string, error) {
+	s := separators.Replace(strings.TrimSpace(raw))
+	if !n.Strict && !strings.HasPrefix(s, "+") {
+		switch {
+		case strings.HasPrefix(s, "00"):
+			s = "+" + s[2:]
+		case len(s) == 11 && (s[0] == '8' || s[0] == '7'):
+			s = "+7" + s[1:]
+		}
+	}
+	if !e164Re.MatchString(s) {
+		return "", ErrInvalid
+	}
+	return s, nil
+}
@@ -0,0 +1,51 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        string
+		wantStrict string // "" — номер отклоняется
+		wantLax    string
+	}{
+		{"e164", "+79001234567", "+79001234567", "+79001234567"},
+		{"e164 with separators", "+7 (900) 123-45-67", "+79001234567", "+79001234567"},
+		{"dots and tabs", "\t+44.20.7946.0958 ", "+442079460958", "+442079460958"},
+		{"international 00 prefix", "00 44 20 7946 0958", "", "+442079460958"},
+		{"russian trunk 8", "8 (900) 123-45-67", "", "+79001234567"},
+		{"russian without plus", "79001234567", "", "+79001234567"},
+		{"ten digits", "9001234567", "", ""},
+		{"leading zero country code", "+0123456789", "", ""},
+		{"too short", "+1234567", "", ""},
+		{"too long", "+1234567890123456", "", ""},
+		{"letters", "+7900CALLME", "", ""},
+		{"plus inside", "7+9001234567", "", ""},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, mode := range []struct {
+				n    Normalizer
+				want string
+			}{
+				{Normalizer{Strict: true}, tt.wantStrict},
+				{Normalizer{}, tt.wantLax},
+			} {
+				got, err := mode.n.Normalize(tt.raw)
+				if mode.want == "" {
+					if !errors.Is(err, ErrInvalid) {
+						t.Errorf("strict=%v: Normalize(%q) = %q, %v; want ErrInvalid", mode.n.Strict, tt.raw, got, err)
+					}
+					continue
+				}
+				if err != nil || got != mode.want {
+					t.Errorf("strict=%v: Normalize(%q) = %q, %v; want %q", mode.n.Strict, tt.raw, got, err, mode.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// FieldChange старое и новое значение поля
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// служебные поля, которые меняются при каждом сохранении
+var diffIgnoredKeys = map[string]struct{}{
+	"created_at": {},
+	"updated_at": {},
+}
+
+// JSONDiff сравнивает JSON-представления old и new и возвращает только изменённые поля.
+// Возвращает nil, если сравнить нельзя или изменений нет.
+func JSONDiff(old, new interface{}) map[string]FieldChange {
+	oldMap, ok := toJSONMap(old)
+	if !ok {
+		return nil
+	}
+	newMap, ok := toJSONMap(new)
+	if !ok {
+		return nil
+	}
+
+	changes := make(map[string]FieldChange)
+	for k, nv := range newMap {
+		if _, skip := diffIgnoredKeys[k]; skip {
+			continue
+		}
+		if ov, ok := oldMap[k]; !ok || !reflect.DeepEqual(ov, nv) {
+			changes[k] = FieldChange{Old: oldMap[k], New: nv}
+		}
+	}
+	for k, ov := range oldMap {
+		if _, skip := diffIgnoredKeys[k]; skip {
+			continue
+		}
+		if _, ok := newMap[k]; !ok {
+			changes[k] = FieldChange{Old: ov, New: nil}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// PtrToJSONDiff то же, что JSONDiff, но сразу в виде строки для audit_log.changes
+func PtrToJSONDiff(old, new interface{}) *string {
+	changes := JSONDiff(old, new)
+	if changes == nil {
+		return nil
+	}
+	return PtrToJSON(changes)
+}
+
+func toJSONMap(v interface{}) (map[string]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil || m == nil {
+		return nil, false
+	}
+	return m, true
+}
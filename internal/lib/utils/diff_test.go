@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type diffSample struct {
+	Name      string    `json:"name"`
+	Grade     int       `json:"grade"`
+	Comment   *string   `json:"comment,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func TestJSONDiff(t *testing.T) {
+	comment := "retake"
+	tests := []struct {
+		name string
+		old  interface{}
+		new  interface{}
+		want map[string]FieldChange
+	}{
+		{
+			name: "unchanged",
+			old:  diffSample{Name: "a", Grade: 4},
+			new:  diffSample{Name: "a", Grade: 4, UpdatedAt: time.Now()},
+			want: nil,
+		},
+		{
+			name: "changed",
+			old:  diffSample{Name: "a", Grade: 4},
+			new:  &diffSample{Name: "a", Grade: 5},
+			want: map[string]FieldChange{"grade": {Old: float64(4), New: float64(5)}},
+		},
+		{
+			name: "added key",
+			old:  diffSample{Name: "a"},
+			new:  diffSample{Name: "a", Comment: &comment},
+			want: map[string]FieldChange{"comment": {Old: nil, New: "retake"}},
+		},
+		{
+			name: "removed key",
+			old:  map[string]interface{}{"name": "a", "comment": "retake"},
+			new:  map[string]interface{}{"name": "a"},
+			want: map[string]FieldChange{"comment": {Old: "retake", New: nil}},
+		},
+		{
+			name: "nil side",
+			old:  (*diffSample)(nil),
+			new:  diffSample{Name: "a"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JSONDiff(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("JSONDiff = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPtrToJSONDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  interface{}
+		new  interface{}
+		want *string
+	}{
+		{"unchanged", diffSample{Name: "a"}, diffSample{Name: "a"}, nil},
+		{"changed", diffSample{Name: "a"}, diffSample{Name: "b"}, PtrToStr(`{"name":{"old":"a","new":"b"}}`)},
+		{"added and removed", map[string]int{"x": 1}, map[string]int{"y": 2}, PtrToStr(`{"x":{"old":1,"new":null},"y":{"old":null,"new":2}}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PtrToJSONDiff(tt.old, tt.new)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("PtrToJSONDiff = %v, want %v", deref(got), deref(tt.want))
+			}
+		})
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
@@ -3,26 +3,36 @@ package utils
 import (
 	"context"
 	"encoding/json"
-	"strconv"
+	"service/internal/domain/models"
+	ware "service/internal/http-server/middleware"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
 )
 
-func GetUserIDFromContext(ctx context.Context) *int64 {
-	val := ctx.Value("user_id")
-	if val == nil {
-		return nil
-	}
-	switch v := val.(type) {
-	case int64:
-		return &v
-	case string:
-		i, err := strconv.ParseInt(v, 10, 64)
-		if err == nil {
-			return &i
-		}
+// GetUserIDFromContext id автора изменения для аудита, nil для анонимного запроса
+func GetUserIDFromContext(ctx context.Context) *models.ID {
+	if id, ok := ware.UserIDFromContext(ctx); ok {
+		userID := models.ID(id)
+		return &userID
 	}
 	return nil
 }
 
+// GetRequestIDFromContext id запроса, выставленный middleware.RequestID, nil вне HTTP-запроса
+func GetRequestIDFromContext(ctx context.Context) *string {
+	id := middleware.GetReqID(ctx)
+	if id == "" {
+		return nil
+	}
+	return &id
+}
+
+// NormalizeEmail приводит email к виду, в котором он хранится и ищется в БД
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func PtrToStr(s string) *string {
 	return &s
 }
@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"service/internal/domain/models"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+
+	maxAttempts  = 5
+	initialDelay = time.Second
+)
+
+type Repository interface {
+	ListWebhookByEvent(ctx context.Context, event string) ([]*models.Webhook, error)
+}
+
+type Payload struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Dispatcher асинхронно рассылает события подписанным вебхукам
+type Dispatcher struct {
+	repo   Repository
+	client *http.Client
+	queue  chan Payload
+	log    *slog.Logger
+	// retryDelay задержка перед первым повтором, дальше удваивается
+	retryDelay time.Duration
+}
+
+func NewDispatcher(repo Repository, bufferSize int, log *slog.Logger) *Dispatcher {
+	d := &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan Payload, bufferSize),
+		log:    log.With(slog.String("component", "webhook")),
+
+		retryDelay: initialDelay,
+	}
+	go d.run()
+	return d
+}
+
+// Dispatch ставит событие в очередь и не блокирует вызывающего
+func (d *Dispatcher) Dispatch(_ context.Context, event string, data interface{}) {
+	p := Payload{Event: event, OccurredAt: time.Now().UTC(), Data: data}
+	select {
+	case d.queue <- p:
+	default:
+		d.log.Error("webhook queue is full, event dropped", slog.String("event", event))
+	}
+}
+
+func (d *Dispatcher) run() {
+	for p := range d.queue {
+		hooks, err := d.repo.ListWebhookByEvent(context.Background(), p.Event)
+		if err != nil {
+			d.log.Error("failed to list webhooks", slog.String("event", p.Event), slog.String("err", err.Error()))
+			continue
+		}
+		if len(hooks) == 0 {
+			continue
+		}
+		body, err := json.Marshal(p)
+		if err != nil {
+			d.log.Error("failed to marshal webhook payload", slog.String("event", p.Event), slog.String("err", err.Error()))
+			continue
+		}
+		for _, hook := range hooks {
+			go d.deliver(hook, p.Event, body)
+		}
+	}
+}
+
+// deliver отправляет payload с повторами и экспоненциальной задержкой
+func (d *Dispatcher) deliver(hook *models.Webhook, event string, body []byte) {
+	log := d.log.With(slog.Int64("webhook_id", hook.WebhookID), slog.String("event", event))
+	delay := d.retryDelay
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err := d.send(hook, event, body)
+		if err == nil {
+			return
+		}
+		log.Info("webhook delivery failed", slog.Int("attempt", attempt), slog.String("err", err.Error()))
+		if attempt < maxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	log.Error("webhook delivery gave up", slog.Int("attempts", maxAttempts))
+}
+
+func (d *Dispatcher) send(hook *models.Webhook, event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(hook.Secret, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Sign возвращает HMAC-SHA256 тела в hex
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
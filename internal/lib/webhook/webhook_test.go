@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"service/internal/domain/models"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type staticRepo []*models.Webhook
+
+func (r staticRepo) ListWebhookByEvent(context.Context, string) ([]*models.Webhook, error) {
+	return r, nil
+}
+
+type received struct {
+	event     string
+	signature string
+	body      []byte
+}
+
+func TestDispatcher_SignsAndRetries(t *testing.T) {
+	var attempts atomic.Int32
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// первые две попытки завершаются ошибкой, третья успешна
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		got <- received{event: r.Header.Get(EventHeader), signature: r.Header.Get(SignatureHeader), body: body}
+	}))
+	defer srv.Close()
+
+	repo := staticRepo{{WebhookID: 1, URL: srv.URL, Secret: "s3cret"}}
+	d := NewDispatcher(repo, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.retryDelay = time.Millisecond
+
+	d.Dispatch(context.Background(), "grade.created", map[string]int{"grade": 5})
+
+	var rcv received
+	select {
+	case rcv = <-got:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook was not delivered, attempts: %d", attempts.Load())
+	}
+
+	if attempts.Load() != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts.Load())
+	}
+	if rcv.event != "grade.created" {
+		t.Fatalf("%s = %q", EventHeader, rcv.event)
+	}
+	if want := "sha256=" + Sign("s3cret", rcv.body); rcv.signature != want {
+		t.Fatalf("signature %q, want %q", rcv.signature, want)
+	}
+	var p Payload
+	if err := json.Unmarshal(rcv.body, &p); err != nil || p.Event != "grade.created" {
+		t.Fatalf("payload %s, err %v", rcv.body, err)
+	}
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(staticRepo{{WebhookID: 1, URL: srv.URL}}, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.retryDelay = time.Millisecond
+	d.deliver(&models.Webhook{WebhookID: 1, URL: srv.URL}, "grade.created", []byte(`{}`))
+
+	if attempts.Load() != maxAttempts {
+		t.Fatalf("attempts = %d, want %d", attempts.Load(), maxAttempts)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("key", "The quick brown fox jumps over the lazy dog")
+	const want = "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got := Sign("key", []byte("The quick brown fox jumps over the lazy dog")); got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+}
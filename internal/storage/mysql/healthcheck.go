@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// requiredTables таблицы, без которых сервис не может обслуживать запросы
+var requiredTables = []string{
+	"user", "roles", "permissions", "user_roles", "role_permissions",
+	"teacher", "student", "student_group", "academic_year", "semester",
+	"discipline", "curriculum", "grade_journal", "attendance",
+	"audit_log", "idempotency_key", "webhook",
+}
+
+// requiredRoles роли, на которые опирается RBAC и регистрация
+var requiredRoles = []string{"admin", "admin-teacher", "teacher", "student"}
+
+// HealthCheck проверяет при старте, что схема накатана: есть все таблицы
+// и базовые роли. Возвращает одну ошибку со списком всего, чего не хватает.
+func HealthCheck(ctx context.Context, db *sql.DB) error {
+	const op = "storage.mysql.HealthCheck"
+
+	existing, err := existingTables(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	var missingTables []string
+	for _, name := range requiredTables {
+		if _, ok := existing[name]; !ok {
+			missingTables = append(missingTables, name)
+		}
+	}
+	if len(missingTables) > 0 {
+		return fmt.Errorf("%s: missing tables: %s (run cmd/migrator)", op, strings.Join(missingTables, ", "))
+	}
+
+	missingRoles, err := missingRoles(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if len(missingRoles) > 0 {
+		return fmt.Errorf("%s: missing roles: %s (check role seeds in migrations)", op, strings.Join(missingRoles, ", "))
+	}
+	return nil
+}
+
+func existingTables(ctx context.Context, db *sql.DB) (map[string]struct{}, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT table_name FROM information_schema.tables
+		WHERE table_schema = DATABASE()
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string]struct{})
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables[strings.ToLower(name)] = struct{}{}
+	}
+	return tables, rows.Err()
+}
+
+func missingRoles(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT role_name FROM roles`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	present := make(map[string]struct{})
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		present[name] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range requiredRoles {
+		if _, ok := present[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
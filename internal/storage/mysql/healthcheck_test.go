@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// schemaDB отвечает на запросы HealthCheck заданными таблицами и ролями
+type schemaDB struct {
+	tables, roles []string
+	err           error
+}
+
+func (s schemaDB) Connect(context.Context) (driver.Conn, error) { return schemaConn{s}, nil }
+func (s schemaDB) Driver() driver.Driver                        { return nil }
+
+type schemaConn struct{ db schemaDB }
+
+func (schemaConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (schemaConn) Close() error                        { return nil }
+func (schemaConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c schemaConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if c.db.err != nil {
+		return nil, c.db.err
+	}
+	if strings.Contains(query, "information_schema.tables") {
+		return &nameRows{names: c.db.tables}, nil
+	}
+	return &nameRows{names: c.db.roles}, nil
+}
+
+type nameRows struct {
+	names []string
+	pos   int
+}
+
+func (*nameRows) Columns() []string { return []string{"name"} }
+func (*nameRows) Close() error      { return nil }
+func (r *nameRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.names) {
+		return io.EOF
+	}
+	dest[0] = r.names[r.pos]
+	r.pos++
+	return nil
+}
+
+func without(names []string, drop ...string) []string {
+	var out []string
+	for _, n := range names {
+		keep := true
+		for _, d := range drop {
+			if n == d {
+				keep = false
+			}
+		}
+		if keep {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func TestHealthCheck(t *testing.T) {
+	// имена таблиц в information_schema бывают в верхнем регистре
+	upper := make([]string, 0, len(requiredTables))
+	for _, name := range requiredTables {
+		upper = append(upper, strings.ToUpper(name))
+	}
+	connErr := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		db      schemaDB
+		wantErr string
+	}{
+		{"schema ready", schemaDB{tables: append(requiredTables, "schema_migrations"), roles: requiredRoles}, ""},
+		{"upper-case table names", schemaDB{tables: upper, roles: requiredRoles}, ""},
+		{"missing tables", schemaDB{tables: without(requiredTables, "webhook", "attendance"), roles: requiredRoles}, "missing tables: attendance, webhook"},
+		{"missing roles", schemaDB{tables: requiredTables, roles: []string{"admin", "student"}}, "missing roles: admin-teacher, teacher"},
+		{"database unavailable", schemaDB{err: connErr}, "connection refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sql.OpenDB(tt.db)
+			defer db.Close()
+
+			err := HealthCheck(context.Background(), db)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
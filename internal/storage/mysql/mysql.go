@@ -13,8 +13,18 @@ type Storage struct {
 }
 
 // New открывает соединение с MySQL и возвращает *sql.DB.
+//
+// Все колонки времени — TIMESTAMP: MySQL хранит их в UTC и переводит
+// в часовой пояс сессии. Сессия и драйвер работают в UTC (time_zone и loc),
+// поэтому значения читаются и пишутся без сдвига и миграция схемы не нужна.
+// При переходе на DATETIME сдвиг пришлось бы учитывать вручную.
+//
+// clientFoundRows: RowsAffected у UPDATE считает найденные строки, а не
+// изменённые, поэтому n == 0 в репозиториях означает именно "записи нет".
+// По той же причине RowsAffected у INSERT ... ON DUPLICATE KEY UPDATE не отличает
+// нетронутую существующую строку от изменённой, и на него полагаться нельзя.
 func New(cfg config.SQLPath) (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&loc=Local",
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&clientFoundRows=true&loc=UTC&time_zone=%%27%%2B00%%3A00%%27",
 		cfg.User, cfg.Password, cfg.Host, fmt.Sprintf("%d", cfg.Port), cfg.DBName,
 	)
 
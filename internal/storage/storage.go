@@ -1,8 +1,53 @@
 package storage
 
-import "errors"
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+)
 
 var (
 	ErrURLNotFound = errors.New("url not found")
 	ErrURLExists   = errors.New("url exists")
+	ErrDuplicate   = errors.New("duplicate entry")
+	ErrReferenced  = errors.New("row is referenced by other rows")
+	// ErrMissingReference запись ссылается на несуществующую строку
+	ErrMissingReference = errors.New("referenced row does not exist")
+	// ErrNotOwner запись принадлежит другому пользователю
+	ErrNotOwner = errors.New("row belongs to another owner")
 )
+
+// ForeignKeyError нарушение внешнего ключа, оборачивает ErrReferenced или ErrMissingReference.
+// Table — таблица на другой стороне связи: ссылающаяся для ErrReferenced,
+// родительская для ErrMissingReference. Пустая, если её не удалось определить.
+type ForeignKeyError struct {
+	Table string
+	Err   error
+}
+
+func (e *ForeignKeyError) Error() string {
+	if e.Table == "" {
+		return e.Err.Error()
+	}
+	return e.Err.Error() + ": " + e.Table
+}
+
+func (e *ForeignKeyError) Unwrap() error {
+	return e.Err
+}
+
+// IsConnError сообщает, что ошибка вызвана недоступностью БД (обрыв соединения, сетевая ошибка),
+// а не самим запросом. Такие запросы имеет смысл повторить позже.
+func IsConnError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// badConnector соединение с БД всегда обрывается
+type badConnector struct{}
+
+func (badConnector) Connect(context.Context) (driver.Conn, error) { return nil, driver.ErrBadConn }
+func (badConnector) Driver() driver.Driver                        { return badDriver{} }
+
+type badDriver struct{}
+
+func (badDriver) Open(string) (driver.Conn, error) { return nil, driver.ErrBadConn }
+
+func TestIsConnError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"bad conn", driver.ErrBadConn, true},
+		{"wrapped bad conn", fmt.Errorf("list students: %w", driver.ErrBadConn), true},
+		{"mysql invalid conn", mysql.ErrInvalidConn, true},
+		{"conn done", sql.ErrConnDone, true},
+		{"network", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"no rows", sql.ErrNoRows, false},
+		{"duplicate", &mysql.MySQLError{Number: 1062}, false},
+		{"plain", errors.New("syntax error"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConnError(tt.err); got != tt.want {
+				t.Errorf("IsConnError(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConnError_FromDatabaseSQL(t *testing.T) {
+	db := sql.OpenDB(badConnector{})
+	defer db.Close()
+
+	_, err := db.QueryContext(context.Background(), "SELECT 1")
+	if err == nil {
+		t.Fatal("query succeeded without connection")
+	}
+	if !IsConnError(err) {
+		t.Fatalf("IsConnError(%v) = false after database/sql retries", err)
+	}
+}
@@ -0,0 +1,10 @@
+DELETE rp
+FROM
+    role_permissions rp
+    JOIN permissions p ON rp.permission_id = p.permission_id
+WHERE
+    p.permission_name = 'stats:view';
+
+DELETE FROM permissions
+WHERE
+    permission_name = 'stats:view';
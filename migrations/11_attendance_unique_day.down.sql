@@ -0,0 +1,9 @@
+ALTER TABLE attendance
+DROP INDEX uq_attendance_student_discipline_date,
+DROP COLUMN attendance_date;
+
+-- возвращаем отметки, перенесённые в архив up-миграцией
+INSERT INTO attendance
+SELECT * FROM attendance_duplicate_archive;
+
+DROP TABLE attendance_duplicate_archive;
@@ -0,0 +1,21 @@
+-- по одной отметке на студента/дисциплину/день (самая ранняя), иначе индекс не создать.
+-- Лишние отметки не удаляются безвозвратно: они переносятся в attendance_duplicate_archive,
+-- откуда их возвращает down-миграция
+CREATE TABLE attendance_duplicate_archive LIKE attendance;
+
+INSERT INTO attendance_duplicate_archive
+SELECT a.* FROM attendance a
+WHERE EXISTS (
+    SELECT 1 FROM attendance b
+    WHERE b.student_id = a.student_id
+      AND b.discipline_id = a.discipline_id
+      AND DATE(b.created_at) = DATE(a.created_at)
+      AND b.attendance_id < a.attendance_id
+);
+
+DELETE a FROM attendance a
+JOIN attendance_duplicate_archive d ON a.attendance_id = d.attendance_id;
+
+ALTER TABLE attendance
+ADD COLUMN attendance_date DATE GENERATED ALWAYS AS (DATE(created_at)) STORED,
+ADD UNIQUE INDEX uq_attendance_student_discipline_date (student_id, discipline_id, attendance_date);
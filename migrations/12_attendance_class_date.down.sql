@@ -0,0 +1,5 @@
+ALTER TABLE attendance
+DROP INDEX uq_attendance_student_discipline_date,
+DROP COLUMN class_date,
+ADD COLUMN attendance_date DATE GENERATED ALWAYS AS (DATE(created_at)) STORED,
+ADD UNIQUE INDEX uq_attendance_student_discipline_date (student_id, discipline_id, attendance_date);
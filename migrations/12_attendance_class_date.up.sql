@@ -0,0 +1,11 @@
+-- class_date — день занятия, может отличаться от created_at при внесении задним числом
+ALTER TABLE attendance
+ADD COLUMN class_date DATE NULL AFTER visit;
+
+UPDATE attendance SET class_date = DATE(created_at);
+
+ALTER TABLE attendance
+MODIFY COLUMN class_date DATE NOT NULL,
+DROP INDEX uq_attendance_student_discipline_date,
+DROP COLUMN attendance_date,
+ADD UNIQUE INDEX uq_attendance_student_discipline_date (student_id, discipline_id, class_date);
@@ -0,0 +1,16 @@
+INSERT INTO
+    permissions (permission_name)
+VALUES
+    ('attendance:view_self');
+
+INSERT INTO
+    role_permissions (role_id, permission_id)
+SELECT
+    r.role_id,
+    p.permission_id
+FROM
+    roles r,
+    permissions p
+WHERE
+    r.role_name IN ('student')
+    AND p.permission_name = 'attendance:view_self';
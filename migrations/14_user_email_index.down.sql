@@ -0,0 +1,2 @@
+ALTER TABLE `user`
+RENAME INDEX uq_user_email TO email;
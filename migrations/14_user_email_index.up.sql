@@ -0,0 +1,4 @@
+-- индекс по email уже создан ограничением UNIQUE в 1_init, но с автоматическим именем;
+-- даём ему явное имя, чтобы на него можно было ссылаться в следующих миграциях
+ALTER TABLE `user`
+RENAME INDEX email TO uq_user_email;
@@ -0,0 +1,15 @@
+ALTER TABLE role_permissions
+DROP FOREIGN KEY fk_role_permissions_permission,
+ADD CONSTRAINT role_permissions_ibfk_2 FOREIGN KEY (permission_id) REFERENCES permissions (permission_id);
+
+ALTER TABLE audit_log
+DROP FOREIGN KEY fk_audit_log_user,
+ADD CONSTRAINT audit_log_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (user_id);
+
+ALTER TABLE idempotency_key
+DROP FOREIGN KEY fk_idempotency_key_user,
+ADD CONSTRAINT idempotency_key_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (user_id);
+
+ALTER TABLE user_roles
+DROP FOREIGN KEY fk_user_roles_user,
+ADD CONSTRAINT user_roles_ibfk_2 FOREIGN KEY (user_id) REFERENCES user (user_id);
@@ -0,0 +1,23 @@
+-- Внешние ключи созданы в 1_init без ON DELETE, то есть с RESTRICT. Для учебных данных
+-- (студенты, дисциплины, оценки, посещаемость, группы, семестры) RESTRICT оставляем:
+-- удаление записи, на которую ссылаются, отклоняется и API отвечает 409.
+-- Каскад и SET NULL — только для служебных связей, которые не имеют смысла без родителя.
+
+-- связи пользователя с ролями и ключи идемпотентности удаляются вместе с пользователем
+ALTER TABLE user_roles
+DROP FOREIGN KEY user_roles_ibfk_2,
+ADD CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES user (user_id) ON DELETE CASCADE;
+
+ALTER TABLE idempotency_key
+DROP FOREIGN KEY idempotency_key_ibfk_1,
+ADD CONSTRAINT fk_idempotency_key_user FOREIGN KEY (user_id) REFERENCES user (user_id) ON DELETE CASCADE;
+
+-- записи аудита сохраняются, автор обнуляется
+ALTER TABLE audit_log
+DROP FOREIGN KEY audit_log_ibfk_1,
+ADD CONSTRAINT fk_audit_log_user FOREIGN KEY (user_id) REFERENCES user (user_id) ON DELETE SET NULL;
+
+-- при удалении права оно снимается со всех ролей
+ALTER TABLE role_permissions
+DROP FOREIGN KEY role_permissions_ibfk_2,
+ADD CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (permission_id) ON DELETE CASCADE;
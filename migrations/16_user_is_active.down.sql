@@ -0,0 +1,13 @@
+DELETE rp
+FROM
+    role_permissions rp
+    JOIN permissions p ON rp.permission_id = p.permission_id
+WHERE
+    p.permission_name = 'user:deactivate';
+
+DELETE FROM permissions
+WHERE
+    permission_name = 'user:deactivate';
+
+ALTER TABLE user
+DROP COLUMN is_active;
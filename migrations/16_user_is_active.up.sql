@@ -0,0 +1,19 @@
+ALTER TABLE user
+ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE AFTER password;
+
+INSERT INTO
+    permissions (permission_name)
+VALUES
+    ('user:deactivate');
+
+INSERT INTO
+    role_permissions (role_id, permission_id)
+SELECT
+    r.role_id,
+    p.permission_id
+FROM
+    roles r,
+    permissions p
+WHERE
+    r.role_name = 'admin'
+    AND p.permission_name = 'user:deactivate';
@@ -0,0 +1,3 @@
+ALTER TABLE audit_log
+DROP INDEX idx_audit_log_request_id,
+DROP COLUMN request_id;
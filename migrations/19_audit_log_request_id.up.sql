@@ -0,0 +1,3 @@
+ALTER TABLE audit_log
+ADD COLUMN request_id VARCHAR(128) NULL AFTER comment,
+ADD INDEX idx_audit_log_request_id (request_id);
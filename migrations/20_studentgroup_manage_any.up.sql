@@ -0,0 +1,16 @@
+INSERT INTO
+    permissions (permission_name)
+VALUES
+    ('studentgroup:manage_any');
+
+INSERT INTO
+    role_permissions (role_id, permission_id)
+SELECT
+    r.role_id,
+    p.permission_id
+FROM
+    roles r,
+    permissions p
+WHERE
+    r.role_name = 'admin'
+    AND p.permission_name = 'studentgroup:manage_any';
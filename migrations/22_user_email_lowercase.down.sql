@@ -0,0 +1,3 @@
+ALTER TABLE `user`
+DROP INDEX uq_user_email,
+ADD UNIQUE INDEX uq_user_email (email);
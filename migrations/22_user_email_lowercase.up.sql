@@ -0,0 +1,8 @@
+UPDATE `user`
+SET
+    email = LOWER(TRIM(email));
+
+-- уникальность по нормализованному значению: email в разном регистре считается одним адресом
+ALTER TABLE `user`
+DROP INDEX uq_user_email,
+ADD UNIQUE INDEX uq_user_email ((LOWER(email)));
@@ -0,0 +1,4 @@
+ALTER TABLE `idempotency_key`
+DROP COLUMN fingerprint,
+DROP COLUMN content_type,
+DROP COLUMN location;
@@ -0,0 +1,8 @@
+-- старые ключи без отпечатка запроса нельзя безопасно сопоставить, удаляем их
+DELETE FROM `idempotency_key`;
+
+-- status_code = 0 — запрос с этим ключом ещё выполняется
+ALTER TABLE `idempotency_key`
+ADD COLUMN fingerprint CHAR(64) NOT NULL AFTER idempotency_key,
+ADD COLUMN content_type VARCHAR(255) NOT NULL DEFAULT '' AFTER status_code,
+ADD COLUMN location VARCHAR(2048) NOT NULL DEFAULT '' AFTER content_type;
@@ -0,0 +1,14 @@
+-- студент, к которому относится запись аудита журнала оценок: история оценок
+-- не должна зависеть от снимков old_data/new_data, которые можно отключить
+ALTER TABLE audit_log
+ADD COLUMN student_id BIGINT NULL AFTER row_id,
+ADD INDEX idx_audit_log_student (table_name, student_id, created_at);
+
+UPDATE audit_log
+SET
+    student_id = COALESCE(
+        JSON_EXTRACT(new_data, '$.student_id'),
+        JSON_EXTRACT(old_data, '$.student_id')
+    )
+WHERE
+    table_name = 'grade_journal';
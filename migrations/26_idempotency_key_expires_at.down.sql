@@ -0,0 +1,2 @@
+ALTER TABLE `idempotency_key`
+DROP INDEX idx_idempotency_key_expires_at;
@@ -0,0 +1,3 @@
+-- для периодического удаления истёкших ключей
+ALTER TABLE `idempotency_key`
+ADD INDEX idx_idempotency_key_expires_at (expires_at);
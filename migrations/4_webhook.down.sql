@@ -0,0 +1,24 @@
+DELETE rp
+FROM
+    role_permissions rp
+    JOIN permissions p ON rp.permission_id = p.permission_id
+WHERE
+    p.permission_name IN (
+        'webhook:create',
+        'webhook:view',
+        'webhook:update',
+        'webhook:delete',
+        'webhook:list'
+    );
+
+DELETE FROM permissions
+WHERE
+    permission_name IN (
+        'webhook:create',
+        'webhook:view',
+        'webhook:update',
+        'webhook:delete',
+        'webhook:list'
+    );
+
+drop table webhook;
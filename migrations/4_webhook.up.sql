@@ -0,0 +1,37 @@
+CREATE TABLE
+    `webhook` (
+        webhook_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+        url VARCHAR(2048) NOT NULL,
+        event_types VARCHAR(500) NOT NULL,
+        secret VARCHAR(255) NOT NULL,
+        CHECK (CHAR_LENGTH(url) >= 8)
+    );
+
+INSERT INTO
+    permissions (permission_name)
+VALUES
+    ('webhook:create'),
+    ('webhook:view'),
+    ('webhook:update'),
+    ('webhook:delete'),
+    ('webhook:list');
+
+INSERT INTO
+    role_permissions (role_id, permission_id)
+SELECT
+    r.role_id,
+    p.permission_id
+FROM
+    roles r,
+    permissions p
+WHERE
+    r.role_name = 'admin'
+    AND p.permission_name IN (
+        'webhook:create',
+        'webhook:view',
+        'webhook:update',
+        'webhook:delete',
+        'webhook:list'
+    );
@@ -0,0 +1,11 @@
+CREATE TABLE
+    `idempotency_key` (
+        user_id BIGINT NOT NULL,
+        idempotency_key VARCHAR(255) NOT NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        expires_at TIMESTAMP NOT NULL,
+        status_code SMALLINT NOT NULL,
+        response_body MEDIUMTEXT NOT NULL,
+        PRIMARY KEY (user_id, idempotency_key),
+        FOREIGN KEY (user_id) REFERENCES user (user_id)
+    );
@@ -0,0 +1,17 @@
+INSERT INTO
+    permissions (permission_name)
+VALUES
+    ('teacher:view_public'),
+    ('teacher:list_public');
+
+INSERT INTO
+    role_permissions (role_id, permission_id)
+SELECT
+    r.role_id,
+    p.permission_id
+FROM
+    roles r,
+    permissions p
+WHERE
+    r.role_name IN ('admin', 'admin-teacher', 'teacher', 'student')
+    AND p.permission_name IN ('teacher:view_public', 'teacher:list_public');
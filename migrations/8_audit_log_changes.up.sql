@@ -0,0 +1,2 @@
+ALTER TABLE audit_log
+ADD COLUMN changes JSON AFTER new_data;